@@ -0,0 +1,551 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// Batch (i.e., multiple input file) flags
+const (
+	FLAG_BATCH_MAX_WORKERS = "max-workers"
+)
+
+// Batch flag help messages
+const (
+	FLAG_BATCH_INPUT_FILES_HELP = "input filename(s) (repeat the flag or use a comma-separated list for multiple files); " +
+		"also accepts glob patterns (e.g., \"sboms/*.json\"), directories (i.e., all \".json\" files found) " +
+		"and \"@\" prefixed files that list one input per line"
+	FLAG_BATCH_MAX_WORKERS_HELP = "maximum number of input files processed concurrently when multiple input files are provided (default: number of CPUs)"
+)
+
+// Batch input file list syntax
+const (
+	BATCH_INPUT_FILE_LIST_PREFIX   = "@"
+	BATCH_INPUT_FILE_LIST_COMMENT  = "#"
+	BATCH_INPUT_GLOB_SPECIAL_CHARS = "*?["
+)
+
+// Batch report columns
+const (
+	BATCH_REPORT_COLUMN_FILENAME = "filename"
+	BATCH_REPORT_COLUMN_ERROR    = "error"
+	BATCH_REPORT_VALUE_NO_ERROR  = REPORT_LIST_VALUE_NONE
+)
+
+// Batch error messages
+const (
+	MSG_BATCH_INPUT_FILE_NOT_FOUND    = "input file not found"
+	MSG_BATCH_INPUT_PATTERN_NO_MATCH  = "no input files match pattern"
+	MSG_BATCH_INPUT_DIRECTORY_EMPTY   = "no input files (i.e., \".json\") found in directory"
+	MSG_BATCH_INPUT_STDIN_NOT_ALLOWED = "standard input (i.e., \"-\") cannot be combined with other input files"
+	MSG_BATCH_DOCUMENTS_INVALID       = "one or more input files are invalid"
+	MSG_BATCH_DOCUMENTS_FAILED        = "one or more input files could not be processed"
+)
+
+// File extensions of BOMs found when an input is a directory
+var BATCH_INPUT_DIRECTORY_FILE_EXTENSIONS = []string{".json"}
+
+// The result of processing a single input file of a batch; where the rows
+// match the column titles of the batch report (less the filename and error columns)
+type BatchDocumentResult struct {
+	Filename string
+	Rows     [][]string
+	Err      error
+}
+
+// The per-document work of a batch command which produces the (report) rows for the input file
+type BatchDocumentFunc func(inputFile string) (rows [][]string, err error)
+
+// A document of a (streamed) batch; if Data is nil, the document is read from the named file
+// Note: an error (e.g., reading the document from the stream) is reported as the document's result
+type BatchDocument struct {
+	Filename string
+	Data     []byte
+	Err      error
+}
+
+// The per-document work of a (streamed) batch which produces the (report) rows for the document
+type BatchStreamFunc func(document BatchDocument) (rows [][]string, err error)
+
+// Add the (local) input file and worker flags to commands that support batches
+// Note: the local "--input-file" flag shadows the (persistent) single input file flag
+// declared on the root command (see "merge" command)
+func initCommandBatchFlags(command *cobra.Command) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.BatchFlags.InputFiles, FLAG_FILENAME_INPUT, FLAG_FILENAME_INPUT_SHORT, nil, FLAG_BATCH_INPUT_FILES_HELP)
+	command.Flags().IntVarP(&utils.GlobalFlags.BatchFlags.MaxWorkers, FLAG_BATCH_MAX_WORKERS, "", 0, FLAG_BATCH_MAX_WORKERS_HELP)
+}
+
+// Command PreRunE helper function to resolve (batch) input files
+// Note: a single (resolved) input file is treated exactly as if given to the
+// (persistent) single input file flag so that its output is unchanged.
+func preRunTestForBatchInputFiles(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	batchFlags := &utils.GlobalFlags.BatchFlags
+	getLogger().Tracef("inputs: %v", batchFlags.InputFiles)
+
+	if len(batchFlags.InputFiles) == 0 {
+		return getLogger().Errorf("Missing required argument(s): %s", FLAG_FILENAME_INPUT)
+	}
+
+	var inputFiles []string
+	if inputFiles, err = ResolveInputFiles(batchFlags.InputFiles); err != nil {
+		return getLogger().Errorf("%s", err)
+	}
+	batchFlags.InputFiles = inputFiles
+
+	if len(inputFiles) == 1 {
+		utils.GlobalFlags.PersistentFlags.InputFile = inputFiles[0]
+	}
+	getLogger().Infof("Resolved (%v) input file(s)", len(inputFiles))
+	return
+}
+
+// Returns true if more than one input file was resolved (i.e., results require aggregation)
+func IsBatch(batchFlags utils.BatchCommandFlags) bool {
+	return len(batchFlags.InputFiles) > 1
+}
+
+// Resolve the input filenames, glob patterns, directories and "@" file lists to a
+// (de-duplicated) list of filenames preserving the order the inputs were provided.
+func ResolveInputFiles(inputs []string) (inputFiles []string, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	resolver := inputFileResolver{
+		found:     make(map[string]bool),
+		fileLists: make(map[string]bool),
+	}
+	for _, input := range inputs {
+		if err = resolver.resolve(input); err != nil {
+			return
+		}
+	}
+	inputFiles = resolver.inputFiles
+
+	if len(inputFiles) > 1 && resolver.found[INPUT_TYPE_STDIN] {
+		err = fmt.Errorf("%s", MSG_BATCH_INPUT_STDIN_NOT_ALLOWED)
+	}
+	return
+}
+
+type inputFileResolver struct {
+	inputFiles []string
+	found      map[string]bool
+	fileLists  map[string]bool // guards against file lists that (recursively) include themselves
+}
+
+func (resolver *inputFileResolver) add(inputFile string) {
+	if !resolver.found[inputFile] {
+		resolver.found[inputFile] = true
+		resolver.inputFiles = append(resolver.inputFiles, inputFile)
+	}
+}
+
+func (resolver *inputFileResolver) resolve(input string) (err error) {
+	input = strings.TrimSpace(input)
+	switch {
+	case input == "":
+		return
+	case input == INPUT_TYPE_STDIN:
+		resolver.add(input)
+		return
+	case strings.HasPrefix(input, BATCH_INPUT_FILE_LIST_PREFIX):
+		return resolver.resolveFileList(strings.TrimPrefix(input, BATCH_INPUT_FILE_LIST_PREFIX))
+	case strings.ContainsAny(input, BATCH_INPUT_GLOB_SPECIAL_CHARS):
+		var matches []string
+		if matches, err = filepath.Glob(batchLocalPath(input)); err != nil {
+			return fmt.Errorf("invalid pattern: `%s`: %w", input, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("%s: `%s`", MSG_BATCH_INPUT_PATTERN_NO_MATCH, input)
+		}
+		for _, match := range matches {
+			if err = resolver.resolvePath(batchDisplayPath(input, match)); err != nil {
+				return
+			}
+		}
+		return
+	}
+	return resolver.resolvePath(input)
+}
+
+func (resolver *inputFileResolver) resolvePath(path string) (err error) {
+	info, errStat := os.Stat(batchLocalPath(path))
+	if errStat != nil {
+		return fmt.Errorf("%s: `%s`", MSG_BATCH_INPUT_FILE_NOT_FOUND, path)
+	}
+
+	if !info.IsDir() {
+		resolver.add(path)
+		return
+	}
+
+	// Add all BOM files (by extension) found (recursively) under the directory in lexical order
+	numFound := 0
+	err = filepath.WalkDir(batchLocalPath(path), func(filename string, entry fs.DirEntry, errWalk error) error {
+		if errWalk != nil {
+			return errWalk
+		}
+		if entry.Type().IsRegular() && hasBatchInputFileExtension(filename) {
+			resolver.add(batchDisplayPath(path, filename))
+			numFound++
+		}
+		return nil
+	})
+	if err == nil && numFound == 0 {
+		err = fmt.Errorf("%s: `%s`", MSG_BATCH_INPUT_DIRECTORY_EMPTY, path)
+	}
+	return
+}
+
+// Each (non-empty) line of a file list is resolved as an input (i.e., a filename,
+// pattern, directory or another file list); lines that begin with "#" are ignored.
+func (resolver *inputFileResolver) resolveFileList(fileList string) (err error) {
+	if resolver.fileLists[fileList] {
+		return
+	}
+	resolver.fileLists[fileList] = true
+
+	file, errOpen := os.Open(batchLocalPath(fileList))
+	if errOpen != nil {
+		return fmt.Errorf("%s: `%s`", MSG_BATCH_INPUT_FILE_NOT_FOUND, fileList)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, BATCH_INPUT_FILE_LIST_COMMENT) {
+			continue
+		}
+		if err = resolver.resolve(line); err != nil {
+			return
+		}
+	}
+	return scanner.Err()
+}
+
+// Relative paths are resolved against the working directory (i.e., the same as BOM input files)
+func batchLocalPath(path string) string {
+	if filepath.IsAbs(path) || utils.GlobalFlags.WorkingDir == "" {
+		return path
+	}
+	return filepath.Join(utils.GlobalFlags.WorkingDir, path)
+}
+
+// Returns the path (found using the input pattern or directory) relative to the
+// working directory if the input was itself relative
+func batchDisplayPath(input string, localPath string) string {
+	if filepath.IsAbs(input) || utils.GlobalFlags.WorkingDir == "" {
+		return localPath
+	}
+	if relativePath, err := filepath.Rel(utils.GlobalFlags.WorkingDir, localPath); err == nil {
+		return relativePath
+	}
+	return localPath
+}
+
+func hasBatchInputFileExtension(filename string) bool {
+	extension := strings.ToLower(filepath.Ext(filename))
+	for _, value := range BATCH_INPUT_DIRECTORY_FILE_EXTENSIONS {
+		if extension == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Process all input files using a bounded pool of (concurrent) workers
+// Note: results are returned in the same order as the input files
+func ProcessBatch(inputFiles []string, maxWorkers int, process BatchDocumentFunc) (results []BatchDocumentResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+	if maxWorkers > len(inputFiles) {
+		maxWorkers = len(inputFiles)
+	}
+	getLogger().Infof("Processing (%v) input files using (%v) workers...", len(inputFiles), maxWorkers)
+
+	documents := make(chan BatchDocument)
+	go func() {
+		for _, inputFile := range inputFiles {
+			documents <- BatchDocument{Filename: inputFile}
+		}
+		close(documents)
+	}()
+
+	return ProcessBatchStream(documents, maxWorkers, func(document BatchDocument) ([][]string, error) {
+		return process(document.Filename)
+	})
+}
+
+// Process all documents received (until the channel is closed) using a pool of (at most maxWorkers)
+// concurrent workers; results are returned in the order the documents were received
+func ProcessBatchStream(documents <-chan BatchDocument, maxWorkers int, process BatchStreamFunc) (results []BatchDocumentResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+
+	type batchJob struct {
+		index    int
+		document BatchDocument
+	}
+
+	jobs := make(chan batchJob)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < maxWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				var rows [][]string
+				err := job.document.Err
+				if err == nil {
+					rows, err = process(job.document)
+				}
+				if err != nil {
+					getLogger().Debugf("input file: `%s`: %v", job.document.Filename, err)
+				}
+				// Note: results may be reallocated (appended to) as documents are received
+				mutex.Lock()
+				results[job.index].Rows, results[job.index].Err = rows, err
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	index := 0
+	for document := range documents {
+		mutex.Lock()
+		results = append(results, BatchDocumentResult{Filename: document.Filename})
+		mutex.Unlock()
+		jobs <- batchJob{index: index, document: document}
+		index++
+	}
+	close(jobs)
+	wg.Wait()
+	return
+}
+
+// Returns an error that determines the overall exit code of the batch; any input
+// file that could not be processed takes precedence over any (merely) invalid input file.
+func BatchResultsError(results []BatchDocumentResult) error {
+	var failed, invalid []string
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		if IsInvalidBOMError(result.Err) {
+			invalid = append(invalid, result.Filename)
+		} else {
+			failed = append(failed, result.Filename)
+		}
+	}
+
+	if len(failed) > 0 {
+		return NewUtilityError(ERR_TYPE_BATCH, MSG_BATCH_DOCUMENTS_FAILED, strings.Join(failed, ", "), nil)
+	}
+	if len(invalid) > 0 {
+		err := NewInvalidSBOMError(nil, MSG_BATCH_DOCUMENTS_INVALID, nil, nil)
+		err.InputFile = strings.Join(invalid, ", ")
+		return err
+	}
+	return nil
+}
+
+// Returns the (batch) report rows for a document result; i.e., prefixed with the
+// filename and suffixed with the error (if any)
+// Note: results without rows (e.g., errors) are given a single row of "none" values
+func (result BatchDocumentResult) reportRows(numColumns int) (rows [][]string) {
+	errorValue := BATCH_REPORT_VALUE_NO_ERROR
+	if result.Err != nil {
+		errorValue = strings.ReplaceAll(result.Err.Error(), "\n", " ")
+	}
+
+	documentRows := result.Rows
+	if len(documentRows) == 0 {
+		emptyRow := make([]string, numColumns)
+		for i := range emptyRow {
+			emptyRow[i] = REPORT_LIST_VALUE_NONE
+		}
+		documentRows = [][]string{emptyRow}
+	}
+
+	for _, documentRow := range documentRows {
+		row := append([]string{result.Filename}, documentRow...)
+		rows = append(rows, append(row, errorValue))
+	}
+	return
+}
+
+func batchReportTitles(titles []string) []string {
+	reportTitles := append([]string{BATCH_REPORT_COLUMN_FILENAME}, titles...)
+	return append(reportTitles, BATCH_REPORT_COLUMN_ERROR)
+}
+
+// Display the aggregated batch results using the requested format (defaults to "txt")
+func DisplayBatchResults(writer io.Writer, format string, titles []string, results []BatchDocumentResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	getLogger().Infof("Outputting batch results (`%s` format)...", format)
+	switch format {
+	case FORMAT_TEXT:
+		DisplayBatchResultsText(writer, titles, results)
+	case FORMAT_CSV:
+		err = DisplayBatchResultsCSV(writer, titles, results)
+	case FORMAT_MARKDOWN:
+		DisplayBatchResultsMarkdown(writer, titles, results)
+	case FORMAT_JSON:
+		err = DisplayBatchResultsJSON(writer, titles, results)
+	default:
+		getLogger().Warningf("Batch results not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplayBatchResultsText(writer, titles, results)
+	}
+	return
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayBatchResultsText(writer io.Writer, titles []string, results []BatchDocumentResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	// create title row and underline row
+	reportTitles := batchReportTitles(titles)
+	fmt.Fprintf(w, "%s\n", strings.Join(reportTitles, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(reportTitles), "\t"))
+
+	for _, result := range results {
+		for _, row := range result.reportRows(len(titles)) {
+			fmt.Fprintf(w, "%s\n", strings.Join(row, "\t"))
+		}
+	}
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayBatchResultsCSV(writer io.Writer, titles []string, results []BatchDocumentResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	reportTitles := batchReportTitles(titles)
+	if err = w.Write(reportTitles); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", reportTitles, err)
+	}
+
+	for _, result := range results {
+		for _, row := range result.reportRows(len(titles)) {
+			if err = w.Write(row); err != nil {
+				return getLogger().Errorf("csv.Write: %w", err)
+			}
+		}
+	}
+	return
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayBatchResultsMarkdown(writer io.Writer, titles []string, results []BatchDocumentResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	reportTitles := batchReportTitles(titles)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(reportTitles))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(reportTitles)))
+
+	for _, result := range results {
+		for _, row := range result.reportRows(len(titles)) {
+			fmt.Fprintf(writer, "%s\n", createMarkdownRow(row))
+		}
+	}
+}
+
+// JSON batch results are grouped by input file where each row is an object keyed by column title
+type BatchDocumentResultJSON struct {
+	Filename string              `json:"filename"`
+	Error    string              `json:"error,omitempty"`
+	Results  []map[string]string `json:"results"`
+}
+
+func DisplayBatchResultsJSON(writer io.Writer, titles []string, results []BatchDocumentResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	jsonResults := make([]BatchDocumentResultJSON, 0, len(results))
+	for _, result := range results {
+		jsonResult := BatchDocumentResultJSON{
+			Filename: result.Filename,
+			Results:  make([]map[string]string, 0, len(result.Rows)),
+		}
+		if result.Err != nil {
+			jsonResult.Error = result.Err.Error()
+		}
+		for _, row := range result.Rows {
+			values := make(map[string]string, len(titles))
+			for i, title := range titles {
+				if i < len(row) {
+					values[title] = row[i]
+				}
+			}
+			jsonResult.Results = append(jsonResult.Results, values)
+		}
+		jsonResults = append(jsonResults, jsonResult)
+	}
+
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, jsonResults,
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
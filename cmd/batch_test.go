@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	// Test batch input file resolution
+	TEST_BATCH_INPUT_FILE_LIST = "test/batch/batch-input-list.txt"
+	TEST_BATCH_INPUT_DIRECTORY = "test/spdx"
+)
+
+// -------------------------------------------
+// batch test helper functions
+// -------------------------------------------
+
+func innerTestResolveInputFiles(t *testing.T, inputs []string, expectedInputFiles []string) {
+	inputFiles, err := ResolveInputFiles(inputs)
+	if err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	if !reflect.DeepEqual(inputFiles, expectedInputFiles) {
+		t.Errorf("invalid input files: inputs: %v: expected: %v, actual: %v", inputs, expectedInputFiles, inputFiles)
+	}
+}
+
+func innerTestResolveInputFilesError(t *testing.T, inputs []string, expectedPhrase string) {
+	_, err := ResolveInputFiles(inputs)
+	if err == nil {
+		t.Errorf("expected error for inputs: %v", inputs)
+		return
+	}
+	EvaluateErrorAndKeyPhrases(t, err, []string{expectedPhrase})
+}
+
+// Invoke the batch function and test its (buffered) output against the test info.
+func innerTestBatch(t *testing.T, testInfo *CommonTestInfo, batch func(writer *bufio.Writer) error) (outputBuffer bytes.Buffer, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+
+	err = batch(outputWriter)
+	// ensure all data is written to buffer before further validation
+	outputWriter.Flush()
+
+	// Note: batch output is still produced (and tested) for documents with errors
+	outputTestInfo := *testInfo
+	if testInfo.ResultExpectedError != nil {
+		if !ErrorTypesMatch(err, testInfo.ResultExpectedError) {
+			t.Errorf("expected error: %T, actual error: %T", testInfo.ResultExpectedError, err)
+		}
+		outputTestInfo.ResultExpectedError = nil
+		err = nil
+	}
+	innerRunReportResultTests(t, &outputTestInfo, outputBuffer, err)
+	return
+}
+
+func newBatchTestFlags(format string, inputFiles ...string) (utils.PersistentCommandFlags, utils.BatchCommandFlags) {
+	var persistentFlags utils.PersistentCommandFlags
+	persistentFlags.OutputFormat = format
+	var batchFlags utils.BatchCommandFlags
+	batchFlags.InputFiles = inputFiles
+	batchFlags.MaxWorkers = 2
+	return persistentFlags, batchFlags
+}
+
+// -------------------------------------------
+// input file resolution tests
+// -------------------------------------------
+
+func TestBatchResolveInputFilesFileList(t *testing.T) {
+	innerTestResolveInputFiles(t, []string{"@" + TEST_BATCH_INPUT_FILE_LIST}, []string{
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		TEST_RESOURCE_LIST_CDX_1_3,
+	})
+}
+
+func TestBatchResolveInputFilesDirectory(t *testing.T) {
+	// Note: non-BOM files (e.g., "README.md") are not included
+	innerTestResolveInputFiles(t, []string{TEST_BATCH_INPUT_DIRECTORY}, []string{
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING,
+		TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES,
+	})
+}
+
+func TestBatchResolveInputFilesGlobAndRepeated(t *testing.T) {
+	innerTestResolveInputFiles(t, []string{TEST_RESOURCE_LIST_CDX_1_3, "test/spdx/spdx-2-2-*.json", TEST_RESOURCE_LIST_CDX_1_3}, []string{
+		TEST_RESOURCE_LIST_CDX_1_3,
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING,
+	})
+}
+
+func TestBatchResolveInputFilesFailNotFound(t *testing.T) {
+	innerTestResolveInputFilesError(t, []string{TEST_SPDX_2_2_MIN_REQUIRED, "test/spdx/not-found.json"},
+		MSG_BATCH_INPUT_FILE_NOT_FOUND)
+}
+
+func TestBatchResolveInputFilesFailPatternNoMatch(t *testing.T) {
+	innerTestResolveInputFilesError(t, []string{"test/spdx/*.xml"}, MSG_BATCH_INPUT_PATTERN_NO_MATCH)
+}
+
+func TestBatchResolveInputFilesFailStdinCombined(t *testing.T) {
+	innerTestResolveInputFilesError(t, []string{INPUT_TYPE_STDIN, TEST_SPDX_2_2_MIN_REQUIRED},
+		MSG_BATCH_INPUT_STDIN_NOT_ALLOWED)
+}
+
+// -------------------------------------------
+// batch command tests
+// -------------------------------------------
+
+func TestBatchResourceListCSV(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_CSV, TEST_RESOURCE_LIST_CDX_1_3, TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES)
+	ti := NewCommonTestInfoBasic(TEST_RESOURCE_LIST_CDX_1_3)
+	ti.ResultLineContainsValues = []string{"filename,type,name,version,bom-ref,error"}
+	ti.ResultLineContainsValuesAtLineNum = 0
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListResourcesBatch(writer, persistentFlags, batchFlags, utils.NewResourceCommandFlags(""), nil)
+	})
+
+	ti.ResultLineContainsValues = []string{TEST_RESOURCE_LIST_CDX_1_3 + ",service,Bar,,service:example.com/myservices/bar,none"}
+	ti.ResultLineContainsValuesAtLineNum = -1
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListResourcesBatch(writer, persistentFlags, batchFlags, utils.NewResourceCommandFlags(""), nil)
+	})
+}
+
+func TestBatchResourceListFailInvalidInputFile(t *testing.T) {
+	// A (non-BOM) input file that cannot be loaded is reported on its own row
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_MARKDOWN, TEST_RESOURCE_LIST_CDX_1_3, TEST_BATCH_INPUT_FILE_LIST)
+	ti := NewCommonTestInfoBasic(TEST_RESOURCE_LIST_CDX_1_3)
+	ti.ResultExpectedError = &UtilityError{}
+	ti.ResultLineContainsValues = []string{"|" + TEST_BATCH_INPUT_FILE_LIST + "|none|none|none|none|invalid character"}
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListResourcesBatch(writer, persistentFlags, batchFlags, utils.NewResourceCommandFlags(""), nil)
+	})
+}
+
+func TestBatchValidateSpdx(t *testing.T) {
+	// Note: the invalid input file determines the overall (error) result
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_CSV, TEST_SPDX_2_2_MIN_REQUIRED, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING)
+	ti := NewCommonTestInfoBasic(TEST_SPDX_2_2_MIN_REQUIRED)
+	ti.ResultExpectedError = &InvalidSBOMError{}
+	ti.ResultLineContainsValues = []string{TEST_SPDX_2_2_MIN_REQUIRED + ",true,none,none,none,none"}
+	ti.ResultLineContainsValuesAtLineNum = 1
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ValidateBatch(writer, persistentFlags, batchFlags, utils.GlobalFlags.ValidateFlags)
+	})
+
+	ti.ResultLineContainsValues = []string{TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING + ",false,required,(root),creationInfo is required"}
+	ti.ResultLineContainsValuesAtLineNum = 2
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ValidateBatch(writer, persistentFlags, batchFlags, utils.GlobalFlags.ValidateFlags)
+	})
+}
+
+// Custom validation of multiple documents by concurrent (batch) workers that share the
+// custom validation config loaded once for the batch
+// Note: run using the race detector (i.e., "go test -race") to detect shared (config) writes
+func TestBatchValidateCustomConcurrent(t *testing.T) {
+	validateFlags := utils.ValidateCommandFlags{CustomValidation: true}
+	customConfig, err := loadCustomValidationConfig(validateFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Note: a nil error denotes a (custom) valid document
+	expectedErrors := map[string]error{
+		TEST_CUSTOM_CDX_1_3_INVALID_COMPOSITION_METADATA_COMPONENT: &SBOMCompositionError{},
+		TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES:                     &InvalidSBOMError{},
+		TEST_CUSTOM_CDX_1_4_METADATA_PROPS_DISCLAIMER_MISSING:      &InvalidSBOMError{},
+		TEST_CDX_1_4_MATURITY_EXAMPLE_1_BASE:                       nil,
+	}
+	var inputFiles []string
+	for i := 0; i < 4; i++ {
+		for inputFile := range expectedErrors {
+			inputFiles = append(inputFiles, inputFile)
+		}
+	}
+
+	results := ProcessBatch(inputFiles, len(inputFiles), func(inputFile string) (rows [][]string, err error) {
+		var document *schema.BOM
+		if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
+			return
+		}
+		// Note: compare the specific (i.e., inner) custom validation error, if any
+		if _, err = validateCustom(document, LicensePolicyConfig, customConfig); err != nil {
+			if invalidErr, ok := err.(*InvalidSBOMError); ok && invalidErr.InnerError != nil {
+				err = invalidErr.InnerError
+			}
+		}
+		return
+	})
+
+	for _, result := range results {
+		if !ErrorTypesMatch(result.Err, expectedErrors[result.Filename]) {
+			t.Errorf("%s: expected error type: `%T`, actual: `%T` (%v)",
+				result.Filename, expectedErrors[result.Filename], result.Err, result.Err)
+		}
+	}
+}
+
+func TestBatchLicenseListJSON(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_JSON, TEST_RESOURCE_LIST_CDX_1_3, TEST_LICENSE_LIST_SPDX_2_3_PACKAGES)
+	ti := NewCommonTestInfoBasic(TEST_RESOURCE_LIST_CDX_1_3)
+	outputBuffer, _ := innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListLicensesBatch(writer, LicensePolicyConfig, persistentFlags, batchFlags, nil)
+	})
+	if !utils.IsValidJsonRaw(outputBuffer.Bytes()) {
+		t.Errorf("output did not contain valid format data; expected: `%s`", FORMAT_JSON)
+		t.Logf("%s", outputBuffer.String())
+	}
+}
+
+func TestBatchVulnerabilityListText(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_TEXT, TEST_VULN_CDX_1_3_EXAMPLE_1_BOM, TEST_VULN_CDX_1_4_EXAMPLE_1_VEX)
+	ti := NewCommonTestInfoBasic(TEST_VULN_CDX_1_4_EXAMPLE_1_VEX)
+	ti.ResultLineContainsValues = []string{TEST_VULN_CDX_1_4_EXAMPLE_1_VEX, "CVE-2022-42004"}
+	_, _ = innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListVulnerabilitiesBatch(writer, persistentFlags, batchFlags, utils.VulnerabilityCommandFlags{Summary: true}, nil)
+	})
+}
+
+func TestBatchStats(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_CSV, TEST_RESOURCE_LIST_CDX_1_3, TEST_STATS_SPDX_2_3_PACKAGES)
+	ti := NewCommonTestInfoBasic(TEST_RESOURCE_LIST_CDX_1_3)
+	// title and one line per statistic of each document (i.e., 9 and 10 lines)
+	ti.ResultExpectedLineCount = 20
+	ti.ResultLineContainsValues = []string{strings.Join(batchReportTitles(STATS_LIST_TITLES), ",")}
+	ti.ResultLineContainsValuesAtLineNum = 0
+	outputBuffer, _ := innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ListStatsBatch(writer, persistentFlags, batchFlags, utils.StatsCommandFlags{})
+	})
+
+	// Each document's statistics are reported (i.e., component, service and vulnerability totals)
+	expectedLines := []string{
+		TEST_RESOURCE_LIST_CDX_1_3 + ",component,total,11,none",
+		TEST_RESOURCE_LIST_CDX_1_3 + ",service,total,2,none",
+		TEST_RESOURCE_LIST_CDX_1_3 + ",vulnerability,total,0,none",
+		TEST_STATS_SPDX_2_3_PACKAGES + ",component,total,5,none",
+		TEST_STATS_SPDX_2_3_PACKAGES + ",component,type: library,3,none",
+		TEST_STATS_SPDX_2_3_PACKAGES + ",vulnerability,total,2,none",
+	}
+	for _, expectedLine := range expectedLines {
+		if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedLine); !found {
+			t.Errorf("expected output to contain: `%s`:\n%s", expectedLine, outputBuffer.String())
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// flags (do not translate)
+const (
+	FLAG_CONVERT_TO            = "to"
+	FLAG_CONVERT_REPORT_FILE   = "report-file"
+	FLAG_CONVERT_REPORT_FORMAT = "report-format"
+)
+
+// flag help (translate)
+const (
+	FLAG_CONVERT_TO_HELP            = "target BOM format (defaults to the format other than the input BOM's format): "
+	FLAG_CONVERT_REPORT_FILE_HELP   = "output filename for the report of fields that could not be converted losslessly (if not set, lossy fields are logged as warnings)"
+	FLAG_CONVERT_REPORT_FORMAT_HELP = "format the conversion report using the specified type"
+)
+
+// Supported conversion target formats
+const (
+	CONVERT_TARGET_CYCLONEDX = "cyclonedx"
+	CONVERT_TARGET_SPDX      = "spdx"
+)
+
+var VALID_CONVERT_TARGETS = []string{CONVERT_TARGET_CYCLONEDX, CONVERT_TARGET_SPDX}
+
+var CONVERT_REPORT_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN}, ", ")
+
+// Conversion report column titles
+const (
+	CONVERT_REPORT_TITLE_LOCATION = "location"
+	CONVERT_REPORT_TITLE_REF      = "ref"
+	CONVERT_REPORT_TITLE_FIELD    = "field"
+	CONVERT_REPORT_TITLE_REASON   = "reason"
+)
+
+var CONVERT_REPORT_TITLES = []string{
+	CONVERT_REPORT_TITLE_LOCATION,
+	CONVERT_REPORT_TITLE_REF,
+	CONVERT_REPORT_TITLE_FIELD,
+	CONVERT_REPORT_TITLE_REASON,
+}
+
+const (
+	MSG_OUTPUT_CONVERSION_LOSSLESS = "[INFO] No lossy fields found."
+)
+
+func NewCommandConvert() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_CONVERT
+	command.Short = "Convert a BOM between CycloneDX and SPDX JSON formats"
+	command.Long = "Convert a CycloneDX (v1.2-v1.5) JSON BOM to an SPDX (v2.3) JSON document or an SPDX (v2.x) JSON document to a CycloneDX (v1.5) JSON BOM and report any fields that could not be represented losslessly"
+	command.RunE = convertCmdImpl
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		// Test for required flags (parameters)
+		if err = preRunTestForInputFile(cmd, args); err != nil {
+			return
+		}
+		if target := utils.GlobalFlags.ConvertFlags.TargetFormat; target != "" && !isValidConvertTarget(target) {
+			err = getLogger().Errorf("invalid `%s` flag value: `%s` (valid values: %s)",
+				FLAG_CONVERT_TO, target, strings.Join(VALID_CONVERT_TARGETS, ", "))
+		}
+		return
+	}
+	initCommandConvertFlags(command)
+
+	return command
+}
+
+func initCommandConvertFlags(command *cobra.Command) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	command.Flags().StringVarP(&utils.GlobalFlags.ConvertFlags.TargetFormat, FLAG_CONVERT_TO, "", "",
+		FLAG_CONVERT_TO_HELP+strings.Join(VALID_CONVERT_TARGETS, ", "))
+	command.Flags().StringVarP(&utils.GlobalFlags.ConvertFlags.ReportFile, FLAG_CONVERT_REPORT_FILE, "", "", FLAG_CONVERT_REPORT_FILE_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.ConvertFlags.ReportFormat, FLAG_CONVERT_REPORT_FORMAT, "", FORMAT_TEXT,
+		FLAG_CONVERT_REPORT_FORMAT_HELP+CONVERT_REPORT_SUPPORTED_FORMATS)
+}
+
+func isValidConvertTarget(target string) bool {
+	for _, value := range VALID_CONVERT_TARGETS {
+		if strings.EqualFold(target, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func convertCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFilename, writer)
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		// always close the output file
+		if outputFile != nil {
+			outputFile.Close()
+			getLogger().Infof("Closed output file: `%s`", outputFilename)
+		}
+	}()
+
+	if err == nil {
+		var report *schema.ConversionReport
+		report, err = Convert(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.ConvertFlags)
+		if err == nil {
+			err = outputConversionReport(report, utils.GlobalFlags.ConvertFlags.ReportFile,
+				utils.GlobalFlags.ConvertFlags.ReportFormat)
+		}
+	}
+
+	return
+}
+
+// Assure all errors are logged
+func processConvertResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+func Convert(writer io.Writer, persistentFlags utils.PersistentCommandFlags, convertFlags utils.ConvertCommandFlags) (report *schema.ConversionReport, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processConvertResults(err)
+		}
+	}()
+
+	var document *schema.BOM
+	if document, err = LoadBOMFileAndDetectSchema(persistentFlags.InputFile); err != nil {
+		return
+	}
+
+	// Determine (and verify) the target format; default to the "other" format
+	target := strings.ToLower(convertFlags.TargetFormat)
+	if target == "" {
+		target = CONVERT_TARGET_SPDX
+		if document.FormatInfo.IsSpdx() {
+			target = CONVERT_TARGET_CYCLONEDX
+		}
+	}
+	if (target == CONVERT_TARGET_SPDX && document.FormatInfo.IsSpdx()) ||
+		(target == CONVERT_TARGET_CYCLONEDX && document.FormatInfo.IsCycloneDx()) {
+		err = getLogger().Errorf("invalid `%s` flag value: input BOM `%s` is already in `%s` format",
+			FLAG_CONVERT_TO, document.GetFilenameInterpolated(), document.FormatInfo.CanonicalName)
+		return
+	}
+
+	getLogger().Infof("Converting BOM `%s` (`%s`, `%s`) to `%s` format...",
+		document.GetFilenameInterpolated(), document.FormatInfo.CanonicalName, document.SchemaInfo.Version, target)
+	var converted interface{}
+	switch target {
+	case CONVERT_TARGET_SPDX:
+		if err = document.UnmarshalCycloneDXBOM(); err != nil {
+			return
+		}
+		converted, report, err = document.ConvertCycloneDXToSPDX()
+	case CONVERT_TARGET_CYCLONEDX:
+		if err = document.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+		converted, report, err = document.ConvertSPDXToCycloneDX()
+	}
+	if err != nil {
+		return
+	}
+
+	// Verify the converted document is a known format and version (with a schema)
+	if _, err = verifyConvertedFormatAndSchema(converted); err != nil {
+		return
+	}
+
+	// Output the converted BOM
+	indentString := utils.GenerateIndentString(int(persistentFlags.OutputIndent))
+	var output bytes.Buffer
+	if output, err = utils.EncodeAnyToIndentedJSONStr(converted, indentString); err != nil {
+		return
+	}
+	_, err = writer.Write(output.Bytes())
+	return
+}
+
+// Assure the converted document's format and version are found in the schema config.
+// Returns the converted document (i.e., loaded from its JSON map) with its detected format and schema.
+func verifyConvertedFormatAndSchema(converted interface{}) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var bytes []byte
+	if bytes, err = json.Marshal(converted); err != nil {
+		return
+	}
+	document = schema.NewBOM(utils.GlobalFlags.PersistentFlags.OutputFile)
+	if err = json.Unmarshal(bytes, &document.JsonMap); err != nil {
+		return
+	}
+	if err = SupportedFormatConfig.FindFormatAndSchema(document); err != nil {
+		return
+	}
+	getLogger().Infof("Converted BOM format, version: `%s`, `%s`",
+		document.FormatInfo.CanonicalName, document.SchemaInfo.Version)
+	return
+}
+
+// Write the conversion report to the report file (if requested) or log lossy fields as warnings
+func outputConversionReport(report *schema.ConversionReport, reportFile string, reportFormat string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if report == nil {
+		return
+	}
+
+	if reportFile == "" {
+		for _, entry := range report.Lossy {
+			getLogger().Warningf("Lossy change (%s): location: `%s`, ref: `%s`, field: `%s`",
+				entry.Reason, entry.Location, entry.Ref, entry.Field)
+		}
+		getLogger().Infof("Converted `%s` (%s) to `%s` (%s) with (%v) lossy change(s)",
+			report.SourceFormat, report.SourceVersion, report.TargetFormat, report.TargetVersion, len(report.Lossy))
+		return
+	}
+
+	outputFile, writer, err := createOutputFile(reportFile)
+	if err != nil {
+		return
+	}
+	defer func() {
+		outputFile.Close()
+		getLogger().Infof("Closed report file: `%s`", reportFile)
+	}()
+
+	err = DisplayConversionReport(writer, report, reportFormat)
+	return
+}
+
+func DisplayConversionReport(writer io.Writer, report *schema.ConversionReport, format string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	getLogger().Infof("Outputting conversion report (`%s` format)...", format)
+	switch format {
+	case FORMAT_JSON:
+		var output bytes.Buffer
+		indentString := utils.GenerateIndentString(int(utils.GlobalFlags.PersistentFlags.OutputIndent))
+		if output, err = utils.EncodeAnyToIndentedJSONStr(report, indentString); err == nil {
+			_, err = writer.Write(output.Bytes())
+		}
+	case FORMAT_CSV:
+		err = DisplayConversionReportCSV(writer, report)
+	case FORMAT_MARKDOWN:
+		DisplayConversionReportMarkdown(writer, report)
+	case FORMAT_TEXT:
+		DisplayConversionReportText(writer, report)
+	default:
+		// Default to Text output for anything else (set as flag default)
+		getLogger().Warningf("Conversion report not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplayConversionReportText(writer, report)
+	}
+	return
+}
+
+func conversionReportLineData(entry schema.ConversionLossyEntry) []string {
+	return []string{entry.Location, entry.Ref, entry.Field, entry.Reason}
+}
+
+func DisplayConversionReportText(writer io.Writer, report *schema.ConversionReport) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\n", strings.Join(CONVERT_REPORT_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(CONVERT_REPORT_TITLES), "\t"))
+
+	if len(report.Lossy) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_CONVERSION_LOSSLESS)
+		return
+	}
+
+	for _, entry := range report.Lossy {
+		fmt.Fprintf(w, "%s\n", strings.Join(conversionReportLineData(entry), "\t"))
+	}
+}
+
+func DisplayConversionReportCSV(writer io.Writer, report *schema.ConversionReport) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	if err = w.Write(CONVERT_REPORT_TITLES); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", CONVERT_REPORT_TITLES, err)
+	}
+
+	if len(report.Lossy) == 0 {
+		if err = w.Write([]string{MSG_OUTPUT_CONVERSION_LOSSLESS}); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", MSG_OUTPUT_CONVERSION_LOSSLESS, err)
+		}
+		return
+	}
+
+	for _, entry := range report.Lossy {
+		line := conversionReportLineData(entry)
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
+		}
+	}
+	return
+}
+
+func DisplayConversionReportMarkdown(writer io.Writer, report *schema.ConversionReport) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(CONVERT_REPORT_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(CONVERT_REPORT_TITLES)))
+
+	if len(report.Lossy) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_CONVERSION_LOSSLESS)
+		return
+	}
+
+	for _, entry := range report.Lossy {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(conversionReportLineData(entry)))
+	}
+}
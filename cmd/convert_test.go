@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	TEST_CONVERT_CDX_1_5_TO_SPDX  = "test/convert/cdx-1-5-convert-spdx.json"
+	TEST_CONVERT_SPDX_2_3_TO_CDX  = TEST_LICENSE_LIST_SPDX_2_3_PACKAGES
+	TEST_CONVERT_CDX_1_4_MIN_REQS = TEST_CDX_1_4_MIN_REQUIRED
+)
+
+func innerBufferedTestConvert(t *testing.T, inputFile string, convertFlags utils.ConvertCommandFlags) (outputBuffer bytes.Buffer, report *schema.ConversionReport, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	var persistentFlags utils.PersistentCommandFlags
+	persistentFlags.InputFile = inputFile
+	persistentFlags.OutputIndent = DEFAULT_OUTPUT_INDENT_LENGTH
+
+	report, err = Convert(outputWriter, persistentFlags, convertFlags)
+	return
+}
+
+func innerTestConvert(t *testing.T, inputFile string, convertFlags utils.ConvertCommandFlags, output interface{}) (report *schema.ConversionReport) {
+	outputBuffer, report, err := innerBufferedTestConvert(t, inputFile, convertFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(outputBuffer.Bytes(), output); err != nil {
+		t.Fatalf("unable to unmarshal converted BOM: %s\n%s", err, outputBuffer.String())
+	}
+	return
+}
+
+func findConversionLossyEntry(report *schema.ConversionReport, ref string, field string) *schema.ConversionLossyEntry {
+	for i, entry := range report.Lossy {
+		if entry.Ref == ref && entry.Field == field {
+			return &report.Lossy[i]
+		}
+	}
+	return nil
+}
+
+func findConvertedPackage(document *schema.SPDXDocument, name string) *schema.SPDXPackage {
+	for i, spdxPackage := range document.GetPackages() {
+		if spdxPackage.Name == name {
+			return &(*document.Packages)[i]
+		}
+	}
+	return nil
+}
+
+func TestConvertCdxToSpdxDocument(t *testing.T) {
+	document := new(schema.SPDXDocument)
+	innerTestConvert(t, TEST_CONVERT_CDX_1_5_TO_SPDX, utils.ConvertCommandFlags{}, document)
+
+	if document.SpdxVersion != schema.CONVERT_SPDX_VERSION || document.DataLicense != schema.CONVERT_SPDX_DATA_LICENSE {
+		t.Errorf("unexpected SPDX version or data license: `%s`, `%s`", document.SpdxVersion, document.DataLicense)
+	}
+	if document.Name != "acme-app-1.0.0" {
+		t.Errorf("expected document name `acme-app-1.0.0`; actual: `%s`", document.Name)
+	}
+	// The namespace reuses the BOM's serial number
+	if !strings.HasSuffix(document.DocumentNamespace, "3e671687-395b-41f5-a30f-a58921a69b79") {
+		t.Errorf("expected document namespace derived from serial number; actual: `%s`", document.DocumentNamespace)
+	}
+	// Timestamps are normalized to UTC
+	if document.CreationInfo == nil || document.CreationInfo.Created != "2023-10-12T17:07:00Z" {
+		t.Fatalf("expected created `2023-10-12T17:07:00Z`; actual: %v", document.CreationInfo)
+	}
+	creators := strings.Join(document.CreationInfo.Creators, ";")
+	for _, creator := range []string{"Tool: cdxgen-9.8.6", "Person: Jane Doe (jane.doe@example.com)", "Organization: ACME Corporation"} {
+		if !strings.Contains(creators, creator) {
+			t.Errorf("expected creator `%s`; actual: %v", creator, document.CreationInfo.Creators)
+		}
+	}
+}
+
+func TestConvertCdxToSpdxPackages(t *testing.T) {
+	document := new(schema.SPDXDocument)
+	innerTestConvert(t, TEST_CONVERT_CDX_1_5_TO_SPDX, utils.ConvertCommandFlags{TargetFormat: CONVERT_TARGET_SPDX}, document)
+
+	// root, nested and top-level components
+	if len(document.GetPackages()) != 4 {
+		t.Fatalf("expected 4 packages; actual: %v", document.Packages)
+	}
+
+	async := findConvertedPackage(document, "async")
+	if async == nil {
+		t.Fatalf("expected package `async`")
+	}
+	if async.LicenseDeclared != "MIT" || async.LicenseConcluded != schema.SPDX_NOASSERTION {
+		t.Errorf("unexpected licenses: declared: `%s`, concluded: `%s`", async.LicenseDeclared, async.LicenseConcluded)
+	}
+	if async.Checksums == nil || (*async.Checksums)[0].Algorithm != "SHA256" {
+		t.Errorf("expected `SHA256` checksum; actual: %v", async.Checksums)
+	}
+	if async.Homepage != "https://caolan.github.io/async/" ||
+		async.DownloadLocation != "https://registry.npmjs.org/async/-/async-2.6.3.tgz" {
+		t.Errorf("unexpected homepage or download location: `%s`, `%s`", async.Homepage, async.DownloadLocation)
+	}
+	if async.Supplier != "Organization: Caolan McMahon" || async.Originator != "Person: Caolan McMahon" {
+		t.Errorf("unexpected supplier or originator: `%s`, `%s`", async.Supplier, async.Originator)
+	}
+
+	// Named licenses are declared as extracted licensing info
+	root := findConvertedPackage(document, "acme-app")
+	if root == nil || root.LicenseDeclared != "LicenseRef-ACME-Proprietary-License" {
+		t.Errorf("expected `LicenseRef-` declared license; actual: %v", root)
+	}
+	if document.FindExtractedLicensingInfo("LicenseRef-ACME-Proprietary-License") == nil {
+		t.Errorf("expected extracted licensing info: %v", document.HasExtractedLicensingInfos)
+	}
+
+	// cpe, purl and vulnerabilities (as advisories) become external refs
+	log4j := findConvertedPackage(document, "log4j-core")
+	if log4j == nil || log4j.ExternalRefs == nil || len(*log4j.ExternalRefs) != 3 {
+		t.Fatalf("expected 3 external refs (purl, cpe, advisory); actual: %v", log4j)
+	}
+	if log4j.LicenseDeclared != "Apache-2.0 OR MIT" {
+		t.Errorf("expected license expression; actual: `%s`", log4j.LicenseDeclared)
+	}
+	vulnerabilities := document.ConvertPackageAdvisoriesToCDXVulnerabilities(*log4j)
+	if len(vulnerabilities) != 1 || vulnerabilities[0].Id != "CVE-2021-44228" {
+		t.Errorf("expected advisory for `CVE-2021-44228`; actual: %v", vulnerabilities)
+	}
+}
+
+func TestConvertCdxToSpdxRelationships(t *testing.T) {
+	document := new(schema.SPDXDocument)
+	innerTestConvert(t, TEST_CONVERT_CDX_1_5_TO_SPDX, utils.ConvertCommandFlags{}, document)
+
+	counts := make(map[string]int)
+	for _, relationship := range document.GetRelationships() {
+		counts[relationship.RelationshipType]++
+	}
+	// root (DESCRIBES), nested component (CONTAINS), resolved dependencies (DEPENDS_ON)
+	if counts[schema.SPDX_RELATIONSHIP_DESCRIBES] != 1 ||
+		counts[schema.SPDX_RELATIONSHIP_CONTAINS] != 1 ||
+		counts[schema.SPDX_RELATIONSHIP_DEPENDS_ON] != 2 {
+		t.Errorf("unexpected relationship counts: %v", counts)
+	}
+	if described := document.GetDescribedElementIds(); len(described) != 1 ||
+		described[0] != "SPDXRef-pkg-npm-acme-app-1.0.0" {
+		t.Errorf("expected root package to be described; actual: %v", described)
+	}
+}
+
+func TestConvertCdxToSpdxReportLossy(t *testing.T) {
+	document := new(schema.SPDXDocument)
+	report := innerTestConvert(t, TEST_CONVERT_CDX_1_5_TO_SPDX, utils.ConvertCommandFlags{}, document)
+
+	if report.SourceFormat != schema.SCHEMA_FORMAT_CYCLONEDX || report.TargetFormat != schema.SCHEMA_FORMAT_SPDX {
+		t.Errorf("unexpected report formats: `%s`, `%s`", report.SourceFormat, report.TargetFormat)
+	}
+	if len(report.Lossy) != 5 {
+		t.Errorf("expected 5 lossy entries; actual: %v", report.Lossy)
+	}
+	log4jRef := "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"
+	for _, expected := range [][]string{
+		{log4jRef, "group"},
+		{log4jRef, "properties"},
+		{"pkg:npm/lodash@4.17.21", "dependsOn"},
+		{"CVE-2021-44228", "ratings"},
+		{"service:acme-api", "services"},
+	} {
+		if findConversionLossyEntry(report, expected[0], expected[1]) == nil {
+			t.Errorf("expected lossy entry (ref: `%s`, field: `%s`); actual: %v", expected[0], expected[1], report.Lossy)
+		}
+	}
+}
+
+func TestConvertSpdxToCdx(t *testing.T) {
+	cdxBom := new(schema.CDXBom)
+	innerTestConvert(t, TEST_CONVERT_SPDX_2_3_TO_CDX, utils.ConvertCommandFlags{}, cdxBom)
+
+	if cdxBom.BOMFormat != schema.CONVERT_CDX_BOM_FORMAT || cdxBom.SpecVersion != schema.CONVERT_CDX_SPEC_VERSION {
+		t.Errorf("unexpected BOM format or version: `%s`, `%s`", cdxBom.BOMFormat, cdxBom.SpecVersion)
+	}
+
+	// The described package becomes the root component; its (contained) file is nested
+	if cdxBom.Metadata == nil || cdxBom.Metadata.Component == nil {
+		t.Fatalf("expected `metadata.component`")
+	}
+	root := cdxBom.Metadata.Component
+	if root.Name != "acme-application" || root.Components == nil || len(*root.Components) != 1 {
+		t.Errorf("expected root `acme-application` with 1 nested component; actual: %v", root)
+	}
+	if cdxBom.Components == nil || len(*cdxBom.Components) != 3 {
+		t.Fatalf("expected 3 components; actual: %v", cdxBom.Components)
+	}
+
+	// DEPENDS_ON relationships become dependencies
+	if cdxBom.Dependencies == nil || len(*cdxBom.Dependencies) != 2 {
+		t.Errorf("expected 2 dependencies; actual: %v", cdxBom.Dependencies)
+	}
+
+	// Advisory external refs become vulnerabilities affecting their package
+	if cdxBom.Vulnerabilities == nil || len(*cdxBom.Vulnerabilities) != 2 {
+		t.Fatalf("expected 2 vulnerabilities; actual: %v", cdxBom.Vulnerabilities)
+	}
+	vulnerability := (*cdxBom.Vulnerabilities)[0]
+	if vulnerability.Id != "CVE-2021-43138" || vulnerability.Affects == nil ||
+		(*vulnerability.Affects)[0].Ref.String() != "SPDXRef-Package-npm-async" {
+		t.Errorf("expected `CVE-2021-43138` to affect `SPDXRef-Package-npm-async`; actual: %v", vulnerability)
+	}
+}
+
+func TestConvertSpdxToCdxReportLossy(t *testing.T) {
+	cdxBom := new(schema.CDXBom)
+	report := innerTestConvert(t, TEST_CONVERT_SPDX_2_3_TO_CDX, utils.ConvertCommandFlags{TargetFormat: CONVERT_TARGET_CYCLONEDX}, cdxBom)
+
+	if findConversionLossyEntry(report, "SPDXRef-Package-pypi-chardet", "licenseDeclared") == nil {
+		t.Errorf("expected lossy entry for differing declared license; actual: %v", report.Lossy)
+	}
+	if findConversionLossyEntry(report, "SPDXRef-Snippet-util", "snippets") == nil {
+		t.Errorf("expected lossy entry for snippet; actual: %v", report.Lossy)
+	}
+}
+
+func TestConvertCdxToCdxInvalidTarget(t *testing.T) {
+	_, _, err := innerBufferedTestConvert(t, TEST_CONVERT_CDX_1_4_MIN_REQS,
+		utils.ConvertCommandFlags{TargetFormat: CONVERT_TARGET_CYCLONEDX})
+	if err == nil {
+		t.Errorf("expected error converting CycloneDX to CycloneDX")
+	}
+}
+
+func TestConvertReportText(t *testing.T) {
+	_, report, err := innerBufferedTestConvert(t, TEST_CONVERT_CDX_1_5_TO_SPDX, utils.ConvertCommandFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outputBuffer bytes.Buffer
+	if err = DisplayConversionReport(&outputBuffer, report, FORMAT_TEXT); err != nil {
+		t.Fatal(err)
+	}
+	// title, separator and 5 lossy entries
+	lines := strings.Split(strings.TrimSpace(outputBuffer.String()), "\n")
+	if len(lines) != 7 {
+		t.Errorf("expected 7 lines; actual: %v\n%s", len(lines), outputBuffer.String())
+	}
+}
+
+func TestConvertReportCSV(t *testing.T) {
+	_, report, err := innerBufferedTestConvert(t, TEST_CONVERT_SPDX_2_3_TO_CDX, utils.ConvertCommandFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outputBuffer bytes.Buffer
+	if err = DisplayConversionReport(&outputBuffer, report, FORMAT_CSV); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(outputBuffer.String(), strings.Join(CONVERT_REPORT_TITLES, ",")) {
+		t.Errorf("expected CSV title row; actual:\n%s", outputBuffer.String())
+	}
+}
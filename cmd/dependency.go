@@ -0,0 +1,639 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SUBCOMMAND_DEPENDENCY_LIST = "list"
+)
+
+var VALID_SUBCOMMANDS_DEPENDENCY = []string{SUBCOMMAND_DEPENDENCY_LIST}
+
+const (
+	FLAG_DEPENDENCY_REF = "ref"
+)
+
+// Command help formatting
+const (
+	FLAG_DEPENDENCY_OUTPUT_FORMAT_HELP = "format dependency output"
+	FLAG_DEPENDENCY_REF_HELP           = "bom-ref of the component (or service) to list the direct and transitive dependencies of"
+)
+
+var DEPENDENCY_LIST_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_CSV, FORMAT_MARKDOWN, FORMAT_JSON, FORMAT_DOT, FORMAT_MERMAID}, ", ")
+
+// Dependency command informational messages
+const (
+	MSG_OUTPUT_NO_DEPENDENCIES_FOUND = "[WARN] no dependencies found"
+)
+
+// Report column titles
+var DEPENDENCY_LIST_TITLES = []string{
+	"bom-ref", "type", "name", "version", "depth", "direct", "transitive", "orphan", "cycle", "dangling",
+}
+
+var DEPENDENCY_REF_TITLES = []string{
+	"bom-ref", "type", "name", "version", "relationship", "distance",
+}
+
+var DEPENDENCY_STATISTICS_TITLES = []string{"statistic", "value"}
+
+// Graph rendering values
+const (
+	DEPENDENCY_GRAPH_NAME         = "dependencies"
+	DEPENDENCY_GRAPH_COLOR_CYCLE  = "red"
+	DEPENDENCY_GRAPH_COLOR_ORPHAN = "gray"
+)
+
+func NewCommandDependency() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_DEPENDENCY_LIST
+	command.Short = "Report on the dependency graph of the BOM input file"
+	command.Long = "Report on the dependency graph (i.e., from the \"dependencies\" array) of the BOM input file including direct and transitive dependencies, dangling references, orphans (i.e., components not reachable from the \"metadata.component\"), cycles and depth statistics"
+	command.Flags().StringVarP(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_FILE_OUTPUT_FORMAT, "", FORMAT_TEXT,
+		FLAG_DEPENDENCY_OUTPUT_FORMAT_HELP+DEPENDENCY_LIST_SUPPORTED_FORMATS)
+	command.Flags().StringVarP(&utils.GlobalFlags.DependencyFlags.Ref, FLAG_DEPENDENCY_REF, "", "", FLAG_DEPENDENCY_REF_HELP)
+	command.RunE = dependencyCmdImpl
+	command.ValidArgs = VALID_SUBCOMMANDS_DEPENDENCY
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		// the dependency command requires at least 1 valid subcommand (argument)
+		getLogger().Tracef("args: %v\n", args)
+		if len(args) == 0 {
+			return getLogger().Errorf("Missing required argument(s).")
+		} else if len(args) > 1 {
+			return getLogger().Errorf("Too many arguments provided: %v", args)
+		}
+
+		// Make sure subcommand is known
+		if !preRunTestForSubcommand(command, VALID_SUBCOMMANDS_DEPENDENCY, args[0]) {
+			return getLogger().Errorf("Subcommand provided is not valid: `%v`", args[0])
+		}
+
+		// Test for required flags (parameters)
+		err = preRunTestForInputFile(cmd, args)
+		return
+	}
+	return command
+}
+
+// Cobra command callback
+func dependencyCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFile, writer)
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		// always close the output file
+		if outputFile != nil {
+			err = outputFile.Close()
+			getLogger().Infof("Closed output file: `%s`", outputFilename)
+		}
+	}()
+
+	if err == nil {
+		err = ListDependencies(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.DependencyFlags)
+	}
+	return
+}
+
+// Assure all errors are logged
+func processDependencyListResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+func ListDependencies(writer io.Writer, persistentFlags utils.PersistentCommandFlags, dependencyFlags utils.DependencyCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processDependencyListResults(err)
+		}
+	}()
+
+	// Note: returns error if either file load or unmarshal to JSON map fails
+	var document *schema.BOM
+	if document, err = LoadInputBOMFileAndDetectSchema(); err != nil {
+		return
+	}
+
+	var graph *schema.DependencyGraph
+	if graph, err = loadDocumentDependencyGraph(document); err != nil {
+		return
+	}
+
+	format := persistentFlags.OutputFormat
+	getLogger().Infof("Outputting listing (`%s` format)...", format)
+
+	// Report on the dependencies of a single bom-ref
+	if ref := dependencyFlags.Ref; ref != "" {
+		if !graph.IsDeclared(ref) && graph.Edges[ref] == nil {
+			return getLogger().Errorf("bom-ref: `%s` not found in BOM: `%s`", ref, document.GetFilename())
+		}
+		entries := graph.Dependencies(ref)
+		switch format {
+		case FORMAT_JSON:
+			err = writeDependencyJSON(writer, entries)
+		case FORMAT_CSV:
+			err = DisplayDependencyRefCSV(writer, entries)
+		case FORMAT_MARKDOWN:
+			DisplayDependencyRefMarkdown(writer, entries)
+		case FORMAT_DOT:
+			DisplayDependencyGraphDOT(writer, graph, dependencyGraphRefs(ref, entries))
+		case FORMAT_MERMAID:
+			DisplayDependencyGraphMermaid(writer, graph, dependencyGraphRefs(ref, entries))
+		case FORMAT_TEXT:
+			DisplayDependencyRefText(writer, entries)
+		default:
+			// Default to Text output for anything else (set as flag default)
+			getLogger().Warningf("Listing not supported for `%s` format; defaulting to `%s` format...",
+				format, FORMAT_TEXT)
+			DisplayDependencyRefText(writer, entries)
+		}
+		return
+	}
+
+	analysis := graph.Analyze()
+	switch format {
+	case FORMAT_JSON:
+		err = writeDependencyJSON(writer, analysis)
+	case FORMAT_CSV:
+		err = DisplayDependencyListCSV(writer, analysis)
+	case FORMAT_MARKDOWN:
+		DisplayDependencyListMarkdown(writer, analysis)
+	case FORMAT_DOT:
+		DisplayDependencyGraphDOT(writer, graph, graph.SortedAllRefs())
+	case FORMAT_MERMAID:
+		DisplayDependencyGraphMermaid(writer, graph, graph.SortedAllRefs())
+	case FORMAT_TEXT:
+		DisplayDependencyListText(writer, analysis)
+	default:
+		// Default to Text output for anything else (set as flag default)
+		getLogger().Warningf("Listing not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplayDependencyListText(writer, analysis)
+	}
+	return
+}
+
+func loadDocumentDependencyGraph(document *schema.BOM) (graph *schema.DependencyGraph, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	// The dependency graph is only declared by CycloneDX BOMs
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_DEPENDENCY, FORMAT_ANY)
+		return
+	}
+
+	if err = document.UnmarshalCycloneDXBOM(); err != nil {
+		return
+	}
+
+	// Hash all components and services (by bom-ref) the graph's bom-refs are resolved against
+	if err = document.HashComponentResources(nil); err != nil {
+		return
+	}
+	if err = document.HashServiceResources(nil); err != nil {
+		return
+	}
+
+	graph = document.NewDependencyGraph()
+	return
+}
+
+// Returns the bom-ref and all of its (direct and transitive) dependencies
+func dependencyGraphRefs(ref string, entries []schema.DependencyEntry) (refs []string) {
+	refs = append(refs, ref)
+	for _, entry := range entries {
+		refs = append(refs, entry.BOMRef)
+	}
+	return
+}
+
+func writeDependencyJSON(writer io.Writer, data interface{}) (err error) {
+	var output bytes.Buffer
+	indentString := utils.GenerateIndentString(int(utils.GlobalFlags.PersistentFlags.OutputIndent))
+	if output, err = utils.EncodeAnyToIndentedJSONStr(data, indentString); err == nil {
+		_, err = writer.Write(output.Bytes())
+	}
+	return
+}
+
+// -------------------
+// Report line data
+// -------------------
+
+func formatDependencyDepth(depth int) string {
+	if depth == schema.DEPENDENCY_DEPTH_UNREACHABLE {
+		return REPORT_LIST_VALUE_NONE
+	}
+	return strconv.Itoa(depth)
+}
+
+func dependencyListLineData(node schema.DependencyNodeInfo) []string {
+	return []string{
+		node.BOMRef,
+		node.Type,
+		node.Name,
+		node.Version,
+		formatDependencyDepth(node.Depth),
+		strconv.Itoa(len(node.Direct)),
+		strconv.Itoa(len(node.Transitive)),
+		strconv.FormatBool(node.Orphan),
+		strconv.FormatBool(node.Cycle),
+		strings.Join(node.Dangling, ", "),
+	}
+}
+
+func dependencyRefLineData(entry schema.DependencyEntry) []string {
+	return []string{
+		entry.BOMRef,
+		entry.Type,
+		entry.Name,
+		entry.Version,
+		entry.Relationship,
+		strconv.Itoa(entry.Distance),
+	}
+}
+
+func dependencyStatisticsLineData(analysis *schema.DependencyAnalysis) (lines [][]string) {
+	root := analysis.Root
+	if root == "" {
+		root = REPORT_LIST_VALUE_NONE
+	}
+	stats := analysis.Statistics
+	lines = [][]string{
+		{"root", root},
+		{"nodes", strconv.Itoa(stats.Nodes)},
+		{"edges", strconv.Itoa(stats.Edges)},
+		{"leaves", strconv.Itoa(stats.Leaves)},
+		{"max-depth", strconv.Itoa(stats.MaxDepth)},
+		{"average-depth", strconv.FormatFloat(stats.AverageDepth, 'f', 2, 64)},
+		{"orphans", strconv.Itoa(stats.Orphans)},
+		{"dangling-refs", strconv.Itoa(stats.DanglingRefs)},
+		{"cycles", strconv.Itoa(stats.Cycles)},
+	}
+	for _, dangling := range analysis.DanglingRefs {
+		lines = append(lines, []string{"dangling-ref",
+			fmt.Sprintf("%s (referenced-by: %s)", dangling.MissingRef, dangling.ReferencedBy)})
+	}
+	for _, cycle := range analysis.Cycles {
+		lines = append(lines, []string{"cycle", schema.FormatDependencyCycle(cycle)})
+	}
+	return
+}
+
+// -------------------
+// Text
+// -------------------
+
+func DisplayDependencyListText(writer io.Writer, analysis *schema.DependencyAnalysis) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\n", strings.Join(DEPENDENCY_LIST_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(DEPENDENCY_LIST_TITLES), "\t"))
+	if len(analysis.Nodes) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_DEPENDENCIES_FOUND)
+	}
+	for _, node := range analysis.Nodes {
+		fmt.Fprintf(w, "%s\n", strings.Join(dependencyListLineData(node), "\t"))
+	}
+	w.Flush()
+
+	// Statistics are output as a separate table
+	fmt.Fprintln(writer)
+	w.Init(writer, 8, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\n", strings.Join(DEPENDENCY_STATISTICS_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(DEPENDENCY_STATISTICS_TITLES), "\t"))
+	for _, line := range dependencyStatisticsLineData(analysis) {
+		fmt.Fprintf(w, "%s\n", strings.Join(line, "\t"))
+	}
+	w.Flush()
+}
+
+func DisplayDependencyRefText(writer io.Writer, entries []schema.DependencyEntry) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\n", strings.Join(DEPENDENCY_REF_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(DEPENDENCY_REF_TITLES), "\t"))
+	if len(entries) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_DEPENDENCIES_FOUND)
+		return
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\n", strings.Join(dependencyRefLineData(entry), "\t"))
+	}
+}
+
+// -------------------
+// CSV
+// -------------------
+
+// NOTE: statistics are not included in CSV output (i.e., only a single table of data is output)
+func DisplayDependencyListCSV(writer io.Writer, analysis *schema.DependencyAnalysis) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var lines [][]string
+	for _, node := range analysis.Nodes {
+		lines = append(lines, dependencyListLineData(node))
+	}
+	return writeDependencyCSV(writer, DEPENDENCY_LIST_TITLES, lines)
+}
+
+func DisplayDependencyRefCSV(writer io.Writer, entries []schema.DependencyEntry) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var lines [][]string
+	for _, entry := range entries {
+		lines = append(lines, dependencyRefLineData(entry))
+	}
+	return writeDependencyCSV(writer, DEPENDENCY_REF_TITLES, lines)
+}
+
+func writeDependencyCSV(writer io.Writer, titles []string, lines [][]string) (err error) {
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	if err = w.Write(titles); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", titles, err)
+	}
+
+	if len(lines) == 0 {
+		if err = w.Write([]string{MSG_OUTPUT_NO_DEPENDENCIES_FOUND}); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", MSG_OUTPUT_NO_DEPENDENCIES_FOUND, err)
+		}
+		return
+	}
+
+	for _, line := range lines {
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
+		}
+	}
+	return
+}
+
+// -------------------
+// Markdown
+// -------------------
+
+func DisplayDependencyListMarkdown(writer io.Writer, analysis *schema.DependencyAnalysis) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(DEPENDENCY_LIST_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(DEPENDENCY_LIST_TITLES)))
+	if len(analysis.Nodes) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_DEPENDENCIES_FOUND)
+	}
+	for _, node := range analysis.Nodes {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(dependencyListLineData(node)))
+	}
+
+	// Statistics are output as a separate table
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(DEPENDENCY_STATISTICS_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(DEPENDENCY_STATISTICS_TITLES)))
+	for _, line := range dependencyStatisticsLineData(analysis) {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(line))
+	}
+}
+
+func DisplayDependencyRefMarkdown(writer io.Writer, entries []schema.DependencyEntry) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(DEPENDENCY_REF_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(DEPENDENCY_REF_TITLES)))
+	if len(entries) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_DEPENDENCIES_FOUND)
+		return
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(dependencyRefLineData(entry)))
+	}
+}
+
+// -------------------
+// Graphs
+// -------------------
+
+// Returns the set of edges (i.e., "ref->dependsOn") found in any cycle
+func dependencyCycleEdges(graph *schema.DependencyGraph) (edges map[string]bool) {
+	edges = make(map[string]bool)
+	for _, cycle := range graph.FindCycles() {
+		for i, ref := range cycle {
+			edges[ref+"->"+cycle[(i+1)%len(cycle)]] = true
+		}
+	}
+	return
+}
+
+// Returns the bom-refs not reachable from the graph's root (if any)
+func dependencyOrphanRefs(graph *schema.DependencyGraph) (orphans map[string]bool) {
+	orphans = make(map[string]bool)
+	if graph.Root == "" {
+		return
+	}
+	distances := graph.Distances(graph.Root)
+	for _, ref := range graph.SortedRefs() {
+		if _, reachable := distances[ref]; !reachable {
+			orphans[ref] = true
+		}
+	}
+	return
+}
+
+func dependencyNodeLabel(graph *schema.DependencyGraph, ref string, separator string) string {
+	resourceInfo, declared := graph.Resources[ref]
+	if !declared {
+		return ref + separator + "(" + schema.DEPENDENCY_RESOURCE_TYPE_MISSING + ")"
+	}
+	if resourceInfo.Name == "" {
+		return ref
+	}
+	if resourceInfo.Version == "" {
+		return resourceInfo.Name
+	}
+	return resourceInfo.Name + separator + resourceInfo.Version
+}
+
+// Quotes a DOT (Graphviz) identifier or label
+// Note: DOT interprets the (escaped) sequence "\n" within a quoted string as a line break
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return "\"" + strings.ReplaceAll(value, "\n", "\\n") + "\""
+}
+
+// Outputs the graph (restricted to the provided bom-refs) using the DOT (Graphviz) language
+func DisplayDependencyGraphDOT(writer io.Writer, graph *schema.DependencyGraph, refs []string) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	included := make(map[string]bool)
+	for _, ref := range refs {
+		included[ref] = true
+	}
+	cycleEdges := dependencyCycleEdges(graph)
+	orphans := dependencyOrphanRefs(graph)
+
+	fmt.Fprintf(writer, "digraph %s {\n", dotQuote(DEPENDENCY_GRAPH_NAME))
+	fmt.Fprintf(writer, "  rankdir=\"LR\";\n")
+	for _, ref := range refs {
+		var attributes []string
+		attributes = append(attributes, "label="+dotQuote(dependencyNodeLabel(graph, ref, "\n")))
+		switch {
+		case ref == graph.Root:
+			attributes = append(attributes, "shape=box", "style=bold")
+		case !graph.IsDeclared(ref):
+			attributes = append(attributes, "style=dashed")
+		case orphans[ref]:
+			attributes = append(attributes, "color="+DEPENDENCY_GRAPH_COLOR_ORPHAN)
+		}
+		fmt.Fprintf(writer, "  %s [%s];\n", dotQuote(ref), strings.Join(attributes, ", "))
+	}
+	for _, ref := range refs {
+		for _, target := range graph.Edges[ref] {
+			if !included[target] {
+				continue
+			}
+			var attributes []string
+			if !graph.IsDeclared(target) {
+				attributes = append(attributes, "style=dashed")
+			}
+			if cycleEdges[ref+"->"+target] {
+				attributes = append(attributes, "color="+DEPENDENCY_GRAPH_COLOR_CYCLE)
+			}
+			if len(attributes) > 0 {
+				fmt.Fprintf(writer, "  %s -> %s [%s];\n", dotQuote(ref), dotQuote(target), strings.Join(attributes, ", "))
+			} else {
+				fmt.Fprintf(writer, "  %s -> %s;\n", dotQuote(ref), dotQuote(target))
+			}
+		}
+	}
+	fmt.Fprintf(writer, "}\n")
+}
+
+// Outputs the graph (restricted to the provided bom-refs) as a Mermaid flowchart
+// Note: bom-refs are not valid Mermaid node IDs; nodes are assigned IDs by position
+func DisplayDependencyGraphMermaid(writer io.Writer, graph *schema.DependencyGraph, refs []string) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	ids := make(map[string]string)
+	for i, ref := range refs {
+		ids[ref] = "n" + strconv.Itoa(i)
+	}
+	cycleEdges := dependencyCycleEdges(graph)
+	orphans := dependencyOrphanRefs(graph)
+
+	fmt.Fprintf(writer, "graph LR\n")
+	var rootIds, missingIds, orphanIds []string
+	for _, ref := range refs {
+		label := strings.ReplaceAll(dependencyNodeLabel(graph, ref, "<br/>"), "\"", "#quot;")
+		fmt.Fprintf(writer, "  %s[\"%s\"]\n", ids[ref], label)
+		switch {
+		case ref == graph.Root:
+			rootIds = append(rootIds, ids[ref])
+		case !graph.IsDeclared(ref):
+			missingIds = append(missingIds, ids[ref])
+		case orphans[ref]:
+			orphanIds = append(orphanIds, ids[ref])
+		}
+	}
+
+	var edgeIndex int
+	var cycleLinks []string
+	for _, ref := range refs {
+		for _, target := range graph.Edges[ref] {
+			targetId, included := ids[target]
+			if !included {
+				continue
+			}
+			arrow := "-->"
+			if !graph.IsDeclared(target) {
+				arrow = "-.->"
+			}
+			fmt.Fprintf(writer, "  %s %s %s\n", ids[ref], arrow, targetId)
+			if cycleEdges[ref+"->"+target] {
+				cycleLinks = append(cycleLinks, strconv.Itoa(edgeIndex))
+			}
+			edgeIndex++
+		}
+	}
+
+	fmt.Fprintf(writer, "  classDef root stroke-width:3px\n")
+	fmt.Fprintf(writer, "  classDef missing stroke-dasharray:5 5\n")
+	fmt.Fprintf(writer, "  classDef orphan stroke:%s,color:%s\n", DEPENDENCY_GRAPH_COLOR_ORPHAN, DEPENDENCY_GRAPH_COLOR_ORPHAN)
+	for _, class := range []struct {
+		name string
+		ids  []string
+	}{{"root", rootIds}, {"missing", missingIds}, {"orphan", orphanIds}} {
+		if len(class.ids) > 0 {
+			fmt.Fprintf(writer, "  class %s %s\n", strings.Join(class.ids, ","), class.name)
+		}
+	}
+	if len(cycleLinks) > 0 {
+		fmt.Fprintf(writer, "  linkStyle %s stroke:%s\n", strings.Join(cycleLinks, ","), DEPENDENCY_GRAPH_COLOR_CYCLE)
+	}
+}
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	// Test "dependency list" command
+	TEST_DEPENDENCY_CDX_1_5_GRAPH = "test/dependency/cdx-1-5-dependency-graph.json"
+)
+
+const (
+	TEST_DEPENDENCY_REF_ROOT        = "pkg:npm/sample-app@1.0.0"
+	TEST_DEPENDENCY_REF_BODY_PARSER = "pkg:npm/body-parser@1.20.1"
+	TEST_DEPENDENCY_REF_DANGLING    = "pkg:npm/debug@2.6.9"
+	TEST_DEPENDENCY_REF_ORPHAN      = "pkg:npm/lodash@4.17.21"
+	TEST_DEPENDENCY_REF_UNKNOWN     = "pkg:npm/unknown@0.0.0"
+)
+
+type DependencyTestInfo struct {
+	CommonTestInfo
+	Ref string
+}
+
+// Stringer interface for DependencyTestInfo (just display subset of key values)
+func (ti *DependencyTestInfo) String() string {
+	buffer, _ := utils.EncodeAnyToDefaultIndentedJSONStr(ti)
+	return buffer.String()
+}
+
+func NewDependencyTestInfoBasic(inputFile string, listFormat string, resultExpectedError error) *DependencyTestInfo {
+	var ti = new(DependencyTestInfo)
+	var pCommon = &ti.CommonTestInfo
+	pCommon.InitBasic(inputFile, listFormat, resultExpectedError)
+	return ti
+}
+
+// -------------------------------------------
+// Dependency list test helper functions
+// -------------------------------------------
+func innerBufferedTestDependencyList(t *testing.T, testInfo *DependencyTestInfo) (outputBuffer bytes.Buffer, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	utils.GlobalFlags.PersistentFlags.OutputFormat = testInfo.OutputFormat
+	flags := utils.DependencyCommandFlags{Ref: testInfo.Ref}
+	err = ListDependencies(outputWriter, utils.GlobalFlags.PersistentFlags, flags)
+	return
+}
+
+func innerTestDependencyList(t *testing.T, testInfo *DependencyTestInfo) (outputBuffer bytes.Buffer, err error) {
+	getLogger().Tracef("TestInfo: %s", testInfo)
+
+	// The command looks for the input filename in global flags struct
+	utils.GlobalFlags.PersistentFlags.InputFile = testInfo.InputFile
+
+	// invoke list command with a byte buffer
+	outputBuffer, err = innerBufferedTestDependencyList(t, testInfo)
+
+	// Run all common tests against "result" values in the CommonTestInfo struct
+	err = innerRunReportResultTests(t, &testInfo.CommonTestInfo, outputBuffer, err)
+
+	return
+}
+
+// ----------------------------------------
+// Command flag tests
+// ----------------------------------------
+
+func TestDependencyListInvalidInputFileLoad(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_INPUT_FILE_NON_EXISTENT,
+		FORMAT_DEFAULT,
+		&fs.PathError{})
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListSpdxUnsupported(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		FORMAT_TEXT,
+		&schema.UnsupportedFormatError{})
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListRefNotFound(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_TEXT,
+		nil)
+	testInfo.Ref = TEST_DEPENDENCY_REF_UNKNOWN
+	_, err := innerBufferedTestDependencyList(t, testInfo)
+	if err == nil {
+		t.Errorf("expected error for unknown bom-ref: `%s`", TEST_DEPENDENCY_REF_UNKNOWN)
+	}
+}
+
+// -------------------------------------------
+// Graph analysis
+// -------------------------------------------
+
+func TestDependencyListCdx15Text(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_TEXT,
+		nil)
+	// node table: title, separator, 7 rows; blank line; statistics table: title, separator, 11 rows
+	testInfo.ResultExpectedLineCount = 23
+	testInfo.ResultLineContainsValues = []string{"pkg:npm/express@4.18.2", "1", "2", "false", TEST_DEPENDENCY_REF_DANGLING}
+	testInfo.ResultLineContainsValuesAtLineNum = 3
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15TextOrphan(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_TEXT,
+		nil)
+	testInfo.ResultLineContainsValues = []string{TEST_DEPENDENCY_REF_ORPHAN, REPORT_LIST_VALUE_NONE, "true"}
+	testInfo.ResultLineContainsValuesAtLineNum = 4
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15TextCycle(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_TEXT,
+		nil)
+	testInfo.ResultLineContainsValues = []string{"cycle",
+		schema.FormatDependencyCycle([]string{"pkg:npm/qs@6.11.0", "pkg:npm/side-channel@1.0.4"})}
+	testInfo.ResultLineContainsValuesAtLineNum = 22
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15CSV(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_CSV,
+		nil)
+	testInfo.ResultExpectedLineCount = 8 // title and 7 rows (no statistics)
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15Markdown(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_MARKDOWN,
+		nil)
+	testInfo.ResultExpectedLineCount = 23
+	testInfo.ResultLineContainsValues = []string{"|max-depth|4|"}
+	testInfo.ResultLineContainsValuesAtLineNum = 16
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15JSON(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_JSON,
+		nil)
+	outputBuffer, err := innerTestDependencyList(t, testInfo)
+	if err != nil {
+		return
+	}
+
+	var analysis schema.DependencyAnalysis
+	if err = json.Unmarshal(outputBuffer.Bytes(), &analysis); err != nil {
+		t.Error(err)
+		return
+	}
+	if analysis.Root != TEST_DEPENDENCY_REF_ROOT {
+		t.Errorf("root: expected: `%s`, actual: `%s`", TEST_DEPENDENCY_REF_ROOT, analysis.Root)
+	}
+	expected := schema.DependencyStatistics{
+		Nodes: 7, Edges: 7, Leaves: 2, MaxDepth: 4, AverageDepth: 2.2,
+		Orphans: 1, DanglingRefs: 1, Cycles: 1,
+	}
+	if analysis.Statistics != expected {
+		t.Errorf("statistics: expected: %+v, actual: %+v", expected, analysis.Statistics)
+	}
+	if len(analysis.DanglingRefs) != 1 || analysis.DanglingRefs[0].MissingRef != TEST_DEPENDENCY_REF_DANGLING {
+		t.Errorf("dangling refs: expected: `%s`, actual: %v", TEST_DEPENDENCY_REF_DANGLING, analysis.DanglingRefs)
+	}
+	if len(analysis.Orphans) != 1 || analysis.Orphans[0] != TEST_DEPENDENCY_REF_ORPHAN {
+		t.Errorf("orphans: expected: `%s`, actual: %v", TEST_DEPENDENCY_REF_ORPHAN, analysis.Orphans)
+	}
+}
+
+// -------------------------------------------
+// Dependencies of a single bom-ref (--ref)
+// -------------------------------------------
+
+func TestDependencyListCdx15RefText(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_TEXT,
+		nil)
+	testInfo.Ref = TEST_DEPENDENCY_REF_BODY_PARSER
+	testInfo.ResultExpectedLineCount = 4 // title, separator and 2 rows
+	testInfo.ResultLineContainsValues = []string{"pkg:npm/side-channel@1.0.4", schema.DEPENDENCY_RELATIONSHIP_TRANSITIVE, "2"}
+	testInfo.ResultLineContainsValuesAtLineNum = 3
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15RefRootCSV(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_CSV,
+		nil)
+	testInfo.Ref = TEST_DEPENDENCY_REF_ROOT
+	testInfo.ResultExpectedLineCount = 7 // title and 6 rows (includes dangling ref.)
+	testInfo.ResultLineContainsValues = []string{TEST_DEPENDENCY_REF_DANGLING, schema.DEPENDENCY_RESOURCE_TYPE_MISSING}
+	testInfo.ResultLineContainsValuesAtLineNum = RESULT_LINE_CONTAINS_ANY
+	innerTestDependencyList(t, testInfo)
+}
+
+// -------------------------------------------
+// Graph renderings
+// -------------------------------------------
+
+func TestDependencyListCdx15DOT(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_DOT,
+		nil)
+	// header (2), 8 nodes (incl. dangling ref.), 7 edges and closing brace
+	testInfo.ResultExpectedLineCount = 18
+	testInfo.ResultLineContainsValues = []string{"\"pkg:npm/qs@6.11.0\" -> \"pkg:npm/side-channel@1.0.4\"", "color=red"}
+	testInfo.ResultLineContainsValuesAtLineNum = RESULT_LINE_CONTAINS_ANY
+	innerTestDependencyList(t, testInfo)
+}
+
+func TestDependencyListCdx15RefMermaid(t *testing.T) {
+	testInfo := NewDependencyTestInfoBasic(
+		TEST_DEPENDENCY_CDX_1_5_GRAPH,
+		FORMAT_MERMAID,
+		nil)
+	testInfo.Ref = TEST_DEPENDENCY_REF_BODY_PARSER
+	// header, 3 nodes, 3 edges, 3 class definitions and cycle link style
+	testInfo.ResultExpectedLineCount = 11
+	testInfo.ResultLineContainsValues = []string{"linkStyle 1,2"}
+	testInfo.ResultLineContainsValuesAtLineNum = 10
+	innerTestDependencyList(t, testInfo)
+}
@@ -19,11 +19,15 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 	diff "github.com/mrutkows/go-jsondiff"
 	"github.com/mrutkows/go-jsondiff/formatter"
@@ -36,16 +40,48 @@ const (
 )
 
 var DIFF_OUTPUT_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
-	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON}, ", ")
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON}, ", ") +
+	" (semantic: " + strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN}, ", ") + ")"
 
 // validation flags
 const (
 	FLAG_DIFF_FILENAME_REVISION       = "input-revision"
 	FLAG_DIFF_FILENAME_REVISION_SHORT = "r"
+	FLAG_DIFF_SEMANTIC                = "semantic"
 	MSG_FLAG_INPUT_REVISION           = "input filename for the revised file to compare against the base file"
 	MSG_FLAG_DIFF_COLORIZE            = "Colorize diff text output (true|false); default false"
+	MSG_FLAG_DIFF_SEMANTIC            = "compare BOM entities (i.e., components, services, vulnerabilities and dependencies) by identity (i.e., bom-ref, purl, name+group+version) instead of by document position"
 )
 
+const (
+	MSG_OUTPUT_NO_DIFF_CHANGES_FOUND = "[INFO] no entity changes found between BOM documents"
+)
+
+// Semantic diff report column titles
+const (
+	DIFF_REPORT_KEY_RESOURCE_TYPE = "resource-type"
+	DIFF_REPORT_KEY_CHANGE        = "change"
+	DIFF_REPORT_KEY_MATCHED_BY    = "matched-by"
+	DIFF_REPORT_KEY_BOMREF        = "bom-ref"
+	DIFF_REPORT_KEY_NAME          = "name"
+	DIFF_REPORT_KEY_VERSION       = "version"
+	DIFF_REPORT_KEY_FIELD         = "field"
+	DIFF_REPORT_KEY_BASE          = "base"
+	DIFF_REPORT_KEY_REVISED       = "revised"
+)
+
+var DIFF_SEMANTIC_REPORT_TITLES = []string{
+	DIFF_REPORT_KEY_RESOURCE_TYPE,
+	DIFF_REPORT_KEY_CHANGE,
+	DIFF_REPORT_KEY_MATCHED_BY,
+	DIFF_REPORT_KEY_BOMREF,
+	DIFF_REPORT_KEY_NAME,
+	DIFF_REPORT_KEY_VERSION,
+	DIFF_REPORT_KEY_FIELD,
+	DIFF_REPORT_KEY_BASE,
+	DIFF_REPORT_KEY_REVISED,
+}
+
 func NewCommandDiff() *cobra.Command {
 	var command = new(cobra.Command)
 	command.Use = CMD_USAGE_DIFF
@@ -59,6 +95,7 @@ func NewCommandDiff() *cobra.Command {
 		"", // no default value (empty)
 		MSG_FLAG_INPUT_REVISION)
 	command.Flags().BoolVarP(&utils.GlobalFlags.DiffFlags.Colorize, FLAG_COLORIZE_OUTPUT, "", false, MSG_FLAG_DIFF_COLORIZE)
+	command.Flags().BoolVarP(&utils.GlobalFlags.DiffFlags.Semantic, FLAG_DIFF_SEMANTIC, "", false, MSG_FLAG_DIFF_SEMANTIC)
 	command.RunE = diffCmdImpl
 	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
 		// Test for required flags (parameters)
@@ -143,6 +180,12 @@ func Diff(persistentFlags utils.PersistentCommandFlags, flags utils.DiffCommandF
 		}
 	}()
 
+	// Identity-aware comparison of BOM entities (vs. RFC 6902 document comparison)
+	if flags.Semantic {
+		err = DiffSemantic(output, persistentFlags, flags)
+		return
+	}
+
 	getLogger().Infof("Reading file (--input-file): `%s` ...", inputFilename)
 	// #nosec G304 (suppress warning)
 	bBaseData, errReadBase := os.ReadFile(inputFilename)
@@ -207,3 +250,184 @@ func Diff(persistentFlags utils.PersistentCommandFlags, flags utils.DiffCommandF
 
 	return
 }
+
+// Compare the BOM entities (i.e., components, services, vulnerabilities and dependencies)
+// of the base and revised files matching them by identity (i.e., bom-ref, then purl,
+// then name+group+version) and report those added, removed or changed.
+func DiffSemantic(writer io.Writer, persistentFlags utils.PersistentCommandFlags, flags utils.DiffCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var baseBom, revisedBom *schema.BOM
+	if baseBom, err = loadDocumentSemanticDiff(persistentFlags.InputFile); err != nil {
+		return
+	}
+	if revisedBom, err = loadDocumentSemanticDiff(flags.RevisedFile); err != nil {
+		return
+	}
+
+	getLogger().Infof("Comparing BOM entities: `%s` (base) to `%s` (revised) ...",
+		baseBom.GetFilenameInterpolated(), revisedBom.GetFilenameInterpolated())
+	var result *schema.BOMDiffResult
+	if result, err = baseBom.DiffSemantic(revisedBom); err != nil {
+		return
+	}
+
+	format := persistentFlags.OutputFormat
+	getLogger().Infof("Outputting listing (`%s` format)...", format)
+	switch format {
+	case FORMAT_TEXT:
+		DisplayDiffSemanticText(result, writer)
+	case FORMAT_JSON:
+		err = DisplayDiffSemanticJson(result, writer)
+	case FORMAT_CSV:
+		err = DisplayDiffSemanticCSV(result, writer)
+	case FORMAT_MARKDOWN:
+		DisplayDiffSemanticMarkdown(result, writer)
+	default:
+		// Default to Text output for anything else (set as flag default)
+		getLogger().Warningf("Diff output format not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplayDiffSemanticText(result, writer)
+	}
+	return
+}
+
+func loadDocumentSemanticDiff(filename string) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if document, err = LoadBOMFileAndDetectSchema(filename); err != nil {
+		return
+	}
+
+	// At this time, fail SPDX format SBOMs as "unsupported"
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_DIFF, FORMAT_ANY)
+		return
+	}
+
+	err = document.UnmarshalCycloneDXBOM()
+	return
+}
+
+// Flatten each entity result into one or more report rows (i.e., one per changed field)
+func createDiffSemanticRows(result *schema.BOMDiffResult) (rows [][]string) {
+	for _, entity := range result.Entities() {
+		row := []string{
+			entity.ResourceType,
+			entity.Change,
+			entity.MatchedBy,
+			entity.BOMRef,
+			entity.Name,
+			entity.Version,
+		}
+		if len(entity.Fields) == 0 {
+			rows = append(rows, append(row, "", "", ""))
+			continue
+		}
+		for _, field := range entity.Fields {
+			fieldRow := append([]string{}, row...)
+			rows = append(rows, append(fieldRow, field.Field, field.Base, field.Revised))
+		}
+	}
+	return
+}
+
+func DisplayDiffSemanticText(result *schema.BOMDiffResult, writer io.Writer) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	// create underline row from compulsory titles
+	underlines := createTitleTextSeparators(DIFF_SEMANTIC_REPORT_TITLES)
+
+	// Add tabs between column titles for the tabWRiter
+	fmt.Fprintf(w, "%s\n", strings.Join(DIFF_SEMANTIC_REPORT_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+
+	// Emit no changes found message into output
+	if !result.HasChanges() {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_DIFF_CHANGES_FOUND)
+		return
+	}
+
+	for _, row := range createDiffSemanticRows(result) {
+		fmt.Fprintf(w, "%s\n", strings.Join(row, "\t"))
+	}
+}
+
+func DisplayDiffSemanticJson(result *schema.BOMDiffResult, writer io.Writer) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var bytes []byte
+	if bytes, err = json.MarshalIndent(result, "", "    "); err != nil {
+		return getLogger().Errorf("unable to marshal diff result: %s", err)
+	}
+	// Note: JSON data files MUST ends in a newline as this is a POSIX standard
+	fmt.Fprintf(writer, "%s\n", bytes)
+	return
+}
+
+func DisplayDiffSemanticCSV(result *schema.BOMDiffResult, writer io.Writer) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	if err = w.Write(DIFF_SEMANTIC_REPORT_TITLES); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", DIFF_SEMANTIC_REPORT_TITLES, err)
+	}
+
+	// Emit no changes found message into output
+	if !result.HasChanges() {
+		currentRow := []string{MSG_OUTPUT_NO_DIFF_CHANGES_FOUND}
+		if err = w.Write(currentRow); err != nil {
+			// unable to emit an error message into output stream
+			return getLogger().Errorf("error writing to output (%v): %s", currentRow, err)
+		}
+		return
+	}
+
+	for _, row := range createDiffSemanticRows(result) {
+		if err = w.Write(row); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
+		}
+	}
+	return
+}
+
+func DisplayDiffSemanticMarkdown(result *schema.BOMDiffResult, writer io.Writer) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// create title row
+	titleRow := createMarkdownRow(DIFF_SEMANTIC_REPORT_TITLES)
+	fmt.Fprintf(writer, "%s\n", titleRow)
+
+	alignments := createMarkdownColumnAlignment(DIFF_SEMANTIC_REPORT_TITLES)
+	alignmentRow := createMarkdownRow(alignments)
+	fmt.Fprintf(writer, "%s\n", alignmentRow)
+
+	// Emit no changes found message into output
+	if !result.HasChanges() {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_DIFF_CHANGES_FOUND)
+		return
+	}
+
+	for _, row := range createDiffSemanticRows(result) {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(row))
+	}
+}
@@ -19,9 +19,14 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
 
@@ -41,6 +46,12 @@ const (
 
 	TEST_ARRAY_ORDER_2_CHANGES_BASE  = "test/diff/json-array-order-2-changes-base.json"
 	TEST_ARRAY_ORDER_2_CHANGES_DELTA = "test/diff/json-array-order-2-changes-delta.json"
+
+	TEST_DIFF_SEMANTIC_CDX_1_5_BASE    = "test/diff/cdx-1-5-semantic-base.json"
+	TEST_DIFF_SEMANTIC_CDX_1_5_REVISED = "test/diff/cdx-1-5-semantic-revised.json"
+
+	TEST_DIFF_SEMANTIC_CDX_1_5_VERSION_BUMP_BASE    = "test/diff/cdx-1-5-semantic-version-bump-base.json"
+	TEST_DIFF_SEMANTIC_CDX_1_5_VERSION_BUMP_REVISED = "test/diff/cdx-1-5-semantic-version-bump-revised.json"
 )
 
 // Tests basic validation and expected errors
@@ -174,6 +185,201 @@ func TestDiffJsonArrayOrderMove1ObjectWithAddAndDeleteFormatText(t *testing.T) {
 	}
 }
 
+// -------------------------------------------
+// Semantic (identity-aware) diff
+// -------------------------------------------
+
+func innerBufferedTestDiffSemantic(t *testing.T, baseFilename string, revisedFilename string, format string) (outputBuffer bytes.Buffer, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	var persistentFlags utils.PersistentCommandFlags
+	persistentFlags.InputFile = baseFilename
+	persistentFlags.OutputFormat = format
+	diffFlags := utils.DiffCommandFlags{
+		RevisedFile: revisedFilename,
+		Semantic:    true,
+	}
+
+	err = DiffSemantic(outputWriter, persistentFlags, diffFlags)
+	return
+}
+
+func findDiffEntityResult(results []schema.DiffEntityResult, change string, bomRef string, name string) *schema.DiffEntityResult {
+	for i, result := range results {
+		if result.Change == change && result.BOMRef == bomRef && result.Name == name {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffSemanticCdx15Json(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_REVISED,
+		FORMAT_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result schema.BOMDiffResult
+	if err = json.Unmarshal(outputBuffer.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reordered (and unchanged) components MUST NOT appear; 1 changed, 1 removed, 1 added
+	if len(result.Components) != 3 {
+		t.Errorf("expected 3 component changes; actual: %v", result.Components)
+	}
+
+	changed := findDiffEntityResult(result.Components, schema.DIFF_CHANGE_CHANGED, "lib-a", "lib-a")
+	if changed == nil {
+		t.Fatalf("expected changed component `lib-a`; actual: %v", result.Components)
+	}
+	if changed.MatchedBy != schema.DIFF_MATCH_BOMREF {
+		t.Errorf("expected match by `%s`; actual: `%s`", schema.DIFF_MATCH_BOMREF, changed.MatchedBy)
+	}
+	var fields []string
+	for _, field := range changed.Fields {
+		fields = append(fields, field.Field)
+	}
+	expectedFields := []string{
+		schema.DIFF_FIELD_VERSION,
+		schema.DIFF_FIELD_PURL,
+		schema.DIFF_FIELD_LICENSES,
+		schema.DIFF_FIELD_HASHES}
+	if strings.Join(fields, ",") != strings.Join(expectedFields, ",") {
+		t.Errorf("expected field changes: %v; actual: %v", expectedFields, fields)
+	}
+
+	if findDiffEntityResult(result.Components, schema.DIFF_CHANGE_REMOVED, "lib-c", "lib-c") == nil {
+		t.Errorf("expected removed component `lib-c`; actual: %v", result.Components)
+	}
+	if findDiffEntityResult(result.Components, schema.DIFF_CHANGE_ADDED, "lib-e", "lib-e") == nil {
+		t.Errorf("expected added component `lib-e`; actual: %v", result.Components)
+	}
+
+	if len(result.Services) != 0 {
+		t.Errorf("expected no service changes; actual: %v", result.Services)
+	}
+
+	if len(result.Vulnerabilities) != 1 ||
+		result.Vulnerabilities[0].Fields[0].Field != schema.DIFF_FIELD_ANALYSIS_STATE {
+		t.Errorf("expected 1 vulnerability analysis state change; actual: %v", result.Vulnerabilities)
+	}
+
+	// 1 dependency changed (dependsOn), 1 dependency removed
+	if len(result.Dependencies) != 2 {
+		t.Errorf("expected 2 dependency changes; actual: %v", result.Dependencies)
+	}
+}
+
+// Components whose version (and version-based bom-ref and purl) changed are
+// matched by package (i.e., purl without version) or by group and name
+func TestDiffSemanticCdx15VersionBump(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_VERSION_BUMP_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_VERSION_BUMP_REVISED,
+		FORMAT_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result schema.BOMDiffResult
+	if err = json.Unmarshal(outputBuffer.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Components) != 2 {
+		t.Errorf("expected 2 component changes; actual: %v", result.Components)
+	}
+
+	expected := []struct {
+		bomRef    string
+		name      string
+		matchedBy string
+		base      string
+		revised   string
+	}{
+		{"pkg:npm/foo@1.1.0", "foo", schema.DIFF_MATCH_PACKAGE, "1.0.0", "1.1.0"},
+		{"bar-2.1.0", "bar", schema.DIFF_MATCH_GROUP_NAME, "2.0.0", "2.1.0"},
+	}
+	for _, entity := range expected {
+		changed := findDiffEntityResult(result.Components, schema.DIFF_CHANGE_CHANGED, entity.bomRef, entity.name)
+		if changed == nil {
+			t.Errorf("expected changed component `%s`; actual: %v", entity.name, result.Components)
+			continue
+		}
+		if changed.MatchedBy != entity.matchedBy {
+			t.Errorf("expected match by `%s`; actual: `%s`", entity.matchedBy, changed.MatchedBy)
+		}
+		if len(changed.Fields) == 0 || changed.Fields[0].Field != schema.DIFF_FIELD_VERSION ||
+			changed.Fields[0].Base != entity.base || changed.Fields[0].Revised != entity.revised {
+			t.Errorf("expected `%s` field change: `%s` to `%s`; actual: %v",
+				schema.DIFF_FIELD_VERSION, entity.base, entity.revised, changed.Fields)
+		}
+	}
+}
+
+func TestDiffSemanticCdx15NoChanges(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		FORMAT_TEXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(outputBuffer.String(), MSG_OUTPUT_NO_DIFF_CHANGES_FOUND) {
+		t.Errorf("expected output to contain: `%s`\n%s", MSG_OUTPUT_NO_DIFF_CHANGES_FOUND, outputBuffer.String())
+	}
+}
+
+func TestDiffSemanticCdx15Text(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_REVISED,
+		FORMAT_TEXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// title + separator + 4 (lib-a fields) + 2 (lib-c, lib-e) + 1 (vuln) + 2 (deps)
+	lines := strings.Split(strings.TrimSpace(outputBuffer.String()), "\n")
+	if len(lines) != 11 {
+		t.Errorf("expected 11 lines of output; actual: %v\n%s", len(lines), outputBuffer.String())
+	}
+}
+
+func TestDiffSemanticCdx15CSV(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_REVISED,
+		FORMAT_CSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "component,changed,bom-ref,lib-a,lib-a,1.1.0,version,1.0.0,1.1.0"
+	if !strings.Contains(outputBuffer.String(), expected) {
+		t.Errorf("expected output to contain: `%s`\n%s", expected, outputBuffer.String())
+	}
+}
+
+func TestDiffSemanticCdx15Markdown(t *testing.T) {
+	outputBuffer, err := innerBufferedTestDiffSemantic(t,
+		TEST_DIFF_SEMANTIC_CDX_1_5_BASE,
+		TEST_DIFF_SEMANTIC_CDX_1_5_REVISED,
+		FORMAT_MARKDOWN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := createMarkdownRow([]string{"component", "added", "", "lib-e", "lib-e", "5.0.0", "", "", ""})
+	if !strings.Contains(outputBuffer.String(), expected) {
+		t.Errorf("expected output to contain: `%s`\n%s", expected, outputBuffer.String())
+	}
+}
+
 // func debugDeltas(deltas []diff.Delta, indent string) (err error) {
 // 	for _, delta := range deltas {
 // 		//fmt.Printf("delta: %v\n", delta)
@@ -223,3 +429,15 @@ func TestDiffJsonArrayOrderMove1ObjectWithAddAndDeleteFormatText(t *testing.T) {
 // func Colorize(color string, text string) (colorizedText string) {
 // 	return color + text + Reset
 // }
+
+func TestDiffSemanticSpdxUnsupportedFormat(t *testing.T) {
+	_, err := loadDocumentSemanticDiff(TEST_PROFILE_SPDX_2_3_PACKAGES)
+	formatErr, ok := err.(*schema.UnsupportedFormatError)
+	if !ok {
+		t.Fatalf("expected: `%T`, actual: `%v`", formatErr, err)
+	}
+	if formatErr.InputFile != TEST_PROFILE_SPDX_2_3_PACKAGES || formatErr.Format != schema.SCHEMA_FORMAT_SPDX {
+		t.Errorf("expected: (file: `%s`, format: `%s`), actual: (`%s`, `%s`)",
+			TEST_PROFILE_SPDX_2_3_PACKAGES, schema.SCHEMA_FORMAT_SPDX, formatErr.InputFile, formatErr.Format)
+	}
+}
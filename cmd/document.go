@@ -19,8 +19,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
@@ -29,7 +31,14 @@ func LoadInputBOMFileAndDetectSchema() (document *schema.BOM, err error) {
 	getLogger().Enter()
 	defer getLogger().Exit()
 
-	inputFile := utils.GlobalFlags.PersistentFlags.InputFile
+	return LoadBOMFileAndDetectSchema(utils.GlobalFlags.PersistentFlags.InputFile)
+}
+
+// Load any named BOM file (i.e., not only the one named by the --input-file flag)
+// and detect its format and schema
+func LoadBOMFileAndDetectSchema(inputFile string) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
 
 	// check for required fields on command
 	getLogger().Tracef("utils.Flags.InputFile: `%s`", inputFile)
@@ -37,7 +46,7 @@ func LoadInputBOMFileAndDetectSchema() (document *schema.BOM, err error) {
 		return nil, fmt.Errorf("invalid input file (-%s): `%s` ", FLAG_FILENAME_INPUT_SHORT, inputFile)
 	}
 
-	// Construct a BOM document object around the input file
+	// Construct a BOM document object around the input file (or stdin)
 	document = schema.NewBOM(inputFile)
 
 	// Load the raw, candidate BOM (file) as JSON data
@@ -48,18 +57,23 @@ func LoadInputBOMFileAndDetectSchema() (document *schema.BOM, err error) {
 	}
 	getLogger().Infof("Successfully unmarshalled data from: `%s`", document.GetFilenameInterpolated())
 
-	// Search the document keys/values for known BOM formats and schema in the config. file
-	getLogger().Infof("Determining file's BOM format and version...")
-	err = SupportedFormatConfig.FindFormatAndSchema(document)
-	if err != nil {
-		return
-	}
-
-	// Display detected format, version with (optional) schema variant (i.e., if requested on command line)
-	getLogger().Infof("Determined BOM format, version (variant): `%s`, `%s` %s",
-		document.FormatInfo.CanonicalName,
-		document.SchemaInfo.Version,
-		schema.FormatSchemaVariant(document.SchemaInfo.Variant))
-	getLogger().Infof("Matching BOM schema (for validation): %s", document.SchemaInfo.File)
+	err = sbom.DetectSchema(context.Background(), document, loadOptions())
 	return
 }
+
+// Load BOM data that was already read (e.g., from a stream of documents) and detect
+// its format and schema; the name is only used to identify the document
+func LoadBOMDataAndDetectSchema(name string, data []byte) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	return sbom.LoadData(context.Background(), name, data, loadOptions())
+}
+
+// Returns the load options from the loaded (schema) configuration and the command flags
+func loadOptions() sbom.LoadOptions {
+	return sbom.LoadOptions{
+		Formats:       &SupportedFormatConfig,
+		SchemaVariant: utils.GlobalFlags.ValidateFlags.SchemaVariant,
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/CycloneDX/sbom-utility/common"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -30,30 +31,34 @@ import (
 const (
 	ERROR_APPLICATION = 1
 	ERROR_VALIDATION  = 2
+	ERROR_UNSIGNED    = 3 // i.e., no signatures found (see "signature verify")
 )
 
 // General error messages
 const (
-	ERR_TYPE_INVALID_JSON_MAP       = "invalid JSON map"
-	ERR_TYPE_INVALID_SBOM           = "invalid SBOM"
-	ERR_TYPE_SBOM_COMPONENT         = "component error"
-	ERR_TYPE_SBOM_LICENSE           = "license error"
-	ERR_TYPE_SBOM_COMPOSITION       = "composition error"
-	ERR_TYPE_SBOM_METADATA          = "metadata error"
-	ERR_TYPE_SBOM_METADATA_PROPERTY = "metadata property error"
-	ERR_TYPE_UNEXPECTED_ERROR       = "unexpected error"
+	ERR_TYPE_INVALID_JSON_MAP = "invalid JSON map"
+	ERR_TYPE_INVALID_SBOM     = "invalid SBOM"
+	ERR_TYPE_SBOM_COMPONENT   = "component error"
+	ERR_TYPE_SBOM_LICENSE     = "license error"
+	ERR_TYPE_SBOM_COMPOSITION = "composition error"
+	ERR_TYPE_SBOM_METADATA    = "metadata error"
+	ERR_TYPE_SBOM_PROFILE     = "profile error"
+	ERR_TYPE_UNEXPECTED_ERROR = "unexpected error"
+	ERR_TYPE_BATCH            = "batch error"
 )
 
 // Validation messages
 const (
 	MSG_FORMAT_TYPE                           = "format: `%s`"
 	MSG_SCHEMA_ERRORS                         = "schema errors found"
+	MSG_MIGRATE_SCHEMA_ERRORS                 = "migrated BOM is not valid against its target schema"
+	MSG_REFERENCE_ERRORS                      = "referential integrity errors found"
+	MSG_RULE_ERRORS                           = "custom validation rule errors found"
+	MSG_RULE_VIOLATION                        = "custom validation rule violation"
+	MSG_PROFILE_BELOW_THRESHOLD               = "validation profile conformance below threshold"
 	MSG_INVALID_METADATA_PROPERTIES           = "field `metadata.properties` is missing or invalid"
 	MSG_INVALID_METADATA_COMPONENT_COMPONENTS = "field `metadata.component.components` array should be empty"
 	MSG_INVALID_METADATA_COMPONENT            = "field `metadata.component` is missing or invalid"
-	MSG_PROPERTY_NOT_FOUND                    = "property not found"
-	MSG_PROPERTY_NOT_UNIQUE                   = "check failed: property not unique"
-	MSG_PROPERTY_REGEX_FAILED                 = "check failed: property regex mismatch"
 )
 
 // License messages
@@ -73,9 +78,11 @@ const (
 
 // Query error details
 const (
-	MSG_QUERY_ERROR_FROM_KEY_NOT_FOUND         = "key not found in path"
-	MSG_QUERY_ERROR_FROM_KEY_SLICE_DEREFERENCE = "key attempts to dereference into an array"
-	MSG_QUERY_ERROR_SELECT_WILDCARD            = "wildcard cannot be used with other values"
+	MSG_QUERY_ERROR_FROM_KEY_NOT_FOUND      = common.MSG_QUERY_PATH_KEY_NOT_FOUND
+	MSG_QUERY_ERROR_FROM_KEY_NOT_ARRAY      = common.MSG_QUERY_PATH_KEY_NOT_ARRAY
+	MSG_QUERY_ERROR_FROM_INDEX_OUT_OF_RANGE = common.MSG_QUERY_PATH_INDEX_OUT_OF_RANGE
+	MSG_QUERY_ERROR_SELECT_WILDCARD         = "wildcard cannot be used with other values"
+	MSG_QUERY_ERROR_NON_OBJECT_RESULTS      = "WHERE and SELECT clauses require object results"
 )
 
 // formatting Error() interface
@@ -106,6 +113,11 @@ func (err BaseError) Error() string {
 	return formattedMessage
 }
 
+// Support the log.TypedError interface (i.e., error types are logged as fields)
+func (err BaseError) ErrorType() string {
+	return err.Type
+}
+
 func (err *BaseError) AppendMessage(addendum string) {
 	if addendum != "" {
 		err.Message += addendum
@@ -153,10 +165,12 @@ type SBOMMetadataError struct {
 	Metadata schema.CDXMetadata
 }
 
-type SBOMMetadataPropertyError struct {
-	SBOMMetadataError
-	Expected *schema.CustomValidationProperty
-	Actual   []schema.CDXProperty
+// NOTE: "conformance" is the percentage of components that conform to the profile
+type SBOMProfileError struct {
+	InvalidSBOMError
+	Profile     string
+	Conformance float64
+	Threshold   float64
 }
 
 func NewInvalidSBOMError(sbom *schema.BOM, m string, errIn error, schemaErrors []gojsonschema.ResultError) *InvalidSBOMError {
@@ -215,20 +229,18 @@ func NewSBOMMetadataError(sbom *schema.BOM, m string, metadata schema.CDXMetadat
 	return err
 }
 
-// TODO: create Error() (interface) method that displays CDXProperty
-func NewSbomMetadataPropertyError(sbom *schema.BOM, m string,
-	expected *schema.CustomValidationProperty,
-	values []schema.CDXProperty) *SBOMMetadataPropertyError {
-
-	var err = new(SBOMMetadataPropertyError)
-	err.Type = ERR_TYPE_SBOM_METADATA_PROPERTY
-	err.Message = m
+func NewSBOMProfileError(sbom *schema.BOM, profile string, conformance float64, threshold float64) *SBOMProfileError {
+	var err = new(SBOMProfileError)
+	err.Type = ERR_TYPE_SBOM_PROFILE
+	err.Message = MSG_PROFILE_BELOW_THRESHOLD
 	err.SBOM = sbom
 	if sbom != nil {
 		err.InputFile = sbom.GetFilename()
 	}
-	err.Expected = expected
-	err.Actual = values
+	err.Profile = profile
+	err.Conformance = conformance
+	err.Threshold = threshold
+	err.Details = fmt.Sprintf("profile: `%s`, conformance: %.2f%%, threshold: %.2f%%", profile, conformance, threshold)
 	return err
 }
 
@@ -23,11 +23,11 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 	"github.com/spf13/cobra"
@@ -91,7 +91,7 @@ var VALID_LICENSE_FILTER_KEYS = []string{
 
 // Command help formatting
 var LICENSE_LIST_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
-	strings.Join([]string{FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN}, ", ") +
+	strings.Join([]string{FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN, FORMAT_SARIF}, ", ") +
 	" (default: json)"
 var LICENSE_LIST_SUMMARY_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_SUMMARY_HELP +
 	strings.Join([]string{FORMAT_TEXT, FORMAT_CSV, FORMAT_MARKDOWN}, ", ") +
@@ -123,9 +123,10 @@ func NewCommandList() *cobra.Command {
 		}
 
 		// Test for required flags (parameters)
-		err = preRunTestForInputFile(cmd, args)
+		err = preRunTestForBatchInputFiles(cmd, args)
 		return
 	}
+	initCommandBatchFlags(command)
 	return (command)
 }
 
@@ -137,10 +138,7 @@ func processLicenseListResults(err error) {
 }
 
 func sortLicenseKeys(licenseKeys []interface{}) {
-	// Sort by license key (i.e., one of `id`, `name` or `expression`)
-	sort.Slice(licenseKeys, func(i, j int) bool {
-		return licenseKeys[i].(string) < licenseKeys[j].(string)
-	})
+	sbom.SortLicenseKeys(licenseKeys)
 }
 
 // NOTE: parm. licenseKeys is actually a string slice
@@ -155,7 +153,7 @@ func checkLicenseListEmptyOrNoAssertionOnly(licenseKeys []interface{}) (empty bo
 	return
 }
 
-// NOTE: The license command ONLY WORKS on CDX format
+// NOTE: The license command works on both CDX and SPDX formats
 // NOTE: "list" commands need not validate (only unmarshal)... only report "none found"
 // TODO: Perhaps make a --validate flag to allow optional validation prior to listing
 func listCmdImpl(cmd *cobra.Command, args []string) (err error) {
@@ -183,6 +181,13 @@ func listCmdImpl(cmd *cobra.Command, args []string) (err error) {
 
 	// Use global license policy config. as loaded by initConfigurations() as
 	// using (optional) filename passed on command line OR the default, built-in config.
+	if IsBatch(utils.GlobalFlags.BatchFlags) {
+		err = ListLicensesBatch(writer, LicensePolicyConfig,
+			utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags,
+			whereFilters)
+		return
+	}
+
 	err = ListLicenses(writer, LicensePolicyConfig,
 		utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.LicenseFlags,
 		whereFilters)
@@ -221,6 +226,12 @@ func ListLicenses(writer io.Writer, policyConfig *schema.LicensePolicyConfig,
 
 	format := persistentFlags.OutputFormat
 
+	// SARIF output reports license policy violations (with or without `--summary`)
+	if format == FORMAT_SARIF {
+		err = DisplaySarif(writer, document.GetFilename(), licensePolicySarifFindings(document))
+		return
+	}
+
 	// if `--summary` report requested
 	if LicenseFlags.Summary {
 		// TODO surface errors returned from "DisplayXXX" functions
@@ -261,6 +272,61 @@ func ListLicenses(writer io.Writer, policyConfig *schema.LicensePolicyConfig,
 	return
 }
 
+// Note: batch results are always reported using the (license) summary columns
+func ListLicensesBatch(writer io.Writer, policyConfig *schema.LicensePolicyConfig,
+	persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags,
+	whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	results := ProcessBatch(batchFlags.InputFiles, batchFlags.MaxWorkers, func(inputFile string) (rows [][]string, err error) {
+		var document *schema.BOM
+		if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
+			return
+		}
+		if err = loadDocumentLicenses(document, policyConfig, whereFilters); err != nil {
+			return
+		}
+		// SARIF output reports license policy violations (see: DisplaySarifBatch)
+		if persistentFlags.OutputFormat == FORMAT_SARIF {
+			return sarifFindingRows(licensePolicySarifFindings(document)), nil
+		}
+		return licenseListSummaryRows(document), nil
+	})
+
+	if persistentFlags.OutputFormat == FORMAT_SARIF {
+		err = DisplaySarifBatch(writer, results)
+	} else {
+		err = DisplayBatchResults(writer, persistentFlags.OutputFormat, LICENSE_SUMMARY_TITLES, results)
+	}
+	if err != nil {
+		return
+	}
+	return BatchResultsError(results)
+}
+
+// Returns the (sorted) license summary report rows (i.e., in the order of LICENSE_SUMMARY_TITLES)
+func licenseListSummaryRows(bom *schema.BOM) (rows [][]string) {
+	licenseKeys := bom.LicenseMap.KeySet()
+	sortLicenseKeys(licenseKeys)
+
+	for _, licenseName := range licenseKeys {
+		arrLicenseInfo, _ := bom.LicenseMap.Get(licenseName)
+		for _, iInfo := range arrLicenseInfo {
+			licenseInfo := iInfo.(schema.LicenseInfo)
+			rows = append(rows, []string{
+				licenseInfo.Policy.UsagePolicy,
+				licenseInfo.LicenseChoiceType,
+				licenseName.(string),
+				licenseInfo.ResourceName,
+				licenseInfo.BOMRef.String(),
+				licenseInfo.BOMLocation,
+			})
+		}
+	}
+	return
+}
+
 // NOTE: This list is NOT de-duplicated
 // NOTE: if no license are found, the "json.Marshal" method(s) will return a value of "null"
 // which is valid JSON (and not an empty array)
@@ -38,6 +38,8 @@ const (
 	TEST_LICENSE_LIST_TEXT_CDX_1_4_INVALID_LICENSE_ID    = "test/cyclonedx/cdx-1-4-license-policy-invalid-spdx-id.json"
 	TEST_LICENSE_LIST_TEXT_CDX_1_4_INVALID_LICENSE_NAME  = "test/cyclonedx/cdx-1-4-license-policy-invalid-license-name.json"
 	TEST_LICENSE_LIST_CDX_1_4_LICENSE_EXPRESSION_IN_NAME = "test/cyclonedx/cdx-1-4-license-expression-in-name.json"
+
+	TEST_LICENSE_LIST_SPDX_2_3_PACKAGES = "test/spdx/spdx-2-3-packages.json"
 )
 
 // default ResourceTestInfo struct values
@@ -149,17 +151,49 @@ func TestLicenseListInvalidInputFileLoad(t *testing.T) {
 }
 
 // -------------------------------------------
-// Test format unsupported (SPDX)
+// Test format SPDX
 // -------------------------------------------
-func TestLicenseListFormatUnsupportedSPDX1(t *testing.T) {
-	lti := NewLicenseTestInfo(TEST_SPDX_2_2_MIN_REQUIRED, FORMAT_DEFAULT, false)
-	lti.ResultExpectedError = &schema.UnsupportedFormatError{}
+func TestLicenseListSpdx22MinRequiredNoneFound(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_SPDX_2_2_MIN_REQUIRED, FORMAT_TEXT, true)
+	lti.ResultExpectedLineCount = 2 // title and separator only
 	innerTestLicenseList(t, lti)
 }
 
-func TestLicenseListFormatUnsupportedSPDX2(t *testing.T) {
-	lti := NewLicenseTestInfo(TEST_SPDX_2_2_EXAMPLE_1, FORMAT_DEFAULT, false)
-	lti.ResultExpectedError = &schema.UnsupportedFormatError{}
+func TestLicenseListSpdx22Example1(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_SPDX_2_2_EXAMPLE_1, FORMAT_TEXT, true)
+	innerTestLicenseList(t, lti)
+}
+
+func TestLicenseListSummarySpdx23Text(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_LICENSE_LIST_SPDX_2_3_PACKAGES, FORMAT_TEXT, true)
+	lti.ResultExpectedLineCount = 12 // title, separator and 10 data rows
+	innerTestLicenseList(t, lti)
+}
+
+func TestLicenseListSummarySpdx23WhereLocationDeclared(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_LICENSE_LIST_SPDX_2_3_PACKAGES, FORMAT_TEXT, true)
+	lti.WhereClause = "bom-location=packages.licenseDeclared"
+	lti.ResultExpectedLineCount = 6 // title, separator and 4 data rows
+	lti.ResultLineContainsValuesAtLineNum = 3
+	lti.ResultLineContainsValues = []string{schema.POLICY_ALLOW, schema.LC_VALUE_ID, "Apache-2.0", "log4j-core"}
+	innerTestLicenseList(t, lti)
+}
+
+func TestLicenseListSummarySpdx23WhereLicenseRefName(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_LICENSE_LIST_SPDX_2_3_PACKAGES, FORMAT_TEXT, true)
+	lti.WhereClause = "license-type=name"
+	lti.ResultExpectedLineCount = 4 // title, separator and 2 data rows
+	lti.ResultLineContainsValuesAtLineNum = 2
+	lti.ResultLineContainsValues = []string{schema.POLICY_UNDEFINED, "ACME Proprietary License", "SPDXRef-Package-acme-application"}
+	innerTestLicenseList(t, lti)
+}
+
+func TestLicenseListSummarySpdx23WhereExpression(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_LICENSE_LIST_SPDX_2_3_PACKAGES, FORMAT_TEXT, true)
+	lti.WhereClause = "license-type=expression"
+	lti.ResultExpectedLineCount = 3 // title, separator and 1 data row
+	lti.ResultLineContainsValuesAtLineNum = 2
+	lti.ResultLineContainsValues = []string{schema.POLICY_ALLOW, "(LGPL-2.1-only OR MIT)", "packages.licenseConcluded"}
 	innerTestLicenseList(t, lti)
 }
 
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// flags (do not translate)
+const (
+	FLAG_MERGE_STRATEGY     = "strategy"
+	FLAG_MERGE_HIERARCHICAL = "hierarchical"
+	FLAG_MERGE_NAME         = "name"
+	FLAG_MERGE_VERSION      = "version"
+	FLAG_MERGE_GROUP        = "group"
+)
+
+// flag help (translate)
+const (
+	FLAG_MERGE_OUTPUT_FORMAT_HELP = "format output using the specified type"
+	FLAG_MERGE_INPUT_FILES_HELP   = "input filename of a BOM to merge (repeat the flag or use a comma-separated list for multiple files)"
+	FLAG_MERGE_STRATEGY_HELP      = "strategy used to resolve duplicate entities (by bom-ref or purl) found across input BOMs: "
+	FLAG_MERGE_HIERARCHICAL_HELP  = "nest each input BOM's components under its own \"metadata.component\" beneath the new (merged) \"metadata.component\""
+	FLAG_MERGE_NAME_HELP          = "name of the new (merged) BOM's \"metadata.component\" (required for hierarchical merge)"
+	FLAG_MERGE_VERSION_HELP       = "version of the new (merged) BOM's \"metadata.component\""
+	FLAG_MERGE_GROUP_HELP         = "group of the new (merged) BOM's \"metadata.component\""
+)
+
+var MERGE_OUTPUT_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_JSON}, ", ")
+
+const (
+	MERGE_DEFAULT_COMPONENT_TYPE = "application"
+)
+
+func NewCommandMerge() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_MERGE
+	command.Short = "Merge multiple CycloneDX BOM input files into a single BOM and write resultant BOM to output"
+	command.Long = "Merge (i.e., union) the components, services, dependencies, vulnerabilities, compositions and external references of multiple CycloneDX BOM input files into a single BOM and write resultant BOM to output"
+	command.RunE = mergeCmdImpl
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		// Test for required flags (parameters)
+		err = preRunTestForInputFiles(cmd, utils.GlobalFlags.MergeFlags.InputFiles)
+		return
+	}
+	initCommandMergeFlags(command)
+
+	return command
+}
+
+func initCommandMergeFlags(command *cobra.Command) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	command.PersistentFlags().StringVar(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_OUTPUT_FORMAT, FORMAT_JSON,
+		FLAG_MERGE_OUTPUT_FORMAT_HELP+MERGE_OUTPUT_SUPPORTED_FORMATS)
+	// NOTE: this (local) flag overrides the root command's (persistent) `--input-file` flag
+	// to allow multiple input files
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.MergeFlags.InputFiles, FLAG_FILENAME_INPUT, FLAG_FILENAME_INPUT_SHORT, nil, FLAG_MERGE_INPUT_FILES_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.MergeFlags.Strategy, FLAG_MERGE_STRATEGY, "", schema.MERGE_STRATEGY_FIRST_WINS,
+		FLAG_MERGE_STRATEGY_HELP+strings.Join(schema.VALID_MERGE_STRATEGIES, ", "))
+	command.Flags().BoolVarP(&utils.GlobalFlags.MergeFlags.Hierarchical, FLAG_MERGE_HIERARCHICAL, "", false, FLAG_MERGE_HIERARCHICAL_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.MergeFlags.Name, FLAG_MERGE_NAME, "", "", FLAG_MERGE_NAME_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.MergeFlags.Version, FLAG_MERGE_VERSION, "", "", FLAG_MERGE_VERSION_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.MergeFlags.Group, FLAG_MERGE_GROUP, "", "", FLAG_MERGE_GROUP_HELP)
+}
+
+// Command PreRunE helper function to test for (multiple) input files
+func preRunTestForInputFiles(cmd *cobra.Command, inputFiles []string) error {
+	getLogger().Enter()
+	defer getLogger().Exit()
+	getLogger().Tracef("inputFiles: %v", inputFiles)
+
+	if len(inputFiles) == 0 {
+		return getLogger().Errorf("Missing required argument(s): %s", FLAG_FILENAME_INPUT)
+	}
+
+	for _, inputFilename := range inputFiles {
+		if inputFilename == INPUT_TYPE_STDIN {
+			continue
+		} else if _, err := os.Stat(inputFilename); err != nil {
+			return getLogger().Errorf("File not found: `%s`", inputFilename)
+		}
+	}
+	return nil
+}
+
+func mergeCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFilename, writer)
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		// always close the output file
+		if outputFile != nil {
+			outputFile.Close()
+			getLogger().Infof("Closed output file: `%s`", outputFilename)
+		}
+	}()
+
+	if err == nil {
+		err = Merge(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.MergeFlags)
+	}
+
+	return
+}
+
+// Assure all errors are logged
+func processMergeResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+func Merge(writer io.Writer, persistentFlags utils.PersistentCommandFlags, mergeFlags utils.MergeCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processMergeResults(err)
+		}
+	}()
+
+	if len(mergeFlags.InputFiles) == 0 {
+		err = getLogger().Errorf("invalid parameter value: missing `%s` value(s) from command", FLAG_FILENAME_INPUT)
+		return
+	}
+
+	// Load (and fully unmarshal) all input BOMs
+	var documents []*schema.BOM
+	for _, inputFile := range mergeFlags.InputFiles {
+		var document *schema.BOM
+		if document, err = loadDocumentMerge(inputFile); err != nil {
+			return
+		}
+		documents = append(documents, document)
+	}
+
+	options := schema.MergeOptions{
+		Strategy:     mergeFlags.Strategy,
+		Hierarchical: mergeFlags.Hierarchical,
+		Component:    newMergeRootComponent(mergeFlags),
+	}
+
+	getLogger().Infof("Merging (%v) BOM documents (strategy: `%s`, hierarchical: `%t`)...",
+		len(documents), options.Strategy, options.Hierarchical)
+	var merged *schema.BOM
+	if merged, err = schema.MergeCycloneDXBOMs(documents, options); err != nil {
+		return
+	}
+
+	// Output the merged BOM
+	format := persistentFlags.OutputFormat
+	getLogger().Infof("Outputting listing (`%s` format)...", format)
+	indentString := utils.GenerateIndentString(int(persistentFlags.OutputIndent))
+	switch format {
+	case FORMAT_JSON:
+		err = merged.EncodeAsFormattedJSON(writer, utils.DEFAULT_JSON_PREFIX_STRING, indentString)
+	default:
+		// Default to JSON output for anything else (set as flag default)
+		getLogger().Warningf("Merge not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_JSON)
+		err = merged.EncodeAsFormattedJSON(writer, utils.DEFAULT_JSON_PREFIX_STRING, indentString)
+	}
+
+	return
+}
+
+func loadDocumentMerge(inputFile string) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
+		return
+	}
+
+	// At this time, fail SPDX format SBOMs as "unsupported"
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_MERGE, FORMAT_ANY)
+		return
+	}
+
+	err = document.UnmarshalCycloneDXBOM()
+	return
+}
+
+// Create the new (merged) BOM's root component if one was described by flags
+func newMergeRootComponent(mergeFlags utils.MergeCommandFlags) (pComponent *schema.CDXComponent) {
+	if mergeFlags.Name == "" {
+		return
+	}
+
+	pComponent = new(schema.CDXComponent)
+	pComponent.Type = MERGE_DEFAULT_COMPONENT_TYPE
+	pComponent.Group = mergeFlags.Group
+	pComponent.Name = mergeFlags.Name
+	pComponent.Version = mergeFlags.Version
+
+	bomRef := schema.CDXRefType(mergeFlags.Name)
+	if mergeFlags.Group != "" {
+		bomRef = schema.CDXRefType(fmt.Sprintf("%s/%s", mergeFlags.Group, mergeFlags.Name))
+	}
+	if mergeFlags.Version != "" {
+		bomRef = schema.CDXRefType(fmt.Sprintf("%s@%s", bomRef, mergeFlags.Version))
+	}
+	pComponent.BOMRef = &bomRef
+	return
+}
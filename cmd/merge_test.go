@@ -0,0 +1,324 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	TEST_MERGE_CDX_1_5_MODULE_A = "test/merge/cdx-1-5-merge-module-a.json"
+	TEST_MERGE_CDX_1_5_MODULE_B = "test/merge/cdx-1-5-merge-module-b.json"
+	TEST_MERGE_CDX_1_5_MODULE_C = "test/merge/cdx-1-5-merge-module-c.json"
+)
+
+func innerBufferedTestMerge(t *testing.T, mergeFlags utils.MergeCommandFlags) (outputBuffer bytes.Buffer, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	var persistentFlags utils.PersistentCommandFlags
+	persistentFlags.OutputFormat = FORMAT_JSON
+	persistentFlags.OutputIndent = DEFAULT_OUTPUT_INDENT_LENGTH
+
+	err = Merge(outputWriter, persistentFlags, mergeFlags)
+	return
+}
+
+func innerTestMerge(t *testing.T, mergeFlags utils.MergeCommandFlags) (cdxBom *schema.CDXBom) {
+	outputBuffer, err := innerBufferedTestMerge(t, mergeFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cdxBom = new(schema.CDXBom)
+	if err = json.Unmarshal(outputBuffer.Bytes(), cdxBom); err != nil {
+		t.Fatalf("unable to unmarshal merged BOM: %s\n%s", err, outputBuffer.String())
+	}
+	return
+}
+
+func findMergedComponent(components *[]schema.CDXComponent, name string) *schema.CDXComponent {
+	if components == nil {
+		return nil
+	}
+	for i, component := range *components {
+		if component.Name == name {
+			return &(*components)[i]
+		}
+	}
+	return nil
+}
+
+func TestMergeCdxFlatFirstWins(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:   schema.MERGE_STRATEGY_FIRST_WINS,
+	})
+
+	// The highest input specVersion is used
+	if cdxBom.SpecVersion != "1.5" {
+		t.Errorf("expected specVersion `1.5`; actual: `%s`", cdxBom.SpecVersion)
+	}
+
+	// The first input's root is retained as the merged root
+	if cdxBom.Metadata == nil || cdxBom.Metadata.Component == nil ||
+		cdxBom.Metadata.Component.Name != "module-a" {
+		t.Fatalf("expected `metadata.component` named `module-a`; actual: %v", cdxBom.Metadata)
+	}
+
+	// module-b, lodash (de-duplicated by purl), left-pad, chalk
+	if cdxBom.Components == nil || len(*cdxBom.Components) != 4 {
+		t.Fatalf("expected 4 components; actual: %v", cdxBom.Components)
+	}
+	lodash := findMergedComponent(cdxBom.Components, "lodash")
+	if lodash == nil || lodash.Description != "from module-a" {
+		t.Errorf("expected first `lodash` component to win; actual: %v", lodash)
+	}
+
+	if cdxBom.Services == nil || len(*cdxBom.Services) != 1 {
+		t.Errorf("expected 1 service; actual: %v", cdxBom.Services)
+	}
+	if cdxBom.ExternalReferences == nil || len(*cdxBom.ExternalReferences) != 1 {
+		t.Errorf("expected 1 external reference; actual: %v", cdxBom.ExternalReferences)
+	}
+	if cdxBom.Vulnerabilities == nil || len(*cdxBom.Vulnerabilities) != 1 {
+		t.Errorf("expected 1 vulnerability; actual: %v", cdxBom.Vulnerabilities)
+	}
+	if cdxBom.Compositions == nil || len(*cdxBom.Compositions) != 1 {
+		t.Errorf("expected 1 composition; actual: %v", cdxBom.Compositions)
+	}
+
+	// Dependencies for the same ref are combined
+	if cdxBom.Dependencies == nil || len(*cdxBom.Dependencies) != 2 {
+		t.Fatalf("expected 2 dependencies; actual: %v", cdxBom.Dependencies)
+	}
+	for _, dependency := range *cdxBom.Dependencies {
+		if dependency.Ref.String() == "module-a" && len(*dependency.DependsOn) != 3 {
+			t.Errorf("expected `module-a` to depend on 3 refs; actual: %v", *dependency.DependsOn)
+		}
+	}
+}
+
+func TestMergeCdxFlatLastWins(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:   schema.MERGE_STRATEGY_LAST_WINS,
+	})
+
+	if cdxBom.Components == nil || len(*cdxBom.Components) != 4 {
+		t.Fatalf("expected 4 components; actual: %v", cdxBom.Components)
+	}
+	lodash := findMergedComponent(cdxBom.Components, "lodash")
+	if lodash == nil || lodash.Description != "from module-b" {
+		t.Errorf("expected last `lodash` component to win; actual: %v", lodash)
+	}
+}
+
+func TestMergeCdxFlatFail(t *testing.T) {
+	_, err := innerBufferedTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:   schema.MERGE_STRATEGY_FAIL,
+	})
+
+	if !ErrorTypesMatch(err, &schema.MergeConflictError{}) {
+		t.Errorf("expected error type: `%T`, actual type: `%T`", &schema.MergeConflictError{}, err)
+	}
+}
+
+// module-c declares a component with the same identity as the root of module-a
+func TestMergeCdxFlatRootConflictFail(t *testing.T) {
+	_, err := innerBufferedTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_C},
+		Strategy:   schema.MERGE_STRATEGY_FAIL,
+	})
+
+	if !ErrorTypesMatch(err, &schema.MergeConflictError{}) {
+		t.Fatalf("expected error type: `%T`, actual type: `%T`", &schema.MergeConflictError{}, err)
+	}
+	if key := err.(*schema.MergeConflictError).Key; key != schema.MERGE_KEY_PREFIX_BOMREF+"module-a" {
+		t.Errorf("expected conflict on root `module-a`; actual: `%s`", key)
+	}
+}
+
+func TestMergeCdxFlatRootConflictLastWins(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_C},
+		Strategy:   schema.MERGE_STRATEGY_LAST_WINS,
+	})
+
+	root := cdxBom.Metadata.Component
+	if root == nil || root.Name != "module-a" || root.Description != "from module-c" {
+		t.Errorf("expected root `module-a` to be replaced by module-c's; actual: %v", root)
+	}
+	if component := findMergedComponent(cdxBom.Components, "module-a"); component != nil {
+		t.Errorf("expected root `module-a` not to be duplicated in components; actual: %v", component)
+	}
+}
+
+// module-b's lodash (purl only) and module-c's lodash (bom-ref only) are distinct
+// until module-a's lodash (bom-ref and purl) collides with both
+func TestMergeCdxFlatLastWinsMultipleKeys(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_B, TEST_MERGE_CDX_1_5_MODULE_C, TEST_MERGE_CDX_1_5_MODULE_A},
+		Strategy:   schema.MERGE_STRATEGY_LAST_WINS,
+	})
+
+	var count int
+	for _, component := range *cdxBom.Components {
+		if component.Name == "lodash" {
+			count++
+			if component.Description != "from module-a" {
+				t.Errorf("expected last `lodash` component to win; actual: %v", component)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected 1 `lodash` component; actual: %v", count)
+	}
+	// module-a (root of the last input), module-c, lodash, chalk, left-pad
+	if len(*cdxBom.Components) != 5 {
+		t.Errorf("expected 5 components; actual: %v", *cdxBom.Components)
+	}
+}
+
+func TestMergeCdxInvalidStrategy(t *testing.T) {
+	_, err := innerBufferedTestMerge(t, utils.MergeCommandFlags{
+		InputFiles: []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:   "unknown",
+	})
+	if err == nil {
+		t.Errorf("expected error for invalid merge strategy")
+	}
+}
+
+func TestMergeCdxHierarchical(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles:   []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:     schema.MERGE_STRATEGY_FIRST_WINS,
+		Hierarchical: true,
+		Name:         "acme-product",
+		Version:      "3.0.0",
+	})
+
+	root := cdxBom.Metadata.Component
+	if root == nil || root.Name != "acme-product" || root.BOMRef.String() != "acme-product@3.0.0" {
+		t.Fatalf("expected new root `metadata.component`; actual: %v", root)
+	}
+
+	// Each input's root is a top-level component with its components nested
+	if cdxBom.Components == nil || len(*cdxBom.Components) != 2 {
+		t.Fatalf("expected 2 (top-level) components; actual: %v", cdxBom.Components)
+	}
+	moduleA := findMergedComponent(cdxBom.Components, "module-a")
+	if moduleA == nil || moduleA.Components == nil || len(*moduleA.Components) != 2 {
+		t.Errorf("expected `module-a` with 2 nested components; actual: %v", moduleA)
+	}
+	// lodash was already merged under module-a (first-wins)
+	moduleB := findMergedComponent(cdxBom.Components, "module-b")
+	if moduleB == nil || moduleB.Components == nil || len(*moduleB.Components) != 1 {
+		t.Errorf("expected `module-b` with 1 nested component; actual: %v", moduleB)
+	}
+
+	// The new root depends on each input's root
+	var found bool
+	for _, dependency := range *cdxBom.Dependencies {
+		if dependency.Ref.String() == root.BOMRef.String() {
+			found = true
+			if len(*dependency.DependsOn) != 2 {
+				t.Errorf("expected root to depend on 2 refs; actual: %v", *dependency.DependsOn)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a dependency for the new root: `%s`", root.BOMRef)
+	}
+}
+
+func TestMergeCdxHierarchicalLastWins(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles:   []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Strategy:     schema.MERGE_STRATEGY_LAST_WINS,
+		Hierarchical: true,
+		Name:         "acme-product",
+		Version:      "3.0.0",
+	})
+
+	// module-b's lodash replaces the one nested (in position) under module-a
+	moduleA := findMergedComponent(cdxBom.Components, "module-a")
+	if moduleA == nil || moduleA.Components == nil || len(*moduleA.Components) != 2 {
+		t.Fatalf("expected `module-a` with 2 nested components; actual: %v", moduleA)
+	}
+	lodash := findMergedComponent(moduleA.Components, "lodash")
+	if lodash == nil || lodash.Description != "from module-b" {
+		t.Errorf("expected last `lodash` component to win; actual: %v", lodash)
+	}
+}
+
+func TestMergeCdxHierarchicalLastWinsRepeatedInput(t *testing.T) {
+	cdxBom := innerTestMerge(t, utils.MergeCommandFlags{
+		InputFiles:   []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B, TEST_MERGE_CDX_1_5_MODULE_A},
+		Strategy:     schema.MERGE_STRATEGY_LAST_WINS,
+		Hierarchical: true,
+		Name:         "acme-product",
+		Version:      "3.0.0",
+	})
+
+	// The replaced module-a retains (and merges into) its nested components
+	if cdxBom.Components == nil || len(*cdxBom.Components) != 2 {
+		t.Fatalf("expected 2 (top-level) components; actual: %v", cdxBom.Components)
+	}
+	moduleA := findMergedComponent(cdxBom.Components, "module-a")
+	if moduleA == nil || moduleA.Components == nil || len(*moduleA.Components) != 2 {
+		t.Fatalf("expected `module-a` with 2 nested components; actual: %v", moduleA)
+	}
+	lodash := findMergedComponent(moduleA.Components, "lodash")
+	if lodash == nil || lodash.Description != "from module-a" {
+		t.Errorf("expected last `lodash` component to win; actual: %v", lodash)
+	}
+}
+
+func TestMergeCdxHierarchicalMissingName(t *testing.T) {
+	_, err := innerBufferedTestMerge(t, utils.MergeCommandFlags{
+		InputFiles:   []string{TEST_MERGE_CDX_1_5_MODULE_A, TEST_MERGE_CDX_1_5_MODULE_B},
+		Hierarchical: true,
+	})
+	if err == nil {
+		t.Errorf("expected error for hierarchical merge without a `--%s` value", FLAG_MERGE_NAME)
+	}
+}
+
+func TestMergeSpdxUnsupportedFormat(t *testing.T) {
+	_, err := loadDocumentMerge(TEST_PROFILE_SPDX_2_3_PACKAGES)
+	formatErr, ok := err.(*schema.UnsupportedFormatError)
+	if !ok {
+		t.Fatalf("expected: `%T`, actual: `%v`", formatErr, err)
+	}
+	if formatErr.InputFile != TEST_PROFILE_SPDX_2_3_PACKAGES || formatErr.Format != schema.SCHEMA_FORMAT_SPDX {
+		t.Errorf("expected: (file: `%s`, format: `%s`), actual: (`%s`, `%s`)",
+			TEST_PROFILE_SPDX_2_3_PACKAGES, schema.SCHEMA_FORMAT_SPDX, formatErr.InputFile, formatErr.Format)
+	}
+}
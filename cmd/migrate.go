@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// flags (do not translate)
+const (
+	FLAG_MIGRATE_SPEC_VERSION = "spec-version"
+)
+
+// flag help (translate)
+const (
+	FLAG_MIGRATE_SPEC_VERSION_HELP = "target CycloneDX specification version (i.e., \"specVersion\"): "
+)
+
+func NewCommandMigrate() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_MIGRATE
+	command.Short = "Upgrade or downgrade a CycloneDX BOM to a target specification version"
+	command.Long = "Rewrite a CycloneDX JSON BOM to a target specification version (i.e., \"specVersion\") by removing or transforming fields not supported by the target version, migrating deprecated constructs and reporting every lossy change"
+	command.RunE = migrateCmdImpl
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		// Test for required flags (parameters)
+		if err = preRunTestForInputFile(cmd, args); err != nil {
+			return
+		}
+		if specVersion := utils.GlobalFlags.MigrateFlags.SpecVersion; !schema.IsValidCDXSpecVersion(specVersion) {
+			err = getLogger().Errorf("invalid `%s` flag value: `%s` (valid values: %s)",
+				FLAG_MIGRATE_SPEC_VERSION, specVersion, strings.Join(schema.VALID_CDX_SPEC_VERSIONS, ", "))
+		}
+		return
+	}
+	initCommandMigrateFlags(command)
+
+	return command
+}
+
+func initCommandMigrateFlags(command *cobra.Command) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	command.Flags().StringVarP(&utils.GlobalFlags.MigrateFlags.SpecVersion, FLAG_MIGRATE_SPEC_VERSION, "", "",
+		FLAG_MIGRATE_SPEC_VERSION_HELP+strings.Join(schema.VALID_CDX_SPEC_VERSIONS, ", "))
+	command.Flags().StringVarP(&utils.GlobalFlags.MigrateFlags.ReportFile, FLAG_CONVERT_REPORT_FILE, "", "", FLAG_CONVERT_REPORT_FILE_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.MigrateFlags.ReportFormat, FLAG_CONVERT_REPORT_FORMAT, "", FORMAT_TEXT,
+		FLAG_CONVERT_REPORT_FORMAT_HELP+CONVERT_REPORT_SUPPORTED_FORMATS)
+}
+
+func migrateCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFilename, writer)
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		// always close the output file
+		if outputFile != nil {
+			outputFile.Close()
+			getLogger().Infof("Closed output file: `%s`", outputFilename)
+		}
+	}()
+
+	if err == nil {
+		var report *schema.ConversionReport
+		report, err = Migrate(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.MigrateFlags)
+		if err == nil {
+			err = outputConversionReport(report, utils.GlobalFlags.MigrateFlags.ReportFile,
+				utils.GlobalFlags.MigrateFlags.ReportFormat)
+		}
+	}
+
+	return
+}
+
+// Assure all errors are logged
+func processMigrateResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+func Migrate(writer io.Writer, persistentFlags utils.PersistentCommandFlags, migrateFlags utils.MigrateCommandFlags) (report *schema.ConversionReport, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processMigrateResults(err)
+		}
+	}()
+
+	var document *schema.BOM
+	if document, err = LoadBOMFileAndDetectSchema(persistentFlags.InputFile); err != nil {
+		return
+	}
+
+	// At this time, fail SPDX format SBOMs as "unsupported"
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_MIGRATE, FORMAT_ANY)
+		return
+	}
+
+	if err = document.UnmarshalCycloneDXBOM(); err != nil {
+		return
+	}
+
+	getLogger().Infof("Migrating BOM `%s` from spec. version `%s` to `%s`...",
+		document.GetFilenameInterpolated(), document.SchemaInfo.Version, migrateFlags.SpecVersion)
+	if report, err = document.MigrateCycloneDXSpecVersion(migrateFlags.SpecVersion); err != nil {
+		return
+	}
+
+	// Verify the migrated BOM's spec. version is found in the schema config
+	// and that the migrated BOM is valid against that (target) schema
+	var migrated *schema.BOM
+	if migrated, err = verifyConvertedFormatAndSchema(document.GetCdxBom()); err != nil {
+		return
+	}
+	if err = validateMigratedBOM(migrated); err != nil {
+		return
+	}
+
+	// Output the migrated BOM
+	indentString := utils.GenerateIndentString(int(persistentFlags.OutputIndent))
+	err = document.EncodeAsFormattedJSON(writer, utils.DEFAULT_JSON_PREFIX_STRING, indentString)
+	return
+}
+
+// Validate the migrated BOM against its (embedded) target schema; a migration
+// that does not produce a valid BOM (for the target version) is an error
+func validateMigratedBOM(migrated *schema.BOM) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	result, err := sbom.Validate(context.Background(), migrated, sbom.ValidateOptions{
+		SchemaCache: ValidationSchemaCache,
+	})
+	if err != nil {
+		return
+	}
+	if !result.Valid {
+		for _, schemaError := range result.SchemaErrors {
+			getLogger().Errorf("migrated BOM schema error: %s", schemaError.String())
+		}
+		err = NewInvalidSBOMError(migrated, MSG_MIGRATE_SCHEMA_ERRORS, nil, result.SchemaErrors)
+	}
+	return
+}
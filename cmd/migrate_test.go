@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	TEST_MIGRATE_CDX_1_5_DOWNGRADE = "test/migrate/cdx-1-5-migrate-downgrade.json"
+	TEST_MIGRATE_CDX_1_3_UPGRADE   = "test/migrate/cdx-1-3-migrate-upgrade.json"
+
+	TEST_MIGRATE_CDX_1_5_MISSING_VERSION = "test/migrate/cdx-1-5-migrate-downgrade-missing-version.json"
+)
+
+func innerBufferedTestMigrate(t *testing.T, inputFile string, specVersion string) (outputBuffer bytes.Buffer, report *schema.ConversionReport, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	var persistentFlags utils.PersistentCommandFlags
+	persistentFlags.InputFile = inputFile
+	persistentFlags.OutputIndent = DEFAULT_OUTPUT_INDENT_LENGTH
+
+	report, err = Migrate(outputWriter, persistentFlags, utils.MigrateCommandFlags{SpecVersion: specVersion})
+	return
+}
+
+func innerTestMigrate(t *testing.T, inputFile string, specVersion string) (jsonMap map[string]interface{}, report *schema.ConversionReport) {
+	outputBuffer, report, err := innerBufferedTestMigrate(t, inputFile, specVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(outputBuffer.Bytes(), &jsonMap); err != nil {
+		t.Fatalf("unable to unmarshal migrated BOM: %s\n%s", err, outputBuffer.String())
+	}
+	if jsonMap["specVersion"] != specVersion {
+		t.Errorf("expected specVersion `%s`; actual: `%v`", specVersion, jsonMap["specVersion"])
+	}
+	return
+}
+
+// Validate the migrated BOM (output) against the schema of its (target) spec. version
+func innerTestMigrateSchema(t *testing.T, jsonMap map[string]interface{}) {
+	document := schema.NewBOM("")
+	document.JsonMap = jsonMap
+	if err := SupportedFormatConfig.FindFormatAndSchema(document); err != nil {
+		t.Fatal(err)
+	}
+	result, err := sbom.Validate(context.Background(), document, sbom.ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid {
+		t.Errorf("expected migrated BOM to be valid against schema `%s`; actual: %v", result.SchemaName, result.SchemaErrors)
+	}
+}
+
+func findMigrateLossyEntry(report *schema.ConversionReport, location string, field string) *schema.ConversionLossyEntry {
+	for i, entry := range report.Lossy {
+		if entry.Location == location && entry.Field == field {
+			return &report.Lossy[i]
+		}
+	}
+	return nil
+}
+
+func TestMigrateCdx15To14(t *testing.T) {
+	jsonMap, report := innerTestMigrate(t, TEST_MIGRATE_CDX_1_5_DOWNGRADE, "1.4")
+
+	// v1.5 (root) fields are removed
+	for _, key := range []string{"annotations", "properties"} {
+		if _, found := jsonMap[key]; found {
+			t.Errorf("expected `%s` to be removed", key)
+		}
+		if findMigrateLossyEntry(report, schema.CONVERT_LOCATION_ROOT, key) == nil {
+			t.Errorf("expected lossy entry for `%s`; actual: %v", key, report.Lossy)
+		}
+	}
+
+	// The tools object form is converted to the (legacy) tools array
+	metadata := jsonMap["metadata"].(map[string]interface{})
+	tools, ok := metadata["tools"].([]interface{})
+	if !ok || len(tools) != 2 {
+		t.Fatalf("expected (legacy) tools array with 2 tools; actual: %v", metadata["tools"])
+	}
+	if tool := tools[0].(map[string]interface{}); tool["vendor"] != "acme" || tool["name"] != "sbom-generator" {
+		t.Errorf("expected tool `acme` `sbom-generator`; actual: %v", tool)
+	}
+	if findMigrateLossyEntry(report, "metadata.tools.components[0]", "description") == nil {
+		t.Errorf("expected lossy entry for tool `description`; actual: %v", report.Lossy)
+	}
+	if _, found := metadata["lifecycles"]; found {
+		t.Errorf("expected `metadata.lifecycles` to be removed")
+	}
+
+	// Enum. values added in v1.5 are replaced
+	components := jsonMap["components"].([]interface{})
+	model := components[0].(map[string]interface{})
+	if model["type"] != schema.COMPONENT_TYPE_FILE {
+		t.Errorf("expected component type `%s`; actual: `%v`", schema.COMPONENT_TYPE_FILE, model["type"])
+	}
+	references := model["externalReferences"].([]interface{})
+	if references[0].(map[string]interface{})["type"] != "other" ||
+		references[1].(map[string]interface{})["type"] != "release-notes" {
+		t.Errorf("unexpected external reference types: %v", references)
+	}
+
+	vulnerability := jsonMap["vulnerabilities"].([]interface{})[0].(map[string]interface{})
+	if _, found := vulnerability["workaround"]; found {
+		t.Errorf("expected vulnerability `workaround` to be removed")
+	}
+	if entry := findMigrateLossyEntry(report, "vulnerabilities[0].ratings[0]", "method"); entry == nil || entry.Ref != "CVE-2021-23337" {
+		t.Errorf("expected lossy entry for rating `method`; actual: %v", report.Lossy)
+	}
+
+	if len(report.Lossy) != 17 {
+		t.Errorf("expected 17 lossy entries; actual: %v: %v", len(report.Lossy), report.Lossy)
+	}
+}
+
+func TestMigrateCdx15To12(t *testing.T) {
+	jsonMap, report := innerTestMigrate(t, TEST_MIGRATE_CDX_1_5_DOWNGRADE, "1.2")
+
+	for _, key := range []string{"compositions", "vulnerabilities", "annotations", "properties"} {
+		if _, found := jsonMap[key]; found {
+			t.Errorf("expected `%s` to be removed", key)
+		}
+	}
+
+	// (legacy) tool "externalReferences" were added in v1.4
+	if findMigrateLossyEntry(report, "metadata.tools[0]", "externalReferences") == nil {
+		t.Errorf("expected lossy entry for tool `externalReferences`; actual: %v", report.Lossy)
+	}
+
+	// The migrated BOM is valid against the (target) v1.2 schema
+	innerTestMigrateSchema(t, jsonMap)
+}
+
+// component "version" is required prior to v1.4 and cannot be invented
+func TestMigrateCdx15To12MissingVersion(t *testing.T) {
+	_, _, err := innerBufferedTestMigrate(t, TEST_MIGRATE_CDX_1_5_MISSING_VERSION, "1.2")
+	if err == nil || !strings.Contains(err.Error(), "components[0].version") {
+		t.Errorf("expected error for missing (required) component `version`; actual: %v", err)
+	}
+
+	// Not required as of v1.4
+	jsonMap, _ := innerTestMigrate(t, TEST_MIGRATE_CDX_1_5_MISSING_VERSION, "1.4")
+	innerTestMigrateSchema(t, jsonMap)
+}
+
+func TestMigrateCdx13To15(t *testing.T) {
+	jsonMap, report := innerTestMigrate(t, TEST_MIGRATE_CDX_1_3_UPGRADE, "1.5")
+
+	// The (legacy) tools array is migrated to the tools object form
+	metadata := jsonMap["metadata"].(map[string]interface{})
+	tools, ok := metadata["tools"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected tools object; actual: %v", metadata["tools"])
+	}
+	tool := tools["components"].([]interface{})[0].(map[string]interface{})
+	if tool["group"] != "ACME" || tool["name"] != "legacy-scanner" || tool["type"] != schema.COMPONENT_TYPE_APPLICATION {
+		t.Errorf("unexpected tool component: %v", tool)
+	}
+
+	// The deprecated "modified" flag is migrated into "pedigree"
+	components := jsonMap["components"].([]interface{})
+	for i, value := range components {
+		component := value.(map[string]interface{})
+		if _, found := component["modified"]; found {
+			t.Errorf("expected `modified` to be removed from component: %v", component)
+		}
+		if _, found := component["pedigree"]; !found {
+			t.Errorf("expected `pedigree` for component[%v]: %v", i, component)
+		}
+	}
+
+	if len(report.Lossy) != 3 {
+		t.Errorf("expected 3 lossy entries; actual: %v", report.Lossy)
+	}
+}
+
+func TestMigrateCdx13To14NoToolsMigration(t *testing.T) {
+	jsonMap, report := innerTestMigrate(t, TEST_MIGRATE_CDX_1_3_UPGRADE, "1.4")
+
+	// The (legacy) tools array is NOT deprecated until v1.5
+	metadata := jsonMap["metadata"].(map[string]interface{})
+	if _, ok := metadata["tools"].([]interface{}); !ok {
+		t.Errorf("expected (legacy) tools array; actual: %v", metadata["tools"])
+	}
+	if len(report.Lossy) != 2 {
+		t.Errorf("expected 2 lossy entries; actual: %v", report.Lossy)
+	}
+}
+
+func TestMigrateCdxInvalidSpecVersion(t *testing.T) {
+	_, _, err := innerBufferedTestMigrate(t, TEST_MIGRATE_CDX_1_3_UPGRADE, "1.1")
+	if err == nil {
+		t.Errorf("expected error for invalid spec. version")
+	}
+}
+
+func TestMigrateSpdxUnsupported(t *testing.T) {
+	_, _, err := innerBufferedTestMigrate(t, TEST_SPDX_2_2_MIN_REQUIRED, "1.4")
+	if _, ok := err.(*schema.UnsupportedFormatError); !ok {
+		t.Errorf("expected `UnsupportedFormatError`; actual: %v", err)
+	}
+}
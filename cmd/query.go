@@ -19,16 +19,16 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/gob"
 	"fmt"
 	"io"
-	"strconv"
+	"sort"
 	"strings"
 
 	"github.com/CycloneDX/sbom-utility/common"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/jmespath/go-jmespath"
 	"github.com/spf13/cobra"
 )
 
@@ -39,27 +39,43 @@ const (
 	FLAG_QUERY_FROM     = "from"
 	FLAG_QUERY_WHERE    = "where"
 	FLAG_QUERY_ORDER_BY = "orderby"
+	FLAG_QUERY_LIMIT    = "limit"
+	FLAG_QUERY_OFFSET   = "offset"
+	FLAG_QUERY_JSONPATH = "jsonpath"
+	FLAG_QUERY_JMESPATH = "jmespath"
 )
 
 // Query command flag help messages
 const (
 	FLAG_QUERY_OUTPUT_FORMAT_HELP = "format output using the specified type"
 	FLAG_QUERY_SELECT_HELP        = "comma-separated list of JSON key names used to select fields within the object designated by the FROM flag" +
-		"\n- the wildcard character `*` can be used to denote inclusion of all found key-values"
+		"\n- the wildcard character `*` can be used to denote inclusion of all found key-values" +
+		"\n- keys may be dot-separated paths (with array selectors) to select nested values (e.g., \"hashes[0].alg\")"
 	FLAG_QUERY_FROM_HELP = "dot-separated list of JSON key names used to dereference into the JSON document" +
-		"\n - if not present, the query assumes document \"root\" as the `--from` object"
-	FLAG_QUERY_WHERE_HELP    = "comma-separated list of key=<regex> of clauses used to filter the SELECT result set"
-	FLAG_QUERY_ORDER_BY_HELP = "key name that appears in the SELECT result set used to order the result records"
+		"\n - if not present, the query assumes document \"root\" as the `--from` object" +
+		"\n - keys of array entries are dereferenced for all entries (e.g., \"components.licenses.license.id\")" +
+		"\n - array selectors: \"key[*]\" (all entries), \"key[n]\" (index), \"key[<where clause>]\" (filter) and \"key[**]\" (recursive)"
+	FLAG_QUERY_WHERE_HELP = "comma-separated list of clauses used to filter the SELECT result set" +
+		"\n - clauses: key=<regex>, key!=<regex>, key<value, key<=value, key>value, key>=value, \"key contains value\", \"key exists\", \"key !exists\"" +
+		"\n - clauses can be combined using AND (or commas), OR and parenthesized groups" +
+		"\n - values of keys that end in \"version\" are compared as semantic versions"
+	FLAG_QUERY_ORDER_BY_HELP = "comma-separated list of key names (each optionally followed by \"asc\" or \"desc\") used to order the result records"
+	FLAG_QUERY_LIMIT_HELP    = "maximum number of result records to return (0 returns all records)"
+	FLAG_QUERY_OFFSET_HELP   = "number of (ordered) result records to skip before returning records"
+	FLAG_QUERY_JSONPATH_HELP = "JSONPath expression (e.g., \"$.components[?(@.name=='qs')].version\") evaluated against the document" +
+		"\n - cannot be combined with other query clauses"
+	FLAG_QUERY_JMESPATH_HELP = "JMESPath expression (e.g., \"components[?name=='qs'].version\") evaluated against the document" +
+		"\n - cannot be combined with other query clauses"
 )
 
 var QUERY_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
-	strings.Join([]string{FORMAT_JSON}, ", ")
+	strings.Join([]string{FORMAT_JSON, FORMAT_TEXT, FORMAT_CSV, FORMAT_MARKDOWN}, ", ")
 
 func NewCommandQuery() *cobra.Command {
 	var command = new(cobra.Command)
 	command.Use = CMD_USAGE_QUERY
 	command.Short = "Query objects and key-values from SBOM (JSON) document"
-	command.Long = "SQL-like query (i.e. SELECT x,y FROM a.b.c WHERE x=<regex>) of JSON objects and specified fields from SBOM (JSON) document."
+	command.Long = "SQL-like query (i.e. SELECT x,y FROM a.b.c WHERE x=<regex> ORDER BY y LIMIT n OFFSET m) of JSON objects and specified fields from SBOM (JSON) document."
 	command.RunE = queryCmdImpl
 	command.PreRunE = func(cmd *cobra.Command, args []string) error {
 		return preRunTestForInputFile(cmd, args)
@@ -73,7 +89,7 @@ func initCommandQuery(command *cobra.Command) {
 	defer getLogger().Exit()
 
 	// Add local flags to command
-	command.PersistentFlags().StringVar(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_OUTPUT_FORMAT, FORMAT_JSON,
+	command.PersistentFlags().StringVar(&utils.GlobalFlags.QueryFlags.OutputFormat, FLAG_OUTPUT_FORMAT, FORMAT_JSON,
 		FLAG_QUERY_OUTPUT_FORMAT_HELP+QUERY_SUPPORTED_FORMATS)
 	command.Flags().StringP(FLAG_QUERY_SELECT, "", common.QUERY_TOKEN_WILDCARD, FLAG_QUERY_SELECT_HELP)
 	// NOTE: TODO: There appears to be a bug in Cobra where the type of the `from`` flag is `--from` (i.e., not string)
@@ -81,6 +97,10 @@ func initCommandQuery(command *cobra.Command) {
 	command.Flags().StringP(FLAG_QUERY_FROM, "", "", FLAG_QUERY_FROM_HELP)
 	command.Flags().StringP(FLAG_QUERY_WHERE, "", "", FLAG_QUERY_WHERE_HELP)
 	command.Flags().StringP(FLAG_QUERY_ORDER_BY, "", "", FLAG_QUERY_ORDER_BY_HELP)
+	command.Flags().IntP(FLAG_QUERY_LIMIT, "", 0, FLAG_QUERY_LIMIT_HELP)
+	command.Flags().IntP(FLAG_QUERY_OFFSET, "", 0, FLAG_QUERY_OFFSET_HELP)
+	command.Flags().StringP(FLAG_QUERY_JSONPATH, "", "", FLAG_QUERY_JSONPATH_HELP)
+	command.Flags().StringP(FLAG_QUERY_JMESPATH, "", "", FLAG_QUERY_JMESPATH_HELP)
 }
 
 // TODO: Support the --output <file> flag
@@ -127,6 +147,16 @@ func readQueryFlags(cmd *cobra.Command) (qr *common.QueryRequest, err error) {
 	getLogger().Enter()
 	defer getLogger().Exit()
 
+	// Read '--jsonpath' and '--jmespath' flags first as they replace all other query clauses
+	jsonPathExpression, errGetString := cmd.Flags().GetString(FLAG_QUERY_JSONPATH)
+	getLogger().Tracef("Query: '%s' flag: %s, err: %s", FLAG_QUERY_JSONPATH, jsonPathExpression, errGetString)
+	jmesPathExpression, errGetString := cmd.Flags().GetString(FLAG_QUERY_JMESPATH)
+	getLogger().Tracef("Query: '%s' flag: %s, err: %s", FLAG_QUERY_JMESPATH, jmesPathExpression, errGetString)
+
+	if jsonPathExpression != "" || jmesPathExpression != "" {
+		return readQueryExpressionFlags(cmd, jsonPathExpression, jmesPathExpression)
+	}
+
 	// Read '--select' flag second as it is the next highly likely field (used to
 	// reduce the result set from querying the "FROM" JSON object)
 	rawSelect, errGetString := cmd.Flags().GetString(FLAG_QUERY_SELECT)
@@ -142,15 +172,59 @@ func readQueryFlags(cmd *cobra.Command) (qr *common.QueryRequest, err error) {
 	rawWhere, errGetString := cmd.Flags().GetString(FLAG_QUERY_WHERE)
 	getLogger().Tracef("Query: '%s' flag: %s, err: %s", FLAG_QUERY_WHERE, rawWhere, errGetString)
 
-	// TODO: Read '--orderby' flag to be used to order by field (keys) data in the "output" phase
-	//rawOrderBy, errGetString := cmd.Flags().GetString(FLAG_QUERY_ORDER_BY)
-	//getLogger().Tracef("Query: '%s' flag: %s, err: %s", FLAG_QUERY_ORDER_BY, rawOrderBy, errGetString)
+	// Read '--orderby' flag to be used to order by field (keys) data in the "output" phase
+	rawOrderBy, errGetString := cmd.Flags().GetString(FLAG_QUERY_ORDER_BY)
+	getLogger().Tracef("Query: '%s' flag: %s, err: %s", FLAG_QUERY_ORDER_BY, rawOrderBy, errGetString)
+
+	// Read '--limit' and '--offset' flags used to "page" through the ordered results
+	limit, errGetInt := cmd.Flags().GetInt(FLAG_QUERY_LIMIT)
+	getLogger().Tracef("Query: '%s' flag: %v, err: %s", FLAG_QUERY_LIMIT, limit, errGetInt)
+	offset, errGetInt := cmd.Flags().GetInt(FLAG_QUERY_OFFSET)
+	getLogger().Tracef("Query: '%s' flag: %v, err: %s", FLAG_QUERY_OFFSET, offset, errGetInt)
 
-	qr, err = common.NewQueryRequestSelectFromWhere(rawSelect, rawFrom, rawWhere)
+	return NewQueryRequestFromClauses(rawSelect, rawFrom, rawWhere, rawOrderBy, limit, offset)
+}
+
+// Create a query request from the (raw) values of all its clauses (e.g., as read from flags)
+func NewQueryRequestFromClauses(rawSelect string, rawFrom string, rawWhere string, rawOrderBy string, limit int, offset int) (qr *common.QueryRequest, err error) {
+	if qr, err = common.NewQueryRequestSelectFromWhere(rawSelect, rawFrom, rawWhere); err != nil {
+		return
+	}
+
+	if _, err = qr.SetRawOrderByKeys(rawOrderBy); err != nil {
+		return
+	}
+
+	if err = qr.SetLimit(limit); err != nil {
+		return
+	}
 
+	err = qr.SetOffset(offset)
 	return
 }
 
+// Create a JSONPath (or JMESPath) query request assuring no other query clauses were provided
+func readQueryExpressionFlags(cmd *cobra.Command, jsonPathExpression string, jmesPathExpression string) (qr *common.QueryRequest, err error) {
+	if jsonPathExpression != "" && jmesPathExpression != "" {
+		err = common.NewQueryConflictingClausesError(nil,
+			fmt.Sprintf("--%s, --%s", FLAG_QUERY_JSONPATH, FLAG_QUERY_JMESPATH))
+		return
+	}
+
+	for _, flag := range []string{FLAG_QUERY_SELECT, FLAG_QUERY_FROM, FLAG_QUERY_WHERE,
+		FLAG_QUERY_ORDER_BY, FLAG_QUERY_LIMIT, FLAG_QUERY_OFFSET} {
+		if cmd.Flags().Changed(flag) {
+			err = common.NewQueryConflictingClausesError(nil, fmt.Sprintf("--%s", flag))
+			return
+		}
+	}
+
+	if jsonPathExpression != "" {
+		return common.NewQueryRequestJSONPath(jsonPathExpression), nil
+	}
+	return common.NewQueryRequestJMESPath(jmesPathExpression), nil
+}
+
 func processQueryResults(err error) {
 	if err != nil {
 		getLogger().Error(err)
@@ -208,10 +282,26 @@ func Query(writer io.Writer, request *common.QueryRequest, response *common.Quer
 	}
 
 	// Use the selected output device (e.g., default stdout or the specified --output-file)
-	// Note: JSON data files MUST ends in a newline as this is a POSIX standard
-	// which is already accounted for by the JSON encoder.
-	_, err = utils.WriteAnyAsEncodedJSONInt(writer, resultJson,
-		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	// Note: tabular formats "flatten" the results into columns
+	format := utils.GlobalFlags.QueryFlags.OutputFormat
+	getLogger().Infof("Outputting query results (`%s` format)...", format)
+	switch format {
+	case FORMAT_TEXT:
+		err = DisplayQueryResultsText(writer, NewQueryResultTable(request, resultJson))
+	case FORMAT_CSV:
+		err = DisplayQueryResultsCSV(writer, NewQueryResultTable(request, resultJson))
+	case FORMAT_MARKDOWN:
+		err = DisplayQueryResultsMarkdown(writer, NewQueryResultTable(request, resultJson))
+	default:
+		if format != FORMAT_JSON && format != "" {
+			getLogger().Warningf("Query not supported for `%s` format; defaulting to `%s` format...",
+				format, FORMAT_JSON)
+		}
+		// Note: JSON data files MUST ends in a newline as this is a POSIX standard
+		// which is already accounted for by the JSON encoder.
+		_, err = utils.WriteAnyAsEncodedJSONInt(writer, resultJson,
+			utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	}
 
 	// NOTE: previously, query results defaulted to an indent of 2 spaces which could be done
 	// just for this command as follows:
@@ -222,6 +312,14 @@ func Query(writer io.Writer, request *common.QueryRequest, response *common.Quer
 }
 
 func QueryJSONMap(jsonMap map[string]interface{}, request *common.QueryRequest) (resultJson interface{}, err error) {
+	// Evaluate JSONPath or JMESPath expressions (if provided) against the entire document
+	if request.GetJSONPathExpression() != "" {
+		return queryJSONPath(jsonMap, request)
+	}
+	if request.GetJMESPathExpression() != "" {
+		return queryJMESPath(jsonMap, request)
+	}
+
 	// Query set of FROM objects
 	// if a FROM select object is not provided, assume "root" search
 	if len(request.GetFromKeys()) == 0 {
@@ -272,53 +370,80 @@ func QueryJSONMap(jsonMap map[string]interface{}, request *common.QueryRequest)
 	return
 }
 
-func findFromObject(request *common.QueryRequest, jsonMap map[string]interface{}) (pResults interface{}, err error) {
+// Note: JSONPath (https://goessner.net/articles/JsonPath/) evaluation errors include
+// expressions that reference keys not found in the document
+func queryJSONPath(jsonMap map[string]interface{}, request *common.QueryRequest) (resultJson interface{}, err error) {
 	getLogger().Enter()
 	defer getLogger().Exit()
 
-	// initialize local map pointer and its return value to starting JSON map
-	var tempMap map[string]interface{} = jsonMap
-	pResults = jsonMap
+	var errEval error
+	if resultJson, errEval = jsonpath.Get(request.GetJSONPathExpression(), jsonMap); errEval != nil {
+		err = common.NewQueryJSONPathError(request, errEval.Error())
+	}
+	return
+}
+
+// Note: JMESPath (https://jmespath.org/) expressions that reference keys not found in the
+// document return a null result (not an error)
+func queryJMESPath(jsonMap map[string]interface{}, request *common.QueryRequest) (resultJson interface{}, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var errEval error
+	if resultJson, errEval = jmespath.Search(request.GetJMESPathExpression(), jsonMap); errEval != nil {
+		err = common.NewQueryJMESPathError(request, errEval.Error())
+	}
+	return
+}
+
+func findFromObject(request *common.QueryRequest, jsonMap map[string]interface{}) (pResults interface{}, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
 
 	getLogger().Tracef("Finding JSON object using path key(s): %v\n", request.GetFromKeys())
 
-	for i, key := range request.GetFromKeys() {
-		pResults = tempMap[key]
+	var collection bool
+	pResults, collection, err = findPathValues(request, jsonMap, request.GetFromPathSegments(), true)
+	if err != nil || collection {
+		return
+	}
 
-		// if we find a nil value, this means we failed to find the object
-		if pResults == nil {
-			err = common.NewQueryFromClauseError(
-				request,
-				fmt.Sprintf("%s: (%s)", MSG_QUERY_ERROR_FROM_KEY_NOT_FOUND, key))
-			return
-		}
+	// A singleton FROM object MUST be either a JSON map or slice
+	switch t := pResults.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		getLogger().Debugf("Invalid datatype of query: path: %v (%T)", request.GetFromKeys(), t)
+		err = common.NewQueryFromClauseError(request,
+			fmt.Sprintf("%s: %T", MSG_QUERY_INVALID_DATATYPE, t))
+	}
+	return
+}
 
-		switch t := pResults.(type) {
-		case map[string]interface{}:
-			// If the resulting value is indeed another map type, we expect for a Json Map
-			// we preserve that pointer for the next iteration
-			tempMap = pResults.(map[string]interface{})
-		case []interface{}:
-			// TODO: We only support a slice (i.e., []interface{}) as the last selector
-			// in theory, we could support arrays (perhaps array notation) in the FROM clause
-			// at any point (e.g., "metadata.component.properties[0]").
-			// TODO: we should still be able to support implicit arrays as well.
-
-			// We no longer have a map to dereference into
-			// So if there are more keys left as selectors it is an error
-			if len(request.GetFromKeys()) > i+1 {
-				err = common.NewQueryFromClauseError(request,
-					fmt.Sprintf("%s: (%s)", MSG_QUERY_ERROR_FROM_KEY_SLICE_DEREFERENCE, key))
-				return
-			}
-		default:
-			getLogger().Debugf("Invalid datatype of query: key: %s (%t)", key, t)
-			err = common.NewQueryFromClauseError(request,
-				fmt.Sprintf("%s: %T", MSG_QUERY_INVALID_DATATYPE, t))
-			return
+// Find the value(s) at the path (segments) starting from the object.
+// If any segment dereferences a key of an array's entries (implicitly), or applies
+// a wildcard, filter or recursive array selector, the result is a "collection" (slice)
+// of all the values found; otherwise, the result is the single value found.
+// If strict, errors are returned for keys (or indices) not found in singleton objects.
+func findPathValues(request *common.QueryRequest, object interface{}, segments []common.QueryPathSegment, strict bool) (result interface{}, collection bool, err error) {
+	var nodes []common.QueryPathNode
+	nodes, collection, err = common.FindQueryPathNodes(
+		[]common.QueryPathNode{common.NewQueryPathNode(object)},
+		segments,
+		common.QueryPathOptions{Strict: strict})
+	if err != nil {
+		err = common.NewQueryFromClauseError(request, err.Error())
+		return
+	}
+
+	if !collection {
+		if len(nodes) > 0 {
+			result = nodes[0].Value
 		}
+		return
 	}
-	return
+
+	// flatten array values found for the last segment into the resulting collection
+	return common.QueryPathNodeValues(common.FlattenQueryPathNodes(nodes)), collection, nil
 }
 
 // NOTE: it is the caller's responsibility to convert to other output formats
@@ -347,7 +472,8 @@ func selectFieldsFromMap(request *common.QueryRequest, jsonMap map[string]interf
 
 	// Copy selected fields into output map
 	// NOTE: wildcard "short-circuit" returns original map above
-	for _, fieldKey := range selectors {
+	selectPaths := request.GetSelectPaths()
+	for i, fieldKey := range selectors {
 		// validate wildcard not used with other fields; if so, that is a conflict
 		if fieldKey == common.QUERY_TOKEN_WILDCARD {
 			err = common.NewQuerySelectClauseError(
@@ -357,6 +483,12 @@ func selectFieldsFromMap(request *common.QueryRequest, jsonMap map[string]interf
 			return
 		}
 
+		// Select (nested) values using paths (e.g., "hashes[0].alg") as the key
+		if i < len(selectPaths) && !common.IsSimpleQueryPath(selectPaths[i]) {
+			mapSelectedFields[fieldKey], _, _ = findPathValues(request, jsonMap, selectPaths[i], false)
+			continue
+		}
+
 		mapSelectedFields[fieldKey] = jsonMap[fieldKey]
 	}
 
@@ -377,15 +509,23 @@ func selectFieldsFromSlice(request *common.QueryRequest, jsonSlice []interface{}
 	}
 
 	// Add only those objects whose field values match provided WhereFilters
-	// and add them to a new "result" slice for further SELECT operations.
-	// If no WhereFilters were provided, then add the object to the "result" slice.
+	// to a new "matched" slice for further ORDERBY and SELECT operations.
+	// If no WhereFilters were provided, then add the object to the "matched" slice.
+	var matchedObjects []interface{}
 	var match bool
 	for _, iObject := range jsonSlice {
 		mapObject, ok := iObject.(map[string]interface{})
 
+		// Note: non-object values (e.g., from the path "components.licenses.license.id")
+		// can only be returned "as-is" (i.e., without WHERE or SELECT clauses)
 		if !ok {
-			err = getLogger().Errorf("Unable to convert object: %v, to map[string]interface{}", iObject)
-			return
+			if whereFilters != nil || !isWildcardSelect(request) {
+				err = common.NewQuerySelectClauseError(request,
+					fmt.Sprintf("%s: %T", MSG_QUERY_ERROR_NON_OBJECT_RESULTS, iObject))
+				return
+			}
+			matchedObjects = append(matchedObjects, iObject)
+			continue
 		}
 
 		// If where filters exist, apply them to the map object
@@ -396,71 +536,88 @@ func selectFieldsFromSlice(request *common.QueryRequest, jsonSlice []interface{}
 			}
 		}
 
-		// If no WHERE filters were provided OR we matched all the regex comparisons,
-		// against the original map object, then add a new map object with only the
-		// SELECT(ed) fields requested.
 		if whereFilters == nil || match {
-			if mapObject, err = selectFieldsFromMap(request, mapObject); err != nil {
+			matchedObjects = append(matchedObjects, mapObject)
+		}
+	}
+
+	// Note: ordering is performed against the original map objects as the
+	// ORDERBY keys need not be part of the SELECT(ed) fields
+	orderQueryResults(matchedObjects, request.GetOrderByKeys())
+	matchedObjects = pageQueryResults(matchedObjects, request.GetOffset(), request.GetLimit())
+
+	// For each matched (and ordered) object, add a new map object with only
+	// the SELECT(ed) fields requested.
+	for _, iObject := range matchedObjects {
+		if mapObject, ok := iObject.(map[string]interface{}); ok {
+			// Reduce result object to only the requested SELECT fields
+			if iObject, err = selectFieldsFromMap(request, mapObject); err != nil {
 				return
 			}
-			// Reduce result object to only the requested SELECT fields
-			sliceSelectedFields = append(sliceSelectedFields, mapObject)
 		}
+		sliceSelectedFields = append(sliceSelectedFields, iObject)
 	}
 
 	return
 }
 
-// Note: Golang supports the RE2 regular exp. engine which does not support many
-// features such as lookahead, lookbehind, etc.
-// See: https://en.wikipedia.org/wiki/Comparison_of_regular_expression_engines
-func whereFilterMatch(mapObject map[string]interface{}, whereFilters []common.WhereFilter) (match bool, err error) {
-	var buf bytes.Buffer
-	var key string
-
-	// create a byte encoder
-	enc := gob.NewEncoder(&buf)
-
-	for _, filter := range whereFilters {
-
-		key = filter.Key
-		value, present := mapObject[key]
-		if !present {
-			match = false
-			err = getLogger().Errorf("key `%s` not found in object map", key)
-			break
-		}
-
-		// Reset the encoder'a byte buffer on each iteration and
-		// convert the value (an interface{}) to []byte we can use on regex. eval.
-		buf.Reset()
+// Return true if the SELECT clause is empty or the wildcard (i.e., select all fields)
+func isWildcardSelect(request *common.QueryRequest) bool {
+	selectors := request.GetSelectKeys()
+	return len(selectors) == 0 ||
+		(len(selectors) == 1 && selectors[0] == common.QUERY_TOKEN_WILDCARD)
+}
 
-		// Do not encode nil pointer values; replace with empty string
-		if value == nil {
-			value = ""
-		}
+// Note: the matching logic (including regex., comparison and boolean operands)
+// is shared with other commands that support the "--where" flag (see common.WhereFilter)
+func whereFilterMatch(mapObject map[string]interface{}, whereFilters []common.WhereFilter) (match bool, err error) {
+	match = common.WhereFiltersMatch(mapObject, whereFilters)
+	return
+}
 
-		// Handle non-string data types in the map by converting them to string
-		switch data := value.(type) {
-		case bool:
-			value = strconv.FormatBool(data)
-		case int:
-			value = strconv.Itoa(data)
-		}
+// Stable sort of the objects by the values of each ORDERBY key (in turn);
+// objects without a value for a key are always ordered last.
+func orderQueryResults(objects []interface{}, orderByKeys []common.OrderByKey) {
+	if len(orderByKeys) == 0 {
+		return
+	}
 
-		err = enc.Encode(value)
+	sort.SliceStable(objects, func(i, j int) bool {
+		// Note: non-object values have no keys (i.e., are ordered last)
+		object1, _ := objects[i].(map[string]interface{})
+		object2, _ := objects[j].(map[string]interface{})
+		for _, orderByKey := range orderByKeys {
+			value1, present1 := object1[orderByKey.Key]
+			value2, present2 := object2[orderByKey.Key]
+			present1, present2 = present1 && value1 != nil, present2 && value2 != nil
+
+			if !present1 || !present2 {
+				if present1 != present2 {
+					return present1
+				}
+				continue
+			}
 
-		if err != nil {
-			err = getLogger().Errorf("Unable to convert value: `%v`, to []byte", value)
-			return
+			result := common.CompareQueryValues(orderByKey.Key, value1, value2)
+			if orderByKey.Descending {
+				result = -result
+			}
+			if result != 0 {
+				return result < 0
+			}
 		}
+		return false
+	})
+}
 
-		// Test that the field value matches the regex supplied in the current filter
-		// Note: the regex compilation is performed during command param. processing
-		if match = filter.ValueRegEx.Match(buf.Bytes()); !match {
-			break
-		}
+// Skip "offset" objects and return (at most) "limit" objects; a limit of 0 returns all
+func pageQueryResults(objects []interface{}, offset int, limit int) []interface{} {
+	if offset >= len(objects) {
+		return nil
 	}
-
-	return
+	objects = objects[offset:]
+	if limit > 0 && limit < len(objects) {
+		objects = objects[:limit]
+	}
+	return objects
 }
@@ -0,0 +1,306 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/common"
+)
+
+// Query (tabular) report column names
+const (
+	QUERY_REPORT_COLUMN_VALUE     = "value" // column for non-object (i.e., scalar) results
+	QUERY_REPORT_COLUMN_KEY_SEP   = "."     // separates the key names of nested objects
+	QUERY_REPORT_ARRAY_VALUES_SEP = ","     // separates values of arrays when wrapping text
+)
+
+// Query command informational messages
+const (
+	MSG_OUTPUT_NO_QUERY_RESULTS_FOUND = "[WARN] no matching results found for query"
+)
+
+// A "flattened" query result where each row maps column names (i.e., dot-separated
+// keys of nested objects) to either a string or, for values from arrays, a []interface{}
+// of strings.
+type QueryResultTable struct {
+	FormatData []ColumnFormatData
+	Rows       []map[string]interface{}
+}
+
+// Flatten the query result (i.e., an object or an array of objects or values) into a table
+// Note: if explicit SELECT keys were provided, columns are grouped in the order of the keys;
+// otherwise, columns appear in (key) sorted order of first appearance.
+func NewQueryResultTable(request *common.QueryRequest, result interface{}) (table *QueryResultTable) {
+	table = new(QueryResultTable)
+
+	var entries []interface{}
+	switch typedResult := result.(type) {
+	case nil:
+	case []interface{}:
+		entries = typedResult
+	default:
+		entries = []interface{}{typedResult}
+	}
+
+	var selectKeys []string
+	if request.GetJSONPathExpression() == "" && request.GetJMESPathExpression() == "" &&
+		!isWildcardSelect(request) {
+		selectKeys = request.GetSelectKeys()
+	}
+
+	// columns (i.e., flattened keys) grouped by the (SELECT) key they were derived from
+	groups := make(map[string][]string)
+	var groupKeys []string
+	seen := make(map[string]bool)
+	addColumn := func(group string, column string) {
+		if _, found := groups[group]; !found {
+			groupKeys = append(groupKeys, group)
+			groups[group] = nil
+		}
+		if column != "" && !seen[column] {
+			seen[column] = true
+			groups[group] = append(groups[group], column)
+		}
+	}
+
+	for _, key := range selectKeys {
+		addColumn(key, "")
+	}
+
+	for _, entry := range entries {
+		row := make(map[string]interface{})
+		mapEntry, isMap := entry.(map[string]interface{})
+
+		switch {
+		case isMap && len(selectKeys) > 0:
+			for _, key := range selectKeys {
+				for _, column := range flattenQueryValue(row, key, mapEntry[key], false) {
+					addColumn(key, column)
+				}
+			}
+		case isMap:
+			for _, column := range flattenQueryValue(row, "", mapEntry, false) {
+				addColumn("", column)
+			}
+		default:
+			for _, column := range flattenQueryValue(row, QUERY_REPORT_COLUMN_VALUE, entry, false) {
+				addColumn("", column)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	// A SELECT key with no (non-null) values still gets a column of its own
+	for _, group := range groupKeys {
+		columns := groups[group]
+		if len(columns) == 0 && group != "" {
+			columns = []string{group}
+		}
+		for _, column := range columns {
+			table.FormatData = append(table.FormatData, ColumnFormatData{
+				column, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, REPORT_REPLACE_LINE_FEEDS_TRUE})
+		}
+	}
+
+	// Assure every row has a value (or nil) for every column
+	for _, row := range table.Rows {
+		for _, column := range table.FormatData {
+			if _, found := row[column.DataKey]; !found {
+				row[column.DataKey] = nil
+			}
+		}
+	}
+	return
+}
+
+// Recursively add the (JSON) value to the row using the key as the column name; the keys of
+// nested objects are appended to the column name and the values of arrays are accumulated.
+// Returns the column names (in sorted key order) values were added to.
+func flattenQueryValue(row map[string]interface{}, key string, value interface{}, inArray bool) (columns []string) {
+	switch typedValue := value.(type) {
+	case nil:
+		return
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for mapKey := range typedValue {
+			keys = append(keys, mapKey)
+		}
+		sort.Strings(keys)
+		for _, mapKey := range keys {
+			columnKey := mapKey
+			if key != "" {
+				columnKey = key + QUERY_REPORT_COLUMN_KEY_SEP + mapKey
+			}
+			columns = append(columns, flattenQueryValue(row, columnKey, typedValue[mapKey], inArray)...)
+		}
+		return
+	case []interface{}:
+		if key == "" {
+			key = QUERY_REPORT_COLUMN_VALUE
+		}
+		for _, entry := range typedValue {
+			columns = append(columns, flattenQueryValue(row, key, entry, true)...)
+		}
+		return
+	}
+
+	stringValue := common.QueryValueString(value)
+	existing, found := row[key]
+	switch typedExisting := existing.(type) {
+	case string:
+		row[key] = []interface{}{typedExisting, stringValue}
+	case []interface{}:
+		row[key] = append(typedExisting, stringValue)
+	default:
+		if found || inArray {
+			row[key] = []interface{}{stringValue}
+		} else {
+			row[key] = stringValue
+		}
+	}
+	return []string{key}
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayQueryResultsText(writer io.Writer, table *QueryResultTable) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	// create title row and underline row
+	titles, underlines := prepareReportTitleData(table.FormatData, false)
+	if len(titles) > 0 {
+		fmt.Fprintf(w, "%s\n", strings.Join(titles, "\t"))
+		fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+	}
+
+	// Emit no results warning into output
+	if len(table.Rows) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_QUERY_RESULTS_FOUND)
+		return
+	}
+
+	// Emit row data; wrapping the values of arrays onto multiple lines
+	var lines [][]string
+	for _, row := range table.Rows {
+		columns := make([]interface{}, 0, len(table.FormatData))
+		for _, columnData := range table.FormatData {
+			switch typedValue := row[columnData.DataKey].(type) {
+			case string:
+				columns = append(columns, strings.ReplaceAll(typedValue, "\n", " "))
+			case []interface{}:
+				values := make([]string, 0, len(typedValue))
+				for _, value := range typedValue {
+					values = append(values, strings.ReplaceAll(value.(string), "\n", " "))
+				}
+				columns = append(columns, values)
+			default:
+				columns = append(columns, nil)
+			}
+		}
+
+		if lines, err = wrapTableRowText(DEFAULT_COLUMN_TRUNCATE_LENGTH, QUERY_REPORT_ARRAY_VALUES_SEP, columns...); err != nil {
+			return
+		}
+		for _, line := range lines {
+			fmt.Fprintf(w, "%s\n", strings.Join(line, "\t"))
+		}
+	}
+	return
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayQueryResultsCSV(writer io.Writer, table *QueryResultTable) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	// Create title row data as []string
+	titles, _ := prepareReportTitleData(table.FormatData, false)
+	if len(titles) > 0 {
+		if err = w.Write(titles); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", titles, err)
+		}
+	}
+
+	// Emit no results warning into output
+	if len(table.Rows) == 0 {
+		currentRow := []string{MSG_OUTPUT_NO_QUERY_RESULTS_FOUND}
+		if err = w.Write(currentRow); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", currentRow, err)
+		}
+		return
+	}
+
+	// Emit row data
+	var line []string
+	for _, row := range table.Rows {
+		if line, err = prepareReportLineData(row, table.FormatData, false); err != nil {
+			return
+		}
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
+		}
+	}
+	return
+}
+
+// TODO: Add a --no-title flag to skip title output
+func DisplayQueryResultsMarkdown(writer io.Writer, table *QueryResultTable) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// Create title row data as []string
+	titles, _ := prepareReportTitleData(table.FormatData, false)
+	if len(titles) > 0 {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(titles))
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(titles)))
+	}
+
+	// Emit no results warning into output
+	if len(table.Rows) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_QUERY_RESULTS_FOUND)
+		return
+	}
+
+	// Emit row data
+	var line []string
+	for _, row := range table.Rows {
+		if line, err = prepareReportLineData(row, table.FormatData, false); err != nil {
+			return
+		}
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(line))
+	}
+	return
+}
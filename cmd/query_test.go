@@ -26,6 +26,9 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/CycloneDX/sbom-utility/common"
@@ -33,6 +36,10 @@ import (
 	"github.com/CycloneDX/sbom-utility/utils"
 )
 
+const (
+	TEST_QUERY_CDX_1_5_COMPONENTS = "test/query/cdx-1-5-query-components.json"
+)
+
 // -------------------------------------------
 // test helper functions
 // -------------------------------------------
@@ -88,7 +95,7 @@ func innerBufferedTestQuery(t *testing.T, testInfo *CommonTestInfo, queryRequest
 	// The command looks for the input & output filename in global flags struct
 	utils.GlobalFlags.PersistentFlags.InputFile = testInfo.InputFile
 	utils.GlobalFlags.PersistentFlags.OutputFile = testInfo.OutputFile
-	utils.GlobalFlags.PersistentFlags.OutputFormat = testInfo.OutputFormat
+	utils.GlobalFlags.QueryFlags.OutputFormat = testInfo.OutputFormat
 	utils.GlobalFlags.PersistentFlags.OutputIndent = testInfo.OutputIndent
 	var outputWriter io.Writer
 	var outputFile *os.File
@@ -350,14 +357,45 @@ func TestQueryFailCdx14MetadataComponentInvalidSelectClause(t *testing.T) {
 	EvaluateErrorAndKeyPhrases(t, err, expectedErrorStrings)
 }
 
-func TestQueryFailCdx14InvalidFromClauseWithArray(t *testing.T) {
+func TestQueryCdx14FromClauseArrayKeyDereference(t *testing.T) {
 	cti := NewCommonTestInfoBasic(TEST_CDX_1_4_MATURITY_EXAMPLE_1_BASE)
 	request, _ := common.NewQueryRequestSelectFrom(
 		common.QUERY_TOKEN_WILDCARD,
 		"metadata.properties.name")
+	// Note: keys are dereferenced within each entry of the (properties) array
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := []interface{}{"urn:example.com:classification", "urn:example.com:disclaimer"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("invalid query result: expected: %v, actual: %v", expected, result)
+	}
+}
+
+func TestQueryFailCdx14InvalidFromClauseIndexNonArray(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_CDX_1_4_MATURITY_EXAMPLE_1_BASE)
+	request, _ := common.NewQueryRequestSelectFrom(
+		common.QUERY_TOKEN_WILDCARD,
+		"metadata.component[0]")
+	expectedErrorStrings := []string{
+		common.MSG_QUERY_INVALID_FROM_CLAUSE,
+		MSG_QUERY_ERROR_FROM_KEY_NOT_ARRAY,
+	}
+	// Expect a QueryError
+	_, err := innerQueryError(t, cti, request, &common.QueryError{})
+	// Assure we received an error with the expected key phrases
+	EvaluateErrorAndKeyPhrases(t, err, expectedErrorStrings)
+}
+
+func TestQueryFailCdx14InvalidFromClauseIndexOutOfRange(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_CDX_1_4_MATURITY_EXAMPLE_1_BASE)
+	request, _ := common.NewQueryRequestSelectFrom(
+		common.QUERY_TOKEN_WILDCARD,
+		"metadata.properties[2]")
 	expectedErrorStrings := []string{
 		common.MSG_QUERY_INVALID_FROM_CLAUSE,
-		MSG_QUERY_ERROR_FROM_KEY_SLICE_DEREFERENCE,
+		MSG_QUERY_ERROR_FROM_INDEX_OUT_OF_RANGE,
 	}
 	// Expect a QueryError
 	_, err := innerQueryError(t, cti, request, &common.QueryError{})
@@ -365,6 +403,15 @@ func TestQueryFailCdx14InvalidFromClauseWithArray(t *testing.T) {
 	EvaluateErrorAndKeyPhrases(t, err, expectedErrorStrings)
 }
 
+func TestQueryFailInvalidFromClauseSyntax(t *testing.T) {
+	for _, rawFrom := range []string{"components[", "components[0", "components[]", "[0]", "components[0]x", "metadata..component"} {
+		_, err := common.NewQueryRequestSelectFrom(common.QUERY_TOKEN_WILDCARD, rawFrom)
+		if !ErrorTypesMatch(err, &common.QueryError{}) {
+			t.Errorf("from: `%s`: expected error type: `%T`, actual type: `%T`", rawFrom, &common.QueryError{}, err)
+		}
+	}
+}
+
 // ----------------------------------------
 // WHERE clause tests
 // ----------------------------------------
@@ -541,3 +588,444 @@ func TestQueryCdx14MetadataComponentIndentedFileWrite(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// Query the test components (slice) and verify the values of a single (selected)
+// key of the results, in order.
+func innerQueryComponentsValues(t *testing.T, rawWhere string, rawOrderBy string, limit int, offset int, key string, expectedValues []string) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request, err := common.NewQueryRequestSelectFromWhere(key, "components", rawWhere)
+	if err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	if _, err = request.SetRawOrderByKeys(rawOrderBy); err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	if err = request.SetLimit(limit); err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	if err = request.SetOffset(offset); err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var actualValues []string
+	if result != nil {
+		for _, object := range result.([]interface{}) {
+			actualValues = append(actualValues, common.QueryValueString(object.(map[string]interface{})[key]))
+		}
+	}
+
+	if strings.Join(actualValues, ",") != strings.Join(expectedValues, ",") {
+		t.Errorf("invalid query result: where: `%s`, orderby: `%s`: expected: %v, actual: %v",
+			rawWhere, rawOrderBy, expectedValues, actualValues)
+	}
+}
+
+func TestQueryWhereNotEquals(t *testing.T) {
+	innerQueryComponentsValues(t, "type!=library", "", 0, 0, "name",
+		[]string{"acme-framework"})
+}
+
+func TestQueryWhereRegexAnchors(t *testing.T) {
+	innerQueryComponentsValues(t, "name=^b", "", 0, 0, "name",
+		[]string{"body-parser", "bytes"})
+}
+
+func TestQueryWhereVersionLessThan(t *testing.T) {
+	// Note: versions are compared as semver (e.g., 1.10.0 > 1.2.0 and 2.0.0-rc.1 < 2.0.0)
+	innerQueryComponentsValues(t, "version<2.0", "", 0, 0, "name",
+		[]string{"body-parser", "bytes", "debug"})
+}
+
+func TestQueryWhereVersionGreaterThanEqual(t *testing.T) {
+	innerQueryComponentsValues(t, "version >= 2.0.0", "", 0, 0, "name",
+		[]string{"qs", "depd", "acme-framework"})
+}
+
+func TestQueryWhereVersionRange(t *testing.T) {
+	innerQueryComponentsValues(t, "version>1.2.0,version<=2.0.0", "", 0, 0, "name",
+		[]string{"body-parser", "debug", "depd"})
+}
+
+func TestQueryWhereContains(t *testing.T) {
+	innerQueryComponentsValues(t, "description contains pars", "", 0, 0, "name",
+		[]string{"qs", "body-parser"})
+}
+
+func TestQueryWhereContainsSliceEntry(t *testing.T) {
+	innerQueryComponentsValues(t, "licenses contains MIT", "", 0, 0, "name",
+		[]string{"bytes", "depd"})
+}
+
+func TestQueryWhereExists(t *testing.T) {
+	innerQueryComponentsValues(t, "description exists", "", 0, 0, "name",
+		[]string{"qs", "body-parser", "acme-framework"})
+}
+
+func TestQueryWhereVersionAndNotExists(t *testing.T) {
+	innerQueryComponentsValues(t, "version<2.0 AND licenses !exists", "", 0, 0, "name",
+		[]string{"body-parser", "debug"})
+}
+
+func TestQueryWhereOr(t *testing.T) {
+	innerQueryComponentsValues(t, "name=qs OR name=bytes", "", 0, 0, "name",
+		[]string{"qs", "bytes"})
+}
+
+func TestQueryWhereOrGroups(t *testing.T) {
+	innerQueryComponentsValues(t, "(type=framework OR name=^d),licenses exists", "", 0, 0, "name",
+		[]string{"depd"})
+	innerQueryComponentsValues(t, "(version<1.5 AND licenses exists) OR (type=framework AND version>3)", "", 0, 0, "name",
+		[]string{"bytes", "acme-framework"})
+}
+
+func TestQueryWhereRegexWithOrKeyword(t *testing.T) {
+	// Note: "OR" is only a boolean keyword if it is followed by another predicate
+	innerQueryComponentsValues(t, "licenses=Apache-2.0 OR MIT", "", 0, 0, "name",
+		[]string{"depd"})
+}
+
+func TestQueryOrderByVersion(t *testing.T) {
+	innerQueryComponentsValues(t, "", "version", 0, 0, "version",
+		[]string{"1.2.0", "1.10.0", "2.0.0-rc.1", "2.0.0", "v3.1", "6.9.7"})
+	innerQueryComponentsValues(t, "", "version desc", 0, 0, "version",
+		[]string{"6.9.7", "v3.1", "2.0.0", "2.0.0-rc.1", "1.10.0", "1.2.0"})
+}
+
+func TestQueryOrderByMultipleKeys(t *testing.T) {
+	// Note: objects without a value for an ORDERBY key are ordered last
+	innerQueryComponentsValues(t, "", "description:desc,name", 0, 0, "name",
+		[]string{"body-parser", "acme-framework", "qs", "bytes", "debug", "depd"})
+}
+
+func TestQueryOrderByLimitOffset(t *testing.T) {
+	innerQueryComponentsValues(t, "type=library", "name", 2, 0, "name",
+		[]string{"body-parser", "bytes"})
+	innerQueryComponentsValues(t, "type=library", "name", 2, 2, "name",
+		[]string{"debug", "depd"})
+	innerQueryComponentsValues(t, "type=library", "name", 0, 4, "name",
+		[]string{"qs"})
+	innerQueryComponentsValues(t, "type=library", "name", 0, 10, "name",
+		nil)
+}
+
+func TestQueryInvalidOrderByClause(t *testing.T) {
+	request, _ := common.NewQueryRequestSelectWildcardFrom("components")
+	_, err := request.SetRawOrderByKeys("name sideways")
+	if !ErrorTypesMatch(err, &common.QueryError{}) {
+		t.Errorf("expected error type: `%T`, actual type: `%T`", &common.QueryError{}, err)
+	}
+	EvaluateErrorAndKeyPhrases(t, err, []string{common.MSG_QUERY_INVALID_ORDER_BY_CLAUSE})
+}
+
+func TestQueryInvalidLimit(t *testing.T) {
+	request, _ := common.NewQueryRequestSelectWildcardFrom("components")
+	err := request.SetLimit(-1)
+	if !ErrorTypesMatch(err, &common.QueryError{}) {
+		t.Errorf("expected error type: `%T`, actual type: `%T`", &common.QueryError{}, err)
+	}
+	EvaluateErrorAndKeyPhrases(t, err, []string{common.MSG_QUERY_INVALID_LIMIT_CLAUSE})
+}
+
+func TestQueryInvalidWhereClauseUnbalancedGroup(t *testing.T) {
+	for _, rawWhere := range []string{"(name=foo", "(name=foo OR name=bar", "name contains", "name exists foo"} {
+		_, err := common.NewQueryRequestSelectFromWhere(
+			common.QUERY_TOKEN_WILDCARD,
+			"components",
+			rawWhere)
+		if !ErrorTypesMatch(err, &common.QueryError{}) {
+			t.Errorf("where: `%s`: expected error type: `%T`, actual type: `%T`", rawWhere, &common.QueryError{}, err)
+		}
+	}
+}
+
+// Note: a trailing (or leading) keyword is not treated as part of a (regex) value
+func TestQueryInvalidWhereClauseMissingOperand(t *testing.T) {
+	for _, rawWhere := range []string{"name=foo OR", "OR name=foo", "name=foo OR OR name=bar", "(name=foo OR) OR name=bar", "name=foo AND"} {
+		_, err := common.NewQueryRequestSelectFromWhere(
+			common.QUERY_TOKEN_WILDCARD,
+			"components",
+			rawWhere)
+		if !ErrorTypesMatch(err, &common.QueryError{}) {
+			t.Errorf("where: `%s`: expected error type: `%T`, actual type: `%T`", rawWhere, &common.QueryError{}, err)
+		}
+	}
+}
+
+// ----------------------------------------
+// FROM/SELECT path (array selector) tests
+// ----------------------------------------
+
+// Query the test components using the FROM path (and SELECT keys) and compare the
+// (JSON encoded) result to the expected JSON
+func innerQueryComponentsPath(t *testing.T, rawSelect string, rawFrom string, rawWhere string, expectedJSON string) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request, err := common.NewQueryRequestSelectFromWhere(rawSelect, rawFrom, rawWhere)
+	if err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var expected interface{}
+	if err = json.Unmarshal([]byte(expectedJSON), &expected); err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	if !reflect.DeepEqual(result, expected) {
+		buffer, _ := utils.EncodeAnyToDefaultIndentedJSONStr(result)
+		t.Errorf("invalid query result: select: `%s`, from: `%s`: expected: %s, actual: %s",
+			rawSelect, rawFrom, expectedJSON, buffer.String())
+	}
+}
+
+func TestQueryFromArrayIndex(t *testing.T) {
+	innerQueryComponentsPath(t, "name", "components[1]", "",
+		`{"name":"body-parser"}`)
+	innerQueryComponentsPath(t, "name", "components[-1]", "",
+		`{"name":"acme-framework"}`)
+	innerQueryComponentsPath(t, "alg", "components[0].hashes[1]", "",
+		`{"alg":"SHA-256"}`)
+	innerQueryComponentsPath(t, "name", "components[type=library][-1]", "",
+		`[{"name":"depd"}]`)
+}
+
+func TestQueryFromArrayIndexScalarCollection(t *testing.T) {
+	innerQueryComponentsPath(t, "*", "components[*].hashes[0].alg", "",
+		`["SHA-1"]`)
+}
+
+func TestQueryFromArrayWildcard(t *testing.T) {
+	innerQueryComponentsPath(t, "*", "components[*].licenses.license.id", "",
+		`["BSD-3-Clause","MIT"]`)
+	innerQueryComponentsPath(t, "alg", "components[*].hashes", "",
+		`[{"alg":"SHA-1"},{"alg":"SHA-256"}]`)
+}
+
+func TestQueryFromArrayFilter(t *testing.T) {
+	innerQueryComponentsPath(t, "name,version", "components[name=qs]", "",
+		`[{"name":"qs","version":"6.9.7"}]`)
+	innerQueryComponentsPath(t, "content", "components[name=qs].hashes[alg=SHA-256]", "",
+		`[{"content":"3c9b2f6e8a1d4c7b0e5f2a9d6c3b8e1f4a7d0c9b2e5f8a1d4c7b0e3f6a9d2c5b"}]`)
+	innerQueryComponentsPath(t, "name", "components[version<2.0,licenses !exists]", "",
+		`[{"name":"body-parser"},{"name":"debug"}]`)
+}
+
+func TestQueryFromArrayRecursive(t *testing.T) {
+	innerQueryComponentsPath(t, "name", "components[**]", "name=^acme",
+		`[{"name":"acme-framework"},{"name":"acme-router"},{"name":"acme-path"}]`)
+	innerQueryComponentsPath(t, "name", "components[**][version<1.0]", "",
+		`[{"name":"acme-path"}]`)
+}
+
+func TestQuerySelectNestedPaths(t *testing.T) {
+	innerQueryComponentsPath(t, "name,hashes[0].alg,licenses.license.id", "components", "name=qs",
+		`[{"name":"qs","hashes[0].alg":"SHA-1","licenses.license.id":["BSD-3-Clause"]}]`)
+	innerQueryComponentsPath(t, "name,components[*].name", "components[-1]", "",
+		`{"name":"acme-framework","components[*].name":["acme-router"]}`)
+}
+
+func TestQueryFailWhereOnNonObjectResults(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request, _ := common.NewQueryRequestSelectWildcardFromWhere("components.name", "name=qs")
+	_, err := innerQueryError(t, cti, request, &common.QueryError{})
+	EvaluateErrorAndKeyPhrases(t, err, []string{MSG_QUERY_ERROR_NON_OBJECT_RESULTS})
+}
+
+// ----------------------------------------
+// JSONPath/JMESPath expression tests
+// ----------------------------------------
+
+func TestQueryJSONPath(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request := common.NewQueryRequestJSONPath(`$.components[?(@.name=="qs")].hashes[*].alg`)
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := []interface{}{"SHA-1", "SHA-256"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("invalid query result: expected: %v, actual: %v", expected, result)
+	}
+}
+
+func TestQueryJSONPathRecursiveDescent(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request := common.NewQueryRequestJSONPath(`$.components[-1:]..components[*].name`)
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := []interface{}{"acme-router", "acme-path"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("invalid query result: expected: %v, actual: %v", expected, result)
+	}
+}
+
+func TestQueryJSONPathFailKeyNotFound(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request := common.NewQueryRequestJSONPath(`$.foo`)
+	_, err := innerQueryError(t, cti, request, &common.QueryError{})
+	EvaluateErrorAndKeyPhrases(t, err, []string{common.MSG_QUERY_INVALID_JSONPATH})
+}
+
+func TestQueryJMESPath(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request := common.NewQueryRequestJMESPath(`components[?!licenses].{name: name, version: version} | [0]`)
+	result, err := innerQueryError(t, cti, request, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := map[string]interface{}{"name": "body-parser", "version": "1.10.0"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("invalid query result: expected: %v, actual: %v", expected, result)
+	}
+}
+
+func TestQueryJMESPathFailSyntax(t *testing.T) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	request := common.NewQueryRequestJMESPath(`components[`)
+	_, err := innerQueryError(t, cti, request, &common.QueryError{})
+	EvaluateErrorAndKeyPhrases(t, err, []string{common.MSG_QUERY_INVALID_JMESPATH})
+}
+
+func TestQueryFailExpressionConflictingClauses(t *testing.T) {
+	tests := [][]string{
+		{"--" + FLAG_QUERY_JSONPATH, "$.components", "--" + FLAG_QUERY_JMESPATH, "components"},
+		{"--" + FLAG_QUERY_JMESPATH, "components", "--" + FLAG_QUERY_FROM, "components"},
+		{"--" + FLAG_QUERY_JSONPATH, "$.components", "--" + FLAG_QUERY_LIMIT, "1"},
+	}
+	for _, args := range tests {
+		cmd := NewCommandQuery()
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+			continue
+		}
+		_, err := readQueryFlags(cmd)
+		if !ErrorTypesMatch(err, &common.QueryError{}) {
+			t.Errorf("args: %v: expected error type: `%T`, actual type: `%T`", args, &common.QueryError{}, err)
+			continue
+		}
+		EvaluateErrorAndKeyPhrases(t, err, []string{common.MSG_QUERY_CONFLICTING_CLAUSES})
+	}
+}
+
+// ----------------------------------------
+// Query (tabular) output format tests
+// ----------------------------------------
+
+// Query the test components and verify the (tabular) output contains the expected
+// values on the given line along with the expected line count
+func innerQueryComponentsFormat(t *testing.T, rawSelect string, rawFrom string, rawWhere string, format string,
+	lineNum int, lineValues []string, lineCount int) (outputBuffer bytes.Buffer) {
+	cti := NewCommonTestInfoBasic(TEST_QUERY_CDX_1_5_COMPONENTS)
+	cti.OutputFormat = format
+	cti.ResultLineContainsValues = lineValues
+	cti.ResultLineContainsValuesAtLineNum = lineNum
+	cti.ResultExpectedLineCount = lineCount
+
+	request, err := common.NewQueryRequestSelectFromWhere(rawSelect, rawFrom, rawWhere)
+	if err != nil {
+		t.Errorf("%s: %v", ERR_TYPE_UNEXPECTED_ERROR, err)
+		return
+	}
+	_, outputBuffer, err = innerQuery(t, cti, request)
+	innerRunReportResultTests(t, cti, outputBuffer, err)
+	return
+}
+
+func TestQueryFormatCSVSelectColumns(t *testing.T) {
+	// title and 6 rows
+	innerQueryComponentsFormat(t, "name,version", "components", "", FORMAT_CSV,
+		0, []string{"name,version"}, 7)
+	innerQueryComponentsFormat(t, "name,version", "components", "", FORMAT_CSV,
+		4, []string{"debug,2.0.0-rc.1"}, 7)
+}
+
+func TestQueryFormatCSVNestedKeysAndArrays(t *testing.T) {
+	innerQueryComponentsFormat(t, "name,hashes,licenses,foo", "components", "name=qs", FORMAT_CSV,
+		0, []string{"name,hashes.alg,hashes.content,licenses.license.id,foo"}, 2)
+	innerQueryComponentsFormat(t, "name,hashes.alg,licenses", "components", "name=qs", FORMAT_CSV,
+		1, []string{`qs,"SHA-1, SHA-256",BSD-3-Clause`}, 2)
+}
+
+func TestQueryFormatMarkdown(t *testing.T) {
+	innerQueryComponentsFormat(t, "name,licenses.license.id", "components[**]", "licenses exists", FORMAT_MARKDOWN,
+		0, []string{"|name|licenses.license.id|"}, 6)
+	innerQueryComponentsFormat(t, "name,licenses.license.id", "components[**]", "licenses exists", FORMAT_MARKDOWN,
+		4, []string{"|depd|none|"}, 6)
+}
+
+func TestQueryFormatTextWrapsArrays(t *testing.T) {
+	// title, underline and 2 rows (i.e., one per hash)
+	innerQueryComponentsFormat(t, "name,version,hashes.alg", "components", "name=qs", FORMAT_TEXT,
+		2, []string{"qs", "6.9.7", "SHA-1"}, 4)
+	innerQueryComponentsFormat(t, "name,version,hashes.alg", "components", "name=qs", FORMAT_TEXT,
+		3, []string{"SHA-256"}, 4)
+}
+
+func TestQueryFormatTextObjectResult(t *testing.T) {
+	innerQueryComponentsFormat(t, "*", "metadata", "", FORMAT_TEXT,
+		0, []string{"component.name", "timestamp"}, 3)
+}
+
+func TestQueryFormatCSVScalarResults(t *testing.T) {
+	innerQueryComponentsFormat(t, "*", "components[*].licenses.license.id", "", FORMAT_CSV,
+		0, []string{QUERY_REPORT_COLUMN_VALUE}, 3)
+}
+
+func TestQueryFormatTextNoResults(t *testing.T) {
+	innerQueryComponentsFormat(t, "name", "components", "name=foo", FORMAT_TEXT,
+		2, []string{MSG_OUTPUT_NO_QUERY_RESULTS_FOUND}, 3)
+}
+
+// Other commands register different defaults (e.g., "txt") for their "--format" flags;
+// the query command's own flag outputs JSON unless it is explicitly set
+func TestQueryFormatDefaultJSON(t *testing.T) {
+	tests := []struct {
+		formatArgs []string
+		expectJSON bool
+	}{
+		{nil, true},
+		{[]string{"--" + FLAG_OUTPUT_FORMAT, FORMAT_CSV}, false},
+	}
+	for _, test := range tests {
+		command := NewCommandQuery()
+		args := append(test.formatArgs, "--"+FLAG_QUERY_SELECT, "name,version", "--"+FLAG_QUERY_FROM, "components")
+		if err := command.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		// i.e., the default of the last command to register a (shared) "--format" flag
+		utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_TEXT
+
+		outputFile := filepath.Join(t.TempDir(), "query-output")
+		utils.GlobalFlags.PersistentFlags.InputFile = TEST_QUERY_CDX_1_5_COMPONENTS
+		utils.GlobalFlags.PersistentFlags.OutputFile = outputFile
+		err := queryCmdImpl(command, nil)
+		utils.GlobalFlags.PersistentFlags.OutputFile = ""
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if json.Valid(data) != test.expectJSON {
+			t.Errorf("args: %v: expected JSON output: %v, actual: `%s`", args, test.expectJSON, data)
+		}
+	}
+}
@@ -119,6 +119,7 @@ func wrapTableRowText(maxChars int, joinChar string, columns ...interface{}) (ta
 	tableData = make([][]string, 1)
 
 	// Allocate the first row of the multi-row "table"
+	// Note: scalar column values are always placed in the first row
 	var numRowsAllocated int = 1
 	rowData := make([]string, numColumns)
 	tableData[0] = rowData
@@ -138,8 +139,7 @@ func wrapTableRowText(maxChars int, joinChar string, columns ...interface{}) (ta
 			if numRowsNeeded > numRowsAllocated {
 				// as long as we need more rows allocated
 				for ; numRowsAllocated < numRowsNeeded; numRowsAllocated++ {
-					rowData = make([]string, numColumns)
-					tableData = append(tableData, rowData)
+					tableData = append(tableData, make([]string, numColumns))
 				}
 				getLogger().Debugf("tableData: (%v)", tableData)
 			}
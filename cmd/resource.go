@@ -19,16 +19,18 @@
 package cmd
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
-	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/jwangsadinata/go-multimap"
 	"github.com/spf13/cobra"
 )
 
@@ -108,10 +110,11 @@ func NewCommandResource() *cobra.Command {
 		}
 
 		// Test for required flags (parameters)
-		err = preRunTestForInputFile(cmd, args)
+		err = preRunTestForBatchInputFiles(cmd, args)
 
 		return
 	}
+	initCommandBatchFlags(command)
 	return command
 }
 
@@ -159,7 +162,11 @@ func resourceCmdImpl(cmd *cobra.Command, args []string) (err error) {
 
 	if err == nil {
 		resourceFlags.ResourceType = resourceType
-		err = ListResources(writer, utils.GlobalFlags.PersistentFlags, resourceFlags, whereFilters)
+		if IsBatch(utils.GlobalFlags.BatchFlags) {
+			err = ListResourcesBatch(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags, resourceFlags, whereFilters)
+		} else {
+			err = ListResources(writer, utils.GlobalFlags.PersistentFlags, resourceFlags, whereFilters)
+		}
 	}
 
 	return
@@ -220,42 +227,60 @@ func ListResources(writer io.Writer, persistentFlags utils.PersistentCommandFlag
 	return
 }
 
-func loadDocumentResources(document *schema.BOM, resourceType string, whereFilters []common.WhereFilter) (err error) {
+// Note: the --type flag has already been validated
+func ListResourcesBatch(writer io.Writer, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags,
+	resourceFlags utils.ResourceCommandFlags, whereFilters []common.WhereFilter) (err error) {
 	getLogger().Enter()
-	defer getLogger().Exit(err)
-
-	// At this time, fail SPDX format SBOMs as "unsupported" (for "any" format)
-	if !document.FormatInfo.IsCycloneDx() {
-		err = schema.NewUnsupportedFormatForCommandError(
-			document.FormatInfo.CanonicalName,
-			document.GetFilename(),
-			CMD_LICENSE, FORMAT_ANY)
-		return
-	}
-
-	// Before looking for license data, fully unmarshal the SBOM into named structures
-	if err = document.UnmarshalCycloneDXBOM(); err != nil {
-		return
-	}
+	defer getLogger().Exit()
 
-	// Add top-level SBOM component
-	if resourceType == schema.RESOURCE_TYPE_DEFAULT || resourceType == schema.RESOURCE_TYPE_COMPONENT {
-		err = document.HashComponentResources(whereFilters)
-		if err != nil {
+	results := ProcessBatch(batchFlags.InputFiles, batchFlags.MaxWorkers, func(inputFile string) (rows [][]string, err error) {
+		var document *schema.BOM
+		if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
 			return
 		}
-	}
-
-	if resourceType == schema.RESOURCE_TYPE_DEFAULT || resourceType == schema.RESOURCE_TYPE_SERVICE {
-		err = document.HashServiceResources(whereFilters)
-		if err != nil {
+		if err = loadDocumentResources(document, resourceFlags.ResourceType, whereFilters); err != nil {
 			return
 		}
+		return resourceListRows(document), nil
+	})
+
+	if err = DisplayBatchResults(writer, persistentFlags.OutputFormat, RESOURCE_LIST_TITLES, results); err != nil {
+		return
 	}
+	return BatchResultsError(results)
+}
 
+func sortResources(entries []multimap.Entry) {
+	sbom.SortResources(entries)
+}
+
+// Returns the (sorted) resource list report rows (i.e., in the order of RESOURCE_LIST_TITLES)
+func resourceListRows(bom *schema.BOM) (rows [][]string) {
+	entries := bom.ResourceMap.Entries()
+	sortResources(entries)
+
+	for _, entry := range entries {
+		resourceInfo := entry.Value.(schema.CDXResourceInfo)
+		rows = append(rows, []string{
+			resourceInfo.Type,
+			resourceInfo.Name,
+			resourceInfo.Version,
+			resourceInfo.BOMRef,
+		})
+	}
 	return
 }
 
+func loadDocumentResources(document *schema.BOM, resourceType string, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	return sbom.HashResources(context.Background(), document, sbom.ResourceOptions{
+		ResourceType: resourceType,
+		WhereFilters: whereFilters,
+	})
+}
+
 // NOTE: This list is NOT de-duplicated
 // TODO: Add a --no-title flag to skip title output
 func DisplayResourceListText(bom *schema.BOM, writer io.Writer) {
@@ -286,15 +311,7 @@ func DisplayResourceListText(bom *schema.BOM, writer io.Writer) {
 	}
 
 	// Sort by Type then Name
-	sort.Slice(entries, func(i, j int) bool {
-		resource1 := (entries[i].Value).(schema.CDXResourceInfo)
-		resource2 := (entries[j].Value).(schema.CDXResourceInfo)
-		if resource1.Type != resource2.Type {
-			return resource1.Type < resource2.Type
-		}
-
-		return resource1.Name < resource2.Name
-	})
+	sortResources(entries)
 
 	var resourceInfo schema.CDXResourceInfo
 
@@ -337,16 +354,8 @@ func DisplayResourceListCSV(bom *schema.BOM, writer io.Writer) (err error) {
 		return fmt.Errorf(currentRow[0])
 	}
 
-	// Sort by Type
-	sort.Slice(entries, func(i, j int) bool {
-		resource1 := (entries[i].Value).(schema.CDXResourceInfo)
-		resource2 := (entries[j].Value).(schema.CDXResourceInfo)
-		if resource1.Type != resource2.Type {
-			return resource1.Type < resource2.Type
-		}
-
-		return resource1.Name < resource2.Name
-	})
+	// Sort by Type then Name
+	sortResources(entries)
 
 	var resourceInfo schema.CDXResourceInfo
 	var line []string
@@ -392,16 +401,8 @@ func DisplayResourceListMarkdown(bom *schema.BOM, writer io.Writer) (err error)
 		return fmt.Errorf(MSG_OUTPUT_NO_RESOURCES_FOUND)
 	}
 
-	// Sort by Type
-	sort.Slice(entries, func(i, j int) bool {
-		resource1 := (entries[i].Value).(schema.CDXResourceInfo)
-		resource2 := (entries[j].Value).(schema.CDXResourceInfo)
-		if resource1.Type != resource2.Type {
-			return resource1.Type < resource2.Type
-		}
-
-		return resource1.Name < resource2.Name
-	})
+	// Sort by Type then Name
+	sortResources(entries)
 
 	var resourceInfo schema.CDXResourceInfo
 	var line []string
@@ -36,6 +36,7 @@ const (
 	TEST_RESOURCE_LIST_CDX_1_3            = "test/cyclonedx/cdx-1-3-resource-list.json"
 	TEST_RESOURCE_LIST_CDX_1_3_NONE_FOUND = "test/cyclonedx/cdx-1-3-resource-list-none-found.json"
 	TEST_RESOURCE_LIST_CDX_1_4_SAAS_1     = "examples/cyclonedx/SaaSBOM/apigateway-microservices-datastores/bom.json"
+	TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES  = "test/spdx/spdx-2-3-packages.json"
 )
 
 type ResourceTestInfo struct {
@@ -138,29 +139,72 @@ func TestResourceListInvalidInputFileLoad(t *testing.T) {
 }
 
 // -------------------------------------------
-// Test format unsupported (SPDX)
+// SPDX variants
 // -------------------------------------------
-func TestResourceListFormatUnsupportedSPDXMinReq(t *testing.T) {
+func TestResourceListTextSpdx22MinReqNoneFound(t *testing.T) {
 	rti := NewResourceTestInfoBasic(
 		TEST_SPDX_2_2_MIN_REQUIRED,
-		FORMAT_DEFAULT,
-		&schema.UnsupportedFormatError{},
+		FORMAT_TEXT,
+		nil, // no error
 		schema.RESOURCE_TYPE_DEFAULT,
 	)
 
-	// verify correct error is returned
+	// verify there is a (warning) message present when no resources are found
+	rti.ResultLineContainsValues = []string{MSG_OUTPUT_NO_RESOURCES_FOUND}
+	rti.ResultLineContainsValuesAtLineNum = 2
 	innerTestResourceList(t, rti)
 }
 
-func TestResourceListFormatUnsupportedSPDX22(t *testing.T) {
+func TestResourceListTextSpdx22Example1(t *testing.T) {
 	rti := NewResourceTestInfoBasic(
 		TEST_SPDX_2_2_EXAMPLE_1,
-		FORMAT_DEFAULT,
-		&schema.UnsupportedFormatError{},
+		FORMAT_TEXT,
+		nil, // no error
 		schema.RESOURCE_TYPE_DEFAULT,
 	)
 
-	// verify correct error is returned
+	innerTestResourceList(t, rti)
+}
+
+func TestResourceListTextSpdx23Packages(t *testing.T) {
+	rti := NewResourceTestInfoBasic(
+		TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		nil, // no error
+		schema.RESOURCE_TYPE_DEFAULT,
+	)
+	rti.ResultExpectedLineCount = 7 // title, separator and 5 data rows (4 packages, 1 file)
+	innerTestResourceList(t, rti)
+}
+
+func TestResourceListTextSpdx23WhereClauseBomRefContainsMaven(t *testing.T) {
+	TEST_INPUT_WHERE_CLAUSE := "bom-ref=^.*maven.*$"
+	TEST_OUTPUT_CONTAINS := []string{"component", "log4j-core", "2.17.1", "SPDXRef-Package-maven-log4j-core"}
+	TEST_OUTPUT_LINES := 3
+
+	rti := NewResourceTestInfo(
+		TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		TI_LIST_SUMMARY_FALSE,
+		TEST_INPUT_WHERE_CLAUSE,
+		TEST_OUTPUT_LINES,
+		schema.RESOURCE_TYPE_COMPONENT)
+	rti.ResultLineContainsValues = TEST_OUTPUT_CONTAINS
+	rti.ResultLineContainsValuesAtLineNum = 2
+	innerTestResourceList(t, rti)
+}
+
+// Note: SPDX has no concept of "services"
+func TestResourceListTextSpdx23NoServicesFound(t *testing.T) {
+	rti := NewResourceTestInfo(
+		TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		TI_LIST_SUMMARY_FALSE,
+		"",
+		TI_RESULT_DEFAULT_LINE_COUNT,
+		schema.RESOURCE_TYPE_SERVICE)
+	rti.ResultLineContainsValues = []string{MSG_OUTPUT_NO_RESOURCES_FOUND}
+	rti.ResultLineContainsValuesAtLineNum = 2
 	innerTestResourceList(t, rti)
 }
 
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 
 	"github.com/CycloneDX/sbom-utility/log"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 	"github.com/spf13/cobra"
@@ -37,11 +38,18 @@ var SupportedFormatConfig schema.BOMFormatAndSchemaConfig
 
 // top-level commands
 const (
+	CMD_CONVERT       = "convert"
+	CMD_DEPENDENCY    = "dependency"
 	CMD_DIFF          = "diff"
 	CMD_LICENSE       = "license"
+	CMD_MERGE         = "merge"
+	CMD_MIGRATE       = "migrate"
 	CMD_QUERY         = "query"
 	CMD_RESOURCE      = "resource"
 	CMD_SCHEMA        = "schema"
+	CMD_SCORE         = "score"
+	CMD_SERVE         = "serve"
+	CMD_SIGNATURE     = "signature"
 	CMD_VALIDATE      = "validate"
 	CMD_VERSION       = "version"
 	CMD_VULNERABILITY = "vulnerability"
@@ -52,15 +60,24 @@ const (
 // WARNING!!! The ".Use" field of a Cobra command MUST have the first word be the actual command
 // otherwise, the command will NOT be found by the Cobra framework. This is poor code assumption is NOT documented.
 const (
-	CMD_USAGE_DIFF               = CMD_DIFF + " --input-file <base_file> --input-revision <revised_file> [--format json|txt] [--colorize=true|false]"
+	CMD_USAGE_CONVERT            = CMD_CONVERT + " --input-file <input_file> [--to cyclonedx|spdx] [--output-file <output_file>] [--report-file <report_file>] [--report-format txt|json|csv|md]"
+	CMD_USAGE_DEPENDENCY_LIST    = CMD_DEPENDENCY + " " + SUBCOMMAND_DEPENDENCY_LIST + " --input-file <input_file> [--ref <bom-ref>] [--format txt|csv|md|json|dot|mermaid]"
+	CMD_USAGE_DIFF               = CMD_DIFF + " --input-file <base_file> --input-revision <revised_file> [--format json|txt|csv|md] [--colorize=true|false] [--semantic]"
 	CMD_USAGE_LICENSE_LIST       = SUBCOMMAND_LICENSE_LIST + " --input-file <input_file> [--summary] [--where key=regex[,...]] [--format json|txt|csv|md]"
 	CMD_USAGE_LICENSE_POLICY     = SUBCOMMAND_LICENSE_POLICY + " [--where key=regex[,...]] [--format txt|csv|md]"
-	CMD_USAGE_QUERY              = CMD_QUERY + " --input-file <input_file> [--select * | field1[,fieldN]] [--from [key1[.keyN]] [--where key=regex[,...]]"
+	CMD_USAGE_MERGE              = CMD_MERGE + " --input-file <input_file> --input-file <input_file> [--input-file ...] [--strategy first-wins|last-wins|fail] [--hierarchical --name <name> [--version <version>] [--group <group>]] [--output-file <output_file>]"
+	CMD_USAGE_MIGRATE            = CMD_MIGRATE + " --input-file <input_file> --spec-version 1.2|1.3|1.4|1.5 [--output-file <output_file>] [--report-file <report_file>] [--report-format txt|json|csv|md]"
+	CMD_USAGE_QUERY              = CMD_QUERY + " --input-file <input_file> [--select * | field1[,fieldN]] [--from [key1[.keyN]] [--where key=regex[,...]] [--orderby key1 [asc|desc][,keyN]] [--limit n] [--offset m] | [--jsonpath expression | --jmespath expression]"
 	CMD_USAGE_RESOURCE_LIST      = CMD_RESOURCE + " --input-file <input_file> [--type component|service] [--where key=regex[,...]] [--format txt|csv|md]"
 	CMD_USAGE_SCHEMA_LIST        = CMD_SCHEMA + " [--where key=regex[,...]] [--format txt|csv|md]"
+	CMD_USAGE_SCORE              = CMD_SCORE + " --input-file <input_file> [--format txt|json|md]"
+	CMD_USAGE_SERVE              = CMD_SERVE + " [--address <host:port>] [--max-request-size <bytes>]"
+	CMD_USAGE_SIGNATURE          = CMD_SIGNATURE + " " + SUBCOMMAND_SIGNATURE_SIGN + "|" + SUBCOMMAND_SIGNATURE_VERIFY + " --input-file <input_file> [flags]"
+	CMD_USAGE_SIGNATURE_SIGN     = CMD_SIGNATURE + " " + SUBCOMMAND_SIGNATURE_SIGN + " --input-file <input_file> --key <private_key_file>[,...] [--type signature|signers|chain] [--algorithm <alg>[,...]] [--key-id <id>[,...]] [--certificate <certificate_file>[,...]] [--embed-key=true|false] [--excludes key1[,keyN]] [--from key1[.keyN]] [--where key=regex[,...]] [--output-file <output_file>]"
+	CMD_USAGE_SIGNATURE_VERIFY   = CMD_SIGNATURE + " " + SUBCOMMAND_SIGNATURE_VERIFY + " --input-file <input_file> [--key <key_file>] [--format txt|csv|md|json]"
 	CMD_USAGE_VALIDATE           = CMD_VALIDATE + " --input-file <input_file> [--variant <variant_name>] [--format txt|json] [--force schema_file]"
 	CMD_USAGE_VULNERABILITY_LIST = CMD_VULNERABILITY + " " + SUBCOMMAND_VULNERABILITY_LIST + " --input-file <input_file> [--summary] [--where key=regex[,...]] [--format json|txt|csv|md]"
-	CMD_USAGE_STATS_LIST         = CMD_STATS + " --input-file <input_file> [--type component|service] [--format txt|csv|md]"
+	CMD_USAGE_STATS_LIST         = CMD_STATS + " --input-file <input_file> [--format txt|csv|md]"
 	CMD_USAGE_TRIM               = CMD_TRIM + " --input-file <input_file>  --input-file <output_file>"
 )
 
@@ -80,6 +97,8 @@ const (
 	FLAG_QUIET_MODE_SHORT         = "q"
 	FLAG_OUTPUT_INDENT            = "indent"
 	FLAG_LOG_OUTPUT_INDENT        = "log-indent"
+	FLAG_LOG_FORMAT               = "log-format"
+	FLAG_LOG_FILE                 = "log-file"
 	FLAG_FILE_OUTPUT_FORMAT       = "format"
 	FLAG_COLORIZE_OUTPUT          = "colorize"
 )
@@ -93,8 +112,11 @@ const (
 	MSG_FLAG_OUTPUT         = "output filename"
 	MSG_FLAG_LOG_QUIET      = "enable quiet logging mode (removes all informational messages from console output); overrides other logging commands"
 	MSG_FLAG_LOG_INDENT     = "enable log indentation of functional callstack"
+	MSG_FLAG_LOG_FORMAT     = "log (line) format: \"text\" (default) or \"json\" (i.e., one JSON object per line)"
+	MSG_FLAG_LOG_FILE       = "append log output to the named file (i.e., instead of the console)"
 	MSG_FLAG_CONFIG_SCHEMA  = "provide custom application schema configuration file (i.e., overrides default `config.json`)"
 	MSG_FLAG_CONFIG_LICENSE = "provide custom application license policy configuration file (i.e., overrides default `license.json`)"
+	MSG_FLAG_CONFIG_CUSTOM  = "provide custom validation configuration file(s) layered in the order provided (i.e., overrides default `custom.json`)"
 	MSG_FLAG_OUTPUT_INDENT  = "number of space characters used to indent JSON formatted output"
 )
 
@@ -104,9 +126,9 @@ const (
 )
 
 const (
-	DEFAULT_SCHEMA_CONFIG            = "config.json"
+	DEFAULT_SCHEMA_CONFIG            = sbom.DEFAULT_SCHEMA_CONFIG
 	DEFAULT_CUSTOM_VALIDATION_CONFIG = "custom.json"
-	DEFAULT_LICENSE_POLICY_CONFIG    = "license.json"
+	DEFAULT_LICENSE_POLICY_CONFIG    = sbom.DEFAULT_LICENSE_POLICY_CONFIG
 )
 
 // Supported output formats
@@ -116,7 +138,11 @@ const (
 	FORMAT_JSON     = "json"
 	FORMAT_CSV      = "csv"
 	FORMAT_MARKDOWN = "md"
-	FORMAT_ANY      = "<any>" // Used for test errors
+	FORMAT_DOT      = "dot"     // Graphviz
+	FORMAT_MERMAID  = "mermaid" // Mermaid flowchart
+	FORMAT_SARIF    = "sarif"   // SARIF v2.1.0 (e.g., code scanning)
+	FORMAT_JUNIT    = "junit"   // JUnit XML (test) report
+	FORMAT_ANY      = "<any>"   // Used for test errors
 )
 
 // TODO: make flag configurable:
@@ -171,9 +197,7 @@ func init() {
 	// as we want the init/load methods to work apart from Cobra.
 	rootCmd.PersistentFlags().StringVarP(&utils.GlobalFlags.ConfigSchemaFile, FLAG_CONFIG_SCHEMA, "", "", MSG_FLAG_CONFIG_SCHEMA)
 	rootCmd.PersistentFlags().StringVarP(&utils.GlobalFlags.ConfigLicensePolicyFile, FLAG_CONFIG_LICENSE_POLICY, "", "", MSG_FLAG_CONFIG_LICENSE)
-	// TODO: Make configurable once we have organized the set of custom validation configurations
-	utils.GlobalFlags.ConfigCustomValidationFile = DEFAULT_CUSTOM_VALIDATION_CONFIG
-	//rootCmd.PersistentFlags().StringVarP(&utils.GlobalFlags.ConfigCustomValidationFile, FLAG_CONFIG_CUSTOM_VALIDATION, "", DEFAULT_CUSTOM_VALIDATION_CONFIG, "TODO")
+	rootCmd.PersistentFlags().StringSliceVarP(&utils.GlobalFlags.ConfigCustomValidationFiles, FLAG_CONFIG_CUSTOM_VALIDATION, "", nil, MSG_FLAG_CONFIG_CUSTOM)
 
 	// Declare top-level, persistent flags and where to place the post-parse values
 	rootCmd.PersistentFlags().BoolVarP(&utils.GlobalFlags.PersistentFlags.Trace, FLAG_TRACE, FLAG_TRACE_SHORT, false, MSG_FLAG_TRACE)
@@ -189,6 +213,11 @@ func init() {
 	// Optionally, allow log callstack trace to be indented
 	rootCmd.PersistentFlags().BoolVarP(&utils.GlobalFlags.LogOutputIndentCallstack, FLAG_LOG_OUTPUT_INDENT, "", false, MSG_FLAG_LOG_INDENT)
 
+	// NOTE: Although the logger applies the log format and file flags (in main) before any
+	// command runs; we track the flags using Cobra to validate them and provide help
+	rootCmd.PersistentFlags().StringVarP(&utils.GlobalFlags.LogFormat, FLAG_LOG_FORMAT, "", log.FORMAT_TEXT, MSG_FLAG_LOG_FORMAT)
+	rootCmd.PersistentFlags().StringVarP(&utils.GlobalFlags.LogFile, FLAG_LOG_FILE, "", "", MSG_FLAG_LOG_FILE)
+
 	// Output (JSON) indent
 	rootCmd.PersistentFlags().Uint8VarP(&utils.GlobalFlags.PersistentFlags.OutputIndent, FLAG_OUTPUT_INDENT, "", DEFAULT_OUTPUT_INDENT_LENGTH, MSG_FLAG_OUTPUT_INDENT)
 
@@ -201,8 +230,14 @@ func init() {
 	rootCmd.AddCommand(NewCommandVulnerability())
 	rootCmd.AddCommand(NewCommandDiff())
 	rootCmd.AddCommand(NewCommandTrim())
-	// TODO: when fully implemented uncomment:
-	//rootCmd.AddCommand(NewCommandStats())
+	rootCmd.AddCommand(NewCommandMerge())
+	rootCmd.AddCommand(NewCommandConvert())
+	rootCmd.AddCommand(NewCommandMigrate())
+	rootCmd.AddCommand(NewCommandDependency())
+	rootCmd.AddCommand(NewCommandSignature())
+	rootCmd.AddCommand(NewCommandServe())
+	rootCmd.AddCommand(NewCommandScore())
+	rootCmd.AddCommand(NewCommandStats())
 
 	// Add license command its subcommands
 	licenseCmd := NewCommandLicense()
@@ -215,7 +250,7 @@ func init() {
 // includes JSON files:
 // config.json (SBOM format/schema definitions),
 // license.json (license policy definitions),
-// custom.json (custom validation settings)
+// custom.json (custom validation settings), which is loaded (and layered) by validate when needed
 // Note: This method cannot return values as it is used as a callback by the Cobra framework
 func initConfigurations() {
 	getLogger().Enter()
@@ -232,6 +267,12 @@ func initConfigurations() {
 		getLogger().Debugf("%s: \n%s", "utils.Flags", flagInfo)
 	}
 
+	if !log.IsValidFormat(utils.GlobalFlags.LogFormat) {
+		getLogger().Errorf("invalid `--%s` value: `%s` (valid values: %v)",
+			FLAG_LOG_FORMAT, utils.GlobalFlags.LogFormat, log.VALID_FORMATS)
+		os.Exit(ERROR_APPLICATION)
+	}
+
 	// NOTE: some commands operate just on the JSON SBOM (i.e., no validation)
 	// we leave the code below "in place" as we may still want to validate any
 	// input file as JSON SBOM document that matches a known format/version (TODO in the future)
@@ -270,9 +311,16 @@ func RootCmdImpl(cmd *cobra.Command, args []string) error {
 func Execute() {
 	// instead of creating a dependency on the "main" module
 	getLogger().Enter()
-	defer getLogger().Exit()
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+
+	// Note: os.Exit() does not run deferred functions; close the log file (if any) first
+	getLogger().Exit()
+	if errClose := getLogger().Close(); errClose != nil {
+		fmt.Fprintln(os.Stderr, errClose)
+	}
+
+	if err != nil {
 		if IsInvalidBOMError(err) {
 			os.Exit(ERROR_VALIDATION)
 		} else {
@@ -0,0 +1,485 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// SARIF (Static Analysis Results Interchange Format) v2.1.0
+// See: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+const (
+	SARIF_VERSION         = "2.1.0"
+	SARIF_SCHEMA          = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIF_INFORMATION_URI = "https://github.com/CycloneDX/sbom-utility"
+)
+
+// SARIF result (and rule) levels
+const (
+	SARIF_LEVEL_ERROR   = "error"
+	SARIF_LEVEL_WARNING = "warning"
+	SARIF_LEVEL_NOTE    = "note"
+)
+
+// SARIF rule id prefixes and (custom validation and license policy) rule ids
+const (
+	SARIF_RULE_PREFIX_SCHEMA         = "schema/"
+	SARIF_RULE_PREFIX_REFERENCE      = "reference/"
+	SARIF_RULE_PREFIX_RULE           = "rule/"
+	SARIF_RULE_PREFIX_PROFILE        = "profile/"
+	SARIF_RULE_PREFIX_LICENSE_POLICY = "license-policy/"
+	SARIF_RULE_CUSTOM_COMPOSITION    = "custom/composition"
+	SARIF_RULE_CUSTOM_LICENSE        = "custom/license"
+	SARIF_RULE_CUSTOM_METADATA       = "custom/metadata"
+	SARIF_RULE_CUSTOM_INVALID_SBOM   = "custom/invalid-sbom"
+	SARIF_RULE_DOCUMENT_ERROR        = "document/error"
+)
+
+// The (batch) row columns used to carry a finding from the worker that found it to the report
+var SARIF_FINDING_COLUMNS = []string{"ruleId", "ruleDescription", "level", "message", "jsonPointer"}
+
+// JSON pointer (RFC 6901) formatting
+const (
+	JSON_POINTER_SEPARATOR = "/"
+	// Note: a delimiter which can not appear in JSON keys of a JSON context (i.e., when split)
+	JSON_CONTEXT_SPLIT_DELIMITER = "\x00"
+)
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// License usage policies reported as (policy violation) findings and their levels
+// Note: licenses with an "allow" usage policy are not reported
+var SARIF_LICENSE_POLICY_LEVELS = map[string]string{
+	schema.POLICY_DENY:         SARIF_LEVEL_ERROR,
+	schema.POLICY_CONFLICT:     SARIF_LEVEL_ERROR,
+	schema.POLICY_NEEDS_REVIEW: SARIF_LEVEL_WARNING,
+	schema.POLICY_UNDEFINED:    SARIF_LEVEL_NOTE,
+}
+
+// Custom validation rule severities and their levels
+var SARIF_RULE_SEVERITY_LEVELS = map[string]string{
+	schema.RULE_SEVERITY_ERROR:   SARIF_LEVEL_ERROR,
+	schema.RULE_SEVERITY_WARNING: SARIF_LEVEL_WARNING,
+	schema.RULE_SEVERITY_INFO:    SARIF_LEVEL_NOTE,
+}
+
+type SarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+type SarifTool struct {
+	Driver SarifToolComponent `json:"driver"`
+}
+
+type SarifToolComponent struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version,omitempty"`
+	InformationUri string                     `json:"informationUri,omitempty"`
+	Rules          []SarifReportingDescriptor `json:"rules"`
+}
+
+type SarifReportingDescriptor struct {
+	Id                   string                      `json:"id"`
+	ShortDescription     SarifMessage                `json:"shortDescription"`
+	DefaultConfiguration SarifReportingConfiguration `json:"defaultConfiguration"`
+}
+
+type SarifReportingConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+}
+
+type SarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+// Note: the "fully qualified name" of a location within a BOM is its JSON pointer
+type SarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SarifFinding is a single (validation or policy) finding within a BOM file
+type SarifFinding struct {
+	RuleId          string
+	RuleDescription string
+	Level           string
+	Message         string
+	JSONPointer     string
+}
+
+// Returns the finding as a (batch) row (i.e., in the order of SARIF_FINDING_COLUMNS)
+func (finding SarifFinding) row() []string {
+	return []string{finding.RuleId, finding.RuleDescription, finding.Level, finding.Message, finding.JSONPointer}
+}
+
+func newSarifFindingFromRow(row []string) SarifFinding {
+	values := make([]string, len(SARIF_FINDING_COLUMNS))
+	copy(values, row)
+	return SarifFinding{
+		RuleId:          values[0],
+		RuleDescription: values[1],
+		Level:           values[2],
+		Message:         values[3],
+		JSONPointer:     values[4],
+	}
+}
+
+func sarifFindingRows(findings []SarifFinding) (rows [][]string) {
+	for _, finding := range findings {
+		rows = append(rows, finding.row())
+	}
+	return
+}
+
+// Create a SARIF log (with a single run) from the findings within the named BOM file
+func NewSarifLog(filename string, findings []SarifFinding) *SarifLog {
+	return &SarifLog{
+		Version: SARIF_VERSION,
+		Schema:  SARIF_SCHEMA,
+		Runs:    []SarifRun{newSarifRun(filename, findings)},
+	}
+}
+
+// Create a SARIF log with a run for each (batch) input file
+// Note: documents that could not be processed (e.g., loaded) are reported as a (document) error finding
+func NewSarifBatchLog(results []BatchDocumentResult) *SarifLog {
+	runs := make([]SarifRun, 0, len(results))
+	for _, result := range results {
+		var findings []SarifFinding
+		for _, row := range result.Rows {
+			findings = append(findings, newSarifFindingFromRow(row))
+		}
+		if result.Err != nil && !IsInvalidBOMError(result.Err) {
+			findings = append(findings, SarifFinding{
+				RuleId:          SARIF_RULE_DOCUMENT_ERROR,
+				RuleDescription: "BOM document could not be processed",
+				Level:           SARIF_LEVEL_ERROR,
+				Message:         result.Err.Error(),
+			})
+		}
+		runs = append(runs, newSarifRun(result.Filename, findings))
+	}
+	return &SarifLog{
+		Version: SARIF_VERSION,
+		Schema:  SARIF_SCHEMA,
+		Runs:    runs,
+	}
+}
+
+func newSarifRun(filename string, findings []SarifFinding) (run SarifRun) {
+	run = SarifRun{
+		Tool: SarifTool{
+			Driver: SarifToolComponent{
+				Name:           utils.GlobalFlags.Project,
+				Version:        utils.GlobalFlags.Version,
+				InformationUri: SARIF_INFORMATION_URI,
+				Rules:          []SarifReportingDescriptor{},
+			},
+		},
+		Results: []SarifResult{},
+	}
+
+	// Rules are declared (once) in the order they are first found
+	ruleIndices := make(map[string]int)
+	for _, finding := range findings {
+		index, found := ruleIndices[finding.RuleId]
+		if !found {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndices[finding.RuleId] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, SarifReportingDescriptor{
+				Id:                   finding.RuleId,
+				ShortDescription:     SarifMessage{Text: finding.RuleDescription},
+				DefaultConfiguration: SarifReportingConfiguration{Level: finding.Level},
+			})
+		}
+
+		location := SarifLocation{
+			PhysicalLocation: SarifPhysicalLocation{
+				ArtifactLocation: SarifArtifactLocation{Uri: filepath.ToSlash(filename)},
+			},
+		}
+		if finding.JSONPointer != "" {
+			location.LogicalLocations = []SarifLogicalLocation{{FullyQualifiedName: finding.JSONPointer}}
+		}
+
+		run.Results = append(run.Results, SarifResult{
+			RuleId:    finding.RuleId,
+			RuleIndex: index,
+			Level:     finding.Level,
+			Message:   SarifMessage{Text: finding.Message},
+			Locations: []SarifLocation{location},
+		})
+	}
+	return
+}
+
+// Note: JSON data files MUST ends in a newline as this is a POSIX standard
+// which is already accounted for by the JSON encoder.
+func DisplaySarif(writer io.Writer, filename string, findings []SarifFinding) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	getLogger().Infof("Outputting (%d) findings (`%s` format)...", len(findings), FORMAT_SARIF)
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, NewSarifLog(filename, findings),
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
+
+// Display the (batch) results as a single SARIF log with a run for each input file
+func DisplaySarifBatch(writer io.Writer, results []BatchDocumentResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	getLogger().Infof("Outputting (%d) runs (`%s` format)...", len(results), FORMAT_SARIF)
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, NewSarifBatchLog(results),
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
+
+// ------------------------------------------------
+// Validation findings
+// ------------------------------------------------
+
+// Returns the findings of a validated document; that is, its schema (or referential integrity)
+// errors (up to the error limit) or the (custom) validation error that invalidated it.
+func validationSarifFindings(schemaErrors []gojsonschema.ResultError, err error, flags utils.ValidateCommandFlags) (findings []SarifFinding) {
+	if lenErrs := len(schemaErrors); lenErrs > 0 {
+		errLimit := flags.MaxNumErrors
+		if lenErrs > errLimit {
+			getLogger().Infof(MSG_INFO_TOO_MANY_ERRORS, errLimit, lenErrs)
+		}
+		for i, resultError := range schemaErrors {
+			if i == errLimit {
+				break
+			}
+			findings = append(findings, schemaErrorSarifFinding(resultError))
+		}
+		return
+	}
+
+	if err != nil {
+		findings = append(findings, customErrorSarifFinding(err))
+	}
+	return
+}
+
+func schemaErrorSarifFinding(resultError gojsonschema.ResultError) SarifFinding {
+	prefix, level := SARIF_RULE_PREFIX_SCHEMA, SARIF_LEVEL_ERROR
+	switch typedError := resultError.(type) {
+	case *ReferenceResultError:
+		prefix = SARIF_RULE_PREFIX_REFERENCE
+	case *RuleResultError:
+		prefix = SARIF_RULE_PREFIX_RULE
+		if ruleLevel, found := SARIF_RULE_SEVERITY_LEVELS[typedError.Severity]; found {
+			level = ruleLevel
+		}
+	}
+
+	return SarifFinding{
+		RuleId:          prefix + resultError.Type(),
+		RuleDescription: fmt.Sprintf("BOM %s error: `%s`", strings.TrimSuffix(prefix, "/"), resultError.Type()),
+		Level:           level,
+		Message:         fmt.Sprintf("%s: %s", resultError.Field(), resultError.Description()),
+		JSONPointer:     JsonContextToPointer(resultError.Context()),
+	}
+}
+
+// Map (custom) validation errors, which are wrapped in an InvalidSBOMError, to a finding
+func customErrorSarifFinding(err error) (finding SarifFinding) {
+	finding.Level = SARIF_LEVEL_ERROR
+	finding.Message = err.Error()
+
+	var invalidErr *InvalidSBOMError
+	if errors.As(err, &invalidErr) && invalidErr.InnerError != nil {
+		err = invalidErr.InnerError
+		finding.Message = err.Error()
+	}
+
+	switch typedErr := err.(type) {
+	case *SBOMCompositionError:
+		finding.RuleId = SARIF_RULE_CUSTOM_COMPOSITION
+		finding.JSONPointer = KeysToJSONPointer(typedErr.FieldKeys)
+	case *SBOMMetadataError:
+		finding.RuleId = SARIF_RULE_CUSTOM_METADATA
+		finding.JSONPointer = KeysToJSONPointer([]string{"metadata"})
+	case *SBOMLicenseError:
+		finding.RuleId = SARIF_RULE_CUSTOM_LICENSE
+	case *SBOMProfileError:
+		finding.RuleId = SARIF_RULE_PREFIX_PROFILE + typedErr.Profile
+		finding.RuleDescription = fmt.Sprintf("BOM profile conformance error: `%s`", finding.RuleId)
+		return
+	default:
+		finding.RuleId = SARIF_RULE_CUSTOM_INVALID_SBOM
+	}
+	finding.RuleDescription = fmt.Sprintf("BOM custom validation error: `%s`", finding.RuleId)
+	return
+}
+
+// ------------------------------------------------
+// License policy findings
+// ------------------------------------------------
+
+// Returns the license (usage) policy violations found in a document (i.e., all
+// hashed licenses whose usage policy is not "allow")
+func licensePolicySarifFindings(bom *schema.BOM) (findings []SarifFinding) {
+	licenseKeys := bom.LicenseMap.KeySet()
+	sortLicenseKeys(licenseKeys)
+
+	for _, licenseName := range licenseKeys {
+		arrLicenseInfo, _ := bom.LicenseMap.Get(licenseName)
+		for _, iInfo := range arrLicenseInfo {
+			licenseInfo := iInfo.(schema.LicenseInfo)
+			usagePolicy := licenseInfo.UsagePolicy
+			if usagePolicy == "" {
+				usagePolicy = schema.POLICY_UNDEFINED
+			}
+			level, isViolation := SARIF_LICENSE_POLICY_LEVELS[usagePolicy]
+			if !isViolation {
+				continue
+			}
+			findings = append(findings, SarifFinding{
+				RuleId:          SARIF_RULE_PREFIX_LICENSE_POLICY + strings.ToLower(usagePolicy),
+				RuleDescription: fmt.Sprintf("license usage policy: `%s`", usagePolicy),
+				Level:           level,
+				Message: fmt.Sprintf("license `%s` (%s) of `%s` (bom-ref: `%s`) has usage policy: `%s`",
+					licenseName, licenseInfo.LicenseChoiceType, licenseInfo.ResourceName,
+					licenseInfo.BOMRef, usagePolicy),
+				JSONPointer: licenseInfoJSONPointer(bom, licenseInfo),
+			})
+		}
+	}
+	return
+}
+
+// Returns the JSON pointer to the license(s) of the BOM resource the license was found in
+func licenseInfoJSONPointer(bom *schema.BOM, licenseInfo schema.LicenseInfo) string {
+	var arrayKey, idKey, licenseKey string
+	switch licenseInfo.BOMLocationValue {
+	case schema.LC_LOC_METADATA:
+		return KeysToJSONPointer([]string{"metadata", "licenses"})
+	case schema.LC_LOC_METADATA_COMPONENT:
+		return KeysToJSONPointer([]string{"metadata", "component", "licenses"})
+	case schema.LC_LOC_COMPONENTS:
+		arrayKey, idKey, licenseKey = "components", "bom-ref", "licenses"
+	case schema.LC_LOC_SERVICES:
+		arrayKey, idKey, licenseKey = "services", "bom-ref", "licenses"
+	case schema.LC_LOC_SPDX_PACKAGES_CONCLUDED:
+		arrayKey, idKey, licenseKey = "packages", "SPDXID", "licenseConcluded"
+	case schema.LC_LOC_SPDX_PACKAGES_DECLARED:
+		arrayKey, idKey, licenseKey = "packages", "SPDXID", "licenseDeclared"
+	case schema.LC_LOC_SPDX_FILES:
+		arrayKey, idKey, licenseKey = "files", "SPDXID", "licenseConcluded"
+	case schema.LC_LOC_SPDX_SNIPPETS:
+		arrayKey, idKey, licenseKey = "snippets", "SPDXID", "licenseConcluded"
+	default:
+		return ""
+	}
+
+	// Find the (possibly nested) resource by its identifier
+	ref := string(licenseInfo.BOMRef)
+	if keys, found := findJSONArrayItem(bom.GetJSONMap(), arrayKey, idKey, ref, nil); found && ref != "" {
+		return KeysToJSONPointer(append(keys, licenseKey))
+	}
+	return KeysToJSONPointer([]string{arrayKey})
+}
+
+// Returns the keys (path) to the first item of the named (possibly nested) array
+// whose identifier (key) has the given value
+func findJSONArrayItem(jsonMap map[string]interface{}, arrayKey string, idKey string, id string, path []string) (keys []string, found bool) {
+	items, ok := jsonMap[arrayKey].([]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range items {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		itemPath := append(append([]string{}, path...), arrayKey, fmt.Sprint(i))
+		if value, ok := itemMap[idKey].(string); ok && value == id {
+			return itemPath, true
+		}
+		if keys, found = findJSONArrayItem(itemMap, arrayKey, idKey, id, itemPath); found {
+			return
+		}
+	}
+	return
+}
+
+// ------------------------------------------------
+// JSON pointers
+// ------------------------------------------------
+
+// Convert a JSON (schema) context (i.e., starting at "(root)") to a JSON pointer (RFC 6901)
+func JsonContextToPointer(context *gojsonschema.JsonContext) string {
+	if context == nil {
+		return ""
+	}
+	keys := strings.Split(context.String(JSON_CONTEXT_SPLIT_DELIMITER), JSON_CONTEXT_SPLIT_DELIMITER)
+	if len(keys) > 0 && keys[0] == JSON_CONTEXT_ROOT {
+		keys = keys[1:]
+	}
+	return KeysToJSONPointer(keys)
+}
+
+// Convert the keys of a path from the document root to a JSON pointer (RFC 6901)
+// Note: the pointer to the document root is the empty string
+func KeysToJSONPointer(keys []string) string {
+	var sb strings.Builder
+	for _, key := range keys {
+		sb.WriteString(JSON_POINTER_SEPARATOR)
+		sb.WriteString(jsonPointerEscaper.Replace(key))
+	}
+	return sb.String()
+}
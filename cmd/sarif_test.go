@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	TEST_SARIF_LICENSE_LIST_CDX_1_3_COMPLEX = "test/cyclonedx/cdx-1-3-license-list-complex.json"
+)
+
+// -------------------------------------------
+// SARIF test helper functions
+// -------------------------------------------
+
+func decodeTestSarifLog(t *testing.T, outputBuffer bytes.Buffer) (sarifLog *SarifLog) {
+	return decodeTestSarifBatchLog(t, outputBuffer, 1)
+}
+
+// Note: batch output is a single SARIF log with a run for each input file
+func decodeTestSarifBatchLog(t *testing.T, outputBuffer bytes.Buffer, expectedRuns int) (sarifLog *SarifLog) {
+	sarifLog = new(SarifLog)
+	if err := json.Unmarshal(outputBuffer.Bytes(), sarifLog); err != nil {
+		t.Fatalf("invalid SARIF output: %s\n%s", err, outputBuffer.String())
+	}
+	if sarifLog.Version != SARIF_VERSION || len(sarifLog.Runs) != expectedRuns {
+		t.Fatalf("invalid SARIF log: version: `%s`, runs: `%v`", sarifLog.Version, len(sarifLog.Runs))
+	}
+	return
+}
+
+func innerTestValidateSarif(t *testing.T, inputFile string, expectedError error) (sarifLog *SarifLog) {
+	vti := NewValidateTestInfo(inputFile, FORMAT_SARIF, SCHEMA_VARIANT_NONE, expectedError)
+	utils.GlobalFlags.PersistentFlags.InputFile = vti.InputFile
+	utils.GlobalFlags.PersistentFlags.OutputFormat = vti.OutputFormat
+	// !!!Important!!! Must reset this global flag for subsequent tests
+	defer func() { utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_TEXT }()
+
+	_, _, _, outputBuffer, err := innerValidateErrorBuffered(t, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.ValidateFlags)
+	if !ErrorTypesMatch(err, expectedError) {
+		t.Errorf("expected error type: `%T`, actual type: `%T`", expectedError, err)
+	}
+	return decodeTestSarifLog(t, outputBuffer)
+}
+
+// -------------------------------------------
+// Validate
+// -------------------------------------------
+
+func TestSarifValidateSpdx22Valid(t *testing.T) {
+	sarifLog := innerTestValidateSarif(t, TEST_SPDX_2_2_MIN_REQUIRED, nil)
+	if results := sarifLog.Runs[0].Results; len(results) != 0 {
+		t.Errorf("SARIF results: expected none, actual: `%v`", results)
+	}
+}
+
+func TestSarifValidateSpdx22SchemaErrors(t *testing.T) {
+	sarifLog := innerTestValidateSarif(t, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, &InvalidSBOMError{})
+	run := sarifLog.Runs[0]
+	if len(run.Results) != 1 || len(run.Tool.Driver.Rules) != 1 {
+		t.Fatalf("SARIF results: expected (1) result and rule, actual: `%v`", run)
+	}
+	result := run.Results[0]
+	if result.RuleId != SARIF_RULE_PREFIX_SCHEMA+"required" || result.Level != SARIF_LEVEL_ERROR {
+		t.Errorf("SARIF result: unexpected rule id: `%s` or level: `%s`", result.RuleId, result.Level)
+	}
+	if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.Uri; uri != TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING {
+		t.Errorf("SARIF result location: expected `%s`, actual: `%s`", TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, uri)
+	}
+}
+
+func TestSarifCustomErrorFindings(t *testing.T) {
+	compositionErr := NewInvalidSBOMError(nil, MSG_INVALID_METADATA_COMPONENT_COMPONENTS,
+		NewSBOMCompositionError(MSG_INVALID_METADATA_COMPONENT_COMPONENTS, nil, []string{"metadata", "component", "components"}), nil)
+	finding := customErrorSarifFinding(compositionErr)
+	if finding.RuleId != SARIF_RULE_CUSTOM_COMPOSITION || finding.JSONPointer != "/metadata/component/components" {
+		t.Errorf("SARIF finding: unexpected rule id: `%s` or pointer: `%s`", finding.RuleId, finding.JSONPointer)
+	}
+
+	metadataErr := NewInvalidSBOMError(nil, MSG_INVALID_METADATA_COMPONENT,
+		NewSBOMMetadataError(nil, MSG_INVALID_METADATA_COMPONENT, schema.CDXMetadata{}), nil)
+	finding = customErrorSarifFinding(metadataErr)
+	if finding.RuleId != SARIF_RULE_CUSTOM_METADATA || finding.JSONPointer != "/metadata" {
+		t.Errorf("SARIF finding: unexpected rule id: `%s` or pointer: `%s`", finding.RuleId, finding.JSONPointer)
+	}
+}
+
+func TestSarifProfileErrorFinding(t *testing.T) {
+	profileErr := NewInvalidSBOMError(nil, MSG_PROFILE_BELOW_THRESHOLD,
+		NewSBOMProfileError(nil, VALIDATE_PROFILE_NTIA, 50, 100), nil)
+	finding := customErrorSarifFinding(profileErr)
+	if finding.RuleId != SARIF_RULE_PREFIX_PROFILE+VALIDATE_PROFILE_NTIA || finding.Level != SARIF_LEVEL_ERROR {
+		t.Errorf("SARIF finding: unexpected rule id: `%s` or level: `%s`", finding.RuleId, finding.Level)
+	}
+}
+
+func TestSarifRuleErrorFindingLevel(t *testing.T) {
+	ruleErr := NewRuleResultError(schema.CustomValidationRuleViolation{
+		RuleId:      "component-bom-ref-unique",
+		Severity:    schema.RULE_SEVERITY_WARNING,
+		Condition:   schema.RULE_CONDITION_UNIQUE,
+		Path:        []string{"components", "2", "bom-ref"},
+		Value:       "pkg:npm/express@4.18.2",
+		Description: "component bom-refs must be unique",
+	})
+	finding := schemaErrorSarifFinding(ruleErr)
+	if finding.RuleId != SARIF_RULE_PREFIX_RULE+"component-bom-ref-unique" || finding.Level != SARIF_LEVEL_WARNING {
+		t.Errorf("SARIF finding: unexpected rule id: `%s` or level: `%s`", finding.RuleId, finding.Level)
+	}
+	if finding.JSONPointer != "/components/2/bom-ref" {
+		t.Errorf("SARIF finding: unexpected pointer: `%s`", finding.JSONPointer)
+	}
+}
+
+func TestSarifJsonContextToPointer(t *testing.T) {
+	context := gojsonschema.NewJsonContext(JSON_CONTEXT_ROOT, nil)
+	for _, key := range []string{"components", "0", "a/b~c"} {
+		context = gojsonschema.NewJsonContext(key, context)
+	}
+	if pointer := JsonContextToPointer(context); pointer != "/components/0/a~1b~0c" {
+		t.Errorf("JSON pointer: expected `/components/0/a~1b~0c`, actual: `%s`", pointer)
+	}
+	if pointer := JsonContextToPointer(gojsonschema.NewJsonContext(JSON_CONTEXT_ROOT, nil)); pointer != "" {
+		t.Errorf("JSON pointer: expected root (i.e., empty), actual: `%s`", pointer)
+	}
+}
+
+func TestSarifValidateBatchSpdx(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_SARIF, TEST_SPDX_2_2_MIN_REQUIRED, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, TEST_BATCH_INPUT_FILE_LIST)
+	ti := NewCommonTestInfoBasic(TEST_SPDX_2_2_MIN_REQUIRED)
+	// Note: the input file that could not be loaded determines the overall (error) result
+	ti.ResultExpectedError = &UtilityError{}
+	outputBuffer, _ := innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ValidateBatch(writer, persistentFlags, batchFlags, utils.GlobalFlags.ValidateFlags)
+	})
+
+	expected := []struct {
+		filename string
+		ruleId   string
+	}{
+		{TEST_SPDX_2_2_MIN_REQUIRED, ""},
+		{TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, SARIF_RULE_PREFIX_SCHEMA + "required"},
+		{TEST_BATCH_INPUT_FILE_LIST, SARIF_RULE_DOCUMENT_ERROR},
+	}
+	for i, run := range decodeTestSarifBatchLog(t, outputBuffer, len(expected)).Runs {
+		if expected[i].ruleId == "" {
+			if len(run.Results) != 0 {
+				t.Errorf("SARIF run[%v]: expected no results, actual: `%v`", i, run.Results)
+			}
+			continue
+		}
+		if len(run.Results) != 1 {
+			t.Errorf("SARIF run[%v]: expected (1) result, actual: `%v`", i, run.Results)
+			continue
+		}
+		result := run.Results[0]
+		if result.RuleId != expected[i].ruleId {
+			t.Errorf("SARIF run[%v] result: expected rule id: `%s`, actual: `%s`", i, expected[i].ruleId, result.RuleId)
+		}
+		if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.Uri; uri != expected[i].filename {
+			t.Errorf("SARIF run[%v] result location: expected `%s`, actual: `%s`", i, expected[i].filename, uri)
+		}
+	}
+}
+
+// -------------------------------------------
+// License policy
+// -------------------------------------------
+
+func TestSarifLicenseListPolicyCdx13(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_SARIF_LICENSE_LIST_CDX_1_3_COMPLEX, FORMAT_SARIF, false)
+	outputBuffer, err := innerTestLicenseListBuffered(t, lti, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var denied bool
+	for _, result := range decodeTestSarifLog(t, outputBuffer).Runs[0].Results {
+		if result.RuleId == SARIF_RULE_PREFIX_LICENSE_POLICY+schema.POLICY_ALLOW {
+			t.Errorf("SARIF result: unexpected (allowed) license: `%s`", result.Message.Text)
+		}
+		if result.RuleId == SARIF_RULE_PREFIX_LICENSE_POLICY+schema.POLICY_DENY {
+			denied = true
+			pointer := result.Locations[0].LogicalLocations[0].FullyQualifiedName
+			if result.Level != SARIF_LEVEL_ERROR || pointer != "/components/6/licenses" {
+				t.Errorf("SARIF result: unexpected level: `%s` or pointer: `%s`", result.Level, pointer)
+			}
+		}
+	}
+	if !denied {
+		t.Errorf("SARIF results: expected (denied) license policy violation")
+	}
+}
+
+func TestSarifLicenseListPolicySpdx23(t *testing.T) {
+	lti := NewLicenseTestInfo(TEST_LICENSE_LIST_SPDX_2_3_PACKAGES, FORMAT_SARIF, false)
+	outputBuffer, err := innerTestLicenseListBuffered(t, lti, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := decodeTestSarifLog(t, outputBuffer).Runs[0].Results
+	if len(results) == 0 {
+		t.Fatalf("SARIF results: expected license policy violations")
+	}
+	if pointer := results[0].Locations[0].LogicalLocations[0].FullyQualifiedName; pointer != "/packages/0/licenseConcluded" {
+		t.Errorf("SARIF result pointer: expected `/packages/0/licenseConcluded`, actual: `%s`", pointer)
+	}
+}
+
+func TestSarifLicenseListBatch(t *testing.T) {
+	inputFiles := []string{TEST_SARIF_LICENSE_LIST_CDX_1_3_COMPLEX, TEST_LICENSE_LIST_SPDX_2_3_PACKAGES}
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_SARIF, inputFiles...)
+	outputBuffer, err := innerTestBatch(t, NewCommonTestInfoBasic(inputFiles[0]), func(writer *bufio.Writer) error {
+		return ListLicensesBatch(writer, LicensePolicyConfig, persistentFlags, batchFlags, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, run := range decodeTestSarifBatchLog(t, outputBuffer, len(inputFiles)).Runs {
+		if len(run.Results) == 0 {
+			t.Errorf("SARIF run[%v]: expected license policy violations", i)
+			continue
+		}
+		if uri := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.Uri; uri != inputFiles[i] {
+			t.Errorf("SARIF run[%v] result location: expected `%s`, actual: `%s`", i, inputFiles[i], uri)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// Command help formatting
+const (
+	FLAG_SCORE_OUTPUT_FORMAT_HELP = "format score output"
+)
+
+var SCORE_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_MARKDOWN}, ", ")
+
+// Requirement results
+const (
+	SCORE_RESULT_PASS           = "pass"
+	SCORE_RESULT_FAIL           = "fail"
+	SCORE_RESULT_NOT_APPLICABLE = "n/a"
+)
+
+const (
+	SCORE_DATA_KEY_ID          = "id"
+	SCORE_DATA_KEY_LEVEL       = "level"
+	SCORE_DATA_KEY_SCOPE       = "scope"
+	SCORE_DATA_KEY_DESCRIPTION = "description"
+	SCORE_DATA_KEY_PRESENT     = "present"
+	SCORE_DATA_KEY_TOTAL       = "total"
+	SCORE_DATA_KEY_COVERAGE    = "coverage"
+	SCORE_DATA_KEY_RESULT      = "result"
+)
+
+// NOTE: columns will be output in order they are listed here:
+var SCORE_ROW_DATA = []ColumnFormatData{
+	{SCORE_DATA_KEY_ID, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_LEVEL, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_SCOPE, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_DESCRIPTION, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_PRESENT, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_TOTAL, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_COVERAGE, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{SCORE_DATA_KEY_RESULT, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+}
+
+func NewCommandScore() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_SCORE
+	command.Short = "Score the BOM input file against the OWASP SCVS BOM Maturity Model"
+	command.Long = "Score the BOM input file against the OWASP Software Component Verification Standard (SCVS) BOM Maturity Model; " +
+		"outputs the pass/fail result of each requirement (by level), the overall score (i.e., percentage of requirements passed) and the maturity level achieved"
+	command.Flags().StringVarP(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_FILE_OUTPUT_FORMAT, "", FORMAT_TEXT,
+		FLAG_SCORE_OUTPUT_FORMAT_HELP+SCORE_SUPPORTED_FORMATS)
+	command.RunE = scoreCmdImpl
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		if len(args) != 0 {
+			return getLogger().Errorf("Too many arguments provided: %v", args)
+		}
+
+		// Test for required flags (parameters)
+		err = preRunTestForInputFile(cmd, args)
+		return
+	}
+	return command
+}
+
+// Cobra command callback
+func scoreCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFile, writer)
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		// always close the output file
+		if outputFile != nil {
+			err = outputFile.Close()
+			getLogger().Infof("Closed output file: `%s`", outputFilename)
+		}
+	}()
+
+	if err == nil {
+		err = Score(writer, utils.GlobalFlags.PersistentFlags)
+	}
+	return
+}
+
+// Assure all errors are logged
+func processScoreResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+func Score(writer io.Writer, persistentFlags utils.PersistentCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processScoreResults(err)
+		}
+	}()
+
+	// Note: returns error if either file load or unmarshal to JSON map fails
+	var document *schema.BOM
+	if document, err = LoadInputBOMFileAndDetectSchema(); err != nil {
+		return
+	}
+
+	var score *schema.ScvsScore
+	if score, err = document.ScoreScvsMaturity(); err != nil {
+		return
+	}
+	getLogger().Infof("score: %.2f%% (level: %v)", score.Score, score.Level)
+
+	format := persistentFlags.OutputFormat
+	getLogger().Infof("Outputting score (`%s` format)...", format)
+	switch format {
+	case FORMAT_JSON:
+		err = DisplayScoreJSON(writer, score)
+	case FORMAT_MARKDOWN:
+		DisplayScoreMarkdown(writer, score)
+	case FORMAT_TEXT:
+		DisplayScoreText(writer, score)
+	default:
+		// Default to Text output for anything else (set as flag default)
+		getLogger().Warningf("Score not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplayScoreText(writer, score)
+	}
+	return
+}
+
+func scoreSummary(score *schema.ScvsScore) string {
+	return fmt.Sprintf("score: %.2f%% (%v/%v requirements passed, %v not applicable), level: %v, components: %v",
+		score.Score, score.Passed, score.Requirements-score.NotApplicable, score.NotApplicable, score.Level, score.Components)
+}
+
+func scoreResultLineData(result schema.ScvsRequirementResult) []string {
+	status := SCORE_RESULT_FAIL
+	if !result.Applicable {
+		status = SCORE_RESULT_NOT_APPLICABLE
+	} else if result.Passed {
+		status = SCORE_RESULT_PASS
+	}
+	return []string{
+		result.Id,
+		strconv.Itoa(result.Level),
+		result.Scope,
+		result.Description,
+		strconv.Itoa(result.Present),
+		strconv.Itoa(result.Total),
+		fmt.Sprintf("%.2f%%", result.Coverage),
+		status,
+	}
+}
+
+func DisplayScoreText(writer io.Writer, score *schema.ScvsScore) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n\n", scoreSummary(score))
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	// create title row and underline row from slices of optional and compulsory titles
+	titles, underlines := prepareReportTitleData(SCORE_ROW_DATA, false)
+	fmt.Fprintf(w, "%s\n", strings.Join(titles, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+
+	for _, result := range score.Results {
+		fmt.Fprintf(w, "%s\n", strings.Join(scoreResultLineData(result), "\t"))
+	}
+}
+
+func DisplayScoreJSON(writer io.Writer, score *schema.ScvsScore) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, score,
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
+
+func DisplayScoreMarkdown(writer io.Writer, score *schema.ScvsScore) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n\n", scoreSummary(score))
+
+	// create title row and alignment row from slices of optional and compulsory titles
+	titles, _ := prepareReportTitleData(SCORE_ROW_DATA, false)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(titles))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(titles)))
+
+	for _, result := range score.Results {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(scoreResultLineData(result)))
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+func innerTestScore(t *testing.T, inputFile string, format string) (outputBuffer bytes.Buffer) {
+	persistentFlags := utils.GlobalFlags.PersistentFlags
+	persistentFlags.InputFile = inputFile
+	persistentFlags.OutputFormat = format
+	utils.GlobalFlags.PersistentFlags.InputFile = inputFile
+
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	err := Score(outputWriter, persistentFlags)
+	outputWriter.Flush()
+
+	if err != nil {
+		t.Fatalf("expected: no error, actual: `%v`:\n%s", err, outputBuffer.String())
+	}
+	return
+}
+
+func testScoreOutputLines(t *testing.T, outputBuffer bytes.Buffer, expectedLines [][]string) {
+	for _, expectedValues := range expectedLines {
+		if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+			t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+		}
+	}
+}
+
+func TestScoreSpdx23PackagesText(t *testing.T) {
+	outputBuffer := innerTestScore(t, TEST_PROFILE_SPDX_2_3_PACKAGES, FORMAT_TEXT)
+	testScoreOutputLines(t, outputBuffer, [][]string{
+		{"score: 53.85%", "7/13", "level: 0", "components: 4"},
+		{schema.SCVS_BOM_CORE_TIMESTAMP, "document", "1", "1", "100.00%", SCORE_RESULT_PASS},
+		{schema.SCVS_BOM_RESOURCE_SUPPLIER, "resource", "3", "4", "75.00%", SCORE_RESULT_FAIL},
+	})
+}
+
+func TestScoreSpdx23PackagesMarkdown(t *testing.T) {
+	outputBuffer := innerTestScore(t, TEST_PROFILE_SPDX_2_3_PACKAGES, FORMAT_MARKDOWN)
+	testScoreOutputLines(t, outputBuffer, [][]string{
+		{"|id|level|scope|description|present|total|coverage|result|"},
+		{"|" + schema.SCVS_BOM_RESOURCE_LICENSES + "|2|resource|", "|4|4|100.00%|pass|"},
+	})
+}
+
+func TestScoreSpdx23PackagesJSON(t *testing.T) {
+	outputBuffer := innerTestScore(t, TEST_PROFILE_SPDX_2_3_PACKAGES, FORMAT_JSON)
+
+	var score schema.ScvsScore
+	if err := json.Unmarshal(outputBuffer.Bytes(), &score); err != nil {
+		t.Fatalf("invalid JSON output: %v:\n%s", err, outputBuffer.String())
+	}
+	if score.Requirements != len(schema.SCVS_BOM_MATURITY_REQUIREMENTS) || score.Passed != 7 || score.Score != 53.85 {
+		t.Errorf("expected: (requirements: %v, passed: 7, score: 53.85), actual: (%v, %v, %v)",
+			len(schema.SCVS_BOM_MATURITY_REQUIREMENTS), score.Requirements, score.Passed, score.Score)
+	}
+	if len(score.Results) != score.Requirements {
+		t.Errorf("expected: %v results, actual: %v", score.Requirements, len(score.Results))
+	}
+}
+
+// Resource requirements are not applicable to a document without components (i.e., packages)
+func TestScoreSpdx22NoPackagesText(t *testing.T) {
+	outputBuffer := innerTestScore(t, TEST_SPDX_2_2_MIN_REQUIRED, FORMAT_TEXT)
+	testScoreOutputLines(t, outputBuffer, [][]string{
+		{"score: 60.00%", "3/5", "8 not applicable", "level: 1", "components: 0"},
+		{schema.SCVS_BOM_RESOURCE_NAME, "resource", "0", "0", SCORE_RESULT_NOT_APPLICABLE},
+		{schema.SCVS_BOM_CORE_DEPENDENCIES, "document", "0", "1", "0.00%", SCORE_RESULT_FAIL},
+	})
+}
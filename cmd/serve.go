@@ -0,0 +1,621 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FLAG_SERVE_ADDRESS          = "address"
+	FLAG_SERVE_MAX_REQUEST_SIZE = "max-request-size"
+)
+
+// Command help formatting
+const (
+	FLAG_SERVE_ADDRESS_HELP          = "network address (i.e., host:port) the server listens on"
+	FLAG_SERVE_MAX_REQUEST_SIZE_HELP = "maximum size (in bytes) of a request body (i.e., BOM)"
+)
+
+const (
+	DEFAULT_SERVE_ADDRESS             = "localhost:8080"
+	DEFAULT_SERVE_MAX_REQUEST_SIZE    = 10 * 1024 * 1024
+	DEFAULT_SERVE_READ_HEADER_TIMEOUT = 10 * time.Second
+	DEFAULT_SERVE_SHUTDOWN_TIMEOUT    = 10 * time.Second
+)
+
+// Server endpoints (paths)
+const (
+	SERVE_PATH_VALIDATE           = "/api/v1/validate"
+	SERVE_PATH_QUERY              = "/api/v1/query"
+	SERVE_PATH_LICENSE_LIST       = "/api/v1/license/list"
+	SERVE_PATH_LICENSE_POLICY     = "/api/v1/license/policy"
+	SERVE_PATH_RESOURCE_LIST      = "/api/v1/resource/list"
+	SERVE_PATH_VULNERABILITY_LIST = "/api/v1/vulnerability/list"
+	SERVE_PATH_STATS              = "/api/v1/stats"
+	SERVE_PATH_DIFF               = "/api/v1/diff"
+)
+
+// Server endpoint (query) parameters
+// Note: parameters are named after their equivalent command flags
+const (
+	SERVE_PARAM_WHERE       = FLAG_REPORT_WHERE
+	SERVE_PARAM_SUMMARY     = FLAG_LICENSE_SUMMARY
+	SERVE_PARAM_TYPE        = FLAG_RESOURCE_TYPE
+	SERVE_PARAM_VARIANT     = FLAG_VALIDATE_SCHEMA_VARIANT
+	SERVE_PARAM_ERROR_LIMIT = FLAG_VALIDATE_ERR_LIMIT
+	SERVE_PARAM_ERROR_VALUE = FLAG_VALIDATE_ERR_VALUE
+)
+
+// Names of the BOM documents read from request bodies (i.e., as shown in error messages)
+const (
+	SERVE_DOCUMENT_NAME_REQUEST = "request"
+	SERVE_DOCUMENT_NAME_BASE    = "base"
+	SERVE_DOCUMENT_NAME_REVISED = "revised"
+)
+
+const (
+	SERVE_CONTENT_TYPE_JSON = "application/json"
+)
+
+// Server messages
+const (
+	MSG_SERVE_LISTENING            = "Listening on `%s`..."
+	MSG_SERVE_SHUTDOWN             = "Shutting down server..."
+	MSG_SERVE_METHOD_NOT_ALLOWED   = "method not allowed"
+	MSG_SERVE_REQUEST_TOO_LARGE    = "request body exceeds maximum size"
+	MSG_SERVE_REQUEST_EMPTY        = "request body is empty"
+	MSG_SERVE_INVALID_PARAMETER    = "invalid parameter"
+	MSG_SERVE_DIFF_INVALID_REQUEST = "request body must be a JSON object with \"" + SERVE_DOCUMENT_NAME_BASE +
+		"\" and \"" + SERVE_DOCUMENT_NAME_REVISED + "\" BOM documents"
+)
+
+// An error that determines the HTTP status of the (error) response
+type ServeError struct {
+	Status int
+	Err    error
+}
+
+func NewServeError(status int, err error) *ServeError {
+	return &ServeError{Status: status, Err: err}
+}
+
+func (err *ServeError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ServeError) Unwrap() error {
+	return err.Err
+}
+
+// The body of all error responses
+type ServeErrorResponse struct {
+	Status  int    `json:"status"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// The body of a validate response; errors are formatted the same as the
+// validate command's "json" formatted error results
+type ServeValidateResponse struct {
+	Valid      bool                     `json:"valid"`
+	Format     string                   `json:"format"`
+	Version    string                   `json:"version"`
+	Variant    string                   `json:"variant,omitempty"`
+	ErrorCount int                      `json:"errorCount"`
+	Errors     []*ValidationErrorResult `json:"errors"`
+}
+
+// The body of a diff request
+type ServeDiffRequest struct {
+	Base    json.RawMessage `json:"base"`
+	Revised json.RawMessage `json:"revised"`
+}
+
+// An endpoint handler returns the (JSON) response body or an error
+type serveHandlerFunc func(writer http.ResponseWriter, request *http.Request) (response interface{}, err error)
+
+// The server holds the configurations loaded at startup so that (concurrent) requests
+// never read or modify configurations (or flags) set by other commands or requests
+type Server struct {
+	flags        utils.ServeCommandFlags
+	formatConfig *schema.BOMFormatAndSchemaConfig
+	policyConfig *schema.LicensePolicyConfig
+	outputIndent int
+	mux          *http.ServeMux
+}
+
+func NewCommandServe() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_SERVE
+	command.Short = "Start an HTTP server with JSON endpoints for BOM validation, queries and reports"
+	command.Long = "Start an HTTP server with JSON endpoints for validate, query, license list and policy, resource list, vulnerability list, stats and diff; " +
+		"BOMs are provided in the request body and license policy and schema configurations (i.e., \"--config-license\", \"--config-schema\") are loaded at startup"
+	command.Flags().StringVarP(&utils.GlobalFlags.ServeFlags.Address, FLAG_SERVE_ADDRESS, "", DEFAULT_SERVE_ADDRESS, FLAG_SERVE_ADDRESS_HELP)
+	command.Flags().Int64VarP(&utils.GlobalFlags.ServeFlags.MaxRequestSize, FLAG_SERVE_MAX_REQUEST_SIZE, "", DEFAULT_SERVE_MAX_REQUEST_SIZE, FLAG_SERVE_MAX_REQUEST_SIZE_HELP)
+	command.RunE = serveCmdImpl
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		if len(args) != 0 {
+			return getLogger().Errorf("Too many arguments provided: %v", args)
+		}
+		if utils.GlobalFlags.ServeFlags.MaxRequestSize <= 0 {
+			return getLogger().Errorf("invalid `--%s` value: `%v`", FLAG_SERVE_MAX_REQUEST_SIZE, utils.GlobalFlags.ServeFlags.MaxRequestSize)
+		}
+		return
+	}
+	return command
+}
+
+func serveCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// Note: the callstack indent of the logger is shared state that (concurrent) requests cannot use
+	getLogger().EnableIndent(false)
+
+	server := NewServer(utils.GlobalFlags.ServeFlags, &SupportedFormatConfig, LicensePolicyConfig,
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return server.ListenAndServe(ctx)
+}
+
+func NewServer(flags utils.ServeCommandFlags, formatConfig *schema.BOMFormatAndSchemaConfig, policyConfig *schema.LicensePolicyConfig, outputIndent int) *Server {
+	server := &Server{
+		flags:        flags,
+		formatConfig: formatConfig,
+		policyConfig: policyConfig,
+		outputIndent: outputIndent,
+		mux:          http.NewServeMux(),
+	}
+
+	server.mux.HandleFunc(SERVE_PATH_VALIDATE, server.handle(http.MethodPost, server.handleValidate))
+	server.mux.HandleFunc(SERVE_PATH_QUERY, server.handle(http.MethodPost, server.handleQuery))
+	server.mux.HandleFunc(SERVE_PATH_LICENSE_LIST, server.handle(http.MethodPost, server.handleLicenseList))
+	server.mux.HandleFunc(SERVE_PATH_LICENSE_POLICY, server.handle(http.MethodGet, server.handleLicensePolicy))
+	server.mux.HandleFunc(SERVE_PATH_RESOURCE_LIST, server.handle(http.MethodPost, server.handleResourceList))
+	server.mux.HandleFunc(SERVE_PATH_VULNERABILITY_LIST, server.handle(http.MethodPost, server.handleVulnerabilityList))
+	server.mux.HandleFunc(SERVE_PATH_STATS, server.handle(http.MethodPost, server.handleStats))
+	server.mux.HandleFunc(SERVE_PATH_DIFF, server.handle(http.MethodPost, server.handleDiff))
+	return server
+}
+
+func (server *Server) Handler() http.Handler {
+	return server.mux
+}
+
+// Serve requests until the context is done (e.g., on interrupt) and then gracefully shutdown
+func (server *Server) ListenAndServe(ctx context.Context) (err error) {
+	httpServer := &http.Server{
+		Addr:              server.flags.Address,
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: DEFAULT_SERVE_READ_HEADER_TIMEOUT,
+	}
+
+	errServe := make(chan error, 1)
+	go func() {
+		getLogger().Infof(MSG_SERVE_LISTENING, server.flags.Address)
+		errServe <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errServe:
+		return
+	case <-ctx.Done():
+		getLogger().Infof(MSG_SERVE_SHUTDOWN)
+	}
+
+	ctxShutdown, cancel := context.WithTimeout(context.Background(), DEFAULT_SERVE_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	return httpServer.Shutdown(ctxShutdown)
+}
+
+// Wrap an endpoint handler to assure the request method and to consistently write (JSON) responses
+func (server *Server) handle(method string, handler serveHandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		getLogger().Infof("%s %s", request.Method, request.URL.Path)
+
+		if request.Method != method {
+			writer.Header().Set("Allow", method)
+			server.writeError(writer, NewServeError(http.StatusMethodNotAllowed,
+				fmt.Errorf("%s: `%s`", MSG_SERVE_METHOD_NOT_ALLOWED, request.Method)))
+			return
+		}
+
+		response, err := handler(writer, request)
+		if err != nil {
+			server.writeError(writer, err)
+			return
+		}
+		server.writeJSON(writer, http.StatusOK, response)
+	}
+}
+
+func (server *Server) writeJSON(writer http.ResponseWriter, status int, response interface{}) {
+	writer.Header().Set("Content-Type", SERVE_CONTENT_TYPE_JSON)
+	writer.WriteHeader(status)
+	if _, err := utils.WriteAnyAsEncodedJSONInt(writer, response, server.outputIndent); err != nil {
+		getLogger().Error(err)
+	}
+}
+
+// Errors from (reading and loading) request data are client errors unless declared otherwise
+func (server *Server) writeError(writer http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	var serveError *ServeError
+	if errors.As(err, &serveError) {
+		status = serveError.Status
+	}
+	getLogger().Debugf("status: %v: %v", status, err)
+
+	server.writeJSON(writer, status, ServeErrorResponse{
+		Status:  status,
+		Error:   http.StatusText(status),
+		Message: err.Error(),
+	})
+}
+
+// Read the (size limited) request body
+func (server *Server) readBody(writer http.ResponseWriter, request *http.Request) (data []byte, err error) {
+	reader := http.MaxBytesReader(writer, request.Body, server.flags.MaxRequestSize)
+	if data, err = io.ReadAll(reader); err != nil {
+		var errMaxBytes *http.MaxBytesError
+		if errors.As(err, &errMaxBytes) {
+			err = NewServeError(http.StatusRequestEntityTooLarge,
+				fmt.Errorf("%s: (%v) bytes", MSG_SERVE_REQUEST_TOO_LARGE, errMaxBytes.Limit))
+		}
+		return
+	}
+	if len(data) == 0 {
+		err = errors.New(MSG_SERVE_REQUEST_EMPTY)
+	}
+	return
+}
+
+// Load BOM data and detect its format and schema using the server's (startup) configuration
+func (server *Server) loadDocument(ctx context.Context, name string, data []byte, variant string) (document *schema.BOM, err error) {
+	return sbom.LoadData(ctx, name, data, sbom.LoadOptions{
+		Formats:       server.formatConfig,
+		SchemaVariant: variant,
+	})
+}
+
+// Read and load the BOM document from the request body
+func (server *Server) readDocument(writer http.ResponseWriter, request *http.Request, variant string) (document *schema.BOM, err error) {
+	var data []byte
+	if data, err = server.readBody(writer, request); err != nil {
+		return
+	}
+	return server.loadDocument(request.Context(), SERVE_DOCUMENT_NAME_REQUEST, data, variant)
+}
+
+// Read the where filters (i.e., "where" parameter) of the request
+func readServeWhereFilters(values url.Values) (whereFilters []common.WhereFilter, err error) {
+	return retrieveWhereFilters(values.Get(SERVE_PARAM_WHERE))
+}
+
+func readServeBoolParameter(values url.Values, key string, defaultValue bool) (value bool, err error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	if value, err = strconv.ParseBool(raw); err != nil {
+		err = fmt.Errorf("%s: `%s`: `%s`", MSG_SERVE_INVALID_PARAMETER, key, raw)
+	}
+	return
+}
+
+func readServeIntParameter(values url.Values, key string, defaultValue int) (value int, err error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	if value, err = strconv.Atoi(raw); err != nil {
+		err = fmt.Errorf("%s: `%s`: `%s`", MSG_SERVE_INVALID_PARAMETER, key, raw)
+	}
+	return
+}
+
+// -------------------------------------------
+// endpoint handlers
+// -------------------------------------------
+
+func (server *Server) handleValidate(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	values := request.URL.Query()
+
+	var validateFlags utils.ValidateCommandFlags
+	validateFlags.SchemaVariant = values.Get(SERVE_PARAM_VARIANT)
+	if validateFlags.MaxNumErrors, err = readServeIntParameter(values, SERVE_PARAM_ERROR_LIMIT, DEFAULT_MAX_ERROR_LIMIT); err != nil {
+		return
+	}
+	if validateFlags.ShowErrorValue, err = readServeBoolParameter(values, SERVE_PARAM_ERROR_VALUE, true); err != nil {
+		return
+	}
+
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, validateFlags.SchemaVariant); err != nil {
+		return
+	}
+
+	// Note: schema errors (i.e., an invalid BOM) are results; not request errors
+	result, err := sbom.Validate(request.Context(), document, sbom.ValidateOptions{
+		SchemaCache: ValidationSchemaCache,
+	})
+	if err != nil {
+		return nil, NewServeError(http.StatusInternalServerError, err)
+	}
+
+	validateResponse := ServeValidateResponse{
+		Valid:      result.Valid,
+		Format:     result.Format,
+		Version:    result.Version,
+		Variant:    result.Variant,
+		ErrorCount: len(result.SchemaErrors),
+		Errors:     []*ValidationErrorResult{},
+	}
+	for i, resultError := range result.SchemaErrors {
+		if validateFlags.MaxNumErrors > 0 && i >= validateFlags.MaxNumErrors {
+			break
+		}
+		validateResponse.Errors = append(validateResponse.Errors, mapSchemaErrorResult(resultError, validateFlags))
+	}
+	return validateResponse, nil
+}
+
+// Returns the query request from the (query) parameters named after the query command's flags
+func readServeQueryRequest(values url.Values) (qr *common.QueryRequest, err error) {
+	jsonPathExpression := values.Get(FLAG_QUERY_JSONPATH)
+	jmesPathExpression := values.Get(FLAG_QUERY_JMESPATH)
+
+	if jsonPathExpression != "" || jmesPathExpression != "" {
+		if jsonPathExpression != "" && jmesPathExpression != "" {
+			err = common.NewQueryConflictingClausesError(nil, FLAG_QUERY_JSONPATH+", "+FLAG_QUERY_JMESPATH)
+			return
+		}
+		for _, key := range []string{FLAG_QUERY_SELECT, FLAG_QUERY_FROM, FLAG_QUERY_WHERE,
+			FLAG_QUERY_ORDER_BY, FLAG_QUERY_LIMIT, FLAG_QUERY_OFFSET} {
+			if values.Has(key) {
+				err = common.NewQueryConflictingClausesError(nil, key)
+				return
+			}
+		}
+		if jsonPathExpression != "" {
+			return common.NewQueryRequestJSONPath(jsonPathExpression), nil
+		}
+		return common.NewQueryRequestJMESPath(jmesPathExpression), nil
+	}
+
+	var limit, offset int
+	if limit, err = readServeIntParameter(values, FLAG_QUERY_LIMIT, 0); err != nil {
+		return
+	}
+	if offset, err = readServeIntParameter(values, FLAG_QUERY_OFFSET, 0); err != nil {
+		return
+	}
+
+	return NewQueryRequestFromClauses(values.Get(FLAG_QUERY_SELECT), values.Get(FLAG_QUERY_FROM),
+		values.Get(FLAG_QUERY_WHERE), values.Get(FLAG_QUERY_ORDER_BY), limit, offset)
+}
+
+func (server *Server) handleQuery(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	var queryRequest *common.QueryRequest
+	if queryRequest, err = readServeQueryRequest(request.URL.Query()); err != nil {
+		return
+	}
+
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, ""); err != nil {
+		return
+	}
+
+	// At this time, fail SPDX format SBOMs as "unsupported" (i.e., the same as the query command)
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_QUERY, FORMAT_ANY)
+		return
+	}
+
+	return QueryJSONMap(document.GetJSONMap(), queryRequest)
+}
+
+// Returns the license choices (i.e., the license list command's "json" output)
+// or, if a summary is requested, the license information found in the BOM
+func (server *Server) handleLicenseList(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	values := request.URL.Query()
+
+	var summary bool
+	if summary, err = readServeBoolParameter(values, SERVE_PARAM_SUMMARY, false); err != nil {
+		return
+	}
+
+	var whereFilters []common.WhereFilter
+	if whereFilters, err = readServeWhereFilters(values); err != nil {
+		return
+	}
+
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, ""); err != nil {
+		return
+	}
+
+	licenseInfos, err := sbom.ListLicenses(request.Context(), document, sbom.LicenseOptions{
+		LicensePolicies: server.policyConfig,
+		WhereFilters:    whereFilters,
+	})
+	if err != nil || summary {
+		return licenseInfos, err
+	}
+
+	licenseChoices := []schema.CDXLicenseChoice{}
+	for _, licenseInfo := range licenseInfos {
+		if licenseInfo.LicenseChoiceTypeValue != schema.LC_TYPE_INVALID {
+			licenseChoices = append(licenseChoices, licenseInfo.LicenseChoice)
+		}
+	}
+	return licenseChoices, nil
+}
+
+// Returns the license policies (sorted by family name) of the server's license policy configuration
+func (server *Server) handleLicensePolicy(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	var whereFilters []common.WhereFilter
+	if whereFilters, err = readServeWhereFilters(request.URL.Query()); err != nil {
+		return
+	}
+
+	filteredMap, err := server.policyConfig.GetFilteredFamilyNameMap(whereFilters)
+	if err != nil {
+		return
+	}
+
+	keyNames := filteredMap.KeySet()
+	sort.Slice(keyNames, func(i, j int) bool {
+		return keyNames[i].(string) < keyNames[j].(string)
+	})
+
+	policies := []schema.LicensePolicy{}
+	for _, key := range keyNames {
+		values, _ := filteredMap.Get(key)
+		for _, value := range values {
+			policies = append(policies, value.(schema.LicensePolicy))
+		}
+	}
+	return policies, nil
+}
+
+func (server *Server) handleResourceList(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	values := request.URL.Query()
+
+	resourceType := values.Get(SERVE_PARAM_TYPE)
+	if resourceType == "" {
+		resourceType = schema.RESOURCE_TYPE_DEFAULT
+	}
+	if !schema.IsValidResourceType(resourceType) {
+		err = fmt.Errorf("%s: `%s`: `%s`", MSG_SERVE_INVALID_PARAMETER, SERVE_PARAM_TYPE, resourceType)
+		return
+	}
+
+	var whereFilters []common.WhereFilter
+	if whereFilters, err = readServeWhereFilters(values); err != nil {
+		return
+	}
+
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, ""); err != nil {
+		return
+	}
+
+	return sbom.ListResources(request.Context(), document, sbom.ResourceOptions{
+		ResourceType: resourceType,
+		WhereFilters: whereFilters,
+	})
+}
+
+func (server *Server) handleVulnerabilityList(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	var whereFilters []common.WhereFilter
+	if whereFilters, err = readServeWhereFilters(request.URL.Query()); err != nil {
+		return
+	}
+
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, ""); err != nil {
+		return
+	}
+
+	return sbom.ListVulnerabilities(request.Context(), document, sbom.VulnerabilityOptions{
+		WhereFilters: whereFilters,
+	})
+}
+
+// Returns the (component, service and vulnerability) statistics (i.e., the same as the stats command's output)
+func (server *Server) handleStats(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	var document *schema.BOM
+	if document, err = server.readDocument(writer, request, ""); err != nil {
+		return
+	}
+
+	if err = loadDocumentStatisticalEntities(document, utils.StatsCommandFlags{}); err != nil {
+		return
+	}
+
+	if err = loadStatistics(document); err != nil {
+		return
+	}
+	return document.Statistics, nil
+}
+
+// Returns the semantic (i.e., identity-aware) diff of the "base" and "revised" BOMs of the request
+func (server *Server) handleDiff(writer http.ResponseWriter, request *http.Request) (response interface{}, err error) {
+	var data []byte
+	if data, err = server.readBody(writer, request); err != nil {
+		return
+	}
+
+	var diffRequest ServeDiffRequest
+	if err = json.Unmarshal(data, &diffRequest); err != nil || len(diffRequest.Base) == 0 || len(diffRequest.Revised) == 0 {
+		return nil, errors.New(MSG_SERVE_DIFF_INVALID_REQUEST)
+	}
+
+	var baseBom, revisedBom *schema.BOM
+	if baseBom, err = server.loadDiffDocument(request.Context(), SERVE_DOCUMENT_NAME_BASE, diffRequest.Base); err != nil {
+		return
+	}
+	if revisedBom, err = server.loadDiffDocument(request.Context(), SERVE_DOCUMENT_NAME_REVISED, diffRequest.Revised); err != nil {
+		return
+	}
+	return baseBom.DiffSemantic(revisedBom)
+}
+
+func (server *Server) loadDiffDocument(ctx context.Context, name string, data []byte) (document *schema.BOM, err error) {
+	if document, err = server.loadDocument(ctx, name, data, ""); err != nil {
+		return
+	}
+
+	// At this time, fail SPDX format SBOMs as "unsupported" (i.e., the same as the diff command)
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_DIFF, FORMAT_ANY)
+		return
+	}
+
+	err = document.UnmarshalCycloneDXBOM()
+	return
+}
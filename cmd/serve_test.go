@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// -------------------------------------------
+// serve test helper functions
+// -------------------------------------------
+
+func newTestServer(maxRequestSize int64) *Server {
+	flags := utils.ServeCommandFlags{
+		Address:        DEFAULT_SERVE_ADDRESS,
+		MaxRequestSize: maxRequestSize,
+	}
+	return NewServer(flags, &SupportedFormatConfig, LicensePolicyConfig, DEFAULT_OUTPUT_INDENT_LENGTH)
+}
+
+// Issue a request against the server's handler and decode the JSON response body
+func innerTestServe(t *testing.T, server *Server, method string, target string, body []byte, expectedStatus int, response interface{}) {
+	request := httptest.NewRequest(method, target, bytes.NewReader(body))
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+
+	if recorder.Code != expectedStatus {
+		t.Errorf("invalid status: `%s %s`: expected: `%v`, actual: `%v`\n%s",
+			method, target, expectedStatus, recorder.Code, recorder.Body.String())
+		return
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != SERVE_CONTENT_TYPE_JSON {
+		t.Errorf("invalid content type: expected: `%s`, actual: `%s`", SERVE_CONTENT_TYPE_JSON, contentType)
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Errorf("unable to decode response: %s\n%s", err, recorder.Body.String())
+		}
+	}
+}
+
+// -------------------------------------------
+// request tests
+// -------------------------------------------
+
+func TestServeMethodNotAllowed(t *testing.T) {
+	var response ServeErrorResponse
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodGet, SERVE_PATH_VALIDATE, nil,
+		http.StatusMethodNotAllowed, &response)
+	if response.Status != http.StatusMethodNotAllowed {
+		t.Errorf("invalid error response status: `%v`", response.Status)
+	}
+}
+
+func TestServeRequestEmpty(t *testing.T) {
+	var response ServeErrorResponse
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_VALIDATE, nil,
+		http.StatusBadRequest, &response)
+	if response.Message != MSG_SERVE_REQUEST_EMPTY {
+		t.Errorf("invalid error response message: `%s`", response.Message)
+	}
+}
+
+func TestServeRequestTooLarge(t *testing.T) {
+	data := readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED)
+	innerTestServe(t, newTestServer(int64(len(data)-1)), http.MethodPost, SERVE_PATH_VALIDATE, data,
+		http.StatusRequestEntityTooLarge, nil)
+}
+
+func TestServeRequestInvalidJSON(t *testing.T) {
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_VALIDATE, []byte("{"),
+		http.StatusBadRequest, nil)
+}
+
+func TestServeRequestInvalidParameter(t *testing.T) {
+	data := readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED)
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost,
+		SERVE_PATH_VALIDATE+"?"+SERVE_PARAM_ERROR_LIMIT+"=abc", data, http.StatusBadRequest, nil)
+}
+
+// -------------------------------------------
+// endpoint tests
+// -------------------------------------------
+
+func TestServeValidateSpdxValid(t *testing.T) {
+	var response ServeValidateResponse
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_VALIDATE,
+		readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED), http.StatusOK, &response)
+	if !response.Valid || response.ErrorCount != 0 {
+		t.Errorf("expected valid BOM: %+v", response)
+	}
+	if response.Format != schema.SCHEMA_FORMAT_SPDX {
+		t.Errorf("invalid format: `%s`", response.Format)
+	}
+}
+
+func TestServeValidateSpdxInvalid(t *testing.T) {
+	// Note: validation error results are (custom) marshalled as ordered maps
+	var response struct {
+		Valid      bool                     `json:"valid"`
+		ErrorCount int                      `json:"errorCount"`
+		Errors     []map[string]interface{} `json:"errors"`
+	}
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_VALIDATE,
+		readTestFile(t, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING), http.StatusOK, &response)
+	if response.Valid || response.ErrorCount != 1 || len(response.Errors) != 1 {
+		t.Errorf("expected (1) schema error: %+v", response)
+		return
+	}
+	if response.Errors[0]["type"] != "required" {
+		t.Errorf("invalid error type: `%v`", response.Errors[0]["type"])
+	}
+}
+
+func TestServeQueryCdx(t *testing.T) {
+	var response []map[string]interface{}
+	target := fmt.Sprintf("%s?%s=name,version&%s=components&%s=2",
+		SERVE_PATH_QUERY, FLAG_QUERY_SELECT, FLAG_QUERY_FROM, FLAG_QUERY_LIMIT)
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, target,
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusOK, &response)
+	if len(response) != 2 || response[0]["name"] != "Library A" {
+		t.Errorf("invalid query result: %v", response)
+	}
+}
+
+func TestServeQueryConflictingClauses(t *testing.T) {
+	target := fmt.Sprintf("%s?%s=$.metadata&%s=components",
+		SERVE_PATH_QUERY, FLAG_QUERY_JSONPATH, FLAG_QUERY_FROM)
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, target,
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusBadRequest, nil)
+}
+
+func TestServeQuerySpdxUnsupported(t *testing.T) {
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_QUERY,
+		readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED), http.StatusBadRequest, nil)
+}
+
+func TestServeLicenseListSummary(t *testing.T) {
+	var response []schema.LicenseInfo
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost,
+		SERVE_PATH_LICENSE_LIST+"?"+SERVE_PARAM_SUMMARY+"=true",
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusOK, &response)
+	if len(response) == 0 {
+		t.Errorf("expected license information")
+	}
+}
+
+func TestServeLicensePolicyWhere(t *testing.T) {
+	var response []schema.LicensePolicy
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodGet,
+		SERVE_PATH_LICENSE_POLICY+"?"+SERVE_PARAM_WHERE+"=family=Apache", nil, http.StatusOK, &response)
+	if len(response) == 0 {
+		t.Errorf("expected license policies")
+	}
+	for _, policy := range response {
+		if policy.Family != "Apache" {
+			t.Errorf("invalid license policy family: `%s`", policy.Family)
+		}
+	}
+}
+
+func TestServeResourceListService(t *testing.T) {
+	var response []schema.CDXResourceInfo
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost,
+		SERVE_PATH_RESOURCE_LIST+"?"+SERVE_PARAM_TYPE+"="+schema.RESOURCE_TYPE_SERVICE,
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusOK, &response)
+	if len(response) == 0 {
+		t.Errorf("expected service resources")
+	}
+	for _, resource := range response {
+		if resource.Type != schema.RESOURCE_TYPE_SERVICE {
+			t.Errorf("invalid resource type: `%s`", resource.Type)
+		}
+	}
+}
+
+func TestServeResourceListInvalidType(t *testing.T) {
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost,
+		SERVE_PATH_RESOURCE_LIST+"?"+SERVE_PARAM_TYPE+"=foo",
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusBadRequest, nil)
+}
+
+func TestServeVulnerabilityList(t *testing.T) {
+	var response []schema.VulnerabilityInfo
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_VULNERABILITY_LIST,
+		readTestFile(t, TEST_VULN_CDX_1_4_EXAMPLE_1_VEX), http.StatusOK, &response)
+	if len(response) == 0 {
+		t.Errorf("expected vulnerabilities")
+	}
+}
+
+func TestServeStats(t *testing.T) {
+	var response schema.StatisticsInfo
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_STATS,
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusOK, &response)
+
+	// The same statistics as the stats command outputs
+	if stats := response.ComponentStats; stats == nil || stats.Total != 11 ||
+		stats.MapTypes["library"] != 10 || stats.MapIdentifiers["purl"] != 11 {
+		t.Errorf("invalid component statistics: %v", stats)
+	}
+	if stats := response.ServiceStats; stats == nil || stats.Total != 2 || stats.MapEndpoints["Foo"] != 1 {
+		t.Errorf("invalid service statistics: %v", stats)
+	}
+	if stats := response.VulnerabilityStats; stats == nil || stats.Total != 0 {
+		t.Errorf("invalid vulnerability statistics: %v", stats)
+	}
+}
+
+func TestServeDiff(t *testing.T) {
+	diffRequest := fmt.Sprintf(`{"base":%s,"revised":%s}`,
+		readTestFile(t, TEST_DIFF_SEMANTIC_CDX_1_5_BASE),
+		readTestFile(t, TEST_DIFF_SEMANTIC_CDX_1_5_REVISED))
+	var response schema.BOMDiffResult
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_DIFF,
+		[]byte(diffRequest), http.StatusOK, &response)
+}
+
+func TestServeDiffInvalidRequest(t *testing.T) {
+	innerTestServe(t, newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE), http.MethodPost, SERVE_PATH_DIFF,
+		readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3), http.StatusBadRequest, nil)
+}
+
+// Note: run with the "-race" flag to detect unsafe use of shared configurations
+func TestServeConcurrentRequests(t *testing.T) {
+	server := newTestServer(DEFAULT_SERVE_MAX_REQUEST_SIZE)
+	cdxData := readTestFile(t, TEST_RESOURCE_LIST_CDX_1_3)
+	spdxData := readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			innerTestServe(t, server, http.MethodPost, SERVE_PATH_VALIDATE, spdxData, http.StatusOK, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			innerTestServe(t, server, http.MethodPost, SERVE_PATH_LICENSE_LIST, cdxData, http.StatusOK, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			innerTestServe(t, server, http.MethodGet, SERVE_PATH_LICENSE_POLICY+"?"+SERVE_PARAM_WHERE+"=family=GPL",
+				nil, http.StatusOK, nil)
+		}()
+	}
+	wg.Wait()
+}
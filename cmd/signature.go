@@ -0,0 +1,558 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SUBCOMMAND_SIGNATURE_SIGN   = "sign"
+	SUBCOMMAND_SIGNATURE_VERIFY = "verify"
+)
+
+var VALID_SUBCOMMANDS_SIGNATURE = []string{SUBCOMMAND_SIGNATURE_SIGN, SUBCOMMAND_SIGNATURE_VERIFY}
+
+const (
+	FLAG_SIGNATURE_KEY         = "key"
+	FLAG_SIGNATURE_ALGORITHM   = "algorithm"
+	FLAG_SIGNATURE_KEY_ID      = "key-id"
+	FLAG_SIGNATURE_CERTIFICATE = "certificate"
+	FLAG_SIGNATURE_EMBED_KEY   = "embed-key"
+	FLAG_SIGNATURE_TYPE        = "type"
+	FLAG_SIGNATURE_EXCLUDES    = "excludes"
+	FLAG_SIGNATURE_FROM        = "from"
+	FLAG_SIGNATURE_WHERE       = "where"
+)
+
+// Command help formatting
+const (
+	FLAG_SIGNATURE_OUTPUT_FORMAT_HELP = "format signature output"
+	FLAG_SIGNATURE_KEY_HELP           = "PEM file(s) containing: (verify) a public key or (X.509) certificate used to verify all signatures (overrides any embedded \"publicKey\" or \"certificatePath\"); " +
+		"(sign) a private key for each signer"
+	FLAG_SIGNATURE_ALGORITHM_HELP   = "(sign) JWA algorithm for each signer (e.g., RS256, PS256, ES384, Ed25519); defaults to an algorithm based upon the private key"
+	FLAG_SIGNATURE_KEY_ID_HELP      = "(sign) key identifier (i.e., \"keyId\") for each signer"
+	FLAG_SIGNATURE_CERTIFICATE_HELP = "(sign) PEM file containing the (X.509) certificate path (signature certificate first) for each signer; used instead of an embedded public key"
+	FLAG_SIGNATURE_EMBED_KEY_HELP   = "(sign) embed each signer's public key (i.e., \"publicKey\") in the signature"
+	FLAG_SIGNATURE_TYPE_HELP        = "(sign) signature type: \"signature\" (single signer), \"signers\" (independent signers) or \"chain\" (each signer also signs prior signers); signers are appended to an existing \"signers\" or \"chain\""
+	FLAG_SIGNATURE_EXCLUDES_HELP    = "(sign) comma-separated list of property names to exclude from the signature(s)"
+	FLAG_SIGNATURE_FROM_HELP        = "(sign) dot-separated list of JSON key names used to select the object(s) to sign (e.g., \"metadata.component\", \"components\"); defaults to the BOM"
+	FLAG_SIGNATURE_WHERE_HELP       = "(sign) comma-separated list of key=<regex> clauses used to filter the array of objects selected using --" + FLAG_SIGNATURE_FROM
+)
+
+var SIGNATURE_VERIFY_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_CSV, FORMAT_MARKDOWN, FORMAT_JSON}, ", ")
+
+// Overall signature verification status (of the BOM)
+const (
+	SIGNATURE_STATUS_UNSIGNED = "unsigned"
+	SIGNATURE_STATUS_VALID    = "valid"
+	SIGNATURE_STATUS_INVALID  = "invalid"
+)
+
+// Signature command informational messages
+const (
+	MSG_OUTPUT_NO_SIGNATURES_FOUND = "[WARN] no signatures found"
+)
+
+// Signature command error messages
+const (
+	MSG_SIGNATURE_KEY_MULTIPLE      = "only one key file may be used to verify signatures"
+	MSG_SIGNATURE_KEY_REQUIRED      = "at least one private key file is required to sign"
+	MSG_SIGNATURE_FLAG_COUNT        = "the number of `--%s` values (%v) must match the number of `--%s` values (%v)"
+	MSG_SIGNATURE_OBJECTS_NOT_FOUND = "no objects found to sign"
+)
+
+// Report column titles
+var SIGNATURE_VERIFY_TITLES = []string{
+	"path", "type", "index", "algorithm", "key-id", "key-source", "valid", "message",
+}
+
+func NewCommandSignature() *cobra.Command {
+	var command = new(cobra.Command)
+	command.Use = CMD_USAGE_SIGNATURE
+	command.Short = "Sign or verify JSON Signature Format (JSF) signatures of the BOM input file"
+	command.Long = "Sign the BOM input file (or selected components and services) using JSON Signature Format (JSF) signatures and write the signed BOM to output, " +
+		"or verify JSF signatures (i.e., simple, \"signers\" and \"chain\") found on the BOM input file as well as on any of its components and services. " +
+		"When verifying, exits with code 0 if all signatures are valid, 2 if any signature is invalid and 3 if no signatures were found"
+	command.Example = "  " + CMD_USAGE_SIGNATURE_SIGN + "\n  " + CMD_USAGE_SIGNATURE_VERIFY
+	command.Flags().StringVarP(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_FILE_OUTPUT_FORMAT, "", FORMAT_TEXT,
+		FLAG_SIGNATURE_OUTPUT_FORMAT_HELP+SIGNATURE_VERIFY_SUPPORTED_FORMATS)
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.SignatureFlags.KeyFiles, FLAG_SIGNATURE_KEY, "", nil, FLAG_SIGNATURE_KEY_HELP)
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.SignatureFlags.Algorithms, FLAG_SIGNATURE_ALGORITHM, "", nil, FLAG_SIGNATURE_ALGORITHM_HELP)
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.SignatureFlags.KeyIds, FLAG_SIGNATURE_KEY_ID, "", nil, FLAG_SIGNATURE_KEY_ID_HELP)
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.SignatureFlags.CertificateFiles, FLAG_SIGNATURE_CERTIFICATE, "", nil, FLAG_SIGNATURE_CERTIFICATE_HELP)
+	command.Flags().BoolVarP(&utils.GlobalFlags.SignatureFlags.EmbedPublicKey, FLAG_SIGNATURE_EMBED_KEY, "", true, FLAG_SIGNATURE_EMBED_KEY_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.SignatureFlags.SignatureType, FLAG_SIGNATURE_TYPE, "", schema.JSF_SIGNATURE_TYPE_SIGNATURE, FLAG_SIGNATURE_TYPE_HELP)
+	command.Flags().StringSliceVarP(&utils.GlobalFlags.SignatureFlags.Excludes, FLAG_SIGNATURE_EXCLUDES, "", nil, FLAG_SIGNATURE_EXCLUDES_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.SignatureFlags.From, FLAG_SIGNATURE_FROM, "", "", FLAG_SIGNATURE_FROM_HELP)
+	command.Flags().StringVarP(&utils.GlobalFlags.SignatureFlags.Where, FLAG_SIGNATURE_WHERE, "", "", FLAG_SIGNATURE_WHERE_HELP)
+	command.RunE = signatureCmdImpl
+	command.ValidArgs = VALID_SUBCOMMANDS_SIGNATURE
+	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
+		// the signature command requires at least 1 valid subcommand (argument)
+		getLogger().Tracef("args: %v\n", args)
+		if len(args) == 0 {
+			return getLogger().Errorf("Missing required argument(s).")
+		} else if len(args) > 1 {
+			return getLogger().Errorf("Too many arguments provided: %v", args)
+		}
+
+		// Make sure subcommand is known
+		if !preRunTestForSubcommand(command, VALID_SUBCOMMANDS_SIGNATURE, args[0]) {
+			return getLogger().Errorf("Subcommand provided is not valid: `%v`", args[0])
+		}
+
+		// Test for required flags (parameters)
+		err = preRunTestForInputFile(cmd, args)
+		return
+	}
+	return command
+}
+
+// Cobra command callback
+func signatureCmdImpl(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter(args)
+	defer getLogger().Exit()
+
+	// Create output writer
+	outputFilename := utils.GlobalFlags.PersistentFlags.OutputFile
+	outputFile, writer, err := createOutputFile(outputFilename)
+	getLogger().Tracef("outputFile: `%v`; writer: `%v`", outputFile, writer)
+	if err != nil {
+		return
+	}
+
+	var status string
+	if args[0] == SUBCOMMAND_SIGNATURE_SIGN {
+		// Note: the signed BOM is output as JSON unless another format is explicitly requested
+		if !cmd.Flags().Changed(FLAG_FILE_OUTPUT_FORMAT) {
+			utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_JSON
+		}
+		err = Sign(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.SignatureFlags)
+	} else {
+		status, err = VerifySignatures(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.SignatureFlags)
+	}
+
+	// always close the output file (before exiting with a status-specific code)
+	if outputFile != nil {
+		outputFile.Close()
+		getLogger().Infof("Closed output file: `%s`", outputFilename)
+	}
+
+	if err != nil {
+		return
+	}
+
+	// Note: distinct exit codes allow CI pipelines to gate on the signature status
+	switch status {
+	case SIGNATURE_STATUS_INVALID:
+		os.Exit(ERROR_VALIDATION)
+	case SIGNATURE_STATUS_UNSIGNED:
+		os.Exit(ERROR_UNSIGNED)
+	}
+	return
+}
+
+// Assure all errors are logged
+func processSignatureResults(err error) {
+	if err != nil {
+		// No special processing at this time
+		getLogger().Error(err)
+	}
+}
+
+// Verifies all signatures found in the input BOM and returns the overall status:
+// "unsigned" (no signatures found), "valid" (all signatures valid) or "invalid"
+func VerifySignatures(writer io.Writer, persistentFlags utils.PersistentCommandFlags, signatureFlags utils.SignatureCommandFlags) (status string, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processSignatureResults(err)
+		}
+	}()
+
+	// Load the (optional) user-supplied public key before loading the BOM
+	var publicKey crypto.PublicKey
+	if len(signatureFlags.KeyFiles) > 1 {
+		err = getLogger().Errorf(MSG_SIGNATURE_KEY_MULTIPLE)
+		return
+	} else if len(signatureFlags.KeyFiles) == 1 {
+		if publicKey, err = loadSignaturePublicKey(signatureFlags.KeyFiles[0]); err != nil {
+			return
+		}
+	}
+
+	// Note: returns error if either file load or unmarshal to JSON map fails
+	var document *schema.BOM
+	if document, err = LoadInputBOMFileAndDetectSchema(); err != nil {
+		return
+	}
+
+	// JSF signatures are only defined for CycloneDX JSON BOMs
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_SIGNATURE, FORMAT_ANY)
+		return
+	}
+
+	results := document.VerifySignatures(publicKey)
+	for i := range results {
+		if results[i].Path == "" {
+			results[i].Path = JSON_CONTEXT_ROOT
+		}
+	}
+	status = signatureStatus(results)
+	getLogger().Infof("Signature status: `%s` (%v signature(s) verified)", status, len(results))
+
+	format := persistentFlags.OutputFormat
+	getLogger().Infof("Outputting listing (`%s` format)...", format)
+	switch format {
+	case FORMAT_JSON:
+		err = DisplaySignatureResultsJSON(writer, results)
+	case FORMAT_CSV:
+		err = DisplaySignatureResultsCSV(writer, results)
+	case FORMAT_MARKDOWN:
+		DisplaySignatureResultsMarkdown(writer, results)
+	case FORMAT_TEXT:
+		DisplaySignatureResultsText(writer, results)
+	default:
+		// Default to Text output for anything else (set as flag default)
+		getLogger().Warningf("Listing not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_TEXT)
+		DisplaySignatureResultsText(writer, results)
+	}
+	return
+}
+
+// Signs the input BOM (or the objects selected using "from" and "where") and outputs the signed BOM
+func Sign(writer io.Writer, persistentFlags utils.PersistentCommandFlags, signatureFlags utils.SignatureCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// use function closure to assure consistent error output based upon error type
+	defer func() {
+		if err != nil {
+			processSignatureResults(err)
+		}
+	}()
+
+	// Load all signing keys (and their signer properties) before loading the BOM
+	var signingKeys []schema.JSFSigningKey
+	if signingKeys, err = loadSigningKeys(signatureFlags); err != nil {
+		return
+	}
+
+	// Note: returns error if either file load or unmarshal to JSON map fails
+	var document *schema.BOM
+	if document, err = LoadInputBOMFileAndDetectSchema(); err != nil {
+		return
+	}
+
+	// JSF signatures are only defined for CycloneDX JSON BOMs
+	if !document.FormatInfo.IsCycloneDx() {
+		err = schema.NewUnsupportedFormatForCommandError(
+			document.GetFilename(),
+			document.FormatInfo.CanonicalName,
+			CMD_SIGNATURE, FORMAT_ANY)
+		return
+	}
+
+	var objects []map[string]interface{}
+	if objects, err = findObjectsToSign(document, signatureFlags); err != nil {
+		return
+	}
+
+	for _, object := range objects {
+		if err = schema.SignJSFObject(object, signatureFlags.SignatureType, signingKeys, signatureFlags.Excludes); err != nil {
+			return
+		}
+	}
+	getLogger().Infof("Signed %v object(s) using %v signer(s)", len(objects), len(signingKeys))
+
+	// Note: the BOM's JSON map is output (i.e., not its CycloneDX structures) so that
+	// the signed data is exactly what is read (and canonicalized) when verifying
+	format := persistentFlags.OutputFormat
+	if format != FORMAT_JSON {
+		getLogger().Warningf("Sign not supported for `%s` format; defaulting to `%s` format...",
+			format, FORMAT_JSON)
+	}
+	var output bytes.Buffer
+	indentString := utils.GenerateIndentString(int(persistentFlags.OutputIndent))
+	if output, err = utils.EncodeAnyToIndentedJSONStr(document.GetJSONMap(), indentString); err == nil {
+		_, err = writer.Write(output.Bytes())
+	}
+	return
+}
+
+// Returns the object(s) to sign; the BOM itself if no "from" path is provided
+func findObjectsToSign(document *schema.BOM, signatureFlags utils.SignatureCommandFlags) (objects []map[string]interface{}, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	jsonMap := document.GetJSONMap()
+	if signatureFlags.From == "" {
+		if signatureFlags.Where != "" {
+			getLogger().Warningf("Cannot apply WHERE filter (%s) to the BOM; ignoring...", signatureFlags.Where)
+		}
+		return []map[string]interface{}{jsonMap}, nil
+	}
+
+	// Note: signing objects within a signed BOM invalidates the BOM's signature
+	if _, signed := jsonMap[schema.JSF_KEY_SIGNATURE]; signed {
+		getLogger().Warningf("BOM signature will be invalidated by signing objects within it (i.e., `%s`)", signatureFlags.From)
+	}
+
+	var request *common.QueryRequest
+	if request, err = common.NewQueryRequestSelectWildcardFromWhere(signatureFlags.From, signatureFlags.Where); err != nil {
+		return
+	}
+	var result interface{}
+	if result, err = QueryJSONMap(jsonMap, request); err != nil {
+		return
+	}
+
+	switch typedResult := result.(type) {
+	case map[string]interface{}:
+		objects = append(objects, typedResult)
+	case []interface{}:
+		for _, entry := range typedResult {
+			if object, ok := entry.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+	if len(objects) == 0 {
+		err = getLogger().Errorf("%s: (from: `%s`, where: `%s`)", MSG_SIGNATURE_OBJECTS_NOT_FOUND,
+			signatureFlags.From, signatureFlags.Where)
+	}
+	return
+}
+
+// Loads the private key for each signer along with any of its (optional) algorithm,
+// key ID and certificate path; when provided, these must be provided for every signer
+func loadSigningKeys(signatureFlags utils.SignatureCommandFlags) (signingKeys []schema.JSFSigningKey, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	numKeys := len(signatureFlags.KeyFiles)
+	if numKeys == 0 {
+		err = getLogger().Errorf(MSG_SIGNATURE_KEY_REQUIRED)
+		return
+	}
+	for flag, values := range map[string][]string{
+		FLAG_SIGNATURE_ALGORITHM:   signatureFlags.Algorithms,
+		FLAG_SIGNATURE_KEY_ID:      signatureFlags.KeyIds,
+		FLAG_SIGNATURE_CERTIFICATE: signatureFlags.CertificateFiles,
+	} {
+		if len(values) > 0 && len(values) != numKeys {
+			err = getLogger().Errorf(MSG_SIGNATURE_FLAG_COUNT, flag, len(values), FLAG_SIGNATURE_KEY, numKeys)
+			return
+		}
+	}
+
+	for i, keyFile := range signatureFlags.KeyFiles {
+		signingKey := schema.JSFSigningKey{
+			EmbedPublicKey: signatureFlags.EmbedPublicKey,
+		}
+		var data []byte
+		if data, err = os.ReadFile(keyFile); err != nil {
+			return
+		}
+		if signingKey.Signer, err = schema.ParsePrivateKeyPEM(data); err != nil {
+			err = getLogger().Errorf("unable to load private key from file: `%s`: %s", keyFile, err)
+			return
+		}
+		if len(signatureFlags.Algorithms) > 0 {
+			signingKey.Algorithm = signatureFlags.Algorithms[i]
+		}
+		if len(signatureFlags.KeyIds) > 0 {
+			signingKey.KeyId = signatureFlags.KeyIds[i]
+		}
+		if len(signatureFlags.CertificateFiles) > 0 {
+			certificateFile := signatureFlags.CertificateFiles[i]
+			if data, err = os.ReadFile(certificateFile); err != nil {
+				return
+			}
+			if signingKey.CertificatePath, err = schema.ParseCertificatePathPEM(data); err != nil {
+				err = getLogger().Errorf("unable to load certificate path from file: `%s`: %s", certificateFile, err)
+				return
+			}
+		}
+		signingKeys = append(signingKeys, signingKey)
+	}
+	return
+}
+
+func loadSignaturePublicKey(keyFile string) (publicKey crypto.PublicKey, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var data []byte
+	if data, err = os.ReadFile(keyFile); err != nil {
+		return
+	}
+	if publicKey, err = schema.ParsePublicKeyPEM(data); err != nil {
+		err = getLogger().Errorf("unable to load public key from file: `%s`: %s", keyFile, err)
+	}
+	return
+}
+
+func signatureStatus(results []schema.JSFVerificationResult) string {
+	if len(results) == 0 {
+		return SIGNATURE_STATUS_UNSIGNED
+	}
+	for _, result := range results {
+		if !result.Valid {
+			return SIGNATURE_STATUS_INVALID
+		}
+	}
+	return SIGNATURE_STATUS_VALID
+}
+
+// -------------------
+// Report line data
+// -------------------
+
+func signatureResultLineData(result schema.JSFVerificationResult) []string {
+	return []string{
+		result.Path,
+		result.Type,
+		strconv.Itoa(result.Index),
+		result.Algorithm,
+		result.KeyId,
+		result.KeySource,
+		strconv.FormatBool(result.Valid),
+		result.Message,
+	}
+}
+
+// -------------------
+// JSON
+// -------------------
+
+func DisplaySignatureResultsJSON(writer io.Writer, results []schema.JSFVerificationResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// Note: assure an empty JSON array (not "null") is output if no signatures were found
+	if results == nil {
+		results = []schema.JSFVerificationResult{}
+	}
+	var output bytes.Buffer
+	indentString := utils.GenerateIndentString(int(utils.GlobalFlags.PersistentFlags.OutputIndent))
+	if output, err = utils.EncodeAnyToIndentedJSONStr(results, indentString); err == nil {
+		_, err = writer.Write(output.Bytes())
+	}
+	return
+}
+
+// -------------------
+// Text
+// -------------------
+
+func DisplaySignatureResultsText(writer io.Writer, results []schema.JSFVerificationResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+	defer w.Flush()
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	fmt.Fprintf(w, "%s\n", strings.Join(SIGNATURE_VERIFY_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(SIGNATURE_VERIFY_TITLES), "\t"))
+	if len(results) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_SIGNATURES_FOUND)
+		return
+	}
+	for _, result := range results {
+		fmt.Fprintf(w, "%s\n", strings.Join(signatureResultLineData(result), "\t"))
+	}
+}
+
+// -------------------
+// CSV
+// -------------------
+
+func DisplaySignatureResultsCSV(writer io.Writer, results []schema.JSFVerificationResult) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	if err = w.Write(SIGNATURE_VERIFY_TITLES); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", SIGNATURE_VERIFY_TITLES, err)
+	}
+
+	if len(results) == 0 {
+		if err = w.Write([]string{MSG_OUTPUT_NO_SIGNATURES_FOUND}); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", MSG_OUTPUT_NO_SIGNATURES_FOUND, err)
+		}
+		return
+	}
+
+	for _, result := range results {
+		if err = w.Write(signatureResultLineData(result)); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
+		}
+	}
+	return
+}
+
+// -------------------
+// Markdown
+// -------------------
+
+func DisplaySignatureResultsMarkdown(writer io.Writer, results []schema.JSFVerificationResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(SIGNATURE_VERIFY_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(SIGNATURE_VERIFY_TITLES)))
+	if len(results) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_SIGNATURES_FOUND)
+		return
+	}
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(signatureResultLineData(result)))
+	}
+}
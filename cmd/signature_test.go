@@ -0,0 +1,385 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	// Test "signature verify" command
+	TEST_SIGNATURE_CDX_1_5_RS256      = "test/signature/cdx-1-5-signature-rs256.json"
+	TEST_SIGNATURE_CDX_1_5_SIGNERS    = "test/signature/cdx-1-5-signature-signers.json"
+	TEST_SIGNATURE_CDX_1_5_CHAIN      = "test/signature/cdx-1-5-signature-chain.json"
+	TEST_SIGNATURE_CDX_1_5_COMPONENTS = "test/signature/cdx-1-5-signature-components.json"
+	TEST_SIGNATURE_CDX_1_5_KEY_ID     = "test/signature/cdx-1-5-signature-keyid.json"
+	TEST_SIGNATURE_CDX_1_5_INVALID    = "test/signature/cdx-1-5-signature-invalid.json"
+	TEST_SIGNATURE_KEY_RSA_PUBLIC     = "test/signature/keys/rsa-public.pem"
+	TEST_SIGNATURE_KEY_RSA_PRIVATE    = "test/signature/keys/rsa-private.pem"
+)
+
+type SignatureTestInfo struct {
+	CommonTestInfo
+	KeyFile              string
+	ResultExpectedStatus string
+}
+
+// Stringer interface for SignatureTestInfo (just display subset of key values)
+func (ti *SignatureTestInfo) String() string {
+	buffer, _ := utils.EncodeAnyToDefaultIndentedJSONStr(ti)
+	return buffer.String()
+}
+
+func NewSignatureTestInfoBasic(inputFile string, listFormat string, resultExpectedError error, resultExpectedStatus string) *SignatureTestInfo {
+	var ti = new(SignatureTestInfo)
+	var pCommon = &ti.CommonTestInfo
+	pCommon.InitBasic(inputFile, listFormat, resultExpectedError)
+	ti.ResultExpectedStatus = resultExpectedStatus
+	return ti
+}
+
+// -------------------------------------------
+// Signature verify test helper functions
+// -------------------------------------------
+func innerBufferedTestSignatureVerify(t *testing.T, testInfo *SignatureTestInfo) (outputBuffer bytes.Buffer, status string, err error) {
+	// Declare an output outputBuffer/outputWriter to use used during tests
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	// ensure all data is written to buffer before further validation
+	defer outputWriter.Flush()
+
+	utils.GlobalFlags.PersistentFlags.OutputFormat = testInfo.OutputFormat
+	flags := utils.SignatureCommandFlags{}
+	if testInfo.KeyFile != "" {
+		flags.KeyFiles = []string{testInfo.KeyFile}
+	}
+	status, err = VerifySignatures(outputWriter, utils.GlobalFlags.PersistentFlags, flags)
+	return
+}
+
+func innerTestSignatureVerify(t *testing.T, testInfo *SignatureTestInfo) (outputBuffer bytes.Buffer, err error) {
+	getLogger().Tracef("TestInfo: %s", testInfo)
+
+	// The command looks for the input filename in global flags struct
+	utils.GlobalFlags.PersistentFlags.InputFile = testInfo.InputFile
+
+	// invoke verify command with a byte buffer
+	var status string
+	outputBuffer, status, err = innerBufferedTestSignatureVerify(t, testInfo)
+
+	if err == nil && status != testInfo.ResultExpectedStatus {
+		t.Errorf("expected status: `%s`, actual: `%s`", testInfo.ResultExpectedStatus, status)
+	}
+
+	// Run all common tests against "result" values in the CommonTestInfo struct
+	err = innerRunReportResultTests(t, &testInfo.CommonTestInfo, outputBuffer, err)
+
+	return
+}
+
+// ----------------------------------------
+// Command flag tests
+// ----------------------------------------
+
+func TestSignatureVerifyInvalidInputFileLoad(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_INPUT_FILE_NON_EXISTENT,
+		FORMAT_DEFAULT,
+		&fs.PathError{}, "")
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifySpdxUnsupported(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		FORMAT_TEXT,
+		&schema.UnsupportedFormatError{}, "")
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyKeyFileNotFound(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_RS256,
+		FORMAT_TEXT,
+		&fs.PathError{}, "")
+	testInfo.KeyFile = TEST_INPUT_FILE_NON_EXISTENT
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyKeyFileNotPublicKey(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_RS256,
+		FORMAT_TEXT,
+		nil, "")
+	testInfo.KeyFile = TEST_SIGNATURE_KEY_RSA_PRIVATE
+	_, _, err := innerBufferedTestSignatureVerify(t, testInfo)
+	if err == nil {
+		t.Errorf("expected error loading public key from: `%s`", testInfo.KeyFile)
+	}
+}
+
+// -------------------------------------------
+// Signature status
+// -------------------------------------------
+
+func TestSignatureVerifyCdx15Unsigned(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_CDX_1_5_MIN_REQUIRED,
+		FORMAT_TEXT,
+		nil, SIGNATURE_STATUS_UNSIGNED)
+	testInfo.ResultExpectedLineCount = 3
+	testInfo.ResultLineContainsValues = []string{MSG_OUTPUT_NO_SIGNATURES_FOUND}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15RS256Text(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_RS256,
+		FORMAT_TEXT,
+		nil, SIGNATURE_STATUS_VALID)
+	testInfo.ResultExpectedLineCount = 3
+	testInfo.ResultLineContainsValues = []string{JSON_CONTEXT_ROOT, schema.JSF_ALGORITHM_RS256, schema.JSF_KEY_SOURCE_PUBLIC_KEY, "true"}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15SignersCSV(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_SIGNERS,
+		FORMAT_CSV,
+		nil, SIGNATURE_STATUS_VALID)
+	testInfo.ResultExpectedLineCount = 3
+	testInfo.ResultLineContainsValues = []string{schema.JSF_SIGNATURE_TYPE_SIGNERS, "1", schema.JSF_ALGORITHM_ES384, "ec-p384-key"}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15ChainMarkdown(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_CHAIN,
+		FORMAT_MARKDOWN,
+		nil, SIGNATURE_STATUS_VALID)
+	testInfo.ResultExpectedLineCount = 4
+	testInfo.ResultLineContainsValues = []string{schema.JSF_SIGNATURE_TYPE_CHAIN, schema.JSF_ALGORITHM_PS256, "rsa-key"}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15ComponentsJSON(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_COMPONENTS,
+		FORMAT_JSON,
+		nil, SIGNATURE_STATUS_VALID)
+	outputBuffer, _ := innerTestSignatureVerify(t, testInfo)
+
+	var results []schema.JSFVerificationResult
+	if err := json.Unmarshal(outputBuffer.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Path != "components.0" || results[1].Path != "services.0" {
+		t.Errorf("expected component and service signature results, actual: %v", results)
+	}
+}
+
+func TestSignatureVerifyCdx15KeyIdUserKey(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_KEY_ID,
+		FORMAT_TEXT,
+		nil, SIGNATURE_STATUS_VALID)
+	testInfo.KeyFile = TEST_SIGNATURE_KEY_RSA_PUBLIC
+	testInfo.ResultLineContainsValues = []string{schema.JSF_ALGORITHM_RS512, "rsa-key", schema.JSF_KEY_SOURCE_USER, "true"}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15KeyIdNoKey(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_KEY_ID,
+		FORMAT_TEXT,
+		nil, SIGNATURE_STATUS_INVALID)
+	testInfo.ResultLineContainsValues = []string{"false", schema.MSG_JSF_KEY_NOT_FOUND}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+func TestSignatureVerifyCdx15Invalid(t *testing.T) {
+	testInfo := NewSignatureTestInfoBasic(
+		TEST_SIGNATURE_CDX_1_5_INVALID,
+		FORMAT_TEXT,
+		nil, SIGNATURE_STATUS_INVALID)
+	testInfo.ResultLineContainsValues = []string{"false", schema.MSG_JSF_SIGNATURE_INVALID}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestSignatureVerify(t, testInfo)
+}
+
+// -------------------------------------------
+// Signature sign
+// -------------------------------------------
+
+const (
+	TEST_SIGNATURE_KEY_EC_P256_PRIVATE    = "test/signature/keys/ec-p256-private.pem"
+	TEST_SIGNATURE_KEY_ED25519_PRIVATE    = "test/signature/keys/ed25519-private.pem"
+	TEST_SIGNATURE_KEY_SIGNER_CERTIFICATE = "test/signature/keys/signer-certificate.pem"
+)
+
+// Signs the input file and returns the signed BOM (as a JSON map)
+func innerTestSignatureSign(t *testing.T, inputFile string, flags utils.SignatureCommandFlags) (jsonMap map[string]interface{}, err error) {
+	var outputBuffer bytes.Buffer
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+
+	utils.GlobalFlags.PersistentFlags.InputFile = inputFile
+	utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_JSON
+	if flags.SignatureType == "" {
+		flags.SignatureType = schema.JSF_SIGNATURE_TYPE_SIGNATURE
+	}
+	err = Sign(outputWriter, utils.GlobalFlags.PersistentFlags, flags)
+	outputWriter.Flush()
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(outputBuffer.Bytes(), &jsonMap)
+	return
+}
+
+func TestSignatureSignKeyRequired(t *testing.T) {
+	_, err := innerTestSignatureSign(t, TEST_CDX_1_5_MIN_REQUIRED, utils.SignatureCommandFlags{})
+	if err == nil {
+		t.Errorf("expected error signing without a key")
+	}
+}
+
+func TestSignatureSignFlagCountMismatch(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles:      []string{TEST_SIGNATURE_KEY_RSA_PRIVATE, TEST_SIGNATURE_KEY_EC_P256_PRIVATE},
+		Algorithms:    []string{schema.JSF_ALGORITHM_PS256},
+		SignatureType: schema.JSF_SIGNATURE_TYPE_SIGNERS,
+	}
+	if _, err := innerTestSignatureSign(t, TEST_CDX_1_5_MIN_REQUIRED, flags); err == nil {
+		t.Errorf("expected error for mismatched number of `--%s` and `--%s` values", FLAG_SIGNATURE_ALGORITHM, FLAG_SIGNATURE_KEY)
+	}
+}
+
+func TestSignatureSignSpdxUnsupported(t *testing.T) {
+	flags := utils.SignatureCommandFlags{KeyFiles: []string{TEST_SIGNATURE_KEY_RSA_PRIVATE}}
+	_, err := innerTestSignatureSign(t, TEST_SPDX_2_2_MIN_REQUIRED, flags)
+	if _, ok := err.(*schema.UnsupportedFormatError); !ok {
+		t.Errorf("expected: UnsupportedFormatError, actual: %v", err)
+	}
+}
+
+func TestSignatureSignCdx15BOM(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles:       []string{TEST_SIGNATURE_KEY_RSA_PRIVATE},
+		Algorithms:     []string{schema.JSF_ALGORITHM_PS512},
+		KeyIds:         []string{"release-key"},
+		EmbedPublicKey: true,
+	}
+	jsonMap, err := innerTestSignatureSign(t, TEST_CDX_1_5_MIN_REQUIRED, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := schema.VerifyJSFSignatures(jsonMap, nil)
+	if len(results) != 1 || !results[0].Valid || results[0].KeyId != "release-key" ||
+		results[0].Algorithm != schema.JSF_ALGORITHM_PS512 {
+		t.Errorf("expected valid signature, actual: %v", results)
+	}
+}
+
+func TestSignatureSignCdx15Signers(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles:       []string{TEST_SIGNATURE_KEY_ED25519_PRIVATE, TEST_SIGNATURE_KEY_EC_P256_PRIVATE},
+		SignatureType:  schema.JSF_SIGNATURE_TYPE_SIGNERS,
+		EmbedPublicKey: true,
+	}
+	jsonMap, err := innerTestSignatureSign(t, TEST_CDX_1_5_MIN_REQUIRED, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := schema.VerifyJSFSignatures(jsonMap, nil)
+	if len(results) != 2 || !results[0].Valid || !results[1].Valid {
+		t.Errorf("expected 2 valid signers, actual: %v", results)
+	}
+}
+
+func TestSignatureSignCdx15ChainExcludes(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles:       []string{TEST_SIGNATURE_KEY_ED25519_PRIVATE, TEST_SIGNATURE_KEY_EC_P256_PRIVATE},
+		SignatureType:  schema.JSF_SIGNATURE_TYPE_CHAIN,
+		Excludes:       []string{"version"},
+		EmbedPublicKey: true,
+	}
+	jsonMap, err := innerTestSignatureSign(t, TEST_CDX_1_5_MIN_REQUIRED, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Excludes are a global option of the (outer) signature object
+	signature := jsonMap[schema.JSF_KEY_SIGNATURE].(map[string]interface{})
+	if excludes, ok := signature[schema.JSF_KEY_EXCLUDES].([]interface{}); !ok || len(excludes) != 1 || excludes[0] != "version" {
+		t.Errorf("expected excludes: `[version]`, actual: `%v`", signature[schema.JSF_KEY_EXCLUDES])
+	}
+	jsonMap["version"] = float64(99)
+	results := schema.VerifyJSFSignatures(jsonMap, nil)
+	if len(results) != 2 || !results[0].Valid || !results[1].Valid {
+		t.Errorf("expected 2 valid signers, actual: %v", results)
+	}
+}
+
+func TestSignatureSignCdx15ComponentsWhere(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles:         []string{TEST_SIGNATURE_KEY_EC_P256_PRIVATE},
+		CertificateFiles: []string{TEST_SIGNATURE_KEY_SIGNER_CERTIFICATE},
+		From:             "components",
+		Where:            "name=qs",
+	}
+	jsonMap, err := innerTestSignatureSign(t, TEST_SIGNATURE_CDX_1_5_RS256, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the matching component is signed (invalidating the existing BOM signature)
+	components := jsonMap["components"].([]interface{})
+	if _, signed := components[0].(map[string]interface{})[schema.JSF_KEY_SIGNATURE]; signed {
+		t.Errorf("expected component not to be signed: %v", components[0])
+	}
+	results := schema.VerifyJSFSignatures(components[1].(map[string]interface{}), nil)
+	if len(results) != 1 || !results[0].Valid || results[0].KeySource != schema.JSF_KEY_SOURCE_CERTIFICATE {
+		t.Errorf("expected valid component signature, actual: %v", results)
+	}
+	if results = schema.VerifyJSFSignatures(jsonMap, nil); len(results) != 1 || results[0].Valid {
+		t.Errorf("expected invalid BOM signature, actual: %v", results)
+	}
+}
+
+func TestSignatureSignCdx15FromNotFound(t *testing.T) {
+	flags := utils.SignatureCommandFlags{
+		KeyFiles: []string{TEST_SIGNATURE_KEY_EC_P256_PRIVATE},
+		From:     "components",
+		Where:    "name=not-found",
+	}
+	if _, err := innerTestSignatureSign(t, TEST_SIGNATURE_CDX_1_5_RS256, flags); err == nil {
+		t.Errorf("expected error when no objects are selected to sign")
+	}
+}
@@ -19,9 +19,11 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
@@ -33,6 +35,25 @@ import (
 var STATS_LIST_OUTPUT_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
 	strings.Join([]string{FORMAT_TEXT, FORMAT_CSV, FORMAT_MARKDOWN}, ", ")
 
+var STATS_LIST_TITLES = []string{"entity", "statistic", "value"}
+
+// Statistic (entity) names
+const (
+	STATS_ENTITY_COMPONENT     = "component"
+	STATS_ENTITY_SERVICE       = "service"
+	STATS_ENTITY_VULNERABILITY = "vulnerability"
+)
+
+// Statistic names; "mapped" statistics are output as "<name>: <key>"
+const (
+	STATS_STATISTIC_TOTAL      = "total"
+	STATS_STATISTIC_TYPE       = "type"
+	STATS_STATISTIC_IDENTIFIER = "identifier"
+	STATS_STATISTIC_MIME_TYPE  = "mime-type"
+	STATS_STATISTIC_ENDPOINTS  = "endpoints"
+	STATS_STATISTIC_SEVERITY   = "severity"
+)
+
 func NewCommandStats() *cobra.Command {
 	var command = new(cobra.Command)
 	command.Use = CMD_USAGE_STATS_LIST
@@ -44,9 +65,10 @@ func NewCommandStats() *cobra.Command {
 	// TODO: command.ValidArgs = VALID_SUBCOMMANDS_S
 	command.PreRunE = func(cmd *cobra.Command, args []string) (err error) {
 		// Test for required flags (parameters)
-		err = preRunTestForInputFile(cmd, args)
+		err = preRunTestForBatchInputFiles(cmd, args)
 		return
 	}
+	initCommandBatchFlags(command)
 	return command
 }
 
@@ -69,7 +91,11 @@ func statsCmdImpl(cmd *cobra.Command, args []string) (err error) {
 	}()
 
 	if err == nil {
-		err = ListStats(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.StatsFlags)
+		if IsBatch(utils.GlobalFlags.BatchFlags) {
+			err = ListStatsBatch(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags, utils.GlobalFlags.StatsFlags)
+		} else {
+			err = ListStats(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.StatsFlags)
+		}
 	}
 
 	return
@@ -103,10 +129,11 @@ func ListStats(writer io.Writer, persistentFlags utils.PersistentCommandFlags, s
 		return
 	}
 
-	loadDocumentStatisticalEntities(document, statsFlags)
+	if err = loadDocumentStatisticalEntities(document, statsFlags); err != nil {
+		return
+	}
 
-	err = loadComponentStats(document)
-	if err != nil {
+	if err = loadStatistics(document); err != nil {
 		return
 	}
 
@@ -115,10 +142,10 @@ func ListStats(writer io.Writer, persistentFlags utils.PersistentCommandFlags, s
 	switch format {
 	case FORMAT_TEXT:
 		DisplayStatsText(document, writer)
-	// case FORMAT_CSV:
-	// 	DisplayResourceListCSV(writer)
-	// case FORMAT_MARKDOWN:
-	// 	DisplayResourceListMarkdown(writer)
+	case FORMAT_CSV:
+		err = DisplayStatsCSV(document, writer)
+	case FORMAT_MARKDOWN:
+		DisplayStatsMarkdown(document, writer)
 	default:
 		// Default to Text output for anything else (set as flag default)
 		getLogger().Warningf("Stats not supported for `%s` format; defaulting to `%s` format...",
@@ -129,6 +156,42 @@ func ListStats(writer io.Writer, persistentFlags utils.PersistentCommandFlags, s
 	return
 }
 
+func ListStatsBatch(writer io.Writer, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags, statsFlags utils.StatsCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	results := ProcessBatch(batchFlags.InputFiles, batchFlags.MaxWorkers, func(inputFile string) (rows [][]string, err error) {
+		var document *schema.BOM
+		if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
+			return
+		}
+		if err = loadDocumentStatisticalEntities(document, statsFlags); err != nil {
+			return
+		}
+		if err = loadStatistics(document); err != nil {
+			return
+		}
+		return statsLineData(document.Statistics), nil
+	})
+
+	if err = DisplayBatchResults(writer, persistentFlags.OutputFormat, STATS_LIST_TITLES, results); err != nil {
+		return
+	}
+	return BatchResultsError(results)
+}
+
+// Calculate all (component, service and vulnerability) statistics from the document's hashed entities
+func loadStatistics(document *schema.BOM) (err error) {
+	if err = loadComponentStats(document); err != nil {
+		return
+	}
+	if err = loadServiceStats(document); err != nil {
+		return
+	}
+	err = loadVulnerabilityStats(document)
+	return
+}
+
 func loadComponentStats(document *schema.BOM) (err error) {
 	if document == nil {
 		return getLogger().Errorf("invalid BOM document")
@@ -140,13 +203,18 @@ func loadComponentStats(document *schema.BOM) (err error) {
 		return getLogger().Errorf("invalid BOM stats")
 	}
 
-	//componentStats := stats.ComponentStats
+	componentStats := stats.ComponentStats
 	mapComponents := document.ComponentMap
 
 	if mapComponents == nil {
 		return getLogger().Errorf("invalid component map")
 	}
 
+	componentStats.Total = 0
+	componentStats.MapIdentifiers = make(map[string]int)
+	componentStats.MapTypes = make(map[string]int)
+	componentStats.MapMimeTypes = make(map[string]int)
+
 	for _, key := range mapComponents.KeySet() {
 		aComponents, _ := mapComponents.Get(key)
 
@@ -155,11 +223,113 @@ func loadComponentStats(document *schema.BOM) (err error) {
 			getLogger().Warningf("component `%v` has duplicate `%v` entries", key, len(aComponents))
 		}
 
-		// for _, c := range aComponents {
-		// 	fmt.Printf("comp=%v", c)
-		// }
+		for _, value := range aComponents {
+			component := value.(schema.CDXResourceInfo).Component
+			componentStats.Total++
+			if component.Type != "" {
+				componentStats.MapTypes[component.Type]++
+			}
+			if component.MimeType != "" {
+				componentStats.MapMimeTypes[component.MimeType]++
+			}
+			if component.BOMRef != nil && *component.BOMRef != "" {
+				componentStats.MapIdentifiers[schema.COMPONENT_ID_BOMREF]++
+			}
+			if component.Purl != "" {
+				componentStats.MapIdentifiers[schema.COMPONENT_ID_PURL]++
+			}
+			if component.Cpe != "" {
+				componentStats.MapIdentifiers[schema.COMPONENT_ID_CPE]++
+			}
+			if component.Swid != nil {
+				componentStats.MapIdentifiers[schema.COMPONENT_ID_SWID]++
+			}
+		}
+	}
+
+	return
+}
+
+func loadServiceStats(document *schema.BOM) (err error) {
+	if document.ServiceMap == nil {
+		return getLogger().Errorf("invalid service map")
+	}
+
+	serviceStats := new(schema.BOMServiceStats)
+	serviceStats.MapEndpoints = make(map[string]int)
+	for _, value := range document.ServiceMap.Values() {
+		service := value.(schema.CDXResourceInfo).Service
+		serviceStats.Total++
+		if service.Endpoints != nil {
+			serviceStats.MapEndpoints[service.Name] += len(*service.Endpoints)
+		}
+	}
+	document.Statistics.ServiceStats = serviceStats
+	return
+}
+
+func loadVulnerabilityStats(document *schema.BOM) (err error) {
+	if document.VulnerabilityMap == nil {
+		return getLogger().Errorf("invalid vulnerability map")
+	}
+
+	vulnerabilityStats := new(schema.BOMVulnerabilityStats)
+	vulnerabilityStats.MapSeverities = make(map[string]int)
+	for _, value := range document.VulnerabilityMap.Values() {
+		vulnerabilityStats.Total++
+		vulnerabilityStats.MapSeverities[vulnerabilitySeverity(value.(schema.VulnerabilityInfo).Vulnerability)]++
+	}
+	document.Statistics.VulnerabilityStats = vulnerabilityStats
+	return
+}
+
+// Returns the severity of the rating from the same source as the vulnerability (if any),
+// otherwise the severity of its first rating
+func vulnerabilitySeverity(vulnerability schema.CDXVulnerability) string {
+	if vulnerability.Ratings == nil || len(*vulnerability.Ratings) == 0 {
+		return schema.VULN_RATING_EMPTY
+	}
+	severity := (*vulnerability.Ratings)[0].Severity
+	for _, rating := range *vulnerability.Ratings {
+		if rating.Source != nil && vulnerability.Source != nil &&
+			rating.Source.Name == vulnerability.Source.Name {
+			severity = rating.Severity
+			break
+		}
+	}
+	if severity == "" {
+		return schema.VULN_RATING_EMPTY
+	}
+	return severity
+}
+
+// Returns one line per statistic; statistics from maps are sorted by key
+func statsLineData(stats *schema.StatisticsInfo) (lines [][]string) {
+	appendMapLines := func(entity string, statistic string, values map[string]int) {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lines = append(lines, []string{entity, statistic + ": " + key, strconv.Itoa(values[key])})
+		}
 	}
 
+	if componentStats := stats.ComponentStats; componentStats != nil {
+		lines = append(lines, []string{STATS_ENTITY_COMPONENT, STATS_STATISTIC_TOTAL, strconv.Itoa(componentStats.Total)})
+		appendMapLines(STATS_ENTITY_COMPONENT, STATS_STATISTIC_TYPE, componentStats.MapTypes)
+		appendMapLines(STATS_ENTITY_COMPONENT, STATS_STATISTIC_IDENTIFIER, componentStats.MapIdentifiers)
+		appendMapLines(STATS_ENTITY_COMPONENT, STATS_STATISTIC_MIME_TYPE, componentStats.MapMimeTypes)
+	}
+	if serviceStats := stats.ServiceStats; serviceStats != nil {
+		lines = append(lines, []string{STATS_ENTITY_SERVICE, STATS_STATISTIC_TOTAL, strconv.Itoa(serviceStats.Total)})
+		appendMapLines(STATS_ENTITY_SERVICE, STATS_STATISTIC_ENDPOINTS, serviceStats.MapEndpoints)
+	}
+	if vulnerabilityStats := stats.VulnerabilityStats; vulnerabilityStats != nil {
+		lines = append(lines, []string{STATS_ENTITY_VULNERABILITY, STATS_STATISTIC_TOTAL, strconv.Itoa(vulnerabilityStats.Total)})
+		appendMapLines(STATS_ENTITY_VULNERABILITY, STATS_STATISTIC_SEVERITY, vulnerabilityStats.MapSeverities)
+	}
 	return
 }
 
@@ -167,12 +337,24 @@ func loadDocumentStatisticalEntities(document *schema.BOM, statsFlags utils.Stat
 	getLogger().Enter()
 	defer getLogger().Exit(err)
 
-	// At this time, fail SPDX format SBOMs as "unsupported" (for "any" format)
+	// SPDX packages (and files) are hashed as (abstract) CycloneDX components
+	if document.FormatInfo.IsSpdx() {
+		if err = document.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+		if err = document.HashSPDXPackageResources(nil); err != nil {
+			return
+		}
+		err = document.HashSPDXVulnerabilityResources(nil)
+		return
+	}
+
+	// Fail any other (unknown) formats as "unsupported" (for "any" format)
 	if !document.FormatInfo.IsCycloneDx() {
 		err = schema.NewUnsupportedFormatForCommandError(
-			document.FormatInfo.CanonicalName,
 			document.GetFilename(),
-			CMD_LICENSE, FORMAT_ANY)
+			document.FormatInfo.CanonicalName,
+			CMD_STATS, FORMAT_ANY)
 		return
 	}
 
@@ -199,7 +381,6 @@ func loadDocumentStatisticalEntities(document *schema.BOM, statsFlags utils.Stat
 	return
 }
 
-// NOTE: This list is NOT de-duplicated
 // TODO: Add a --no-title flag to skip title output
 func DisplayStatsText(bom *schema.BOM, writer io.Writer) {
 	getLogger().Enter()
@@ -212,44 +393,45 @@ func DisplayStatsText(bom *schema.BOM, writer io.Writer) {
 	// min-width, tab-width, padding, pad-char, flags
 	w.Init(writer, 8, 2, 2, ' ', 0)
 
-	// create underline row from compulsory titles
-	underlines := createTitleTextSeparators(RESOURCE_LIST_TITLES)
-
 	// Add tabs between column titles for the tabWRiter
-	fmt.Fprintf(w, "%s\n", strings.Join(RESOURCE_LIST_TITLES, "\t"))
-	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(STATS_LIST_TITLES, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(createTitleTextSeparators(STATS_LIST_TITLES), "\t"))
 
-	// Display a warning "missing" in the actual output and return (short-circuit)
-	entries := bom.ResourceMap.Entries()
+	for _, line := range statsLineData(bom.Statistics) {
+		fmt.Fprintf(w, "%s\n", strings.Join(line, "\t"))
+	}
+}
 
-	// Emit no license warning into output
-	if len(entries) == 0 {
-		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_RESOURCES_FOUND)
-		return
+// TODO: Add a --no-title flag to skip title output
+func DisplayStatsCSV(bom *schema.BOM, writer io.Writer) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	if err = w.Write(STATS_LIST_TITLES); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", STATS_LIST_TITLES, err)
 	}
 
-	// Sort by Type then Name
-	sort.Slice(entries, func(i, j int) bool {
-		resource1 := (entries[i].Value).(schema.CDXResourceInfo)
-		resource2 := (entries[j].Value).(schema.CDXResourceInfo)
-		if resource1.Type != resource2.Type {
-			return resource1.Type < resource2.Type
+	for _, line := range statsLineData(bom.Statistics) {
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("csv.Write: %w", err)
 		}
+	}
+	return
+}
 
-		return resource1.Name < resource2.Name
-	})
-
-	var resourceInfo schema.CDXResourceInfo
+// TODO: Add a --no-title flag to skip title output
+func DisplayStatsMarkdown(bom *schema.BOM, writer io.Writer) {
+	getLogger().Enter()
+	defer getLogger().Exit()
 
-	for _, entry := range entries {
-		value := entry.Value
-		resourceInfo = value.(schema.CDXResourceInfo)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(STATS_LIST_TITLES))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(STATS_LIST_TITLES)))
 
-		// Format line and write to output
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			resourceInfo.Type,
-			resourceInfo.Name,
-			resourceInfo.Version,
-			resourceInfo.BOMRef)
+	for _, line := range statsLineData(bom.Statistics) {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(line))
 	}
 }
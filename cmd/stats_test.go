@@ -24,15 +24,16 @@ import (
 	"io/fs"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
-	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
 
 const (
 	// Test "resource list" command
 	TEST_STATS_CDX_1_4_SAMPLE_XXL_1 = "test/stats/stats-cdx-1-4-sample-xxl-1.json"
+	TEST_STATS_SPDX_2_3_PACKAGES    = "test/spdx/spdx-2-3-packages.json"
 )
 
 type StatsTestInfo struct {
@@ -101,18 +102,101 @@ func innerTestStatsList(t *testing.T, testInfo *StatsTestInfo) (outputBuffer byt
 
 // TBD
 
+func testStatsOutputLines(t *testing.T, outputBuffer bytes.Buffer, expectedLines [][]string) {
+	for _, expectedValues := range expectedLines {
+		if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+			t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+		}
+	}
+}
+
 // -------------------------------------------
-// Test format unsupported (SPDX)
+// SPDX variants
 // -------------------------------------------
-func TestStatsListFormatUnsupportedSPDXMinReq(t *testing.T) {
+func TestStatsListSpdx22MinReq(t *testing.T) {
 	ti := NewStatsTestInfoBasic(
 		TEST_SPDX_2_2_MIN_REQUIRED,
 		FORMAT_DEFAULT,
-		&schema.UnsupportedFormatError{},
+		nil,
 	)
 
-	// verify correct error is returned
-	innerTestStatsList(t, ti)
+	_, _, err := innerTestStatsList(t, ti)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatsListSpdx23Packages(t *testing.T) {
+	ti := NewStatsTestInfoBasic(
+		TEST_STATS_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		nil,
+	)
+
+	outputBuffer, _, err := innerTestStatsList(t, ti)
+	if err != nil {
+		t.Error(err)
+	}
+	// title, separator and 10 statistic rows
+	if lines := strings.Count(outputBuffer.String(), "\n"); lines != 12 {
+		t.Errorf("output did not contain expected line count: %v/%v (expected/actual)", 12, lines)
+	}
+	// 4 packages and 1 file
+	testStatsOutputLines(t, outputBuffer, [][]string{
+		{STATS_ENTITY_COMPONENT, STATS_STATISTIC_TOTAL, "5"},
+		{STATS_ENTITY_COMPONENT, "type: library", "3"},
+		{STATS_ENTITY_COMPONENT, "identifier: purl", "3"},
+		{STATS_ENTITY_SERVICE, STATS_STATISTIC_TOTAL, "0"},
+		{STATS_ENTITY_VULNERABILITY, STATS_STATISTIC_TOTAL, "2"},
+	})
+}
+
+// -------------------------------------------
+// CycloneDX variants
+// -------------------------------------------
+func TestStatsListCdx13VexCSV(t *testing.T) {
+	ti := NewStatsTestInfoBasic(
+		TEST_VULN_CDX_1_3_EXAMPLE_1_BOM_VEX,
+		FORMAT_CSV,
+		nil,
+	)
+
+	outputBuffer, _, err := innerTestStatsList(t, ti)
+	if err != nil {
+		t.Error(err)
+	}
+	testStatsOutputLines(t, outputBuffer, [][]string{
+		{"entity,statistic,value"},
+		{"component,total,4"},
+		{"component,identifier: bom-ref,4"},
+		{"vulnerability,total,3"},
+		{"vulnerability,severity: high,3"},
+	})
+}
+
+func TestStatsListCdx13VexMarkdown(t *testing.T) {
+	ti := NewStatsTestInfoBasic(
+		TEST_VULN_CDX_1_3_EXAMPLE_1_BOM_VEX,
+		FORMAT_MARKDOWN,
+		nil,
+	)
+
+	outputBuffer, _, err := innerTestStatsList(t, ti)
+	if err != nil {
+		t.Error(err)
+	}
+	testStatsOutputLines(t, outputBuffer, [][]string{
+		{"|entity|statistic|value|"},
+		{"|vulnerability|severity: high|3|"},
+	})
+}
+
+// The stats command MUST be available from the root command
+func TestStatsCommandRegistered(t *testing.T) {
+	command, _, err := rootCmd.Find([]string{CMD_STATS})
+	if err != nil || command.Name() != CMD_STATS {
+		t.Errorf("expected command: `%s`, actual: `%v` (%v)", CMD_STATS, command, err)
+	}
 }
 
 // -------------------------------------------
@@ -20,14 +20,15 @@ package cmd
 
 // "github.com/iancoleman/orderedmap"
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
-	"github.com/CycloneDX/sbom-utility/resources"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 	"github.com/spf13/cobra"
@@ -49,6 +50,7 @@ const (
 	FLAG_VALIDATE_CUSTOM           = "custom" // TODO: document when no longer experimental
 	FLAG_VALIDATE_ERR_LIMIT        = "error-limit"
 	FLAG_VALIDATE_ERR_VALUE        = "error-value"
+	FLAG_VALIDATE_STREAM           = "stream"
 	MSG_VALIDATE_SCHEMA_FORCE      = "force specified schema file for validation; overrides inferred schema"
 	MSG_VALIDATE_SCHEMA_VARIANT    = "select named schema variant (e.g., \"strict\"); variant must be declared in configuration file (i.e., \"config.json\")"
 	MSG_VALIDATE_FLAG_CUSTOM       = "perform custom validation using custom configuration settings (i.e., \"custom.json\")"
@@ -56,10 +58,12 @@ const (
 	MSG_VALIDATE_FLAG_ERR_LIMIT    = "Limit number of errors output to specified (integer) (default 10)"
 	MSG_VALIDATE_FLAG_ERR_FORMAT   = "format error results using the specified format type"
 	MSG_VALIDATE_FLAG_ERR_VALUE    = "include details of failing value in error results (bool) (default: true)"
+	MSG_VALIDATE_FLAG_STREAM       = "validate a stream of documents read from the input (default: stdin): " +
+		"\"paths\" (newline-delimited file paths) or \"json\" (concatenated JSON documents)"
 )
 
 var VALIDATE_SUPPORTED_ERROR_FORMATS = MSG_VALIDATE_FLAG_ERR_FORMAT +
-	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV}, ", ") + " (default: txt)"
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV, FORMAT_SARIF, FORMAT_JUNIT}, ", ") + " (default: txt)"
 
 // limits
 const (
@@ -72,6 +76,21 @@ const (
 	PROTOCOL_PREFIX_FILE = "file://"
 )
 
+// Batch (i.e., multiple input file) report columns
+const (
+	VALIDATE_BATCH_COLUMN_VALID       = "valid"
+	VALIDATE_BATCH_COLUMN_TYPE        = "type"
+	VALIDATE_BATCH_COLUMN_FIELD       = "field"
+	VALIDATE_BATCH_COLUMN_DESCRIPTION = "description"
+)
+
+var VALIDATE_BATCH_TITLES = []string{
+	VALIDATE_BATCH_COLUMN_VALID,
+	VALIDATE_BATCH_COLUMN_TYPE,
+	VALIDATE_BATCH_COLUMN_FIELD,
+	VALIDATE_BATCH_COLUMN_DESCRIPTION,
+}
+
 func NewCommandValidate() *cobra.Command {
 	// NOTE: `RunE` function takes precedent over `Run` (anonymous) function if both provided
 	var command = new(cobra.Command)
@@ -82,9 +101,20 @@ func NewCommandValidate() *cobra.Command {
 	command.Flags().StringVarP(&utils.GlobalFlags.PersistentFlags.OutputFormat, FLAG_FILE_OUTPUT_FORMAT, "", "",
 		MSG_VALIDATE_FLAG_ERR_FORMAT+VALIDATE_SUPPORTED_ERROR_FORMATS)
 	command.PreRunE = func(cmd *cobra.Command, args []string) error {
-		return preRunTestForInputFile(cmd, args)
+		// Note: listing custom validation rules does not require input
+		if utils.GlobalFlags.ValidateFlags.ListRules {
+			return nil
+		}
+		if err := preRunTestForValidationProfile(); err != nil {
+			return err
+		}
+		if utils.GlobalFlags.ValidateFlags.StreamMode != "" {
+			return preRunTestForStreamInput(cmd, args)
+		}
+		return preRunTestForBatchInputFiles(cmd, args)
 	}
 	initCommandValidateFlags(command)
+	initCommandBatchFlags(command)
 	return command
 }
 
@@ -101,6 +131,10 @@ func initCommandValidateFlags(command *cobra.Command) {
 	command.Flags().BoolVarP(&utils.GlobalFlags.ValidateFlags.ColorizeErrorOutput, FLAG_COLORIZE_OUTPUT, "", false, MSG_VALIDATE_FLAG_ERR_COLORIZE)
 	command.Flags().IntVarP(&utils.GlobalFlags.ValidateFlags.MaxNumErrors, FLAG_VALIDATE_ERR_LIMIT, "", DEFAULT_MAX_ERROR_LIMIT, MSG_VALIDATE_FLAG_ERR_LIMIT)
 	command.Flags().BoolVarP(&utils.GlobalFlags.ValidateFlags.ShowErrorValue, FLAG_VALIDATE_ERR_VALUE, "", true, MSG_VALIDATE_FLAG_ERR_COLORIZE)
+	command.Flags().StringVarP(&utils.GlobalFlags.ValidateFlags.StreamMode, FLAG_VALIDATE_STREAM, "", "", MSG_VALIDATE_FLAG_STREAM)
+	command.Flags().BoolVarP(&utils.GlobalFlags.ValidateFlags.ListRules, FLAG_VALIDATE_LIST_RULES, "", false, MSG_VALIDATE_FLAG_LIST_RULES+RULE_LIST_SUPPORTED_FORMATS)
+	command.Flags().StringVarP(&utils.GlobalFlags.ValidateFlags.Profile, FLAG_VALIDATE_PROFILE, "", "", VALIDATE_PROFILE_SUPPORTED_FORMATS)
+	command.Flags().Float64VarP(&utils.GlobalFlags.ValidateFlags.ProfileThreshold, FLAG_VALIDATE_PROFILE_THRESHOLD, "", DEFAULT_PROFILE_THRESHOLD, MSG_VALIDATE_FLAG_PROFILE_THRESHOLD)
 }
 
 func validateCmdImpl(cmd *cobra.Command, args []string) error {
@@ -127,6 +161,32 @@ func validateCmdImpl(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	// list the effective custom validation rules (i.e., of all layered config files) instead of validating
+	if utils.GlobalFlags.ValidateFlags.ListRules {
+		if err = ListCustomValidationRules(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.ConfigCustomValidationFiles); err != nil {
+			getLogger().Error(err)
+			os.Exit(ERROR_APPLICATION)
+		}
+		return nil
+	}
+
+	// multiple input files (or a stream of documents) are validated (concurrently) and reported together
+	if utils.GlobalFlags.ValidateFlags.StreamMode != "" || IsBatch(utils.GlobalFlags.BatchFlags) {
+		if utils.GlobalFlags.ValidateFlags.StreamMode != "" {
+			err = ValidateInputStream(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags, utils.GlobalFlags.ValidateFlags)
+		} else {
+			err = ValidateBatch(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags, utils.GlobalFlags.ValidateFlags)
+		}
+		if err != nil {
+			getLogger().Error(err)
+			if IsInvalidBOMError(err) {
+				os.Exit(ERROR_VALIDATION)
+			}
+			os.Exit(ERROR_APPLICATION)
+		}
+		return nil
+	}
+
 	// invoke validate and consistently manage exit messages and codes
 	isValid, _, _, err := Validate(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.ValidateFlags)
 
@@ -200,6 +260,51 @@ func Validate(writer io.Writer, persistentFlags utils.PersistentCommandFlags, va
 		return INVALID, document, schemaErrors, err
 	}
 
+	var customConfig *schema.CustomValidationConfig
+	if customConfig, err = loadCustomValidationConfig(validateFlags); err != nil {
+		return INVALID, document, schemaErrors, err
+	}
+
+	valid, schemaErrors, err = validateDocument(writer, document, persistentFlags, validateFlags, customConfig)
+	return
+}
+
+// Load the custom validation config once, if custom validation is requested, so that
+// it may be shared by all documents validated (i.e., read by concurrent batch workers)
+func loadCustomValidationConfig(validateFlags utils.ValidateCommandFlags) (customConfig *schema.CustomValidationConfig, err error) {
+	if !validateFlags.CustomValidation {
+		return
+	}
+	return schema.NewCustomValidationConfig(utils.GlobalFlags.ConfigCustomValidationFiles, DEFAULT_CUSTOM_VALIDATION_CONFIG)
+}
+
+// Validate a (loaded) document against its (detected or forced) schema and,
+// optionally, any custom validation requirements (i.e., of the loaded custom config)
+func validateDocument(writer io.Writer, document *schema.BOM, persistentFlags utils.PersistentCommandFlags, validateFlags utils.ValidateCommandFlags, customConfig *schema.CustomValidationConfig) (valid bool, schemaErrors []gojsonschema.ResultError, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// SARIF and JUnit output are reports of all findings (i.e., schema and custom validation
+	// errors) which are written once validation completes, even if the document is valid
+	if persistentFlags.OutputFormat == FORMAT_SARIF || persistentFlags.OutputFormat == FORMAT_JUNIT {
+		defer func() {
+			if err != nil && !IsInvalidBOMError(err) {
+				return
+			}
+			var errReport error
+			if persistentFlags.OutputFormat == FORMAT_SARIF {
+				findings := validationSarifFindings(schemaErrors, err, validateFlags)
+				errReport = DisplaySarif(writer, document.GetFilename(), findings)
+			} else {
+				testCases := validationJUnitTestCases(schemaErrors, err, validateFlags)
+				errReport = DisplayJUnit(writer, []JUnitTestSuite{NewJUnitTestSuite(document.GetFilename(), testCases)})
+			}
+			if errReport != nil {
+				getLogger().Error(errReport)
+			}
+		}()
+	}
+
 	// if "custom" flag exists, then assure we support the format
 	if validateFlags.CustomValidation && !document.FormatInfo.IsCycloneDx() {
 		err = schema.NewUnsupportedFormatError(
@@ -208,146 +313,179 @@ func Validate(writer io.Writer, persistentFlags utils.PersistentCommandFlags, va
 			document.FormatInfo.CanonicalName,
 			CMD_VALIDATE,
 			FLAG_VALIDATE_CUSTOM)
-		return valid, document, schemaErrors, err
+		return valid, schemaErrors, err
 	}
 
-	// Create a loader for the BOM (JSON) document
-	var documentLoader gojsonschema.JSONLoader
-	var schemaLoader gojsonschema.JSONLoader
-	var errRead error
-	var bSchema, bDocument []byte
-
-	if bDocument = document.GetRawBytes(); len(bDocument) > 0 {
-		bufferTemp := new(bytes.Buffer)
-		// Strip off newlines which the json Decoder dislikes at EOF (as well as extra spaces, etc.)
-		if err := json.Compact(bufferTemp, bDocument); err != nil {
-			fmt.Println(err)
-		}
-		documentLoader = gojsonschema.NewBytesLoader(bufferTemp.Bytes())
-	} else {
-		inputFile := persistentFlags.InputFile
-		documentLoader = gojsonschema.NewReferenceLoader(PROTOCOL_PREFIX_FILE + inputFile)
-	}
-
-	if documentLoader == nil {
-		return INVALID, document, schemaErrors, fmt.Errorf("unable to load document: `%s`", document.GetFilename())
+	// Validate against the (detected or forced) schema and save result determination
+	// Note: we force result to INVALID as any errors from the library means
+	// we could NOT actually confirm the input documents validity
+	result, err := sbom.Validate(context.Background(), document, sbom.ValidateOptions{
+		ForcedSchemaFile: validateFlags.ForcedJsonSchemaFile,
+		SchemaCache:      ValidationSchemaCache,
+	})
+	if err != nil {
+		return INVALID, schemaErrors, err
 	}
+	valid = result.Valid
 
-	schemaName := document.SchemaInfo.File
-
-	// If caller "forced" a specific schema file (version), load it instead of
-	// any SchemaInfo found in config.json
-	// TODO: support remote schema load (via URL) with a flag (default should always be local file for security)
-	forcedSchemaFile := validateFlags.ForcedJsonSchemaFile
-	if forcedSchemaFile != "" {
-		getLogger().Infof("Validating document using forced schema (i.e., `--force %s`)", forcedSchemaFile)
-		//schemaName = document.SchemaInfo.File
-		schemaName = "file://" + forcedSchemaFile
-		getLogger().Infof("Loading schema `%s`...", schemaName)
-		schemaLoader = gojsonschema.NewReferenceLoader(schemaName)
-	} else {
-		// Load the matching JSON schema (format, version and variant) from embedded resources
-		// i.e., using the matching schema found in config.json (as SchemaInfo)
-		getLogger().Infof("Loading schema `%s`...", document.SchemaInfo.File)
-		bSchema, errRead = resources.BOMSchemaFiles.ReadFile(document.SchemaInfo.File)
-
-		if errRead != nil {
-			// we force result to INVALID as any errors from the library means
-			// we could NOT actually confirm the input documents validity
-			return INVALID, document, schemaErrors, errRead
-		}
+	// Note: actual schema validation errors appear in the `result` object
+	// Save all schema errors found in the `result` object in an explicit, typed error
+	if schemaErrors = result.SchemaErrors; len(schemaErrors) > 0 {
+		errInvalid := NewInvalidSBOMError(
+			document,
+			MSG_SCHEMA_ERRORS,
+			nil,
+			schemaErrors)
 
-		schemaLoader = gojsonschema.NewBytesLoader(bSchema)
-	}
+		// TODO: de-duplicate errors (e.g., array item not "unique"...)
+		formatValidationErrors(writer, schemaErrors, validateFlags, persistentFlags.OutputFormat)
 
-	if schemaLoader == nil {
-		// we force result to INVALID as any errors from the library means
-		// we could NOT actually confirm the input documents validity
-		return INVALID, document, schemaErrors, fmt.Errorf("unable to read schema: `%s`", schemaName)
+		return INVALID, schemaErrors, errInvalid
 	}
 
-	// create a reusable schema object (TODO: validate multiple documents)
-	var errLoad error = nil
-	const RETRY int = 3
-	var jsonBOMSchema *gojsonschema.Schema
-
-	// we force result to INVALID as any errors from the library means
-	// we could NOT actually confirm the input documents validity
-	// WARNING: if schemas reference "remote" schemas which are loaded
-	// over http... then there is a chance of 503 errors (as the pkg. loads
-	// externally referenced schemas over network)... attempt fixed retry...
-	for i := 0; i < RETRY; i++ {
-		jsonBOMSchema, errLoad = gojsonschema.NewSchema(schemaLoader)
+	// TODO: Perhaps factor in these errors into the JSON output as if they were actual schema errors...
+	// Perform additional validation in document composition/structure
+	// and "custom" required data within specified fields
+	if validateFlags.CustomValidation {
+		valid, err = validateCustom(document, LicensePolicyConfig, customConfig)
 
-		if errLoad == nil {
-			break
+		// Referential integrity errors are formatted (and returned) the same as schema errors
+		if invalidErr, ok := err.(*InvalidSBOMError); ok && len(invalidErr.SchemaErrors) > 0 {
+			schemaErrors = invalidErr.SchemaErrors
+			formatValidationErrors(writer, schemaErrors, validateFlags, persistentFlags.OutputFormat)
 		}
-		getLogger().Warningf("unable to load referenced schema over HTTP: \"%v\"\n retrying...", errLoad)
 	}
 
-	if errLoad != nil {
-		return INVALID, document, schemaErrors, fmt.Errorf("unable to load schema: `%s`", schemaName)
+	// Check conformance to a (built-in) profile (e.g., NTIA minimum elements) of an otherwise valid BOM
+	if validateFlags.Profile != "" && err == nil {
+		if err = validateProfile(writer, document, validateFlags, persistentFlags.OutputFormat); err != nil {
+			valid = INVALID
+		}
 	}
 
-	getLogger().Infof("Schema `%s` loaded.", schemaName)
+	// All validation tests passed; return VALID
+	return
+}
 
-	// Validate against the schema and save result determination
-	getLogger().Infof("Validating `%s`...", document.GetFilenameInterpolated())
-	result, errValidate := jsonBOMSchema.Validate(documentLoader)
+// Validate all (batch) input files and report their results together
+// Note: formatted schema errors are reported as rows of the batch report (i.e., one per error)
+func ValidateBatch(writer io.Writer, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags, validateFlags utils.ValidateCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// Note: the custom validation config is loaded once and shared (read-only) by all workers
+	var customConfig *schema.CustomValidationConfig
+	if customConfig, err = loadCustomValidationConfig(validateFlags); err != nil {
+		return
+	}
+
+	results := ProcessBatch(batchFlags.InputFiles, batchFlags.MaxWorkers, func(inputFile string) (rows [][]string, err error) {
+		return validateBatchDocument(BatchDocument{Filename: inputFile}, persistentFlags, validateFlags, customConfig)
+	})
 
-	// ALWAYS set the valid return parameter and provide user an informative message
-	valid = result.Valid()
-	getLogger().Infof("BOM valid against JSON schema: `%t`", valid)
+	return displayValidateBatchResults(writer, persistentFlags.OutputFormat, results)
+}
 
-	// Catch general errors from the validation package/library itself and display them
-	if errValidate != nil {
-		// we force result to INVALID as any errors from the library means
-		// we could NOT actually confirm the input documents validity
-		return INVALID, document, schemaErrors, errValidate
+// Display the (batch) validation report and return the overall (batch) error
+func displayValidateBatchResults(writer io.Writer, format string, results []BatchDocumentResult) (err error) {
+	if format == "" {
+		format = FORMAT_TEXT
 	}
+	switch format {
+	case FORMAT_JUNIT:
+		err = DisplayValidateBatchJUnit(writer, results)
+	case FORMAT_SARIF:
+		err = DisplaySarifBatch(writer, results)
+	default:
+		err = DisplayBatchResults(writer, format, VALIDATE_BATCH_TITLES, results)
+	}
+	if err != nil {
+		return
+	}
+	return BatchResultsError(results)
+}
 
-	// Note: actual schema validation errors appear in the `result` object
-	// Save all schema errors found in the `result` object in an explicit, typed error
-	if schemaErrors = result.Errors(); len(schemaErrors) > 0 {
-		errInvalid := NewInvalidSBOMError(
-			document,
-			MSG_SCHEMA_ERRORS,
-			nil,
-			schemaErrors)
+// Load and validate a single document of a batch (or stream) and return its batch report rows
+func validateBatchDocument(batchDocument BatchDocument, persistentFlags utils.PersistentCommandFlags, validateFlags utils.ValidateCommandFlags, customConfig *schema.CustomValidationConfig) (rows [][]string, err error) {
+	var document *schema.BOM
+	if batchDocument.Data != nil {
+		document, err = LoadBOMDataAndDetectSchema(batchDocument.Filename, batchDocument.Data)
+	} else {
+		document, err = LoadBOMFileAndDetectSchema(batchDocument.Filename)
+	}
+	if err != nil {
+		return
+	}
 
-		// TODO: de-duplicate errors (e.g., array item not "unique"...)
-		format := persistentFlags.OutputFormat
-		switch format {
-		case FORMAT_JSON:
-			fallthrough
-		case FORMAT_CSV:
-			fallthrough
-		case FORMAT_TEXT:
-			// Note: we no longer add the formatted errors to the actual error "detail" field;
-			// since BOMs can have large numbers of errors.  The new method is to allow
-			// the user to control the error result output (e.g., file, detail, etc.) via flags
-			FormatSchemaErrors(writer, schemaErrors, validateFlags, format)
-		default:
-			// Notify caller that we are defaulting to "txt" format
-			getLogger().Warningf(MSG_WARN_INVALID_FORMAT, format, FORMAT_TEXT)
-			FormatSchemaErrors(writer, schemaErrors, validateFlags, FORMAT_TEXT)
+	documentFlags := persistentFlags
+	documentFlags.InputFile = batchDocument.Filename
+	documentFlags.OutputFormat = FORMAT_TEXT
+
+	valid, schemaErrors, err := validateDocument(io.Discard, document, documentFlags, validateFlags, customConfig)
+	getLogger().Infof("document `%s`: valid=[%t]", document.GetFilename(), valid)
+	switch persistentFlags.OutputFormat {
+	case FORMAT_JUNIT:
+		// Note: documents that could not be validated are reported (as errors) without test cases
+		if err == nil || IsInvalidBOMError(err) {
+			rows = validateJUnitRows(schemaErrors, err, validateFlags)
 		}
-
-		return INVALID, document, schemaErrors, errInvalid
+		return
+	case FORMAT_SARIF:
+		// Note: documents that could not be validated are reported (as errors) without findings
+		if err == nil || IsInvalidBOMError(err) {
+			rows = sarifFindingRows(validationSarifFindings(schemaErrors, err, validateFlags))
+		}
+		return
 	}
+	return validateBatchRows(valid, schemaErrors, validateFlags), err
+}
 
-	// TODO: Perhaps factor in these errors into the JSON output as if they were actual schema errors...
-	// Perform additional validation in document composition/structure
-	// and "custom" required data within specified fields
-	if validateFlags.CustomValidation {
-		valid, err = validateCustom(document, LicensePolicyConfig)
+// Returns the batch report rows (i.e., in the order of VALIDATE_BATCH_TITLES) for a validated document
+func validateBatchRows(valid bool, schemaErrors []gojsonschema.ResultError, validateFlags utils.ValidateCommandFlags) (rows [][]string) {
+	for i, resultError := range schemaErrors {
+		if validateFlags.MaxNumErrors > 0 && i >= validateFlags.MaxNumErrors {
+			break
+		}
+		rows = append(rows, []string{
+			strconv.FormatBool(valid),
+			resultError.Type(),
+			resultError.Field(),
+			resultError.Description(),
+		})
 	}
 
-	// All validation tests passed; return VALID
+	if len(rows) == 0 {
+		rows = append(rows, []string{
+			strconv.FormatBool(valid),
+			REPORT_LIST_VALUE_NONE,
+			REPORT_LIST_VALUE_NONE,
+			REPORT_LIST_VALUE_NONE,
+		})
+	}
 	return
 }
 
-func validateCustom(document *schema.BOM, policyConfig *schema.LicensePolicyConfig) (valid bool, err error) {
+func formatValidationErrors(writer io.Writer, schemaErrors []gojsonschema.ResultError, validateFlags utils.ValidateCommandFlags, format string) {
+	switch format {
+	case FORMAT_JSON:
+		fallthrough
+	case FORMAT_CSV:
+		fallthrough
+	case FORMAT_TEXT:
+		// Note: we no longer add the formatted errors to the actual error "detail" field;
+		// since BOMs can have large numbers of errors.  The new method is to allow
+		// the user to control the error result output (e.g., file, detail, etc.) via flags
+		FormatSchemaErrors(writer, schemaErrors, validateFlags, format)
+	case FORMAT_SARIF, FORMAT_JUNIT:
+		// Note: SARIF findings and JUnit test cases are written once validation completes (see validateDocument)
+	default:
+		// Notify caller that we are defaulting to "txt" format
+		getLogger().Warningf(MSG_WARN_INVALID_FORMAT, format, FORMAT_TEXT)
+		FormatSchemaErrors(writer, schemaErrors, validateFlags, FORMAT_TEXT)
+	}
+}
+
+func validateCustom(document *schema.BOM, policyConfig *schema.LicensePolicyConfig, customConfig *schema.CustomValidationConfig) (valid bool, err error) {
 
 	// If the validated BOM is of a known format, we can unmarshal it into
 	// more convenient typed structures for simplified custom validation
@@ -361,7 +499,7 @@ func validateCustom(document *schema.BOM, policyConfig *schema.LicensePolicyConf
 	// Perform all custom validation
 	// TODO Implement customValidation as an interface supported by the CDXDocument type
 	// and later supported by a SPDXDocument type.
-	err = validateCustomCDXDocument(document, policyConfig)
+	err = validateCustomCDXDocument(document, policyConfig, customConfig)
 	if err != nil {
 		// Wrap any specific validation error in a single invalid BOM error
 		if !IsInvalidBOMError(err) {
@@ -19,30 +19,54 @@
 package cmd
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/CycloneDX/sbom-utility/schema"
-	"github.com/CycloneDX/sbom-utility/utils"
-	"github.com/jwangsadinata/go-multimap/slicemultimap"
+	"github.com/xeipuuv/gojsonschema"
 )
 
+// JSON schema (error) context root
+const JSON_CONTEXT_ROOT = "(root)"
+
+// Custom validation checks
+const (
+	CUSTOM_CHECK_COMPOSITION  = "composition"
+	CUSTOM_CHECK_REFERENCES   = "references"
+	CUSTOM_CHECK_LICENSE_DATA = "license data"
+	CUSTOM_CHECK_METADATA     = "metadata"
+	CUSTOM_CHECK_RULES        = "rules"
+)
+
+// Note: custom validation checks are listed in the order they are performed;
+// the first check that fails ends custom validation
+var CUSTOM_VALIDATION_CHECKS = []string{
+	CUSTOM_CHECK_COMPOSITION,
+	CUSTOM_CHECK_REFERENCES,
+	CUSTOM_CHECK_LICENSE_DATA,
+	CUSTOM_CHECK_METADATA,
+	CUSTOM_CHECK_RULES,
+}
+
 // Validate all custom requirements that cannot be found be schema validation
 // These custom requirements are categorized by the following areas:
 // 1. Composition - document elements are organized as required (even though allowed by schema)
-// 2. Metadata - Top-level, document metadata includes specific fields and/or values that match required criteria (e.g., regex)
-// 3. License data - Components, Services (or any object that carries a License) meets specified requirements
-func validateCustomCDXDocument(document *schema.BOM, policyConfig *schema.LicensePolicyConfig) (innerError error) {
+// 2. References - bom-refs are unique and all references to them resolve (i.e., referential integrity)
+// 3. Metadata - Top-level, document metadata includes required fields (e.g., a top-level component)
+// 4. License data - Components, Services (or any object that carries a License) meets specified requirements
+// 5. Rules - declarative rules (i.e., a selector, condition and severity) from the custom validation config file are met
+// (e.g., required metadata properties exist with values that match a regex)
+func validateCustomCDXDocument(document *schema.BOM, policyConfig *schema.LicensePolicyConfig, customConfig *schema.CustomValidationConfig) (innerError error) {
 	getLogger().Enter()
 	defer getLogger().Exit(innerError)
 
-	// Load custom validation file
-	errCfg := schema.LoadCustomValidationConfig(utils.GlobalFlags.ConfigCustomValidationFile)
-	if errCfg != nil {
-		getLogger().Warningf("custom validation not possible: %s", errCfg.Error())
-		innerError = errCfg
+	// Validate all custom composition requirements for overall CDX SBOM are met
+	if innerError = validateCustomDocumentComposition(document); innerError != nil {
 		return
 	}
 
-	// Validate all custom composition requirements for overall CDX SBOM are met
-	if innerError = validateCustomDocumentComposition(document); innerError != nil {
+	// Validate all bom-refs are unique and all references resolve to a declared bom-ref
+	if innerError = validateCustomReferences(document); innerError != nil {
 		return
 	}
 
@@ -57,9 +81,43 @@ func validateCustomCDXDocument(document *schema.BOM, policyConfig *schema.Licens
 	if innerError = validateCustomMetadata(document); innerError != nil {
 		return
 	}
+
+	// Validate all (declarative) rules from the custom validation config file are met
+	if innerError = validateCustomRules(document, customConfig); innerError != nil {
+		return
+	}
 	return
 }
 
+// Returns the custom validation check that failed with the given error (i.e., as returned
+// from validateCustom()); returns an empty string if the check can not be determined
+// (e.g., the custom validation configuration could not be loaded)
+func customValidationCheckName(err error) string {
+	var invalidErr *InvalidSBOMError
+	if errors.As(err, &invalidErr) {
+		if invalidErr.InnerError == nil {
+			if len(invalidErr.SchemaErrors) == 0 {
+				return ""
+			}
+			if _, isRule := invalidErr.SchemaErrors[0].(*RuleResultError); isRule {
+				return CUSTOM_CHECK_RULES
+			}
+			return CUSTOM_CHECK_REFERENCES
+		}
+		err = invalidErr.InnerError
+	}
+
+	switch err.(type) {
+	case *SBOMCompositionError:
+		return CUSTOM_CHECK_COMPOSITION
+	case *SBOMLicenseError:
+		return CUSTOM_CHECK_LICENSE_DATA
+	case *SBOMMetadataError:
+		return CUSTOM_CHECK_METADATA
+	}
+	return ""
+}
+
 // This validation function checks for custom composition requirements as follows:
 // 1. Assure that the "metadata.component" does NOT have child Components
 // 2. TODO: Assure that the "components" list is a "flat" list
@@ -90,129 +148,127 @@ func validateCustomDocumentComposition(document *schema.BOM) (innerError error)
 	return
 }
 
-// This validation function checks for custom metadata requirements are as follows:
-// 1. required "Properties" exist and have valid values (against supplied regex)
-// 2. Supplier field is filled out according to custom requirements
-// 3. Manufacturer field is filled out according to custom requirements
-// TODO: test for custom values in other metadata/fields:
-func validateCustomMetadata(document *schema.BOM) (err error) {
+// This validation function checks for referential integrity as follows:
+// 1. Assure that all "bom-ref" values (e.g., of components, services, vulnerabilities, licenses, formulation) are unique
+// 2. Assure that all references (i.e., "dependencies", "compositions.assemblies", "vulnerabilities.affects.ref",
+// "annotations.subjects" and "formulation" resource references) resolve to a declared "bom-ref"
+// Note: issues are returned as (JSON schema) result errors so they can be formatted the same as schema errors
+func validateCustomReferences(document *schema.BOM) (innerError error) {
 	getLogger().Enter()
-	defer getLogger().Exit(err)
+	defer getLogger().Exit(innerError)
 
-	// validate that the top-level pComponent is declared with all required values
-	if pComponent := document.GetCdxMetadataComponent(); pComponent == nil {
-		err := NewSBOMMetadataError(
-			document,
-			MSG_INVALID_METADATA_COMPONENT,
-			*document.GetCdxMetadata())
-		return err
+	issues := document.FindReferenceIssues()
+	if len(issues) == 0 {
+		return
 	}
 
-	// Validate required custom properties (by `name`) exist with appropriate values
-	err = validateCustomMetadataProperties(document)
-	if err != nil {
-		return err
+	referenceErrors := make([]gojsonschema.ResultError, 0, len(issues))
+	for _, issue := range issues {
+		referenceErrors = append(referenceErrors, NewReferenceResultError(issue))
 	}
+	innerError = NewInvalidSBOMError(
+		document,
+		MSG_REFERENCE_ERRORS,
+		nil,
+		referenceErrors)
+	return
+}
 
-	return err
+// ReferenceResultError describes a referential integrity issue as a JSON schema result error
+type ReferenceResultError struct {
+	gojsonschema.ResultErrorFields
 }
 
-// This validation function checks for custom metadata property requirements (i.e., names, values)
-// TODO: Evaluate need for this given new means to do this with JSON Schema v6 and 7
-func validateCustomMetadataProperties(document *schema.BOM) (err error) {
+func NewReferenceResultError(issue schema.BOMReferenceIssue) *ReferenceResultError {
+	resultError := new(ReferenceResultError)
+	resultError.SetType(issue.Type)
+	resultError.SetContext(newResultErrorContext(issue.Path))
+	resultError.SetValue(issue.Ref)
+	resultError.SetDescriptionFormat(issue.Description)
+	resultError.SetDescription(issue.Description)
+	return resultError
+}
+
+// Create the JSON context (i.e., a path from the document root) to the offending value
+func newResultErrorContext(path []string) *gojsonschema.JsonContext {
+	context := gojsonschema.NewJsonContext(JSON_CONTEXT_ROOT, nil)
+	for _, key := range path {
+		context = gojsonschema.NewJsonContext(key, context)
+	}
+	return context
+}
+
+// This validation function evaluates the (declarative) rules of the custom validation config file.
+// Rule violations with an "error" severity invalidate the BOM and are returned (along with any
+// other violations) as (JSON schema) result errors; otherwise, violations are only logged.
+func validateCustomRules(document *schema.BOM, customConfig *schema.CustomValidationConfig) (innerError error) {
 	getLogger().Enter()
-	defer getLogger().Exit(err)
+	defer getLogger().Exit(innerError)
 
-	validationProps := schema.CustomValidationChecks.GetCustomValidationMetadataProperties()
-	if len(validationProps) == 0 {
-		getLogger().Infof("No properties to validate")
+	violations := customConfig.EvaluateCustomValidationRules(document.GetJSONMap())
+	if len(violations) == 0 {
 		return
 	}
 
-	// TODO: move map to BOM object
-	hashmap := slicemultimap.New()
-	pProperties := document.GetCdxMetadataProperties()
-	if pProperties != nil {
-		err = hashMetadataProperties(hashmap, *pProperties)
-		if err != nil {
-			return
+	var invalid bool
+	ruleErrors := make([]gojsonschema.ResultError, 0, len(violations))
+	for _, violation := range violations {
+		switch violation.Severity {
+		case schema.RULE_SEVERITY_ERROR:
+			invalid = true
+		case schema.RULE_SEVERITY_WARNING:
+			getLogger().Warningf("%s: `%s` (%s): %s", MSG_RULE_VIOLATION, violation.RuleId, strings.Join(violation.Path, "."), violation.Description)
+		default:
+			getLogger().Infof("%s: `%s` (%s): %s", MSG_RULE_VIOLATION, violation.RuleId, strings.Join(violation.Path, "."), violation.Description)
 		}
+		ruleErrors = append(ruleErrors, NewRuleResultError(violation))
 	}
 
-	for _, checks := range validationProps {
-		getLogger().Tracef("Running validation checks: Property name: `%s`, checks(s): `%v`...", checks.Name, checks)
-		values, found := hashmap.Get(checks.Name)
-		if !found {
-			err = NewSbomMetadataPropertyError(
-				document,
-				MSG_PROPERTY_NOT_FOUND,
-				&checks, nil)
-			return err
-		}
-
-		// Check: (key) uniqueness
-		// i.e., Multiple values with same "key" (specified), not provided
-		// TODO: currently hashmap assumes "name" as the key; this could be dynamic (using reflect)
-		if checks.CheckUnique != "" {
-			getLogger().Tracef("CheckUnique: key: `%s`, `%s`, value(s): `%v`...", checks.Key, checks.CheckUnique, values)
-			// if multi-hashmap has more than one value, property is NOT unique
-			if len(values) > 1 {
-				err := NewSbomMetadataPropertyError(
-					document,
-					MSG_PROPERTY_NOT_UNIQUE,
-					&checks, nil)
-				return err
-			}
-		}
-
-		if checks.CheckRegex != "" {
-			getLogger().Tracef("CheckRegex: field: `%s`, regex: `%v`...", checks.CheckRegex, checks.Value)
-			compiledRegex, errCompile := utils.CompileRegex(checks.Value)
-			if errCompile != nil {
-				return errCompile
-			}
-
-			// TODO: check multiple values if provided
-			value := values[0]
-			if stringValue, ok := value.(string); ok {
-				getLogger().Debugf(">> Testing value: `%s`...", stringValue)
-				matched := compiledRegex.Match([]byte(stringValue))
-				if !matched {
-					err = NewSbomMetadataPropertyError(
-						document,
-						MSG_PROPERTY_REGEX_FAILED,
-						&checks, nil)
-					return err
-				} else {
-					getLogger().Debugf("matched:  ")
-				}
-
-			} else {
-				err = NewSbomMetadataPropertyError(
-					document,
-					MSG_PROPERTY_NOT_UNIQUE,
-					&checks, nil)
-				return err
-			}
-
-		}
+	if invalid {
+		innerError = NewInvalidSBOMError(
+			document,
+			MSG_RULE_ERRORS,
+			nil,
+			ruleErrors)
 	}
+	return
+}
 
-	return err
+// RuleResultError describes a custom validation rule violation as a JSON schema result error
+// Note: the (result error) type is the rule's id
+type RuleResultError struct {
+	gojsonschema.ResultErrorFields
+	Condition string
+	Severity  string
 }
 
-func hashMetadataProperties(hashmap *slicemultimap.MultiMap, properties []schema.CDXProperty) (err error) {
-	getLogger().Enter()
-	defer getLogger().Exit()
+func NewRuleResultError(violation schema.CustomValidationRuleViolation) *RuleResultError {
+	resultError := new(RuleResultError)
+	resultError.Condition = violation.Condition
+	resultError.Severity = violation.Severity
+	resultError.SetType(violation.RuleId)
+	resultError.SetContext(newResultErrorContext(violation.Path))
+	resultError.SetValue(violation.Value)
+	resultError.SetDescriptionFormat(violation.Description)
+	resultError.SetDescription(violation.Description)
+	return resultError
+}
 
-	if hashmap == nil {
-		return getLogger().Errorf("invalid hashmap: %v", hashmap)
-	}
+// This validation function checks for custom metadata requirements are as follows:
+// 1. the top-level "metadata.component" is declared
+// Note: required metadata "properties" (names, values) are checked by (declarative) rules
+// TODO: test for custom values in other metadata/fields:
+func validateCustomMetadata(document *schema.BOM) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
 
-	for _, prop := range properties {
-		hashmap.Put(prop.Name, prop.Value)
+	// validate that the top-level pComponent is declared with all required values
+	if pComponent := document.GetCdxMetadataComponent(); pComponent == nil {
+		err = NewSBOMMetadataError(
+			document,
+			MSG_INVALID_METADATA_COMPONENT,
+			*document.GetCdxMetadata())
 	}
-
 	return
 }
 
@@ -19,6 +19,10 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/CycloneDX/sbom-utility/schema"
@@ -51,6 +55,16 @@ const (
 	// Composition
 	TEST_CUSTOM_CDX_1_3_INVALID_COMPOSITION_COMPONENTS         = "test/custom/cdx-1-3-test-custom-invalid-composition-components.json"
 	TEST_CUSTOM_CDX_1_3_INVALID_COMPOSITION_METADATA_COMPONENT = "test/custom/cdx-1-3-test-custom-invalid-composition-metadata-component.json"
+
+	// References
+	TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES = "test/custom/cdx-1-5-test-custom-invalid-references.json"
+
+	// Rules
+	TEST_CUSTOM_CONFIG_RULES          = "test/custom/custom-rules.json"
+	TEST_CUSTOM_CONFIG_RULES_OVERRIDE = "test/custom/custom-rules-override.json"
+	TEST_CUSTOM_CDX_1_5_RULES         = "test/custom/cdx-1-5-test-custom-rules.json"
+	// Note: only violates rules with a "warning" (i.e., non-error) severity
+	TEST_CUSTOM_CDX_1_5_RULES_WARNINGS = "test/custom/cdx-1-5-test-custom-rules-warnings.json"
 )
 
 // -------------------------------------------
@@ -163,20 +177,39 @@ func TestValidateCustomCdx14MetadataPropsInvalidClassification(t *testing.T) {
 // -------------------------------------------
 // Note: The "uniqueness" constraint for objects is not supported in JSON schema v7
 
+// Note: required metadata properties are checked by the rules of the default (embedded) config
+func innerTestValidateCustomMetadataPropertyRule(t *testing.T, filename string, ruleId string, condition string) {
+	vti := NewValidateTestInfo(filename, FORMAT_TEXT, SCHEMA_VARIANT_NONE, &InvalidSBOMError{})
+	document, results, _ := innerTestValidateCustom(t, *vti)
+	getLogger().Debugf("filename: `%s`, results:\n%v", document.GetFilename(), results)
+
+	for _, result := range results {
+		if ruleErr, ok := result.(*RuleResultError); ok && ruleErr.Type() == ruleId && ruleErr.Condition == condition {
+			return
+		}
+	}
+	t.Errorf("expected rule error: Type=`%s`, Condition=`%s`, actual: %v", ruleId, condition, results)
+}
+
 func TestValidateCustomCdx14MetadataPropertyUniqueDisclaimer(t *testing.T) {
-	document, results, _ := innerTestValidateCustomInvalidSBOMInnerError(t,
+	innerTestValidateCustomMetadataPropertyRule(t,
 		TEST_CUSTOM_CDX_1_4_METADATA_PROPS_DISCLAIMER_UNIQUE,
-		SCHEMA_VARIANT_NONE,
-		&SBOMMetadataPropertyError{})
-	getLogger().Debugf("filename: `%s`, results:\n%v", document.GetFilename(), results)
+		"metadata-property-disclaimer",
+		schema.RULE_CONDITION_MAX_COUNT)
 }
 
 func TestValidateCustomCdx14MetadataPropertyUniqueClassification(t *testing.T) {
-	document, results, _ := innerTestValidateCustomInvalidSBOMInnerError(t,
-		TEST_CUSTOM_CDX_1_4_METADATA_PROPS_DISCLAIMER_UNIQUE,
-		SCHEMA_VARIANT_NONE,
-		&SBOMMetadataPropertyError{})
-	getLogger().Debugf("filename: `%s`, results:\n%v", document.GetFilename(), results)
+	innerTestValidateCustomMetadataPropertyRule(t,
+		TEST_CUSTOM_CDX_1_4_METADATA_PROPS_CLASSIFICATION_UNIQUE,
+		"metadata-property-classification",
+		schema.RULE_CONDITION_MAX_COUNT)
+}
+
+func TestValidateCustomCdx14MetadataPropertyNotFoundDisclaimer(t *testing.T) {
+	innerTestValidateCustomMetadataPropertyRule(t,
+		TEST_CUSTOM_CDX_1_4_METADATA_PROPS_DISCLAIMER_MISSING,
+		"metadata-property-disclaimer",
+		schema.RULE_CONDITION_MIN_COUNT)
 }
 
 // -------------------------------------------
@@ -199,6 +232,168 @@ func TestValidateCustomErrorCdx13InvalidCompositionComponents(t *testing.T) {
 		&SBOMCompositionError{})
 }
 
+// -------------------------------------------
+// Reference (integrity) tests
+// -------------------------------------------
+
+func TestValidateCustomErrorCdx15InvalidReferences(t *testing.T) {
+	vti := NewValidateTestInfo(TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES, FORMAT_TEXT, SCHEMA_VARIANT_NONE, &InvalidSBOMError{})
+	document, results, _ := innerTestValidateCustom(t, *vti)
+	getLogger().Debugf("filename: `%s`, results:\n%v", document.GetFilename(), results)
+
+	if !schemaErrorExists(results, schema.REFERENCE_ISSUE_NOT_UNIQUE, "components.1.bom-ref", "pkg:npm/express@4.18.2") {
+		t.Errorf("expected reference error: Type=`%s`, Field=`%s`", schema.REFERENCE_ISSUE_NOT_UNIQUE, "components.1.bom-ref")
+	}
+	if !schemaErrorExists(results, schema.REFERENCE_ISSUE_NOT_RESOLVED, "dependencies.0.dependsOn.1", "pkg:npm/debug@2.6.9") {
+		t.Errorf("expected reference error: Type=`%s`, Field=`%s`", schema.REFERENCE_ISSUE_NOT_RESOLVED, "dependencies.0.dependsOn.1")
+	}
+}
+
+// Reference errors are formatted the same as schema errors (i.e., using FormatSchemaErrors)
+func TestValidateCustomReferencesFormatCSV(t *testing.T) {
+	utils.GlobalFlags.PersistentFlags.InputFile = TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES
+	document, err := LoadInputBOMFileAndDetectSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = validateCustomReferences(document)
+	invalidErr, ok := err.(*InvalidSBOMError)
+	if !ok {
+		t.Fatalf("expected error type: `%T`, actual type: `%T`", &InvalidSBOMError{}, err)
+	}
+
+	var outputBuffer bytes.Buffer
+	outputWriter := bufio.NewWriter(&outputBuffer)
+	FormatSchemaErrors(outputWriter, invalidErr.SchemaErrors, utils.GlobalFlags.ValidateFlags, FORMAT_CSV)
+	outputWriter.Flush()
+
+	// title and 9 errors
+	if lineCount := strings.Count(outputBuffer.String(), "\n"); lineCount != 10 {
+		t.Errorf("expected: 10 lines, actual: %v:\n%s", lineCount, outputBuffer.String())
+	}
+	expectedValues := []string{schema.REFERENCE_ISSUE_NOT_RESOLVED, "annotations.0.subjects.1", "(root).annotations.0.subjects.1", "service:missing"}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+		t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+	}
+}
+
+// -------------------------------------------
+// Rule tests
+// -------------------------------------------
+
+func innerTestValidateCustomRules(t *testing.T, filename string) (err error) {
+	customConfig, err := schema.NewCustomValidationConfig([]string{TEST_CUSTOM_CONFIG_RULES}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utils.GlobalFlags.PersistentFlags.InputFile = filename
+	document, err := LoadInputBOMFileAndDetectSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return validateCustomRules(document, customConfig)
+}
+
+func TestValidateCustomRulesCdx15(t *testing.T) {
+	err := innerTestValidateCustomRules(t, TEST_CUSTOM_CDX_1_5_RULES)
+	invalidErr, ok := err.(*InvalidSBOMError)
+	if !ok {
+		t.Fatalf("expected error type: `%T`, actual type: `%T`", &InvalidSBOMError{}, err)
+	}
+	if check := customValidationCheckName(err); check != CUSTOM_CHECK_RULES {
+		t.Errorf("expected custom check: `%s`, actual: `%s`", CUSTOM_CHECK_RULES, check)
+	}
+
+	// Note: rule violations of all severities are returned once the BOM is invalid
+	results := invalidErr.SchemaErrors
+	if len(results) != 4 {
+		t.Fatalf("expected: 4 rule errors, actual: %v: %v", len(results), results)
+	}
+	if !schemaErrorExists(results[1:], "component-purl", "components.1.components.0.purl", "npm/bytes@3.1.2") {
+		t.Errorf("expected rule error: Type=`%s`, Field=`%s`", "component-purl", "components.1.components.0.purl")
+	}
+	if ruleErr, ok := results[3].(*RuleResultError); !ok || ruleErr.Severity != schema.RULE_SEVERITY_WARNING || ruleErr.Condition != schema.RULE_CONDITION_UNIQUE {
+		t.Errorf("expected `%s` rule error with severity `%s`, actual: %v", schema.RULE_CONDITION_UNIQUE, schema.RULE_SEVERITY_WARNING, results[3])
+	}
+}
+
+// Rule violations without an "error" severity do not invalidate the BOM
+func TestValidateCustomRulesCdx15WarningsValid(t *testing.T) {
+	if err := innerTestValidateCustomRules(t, TEST_CUSTOM_CDX_1_5_RULES_WARNINGS); err != nil {
+		t.Error(err)
+	}
+}
+
+func innerTestListCustomValidationRules(t *testing.T, format string, configFiles []string) (outputBuffer bytes.Buffer) {
+	defer func() { schema.CustomValidationChecks = schema.CustomValidationConfig{} }()
+
+	persistentFlags := utils.GlobalFlags.PersistentFlags
+	persistentFlags.OutputFormat = format
+	outputWriter := bufio.NewWriter(&outputBuffer)
+	if err := ListCustomValidationRules(outputWriter, persistentFlags, configFiles); err != nil {
+		t.Fatal(err)
+	}
+	outputWriter.Flush()
+	return
+}
+
+func TestValidateListRulesLayeredCSV(t *testing.T) {
+	outputBuffer := innerTestListCustomValidationRules(t, FORMAT_CSV,
+		[]string{TEST_CUSTOM_CONFIG_RULES, TEST_CUSTOM_CONFIG_RULES_OVERRIDE})
+
+	// title and 5 rules
+	if lineCount := strings.Count(outputBuffer.String(), "\n"); lineCount != 6 {
+		t.Errorf("expected: 6 lines, actual: %v:\n%s", lineCount, outputBuffer.String())
+	}
+	expectedValues := []string{"component-bom-ref-unique", "false", schema.RULE_SEVERITY_WARNING, TEST_CUSTOM_CONFIG_RULES_OVERRIDE}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+		t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+	}
+	expectedValues = []string{"component-purl", "true", "required, regex: ^pkg:", TEST_CUSTOM_CONFIG_RULES}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+		t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+	}
+}
+
+func TestValidateListRulesJSON(t *testing.T) {
+	outputBuffer := innerTestListCustomValidationRules(t, FORMAT_JSON, []string{TEST_CUSTOM_CONFIG_RULES})
+
+	var rules []map[string]interface{}
+	if err := json.Unmarshal(outputBuffer.Bytes(), &rules); err != nil {
+		t.Fatalf("invalid JSON output: %s:\n%s", err, outputBuffer.String())
+	}
+	if len(rules) != 4 || rules[0]["id"] != "component-purl" || rules[0]["enabled"] != true || rules[0]["source"] != TEST_CUSTOM_CONFIG_RULES {
+		t.Errorf("unexpected JSON output:\n%s", outputBuffer.String())
+	}
+}
+
+// The default (embedded) custom validation config declares the (metadata property) rules
+func TestValidateListRulesDefaultText(t *testing.T) {
+	outputBuffer := innerTestListCustomValidationRules(t, FORMAT_TEXT, nil)
+	if strings.Contains(outputBuffer.String(), MSG_OUTPUT_NO_RULES_FOUND) {
+		t.Errorf("expected output to not contain: `%s`:\n%s", MSG_OUTPUT_NO_RULES_FOUND, outputBuffer.String())
+	}
+	for _, ruleId := range []string{"metadata-property-disclaimer", "metadata-property-classification-value"} {
+		if !strings.Contains(outputBuffer.String(), ruleId) {
+			t.Errorf("expected output to contain: `%s`:\n%s", ruleId, outputBuffer.String())
+		}
+	}
+}
+
+func TestValidateListRulesDefaultJSON(t *testing.T) {
+	outputBuffer := innerTestListCustomValidationRules(t, FORMAT_JSON, nil)
+
+	var rules []map[string]interface{}
+	if err := json.Unmarshal(outputBuffer.Bytes(), &rules); err != nil {
+		t.Fatalf("invalid JSON output: %s:\n%s", err, outputBuffer.String())
+	}
+	if len(rules) != 4 || rules[0]["id"] != "metadata-property-disclaimer" || rules[0]["enabled"] != true || rules[0]["source"] != DEFAULT_CUSTOM_VALIDATION_CONFIG {
+		t.Errorf("unexpected JSON output:\n%s", outputBuffer.String())
+	}
+}
+
 // Make sure we can List all components in an SBOM, including those in hierarchical compositions
 // TODO: Actually verify one or more of the hierarchical comps. appear in list results
 // func TestValidateCustomCompositionHierarchicalComponentList(t *testing.T) {
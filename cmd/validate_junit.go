@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/log"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// JUnit (XML) test report
+const (
+	JUNIT_TESTSUITES_NAME    = "validate"
+	JUNIT_CLASSNAME_DOCUMENT = "validate.document"
+	JUNIT_CLASSNAME_SCHEMA   = "validate.schema"
+	JUNIT_CLASSNAME_CUSTOM   = "validate.custom"
+	JUNIT_CLASSNAME_PROFILE  = "validate.profile"
+	JUNIT_TESTCASE_DOCUMENT  = "document"
+	JUNIT_TESTCASE_SCHEMA    = "schema"
+	JUNIT_TESTCASE_CUSTOM    = "custom"
+)
+
+// JUnit test case status
+const (
+	JUNIT_STATUS_PASSED  = "passed"
+	JUNIT_STATUS_FAILED  = "failed"
+	JUNIT_STATUS_ERROR   = "error"
+	JUNIT_STATUS_SKIPPED = "skipped"
+)
+
+const (
+	MSG_JUNIT_CHECK_SKIPPED = "check not performed; a previous validation check failed"
+)
+
+// Note: test cases of batch (i.e., multiple input file) results are reported as batch rows
+// using these columns (i.e., one row per test case)
+var JUNIT_TESTCASE_COLUMNS = []string{"classname", "name", "status", "type", "message", "details"}
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitResult  `xml:"failure,omitempty"`
+	Error     *JUnitResult  `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+type JUnitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Details string `xml:",cdata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func NewJUnitTestCase(className string, name string, status string, errorType string, message string, details string) (testCase JUnitTestCase) {
+	testCase = JUnitTestCase{ClassName: className, Name: name}
+	switch status {
+	case JUNIT_STATUS_FAILED:
+		testCase.Failure = &JUnitResult{Message: message, Type: errorType, Details: details}
+	case JUNIT_STATUS_ERROR:
+		testCase.Error = &JUnitResult{Message: message, Type: errorType, Details: details}
+	case JUNIT_STATUS_SKIPPED:
+		testCase.Skipped = &JUnitSkipped{Message: message}
+	}
+	return
+}
+
+func (testCase JUnitTestCase) Status() string {
+	switch {
+	case testCase.Failure != nil:
+		return JUNIT_STATUS_FAILED
+	case testCase.Error != nil:
+		return JUNIT_STATUS_ERROR
+	case testCase.Skipped != nil:
+		return JUNIT_STATUS_SKIPPED
+	}
+	return JUNIT_STATUS_PASSED
+}
+
+// Returns the test case as a (batch) row (i.e., in the order of JUNIT_TESTCASE_COLUMNS)
+func (testCase JUnitTestCase) row() []string {
+	var errorType, message, details string
+	switch {
+	case testCase.Failure != nil:
+		errorType, message, details = testCase.Failure.Type, testCase.Failure.Message, testCase.Failure.Details
+	case testCase.Error != nil:
+		errorType, message, details = testCase.Error.Type, testCase.Error.Message, testCase.Error.Details
+	case testCase.Skipped != nil:
+		message = testCase.Skipped.Message
+	}
+	return []string{testCase.ClassName, testCase.Name, testCase.Status(), errorType, message, details}
+}
+
+func newJUnitTestCaseFromRow(row []string) JUnitTestCase {
+	values := make([]string, len(JUNIT_TESTCASE_COLUMNS))
+	copy(values, row)
+	return NewJUnitTestCase(values[0], values[1], values[2], values[3], values[4], values[5])
+}
+
+func NewJUnitTestSuite(name string, testCases []JUnitTestCase) (testSuite JUnitTestSuite) {
+	testSuite = JUnitTestSuite{Name: name, TestCases: testCases}
+	for _, testCase := range testCases {
+		testSuite.Tests++
+		switch testCase.Status() {
+		case JUNIT_STATUS_FAILED:
+			testSuite.Failures++
+		case JUNIT_STATUS_ERROR:
+			testSuite.Errors++
+		case JUNIT_STATUS_SKIPPED:
+			testSuite.Skipped++
+		}
+	}
+	return
+}
+
+func NewJUnitTestSuites(testSuites []JUnitTestSuite) (junitTestSuites *JUnitTestSuites) {
+	junitTestSuites = &JUnitTestSuites{Name: JUNIT_TESTSUITES_NAME, Suites: testSuites}
+	for _, testSuite := range testSuites {
+		junitTestSuites.Tests += testSuite.Tests
+		junitTestSuites.Failures += testSuite.Failures
+		junitTestSuites.Errors += testSuite.Errors
+		junitTestSuites.Skipped += testSuite.Skipped
+	}
+	return
+}
+
+// Note: XML data files MUST ends in a newline as this is a POSIX standard
+func DisplayJUnit(writer io.Writer, testSuites []JUnitTestSuite) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	getLogger().Infof("Outputting (%d) test suites (`%s` format)...", len(testSuites), FORMAT_JUNIT)
+	indent := utils.GenerateIndentString(utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	var data []byte
+	if data, err = xml.MarshalIndent(NewJUnitTestSuites(testSuites), "", indent); err != nil {
+		return
+	}
+	_, err = fmt.Fprintf(writer, "%s%s\n", xml.Header, data)
+	return
+}
+
+// Display the (batch) validation results using a test suite per input file
+func DisplayValidateBatchJUnit(writer io.Writer, results []BatchDocumentResult) (err error) {
+	testSuites := make([]JUnitTestSuite, 0, len(results))
+	for _, result := range results {
+		var testCases []JUnitTestCase
+		for _, row := range result.Rows {
+			testCases = append(testCases, newJUnitTestCaseFromRow(row))
+		}
+		// Documents that could not be validated (e.g., loaded) are reported as errors
+		if result.Err != nil && !IsInvalidBOMError(result.Err) {
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_DOCUMENT, JUNIT_TESTCASE_DOCUMENT,
+				JUNIT_STATUS_ERROR, junitErrorType(result.Err), result.Err.Error(), ""))
+		}
+		testSuites = append(testSuites, NewJUnitTestSuite(result.Filename, testCases))
+	}
+	return DisplayJUnit(writer, testSuites)
+}
+
+// Returns the (batch) rows of the test cases of a validated document
+func validateJUnitRows(schemaErrors []gojsonschema.ResultError, err error, validateFlags utils.ValidateCommandFlags) (rows [][]string) {
+	for _, testCase := range validationJUnitTestCases(schemaErrors, err, validateFlags) {
+		rows = append(rows, testCase.row())
+	}
+	return
+}
+
+// Returns the test cases of a validated document; that is, a test case for schema validation
+// (or one per schema error) followed by a test case for each custom validation check and
+// for the validation profile, if requested.
+// Note: schema (and referential integrity) errors are limited using the error limit flag
+func validationJUnitTestCases(schemaErrors []gojsonschema.ResultError, err error, validateFlags utils.ValidateCommandFlags) (testCases []JUnitTestCase) {
+	var schemaFailures, referenceFailures, ruleFailures []JUnitTestCase
+	for i, resultError := range schemaErrors {
+		if i == validateFlags.MaxNumErrors {
+			getLogger().Infof(MSG_INFO_TOO_MANY_ERRORS, validateFlags.MaxNumErrors, len(schemaErrors))
+			break
+		}
+		switch resultError.(type) {
+		case *ReferenceResultError:
+			referenceFailures = append(referenceFailures,
+				schemaErrorJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, CUSTOM_CHECK_REFERENCES+": ", resultError, validateFlags))
+		case *RuleResultError:
+			ruleFailures = append(ruleFailures,
+				schemaErrorJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, CUSTOM_CHECK_RULES+": ", resultError, validateFlags))
+		default:
+			schemaFailures = append(schemaFailures,
+				schemaErrorJUnitTestCase(JUNIT_CLASSNAME_SCHEMA, "", resultError, validateFlags))
+		}
+	}
+
+	if len(schemaFailures) > 0 {
+		testCases = append(testCases, schemaFailures...)
+	} else {
+		testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_SCHEMA, JUNIT_TESTCASE_SCHEMA, JUNIT_STATUS_PASSED, "", "", ""))
+	}
+
+	// Note: the profile is only checked if all other checks passed
+	var profileErr *SBOMProfileError
+	var invalidErr *InvalidSBOMError
+	if errors.As(err, &invalidErr) && errors.As(invalidErr.InnerError, &profileErr) {
+		err = nil
+	}
+	performed := len(schemaFailures) == 0
+
+	if validateFlags.CustomValidation {
+		customTestCases := customValidationJUnitTestCases(performed, referenceFailures, ruleFailures, err)
+		testCases = append(testCases, customTestCases...)
+		performed = performed && err == nil
+	}
+
+	if validateFlags.Profile != "" {
+		switch {
+		case !performed:
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_PROFILE, validateFlags.Profile, JUNIT_STATUS_SKIPPED, "", MSG_JUNIT_CHECK_SKIPPED, ""))
+		case profileErr != nil:
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_PROFILE, validateFlags.Profile, JUNIT_STATUS_FAILED,
+				junitErrorType(profileErr), profileErr.Error(), ""))
+		default:
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_PROFILE, validateFlags.Profile, JUNIT_STATUS_PASSED, "", "", ""))
+		}
+	}
+	return
+}
+
+// Returns a test case for each custom validation check (i.e., in order) where checks
+// are skipped if not performed (e.g., schema validation failed) or after the first failed check
+func customValidationJUnitTestCases(performed bool, referenceFailures []JUnitTestCase, ruleFailures []JUnitTestCase, err error) (testCases []JUnitTestCase) {
+	// Custom checks are only performed (in order) until the first check fails
+	failedCheck := ""
+	if performed && err != nil {
+		failedCheck = customValidationCheckName(err)
+		if failedCheck == "" {
+			// the failed check could not be determined (e.g., custom configuration error)
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, JUNIT_TESTCASE_CUSTOM,
+				JUNIT_STATUS_FAILED, junitErrorType(err), err.Error(), ""))
+			performed = false
+		}
+	}
+
+	for _, check := range CUSTOM_VALIDATION_CHECKS {
+		switch {
+		case !performed:
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, check, JUNIT_STATUS_SKIPPED, "", MSG_JUNIT_CHECK_SKIPPED, ""))
+		case check != failedCheck:
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, check, JUNIT_STATUS_PASSED, "", "", ""))
+		case check == CUSTOM_CHECK_REFERENCES && len(referenceFailures) > 0:
+			testCases = append(testCases, referenceFailures...)
+			performed = false
+		case check == CUSTOM_CHECK_RULES && len(ruleFailures) > 0:
+			testCases = append(testCases, ruleFailures...)
+			performed = false
+		default:
+			innerErr := err
+			var invalidErr *InvalidSBOMError
+			if errors.As(err, &invalidErr) && invalidErr.InnerError != nil {
+				innerErr = invalidErr.InnerError
+			}
+			testCases = append(testCases, NewJUnitTestCase(JUNIT_CLASSNAME_CUSTOM, check, JUNIT_STATUS_FAILED,
+				junitErrorType(innerErr), innerErr.Error(), ""))
+			performed = false
+		}
+	}
+	return
+}
+
+// Map a schema (or referential integrity or rule) error to a failed test case
+// Note: failure details use the same (uncolorized) result mapping as the "json" and "txt" formats
+func schemaErrorJUnitTestCase(className string, namePrefix string, resultError gojsonschema.ResultError, validateFlags utils.ValidateCommandFlags) JUnitTestCase {
+	validateFlags.ColorizeErrorOutput = false
+	validationErrorResult := mapSchemaErrorResult(resultError, validateFlags)
+	details := strings.TrimSpace(validationErrorResult.formatResultMap(validateFlags))
+
+	context := JSON_CONTEXT_ROOT
+	if resultError.Context() != nil {
+		context = resultError.Context().String()
+	}
+	name := fmt.Sprintf("%s%s: %s", namePrefix, context, resultError.Type())
+	return NewJUnitTestCase(className, name, JUNIT_STATUS_FAILED, resultError.Type(), resultError.Description(), details)
+}
+
+func junitErrorType(err error) string {
+	var typedError log.TypedError
+	if errors.As(err, &typedError) {
+		return typedError.ErrorType()
+	}
+	return fmt.Sprintf("%T", err)
+}
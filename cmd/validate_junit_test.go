@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// -------------------------------------------
+// JUnit test helper functions
+// -------------------------------------------
+
+func decodeTestJUnit(t *testing.T, outputBuffer bytes.Buffer) (testSuites *JUnitTestSuites) {
+	testSuites = new(JUnitTestSuites)
+	if err := xml.Unmarshal(outputBuffer.Bytes(), testSuites); err != nil {
+		t.Fatalf("invalid JUnit output: %s\n%s", err, outputBuffer.String())
+	}
+	return
+}
+
+func innerTestJUnitStatuses(t *testing.T, testCases []JUnitTestCase, expected map[string]string) {
+	actual := make(map[string]string)
+	for _, testCase := range testCases {
+		actual[testCase.Name] = testCase.Status()
+	}
+	for name, status := range expected {
+		if actual[name] != status {
+			t.Errorf("test case `%s`: expected status `%s`, actual: `%s` (test cases: %v)", name, status, actual[name], actual)
+		}
+	}
+}
+
+func newTestCustomValidateFlags() (validateFlags utils.ValidateCommandFlags) {
+	validateFlags.CustomValidation = true
+	validateFlags.MaxNumErrors = DEFAULT_MAX_ERROR_LIMIT
+	return
+}
+
+// -------------------------------------------
+// Validate
+// -------------------------------------------
+
+func TestJUnitValidateSpdx22SchemaErrors(t *testing.T) {
+	utils.GlobalFlags.PersistentFlags.InputFile = TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING
+	utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_JUNIT
+	// !!!Important!!! Must reset this global flag for subsequent tests
+	defer func() { utils.GlobalFlags.PersistentFlags.OutputFormat = FORMAT_TEXT }()
+
+	_, _, _, outputBuffer, err := innerValidateErrorBuffered(t, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.ValidateFlags)
+	if !ErrorTypesMatch(err, &InvalidSBOMError{}) {
+		t.Errorf("expected error type: `%T`, actual type: `%T`", &InvalidSBOMError{}, err)
+	}
+
+	testSuites := decodeTestJUnit(t, outputBuffer)
+	if len(testSuites.Suites) != 1 || testSuites.Tests != 1 || testSuites.Failures != 1 {
+		t.Fatalf("JUnit test suites: unexpected counts: `%+v`", testSuites)
+	}
+	failure := testSuites.Suites[0].TestCases[0].Failure
+	if failure == nil || failure.Type != "required" || failure.Message != "creationInfo is required" {
+		t.Errorf("JUnit test case: unexpected failure: `%+v`", failure)
+	}
+}
+
+func TestJUnitValidateBatchSpdx(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_JUNIT, TEST_SPDX_2_2_MIN_REQUIRED, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, TEST_BATCH_INPUT_FILE_LIST)
+	ti := NewCommonTestInfoBasic(TEST_SPDX_2_2_MIN_REQUIRED)
+	// Note: the input file that could not be loaded determines the overall (error) result
+	ti.ResultExpectedError = &UtilityError{}
+	outputBuffer, _ := innerTestBatch(t, ti, func(writer *bufio.Writer) error {
+		return ValidateBatch(writer, persistentFlags, batchFlags, utils.GlobalFlags.ValidateFlags)
+	})
+
+	testSuites := decodeTestJUnit(t, outputBuffer)
+	if len(testSuites.Suites) != 3 {
+		t.Fatalf("JUnit test suites: expected (3) suites, actual: `%v`", len(testSuites.Suites))
+	}
+	expected := []struct {
+		name     string
+		failures int
+		errors   int
+	}{
+		{TEST_SPDX_2_2_MIN_REQUIRED, 0, 0},
+		{TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, 1, 0},
+		{TEST_BATCH_INPUT_FILE_LIST, 0, 1},
+	}
+	for i, suite := range testSuites.Suites {
+		if suite.Name != expected[i].name || suite.Failures != expected[i].failures || suite.Errors != expected[i].errors {
+			t.Errorf("JUnit test suite: expected `%+v`, actual: `%+v`", expected[i], suite)
+		}
+	}
+}
+
+// -------------------------------------------
+// Custom validation checks
+// -------------------------------------------
+
+func TestJUnitCustomChecksPassed(t *testing.T) {
+	testCases := validationJUnitTestCases(nil, nil, newTestCustomValidateFlags())
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		JUNIT_TESTCASE_SCHEMA:     JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_COMPOSITION:  JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_REFERENCES:   JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_LICENSE_DATA: JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_METADATA:     JUNIT_STATUS_PASSED,
+	})
+}
+
+func TestJUnitCustomChecksCompositionFailed(t *testing.T) {
+	err := NewInvalidSBOMError(nil, MSG_INVALID_METADATA_COMPONENT_COMPONENTS,
+		NewSBOMCompositionError(MSG_INVALID_METADATA_COMPONENT_COMPONENTS, nil, []string{"metadata", "component", "components"}), nil)
+	testCases := validationJUnitTestCases(nil, err, newTestCustomValidateFlags())
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		JUNIT_TESTCASE_SCHEMA:     JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_COMPOSITION:  JUNIT_STATUS_FAILED,
+		CUSTOM_CHECK_REFERENCES:   JUNIT_STATUS_SKIPPED,
+		CUSTOM_CHECK_LICENSE_DATA: JUNIT_STATUS_SKIPPED,
+		CUSTOM_CHECK_METADATA:     JUNIT_STATUS_SKIPPED,
+	})
+}
+
+func TestJUnitCustomChecksMetadataFailed(t *testing.T) {
+	err := NewInvalidSBOMError(nil, MSG_INVALID_METADATA_COMPONENT,
+		NewSBOMMetadataError(nil, MSG_INVALID_METADATA_COMPONENT, schema.CDXMetadata{}), nil)
+	testCases := validationJUnitTestCases(nil, err, newTestCustomValidateFlags())
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		CUSTOM_CHECK_LICENSE_DATA: JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_METADATA:     JUNIT_STATUS_FAILED,
+	})
+}
+
+func TestJUnitCustomChecksReferencesFailed(t *testing.T) {
+	referenceErrors := []gojsonschema.ResultError{
+		NewReferenceResultError(schema.BOMReferenceIssue{
+			Type:        "ref_not_resolved",
+			Path:        []string{"dependencies", "0", "ref"},
+			Ref:         "pkg:npm/foo@1.0.0",
+			Description: "reference does not match any declared bom-ref",
+		}),
+	}
+	err := NewInvalidSBOMError(nil, MSG_REFERENCE_ERRORS, nil, referenceErrors)
+	testCases := validationJUnitTestCases(referenceErrors, err, newTestCustomValidateFlags())
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		JUNIT_TESTCASE_SCHEMA:    JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_COMPOSITION: JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_REFERENCES + ": (root).dependencies.0.ref: ref_not_resolved": JUNIT_STATUS_FAILED,
+		CUSTOM_CHECK_LICENSE_DATA: JUNIT_STATUS_SKIPPED,
+		CUSTOM_CHECK_METADATA:     JUNIT_STATUS_SKIPPED,
+	})
+}
+
+func TestJUnitCustomChecksRulesFailed(t *testing.T) {
+	ruleErrors := []gojsonschema.ResultError{
+		NewRuleResultError(schema.CustomValidationRuleViolation{
+			RuleId:      "component-purl",
+			Severity:    schema.RULE_SEVERITY_ERROR,
+			Condition:   schema.RULE_CONDITION_REQUIRED,
+			Path:        []string{"components", "1"},
+			Description: "every component must have a valid purl: required value not found: `purl`",
+		}),
+	}
+	err := NewInvalidSBOMError(nil, MSG_RULE_ERRORS, nil, ruleErrors)
+	testCases := validationJUnitTestCases(ruleErrors, err, newTestCustomValidateFlags())
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		JUNIT_TESTCASE_SCHEMA:   JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_REFERENCES: JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_METADATA:   JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_RULES + ": (root).components.1: component-purl": JUNIT_STATUS_FAILED,
+	})
+}
+
+func TestJUnitProfileFailed(t *testing.T) {
+	err := NewInvalidSBOMError(nil, MSG_PROFILE_BELOW_THRESHOLD,
+		NewSBOMProfileError(nil, VALIDATE_PROFILE_NTIA, 50, 100), nil)
+	validateFlags := newTestCustomValidateFlags()
+	validateFlags.Profile = VALIDATE_PROFILE_NTIA
+	testCases := validationJUnitTestCases(nil, err, validateFlags)
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		JUNIT_TESTCASE_SCHEMA:   JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_REFERENCES: JUNIT_STATUS_PASSED,
+		CUSTOM_CHECK_RULES:      JUNIT_STATUS_PASSED,
+		VALIDATE_PROFILE_NTIA:   JUNIT_STATUS_FAILED,
+	})
+}
+
+func TestJUnitProfileSkipped(t *testing.T) {
+	err := NewInvalidSBOMError(nil, MSG_INVALID_METADATA_COMPONENT,
+		NewSBOMMetadataError(nil, MSG_INVALID_METADATA_COMPONENT, schema.CDXMetadata{}), nil)
+	validateFlags := newTestCustomValidateFlags()
+	validateFlags.Profile = VALIDATE_PROFILE_NTIA
+	testCases := validationJUnitTestCases(nil, err, validateFlags)
+	innerTestJUnitStatuses(t, testCases, map[string]string{
+		CUSTOM_CHECK_METADATA: JUNIT_STATUS_FAILED,
+		VALIDATE_PROFILE_NTIA: JUNIT_STATUS_SKIPPED,
+	})
+}
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	FLAG_VALIDATE_PROFILE               = "profile"
+	FLAG_VALIDATE_PROFILE_THRESHOLD     = "profile-threshold"
+	MSG_VALIDATE_FLAG_PROFILE           = "validate conformance to a built-in profile and output its conformance report; profiles: "
+	MSG_VALIDATE_FLAG_PROFILE_THRESHOLD = "minimum percentage (0-100) of components that must conform to the profile"
+)
+
+// Built-in validation profiles
+const (
+	VALIDATE_PROFILE_NTIA = "ntia" // NTIA minimum elements
+)
+
+var VALIDATE_PROFILES = []string{VALIDATE_PROFILE_NTIA}
+
+const (
+	DEFAULT_PROFILE_THRESHOLD = 100.0
+)
+
+const (
+	MSG_VALIDATE_PROFILE_INVALID           = "invalid validation profile"
+	MSG_VALIDATE_PROFILE_THRESHOLD_INVALID = "invalid validation profile threshold (must be 0-100)"
+	MSG_OUTPUT_NO_COMPONENTS_FOUND         = "[WARN] no components found"
+	MSG_OUTPUT_NO_COMPONENTS_NONCONFORMING = "[INFO] all components conform to the profile"
+)
+
+const (
+	PROFILE_ELEMENT_DATA_KEY_ELEMENT     = "element"
+	PROFILE_ELEMENT_DATA_KEY_DESCRIPTION = "description"
+	PROFILE_ELEMENT_DATA_KEY_SCVS        = "scvs"
+	PROFILE_ELEMENT_DATA_KEY_PRESENT     = "present"
+	PROFILE_ELEMENT_DATA_KEY_TOTAL       = "total"
+	PROFILE_ELEMENT_DATA_KEY_COVERAGE    = "coverage"
+)
+
+const (
+	PROFILE_COMPONENT_DATA_KEY_ID      = "id"
+	PROFILE_COMPONENT_DATA_KEY_NAME    = "name"
+	PROFILE_COMPONENT_DATA_KEY_VERSION = "version"
+	PROFILE_COMPONENT_DATA_KEY_MISSING = "missing"
+)
+
+// NOTE: columns will be output in order they are listed here:
+var PROFILE_ELEMENT_ROW_DATA = []ColumnFormatData{
+	{PROFILE_ELEMENT_DATA_KEY_ELEMENT, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_ELEMENT_DATA_KEY_DESCRIPTION, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_ELEMENT_DATA_KEY_SCVS, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_ELEMENT_DATA_KEY_PRESENT, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_ELEMENT_DATA_KEY_TOTAL, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_ELEMENT_DATA_KEY_COVERAGE, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+}
+
+// NOTE: columns will be output in order they are listed here:
+var PROFILE_COMPONENT_ROW_DATA = []ColumnFormatData{
+	{PROFILE_COMPONENT_DATA_KEY_ID, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_COMPONENT_DATA_KEY_NAME, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_COMPONENT_DATA_KEY_VERSION, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{PROFILE_COMPONENT_DATA_KEY_MISSING, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+}
+
+// Command help formatting
+var VALIDATE_PROFILE_SUPPORTED_FORMATS = MSG_VALIDATE_FLAG_PROFILE + strings.Join(VALIDATE_PROFILES, ", ") +
+	MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN}, ", ")
+
+// The profile conformance report (as output in "json" format)
+type ValidationProfileReport struct {
+	Profile   string  `json:"profile"`
+	Threshold float64 `json:"threshold"`
+	Passed    bool    `json:"passed"`
+	*schema.NTIAConformance
+}
+
+// Command PreRunE helper function to test the validation profile flags (if any)
+func preRunTestForValidationProfile() (err error) {
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	if validateFlags.Profile == "" {
+		return
+	}
+	if !isValidValidationProfile(validateFlags.Profile) {
+		return getLogger().Errorf("%s: `%s` (supported profiles: %s)", MSG_VALIDATE_PROFILE_INVALID,
+			validateFlags.Profile, strings.Join(VALIDATE_PROFILES, ", "))
+	}
+	if validateFlags.ProfileThreshold < 0 || validateFlags.ProfileThreshold > 100 {
+		return getLogger().Errorf("%s: `%v`", MSG_VALIDATE_PROFILE_THRESHOLD_INVALID, validateFlags.ProfileThreshold)
+	}
+	return
+}
+
+func isValidValidationProfile(profile string) bool {
+	for _, name := range VALIDATE_PROFILES {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Check the (schema valid) document's conformance to the requested profile, output
+// the conformance report and return an InvalidSBOMError if below the threshold
+func validateProfile(writer io.Writer, document *schema.BOM, validateFlags utils.ValidateCommandFlags, format string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	// Note: "ntia" is currently the only (built-in) profile
+	conformance, err := document.CheckNTIAConformance()
+	if err != nil {
+		return
+	}
+
+	report := ValidationProfileReport{
+		Profile:         validateFlags.Profile,
+		Threshold:       validateFlags.ProfileThreshold,
+		Passed:          conformance.Coverage >= validateFlags.ProfileThreshold,
+		NTIAConformance: conformance,
+	}
+	getLogger().Infof("profile `%s`: components: %v, conforming: %v (%.2f%%)",
+		report.Profile, conformance.Components, conformance.Conforming, conformance.Coverage)
+
+	if err = DisplayValidationProfileReport(writer, report, format); err != nil {
+		return
+	}
+
+	if !report.Passed {
+		profileErr := NewSBOMProfileError(document, report.Profile, conformance.Coverage, report.Threshold)
+		invalidErr := NewInvalidSBOMError(
+			document,
+			MSG_PROFILE_BELOW_THRESHOLD,
+			profileErr,
+			nil)
+		invalidErr.Details = profileErr.Details
+		err = invalidErr
+	}
+	return
+}
+
+func DisplayValidationProfileReport(writer io.Writer, report ValidationProfileReport, format string) (err error) {
+	switch format {
+	case FORMAT_DEFAULT, FORMAT_TEXT:
+		err = DisplayValidationProfileTabbedText(writer, report)
+	case FORMAT_JSON:
+		err = DisplayValidationProfileJSON(writer, report)
+	case FORMAT_CSV:
+		err = DisplayValidationProfileCSV(writer, report)
+	case FORMAT_MARKDOWN:
+		err = DisplayValidationProfileMarkdown(writer, report)
+	case FORMAT_SARIF, FORMAT_JUNIT:
+		// Note: a failed profile check is reported as a SARIF finding (or JUnit test case)
+	default:
+		// default to text format for anything else
+		getLogger().Warningf("unsupported format: `%s`; using default format.", format)
+		err = DisplayValidationProfileTabbedText(writer, report)
+	}
+	return
+}
+
+func validationProfileSummary(report ValidationProfileReport) string {
+	return fmt.Sprintf("profile: `%s`, components: %v, conforming: %v, coverage: %.2f%% (threshold: %.2f%%), passed: %t",
+		report.Profile, report.Components, report.Conforming, report.Coverage, report.Threshold, report.Passed)
+}
+
+func validationProfileNoneMessage(report ValidationProfileReport) string {
+	if report.Components == 0 {
+		return MSG_OUTPUT_NO_COMPONENTS_FOUND
+	}
+	return MSG_OUTPUT_NO_COMPONENTS_NONCONFORMING
+}
+
+func validationProfileElementLineData(element schema.NTIAElementCoverage) []string {
+	return []string{
+		element.Element,
+		element.Description,
+		strings.Join(element.Scvs, ", "),
+		strconv.Itoa(element.Present),
+		strconv.Itoa(element.Total),
+		fmt.Sprintf("%.2f%%", element.Coverage),
+	}
+}
+
+func validationProfileComponentLineData(component schema.NTIAComponent) []string {
+	return []string{
+		component.Id,
+		component.Name,
+		component.Version,
+		strings.Join(component.Missing, ", "),
+	}
+}
+
+func DisplayValidationProfileTabbedText(writer io.Writer, report ValidationProfileReport) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n\n", validationProfileSummary(report))
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+
+	// create title row and underline row from slices of optional and compulsory titles
+	titles, underlines := prepareReportTitleData(PROFILE_ELEMENT_ROW_DATA, false)
+	fmt.Fprintf(w, "%s\n", strings.Join(titles, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+	for _, element := range report.Elements {
+		fmt.Fprintf(w, "%s\n", strings.Join(validationProfileElementLineData(element), "\t"))
+	}
+	w.Flush()
+	fmt.Fprintln(writer)
+
+	// Note: the columns of each table are aligned independently
+	w.Init(writer, 8, 2, 2, ' ', 0)
+	defer w.Flush()
+
+	titles, underlines = prepareReportTitleData(PROFILE_COMPONENT_ROW_DATA, false)
+	fmt.Fprintf(w, "%s\n", strings.Join(titles, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+
+	// Emit no components found (or all components conform) message into output
+	if len(report.NonConforming) == 0 {
+		fmt.Fprintf(w, "%s\n", validationProfileNoneMessage(report))
+		return
+	}
+
+	for _, component := range report.NonConforming {
+		fmt.Fprintf(w, "%s\n", strings.Join(validationProfileComponentLineData(component), "\t"))
+	}
+	return
+}
+
+func DisplayValidationProfileJSON(writer io.Writer, report ValidationProfileReport) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, report,
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
+
+// Note: the element coverage and the non-conforming components are written
+// as two (CSV) tables separated by an empty line
+func DisplayValidationProfileCSV(writer io.Writer, report ValidationProfileReport) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	titles, _ := prepareReportTitleData(PROFILE_ELEMENT_ROW_DATA, false)
+	if err = w.Write(titles); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", titles, err)
+	}
+	for _, element := range report.Elements {
+		line := validationProfileElementLineData(element)
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", line, err)
+		}
+	}
+	if err = w.Write([]string{}); err != nil {
+		return
+	}
+
+	titles, _ = prepareReportTitleData(PROFILE_COMPONENT_ROW_DATA, false)
+	if err = w.Write(titles); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", titles, err)
+	}
+	for _, component := range report.NonConforming {
+		line := validationProfileComponentLineData(component)
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", line, err)
+		}
+	}
+	return
+}
+
+func DisplayValidationProfileMarkdown(writer io.Writer, report ValidationProfileReport) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	fmt.Fprintf(writer, "%s\n\n", validationProfileSummary(report))
+
+	// create title row and alignment row from slices of optional and compulsory titles
+	titles, _ := prepareReportTitleData(PROFILE_ELEMENT_ROW_DATA, false)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(titles))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(titles)))
+	for _, element := range report.Elements {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(validationProfileElementLineData(element)))
+	}
+	fmt.Fprintln(writer)
+
+	titles, _ = prepareReportTitleData(PROFILE_COMPONENT_ROW_DATA, false)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(titles))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(titles)))
+
+	// Emit no components found (or all components conform) message into output
+	if len(report.NonConforming) == 0 {
+		fmt.Fprintf(writer, "%s\n", validationProfileNoneMessage(report))
+		return
+	}
+
+	for _, component := range report.NonConforming {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(validationProfileComponentLineData(component)))
+	}
+	return
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	TEST_PROFILE_SPDX_2_3_PACKAGES = "test/spdx/spdx-2-3-packages.json"
+)
+
+func innerTestValidateProfile(t *testing.T, inputFile string, format string, threshold float64, expectedError error) (outputBuffer bytes.Buffer, err error) {
+	persistentFlags := utils.GlobalFlags.PersistentFlags
+	persistentFlags.InputFile = inputFile
+	persistentFlags.OutputFormat = format
+	utils.GlobalFlags.PersistentFlags.InputFile = inputFile
+
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	validateFlags.Profile = VALIDATE_PROFILE_NTIA
+	validateFlags.ProfileThreshold = threshold
+
+	var outputWriter = bufio.NewWriter(&outputBuffer)
+	_, _, _, err = Validate(outputWriter, persistentFlags, validateFlags)
+	outputWriter.Flush()
+
+	if expectedError == nil && err != nil {
+		t.Fatalf("expected: no error, actual: `%v`:\n%s", err, outputBuffer.String())
+	}
+	if expectedError != nil && !ErrorTypesMatch(err, expectedError) {
+		t.Fatalf("expected error type: `%T`, actual type: `%T` (%v)", expectedError, err, err)
+	}
+	return
+}
+
+func TestValidateProfileNtiaSpdx23BelowThreshold(t *testing.T) {
+	outputBuffer, err := innerTestValidateProfile(t, TEST_PROFILE_SPDX_2_3_PACKAGES, FORMAT_TEXT, DEFAULT_PROFILE_THRESHOLD, &InvalidSBOMError{})
+
+	var profileErr *SBOMProfileError
+	if invalidErr, ok := err.(*InvalidSBOMError); !ok || !errors.As(invalidErr.InnerError, &profileErr) {
+		t.Fatalf("expected inner error type: `%T`, actual: `%v`", profileErr, err)
+	}
+	if profileErr.Profile != VALIDATE_PROFILE_NTIA || profileErr.Conformance != 50 || profileErr.Threshold != 100 {
+		t.Errorf("unexpected profile error: %v", profileErr)
+	}
+
+	expectedLines := [][]string{
+		{"supplier", "3", "4", "75.00%"},
+		{"identifiers", "3", "4", "75.00%"},
+		{"SPDXRef-Package-npm-async", "async", "2.6.3", "supplier"},
+		{"SPDXRef-Package-acme-application", "acme-application", "1.0.0", "identifiers"},
+	}
+	for _, expectedValues := range expectedLines {
+		if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+			t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+		}
+	}
+}
+
+func TestValidateProfileNtiaSpdx23ThresholdJSON(t *testing.T) {
+	outputBuffer, _ := innerTestValidateProfile(t, TEST_PROFILE_SPDX_2_3_PACKAGES, FORMAT_JSON, 50, nil)
+
+	var report map[string]interface{}
+	if err := json.Unmarshal(outputBuffer.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %s:\n%s", err, outputBuffer.String())
+	}
+	if report["profile"] != VALIDATE_PROFILE_NTIA || report["passed"] != true || report["coverage"] != 50.0 {
+		t.Errorf("unexpected JSON output:\n%s", outputBuffer.String())
+	}
+	if nonConforming, ok := report["nonConforming"].([]interface{}); !ok || len(nonConforming) != 2 {
+		t.Errorf("expected: (2) non-conforming components:\n%s", outputBuffer.String())
+	}
+}
+
+// An SBOM without components (i.e., SPDX packages) does not conform
+func TestValidateProfileNtiaSpdx22NoComponentsMarkdown(t *testing.T) {
+	outputBuffer, _ := innerTestValidateProfile(t, TEST_SPDX_2_2_MIN_REQUIRED, FORMAT_MARKDOWN, DEFAULT_PROFILE_THRESHOLD, &InvalidSBOMError{})
+
+	expectedValues := []string{"|timestamp|", "|1|1|100.00%|"}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, expectedValues...); !found {
+		t.Errorf("expected output to contain: %v:\n%s", expectedValues, outputBuffer.String())
+	}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, MSG_OUTPUT_NO_COMPONENTS_FOUND); !found {
+		t.Errorf("expected output to contain: `%s`:\n%s", MSG_OUTPUT_NO_COMPONENTS_FOUND, outputBuffer.String())
+	}
+}
+
+// Schema errors are reported instead of the profile's conformance
+func TestValidateProfileNtiaSpdx22SchemaErrors(t *testing.T) {
+	outputBuffer, err := innerTestValidateProfile(t, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING, FORMAT_TEXT, DEFAULT_PROFILE_THRESHOLD, &InvalidSBOMError{})
+	if invalidErr, ok := err.(*InvalidSBOMError); !ok || len(invalidErr.SchemaErrors) == 0 {
+		t.Errorf("expected: schema errors, actual: `%v`", err)
+	}
+	if _, found := bufferLineContainsValues(outputBuffer, RESULT_LINE_CONTAINS_ANY, "coverage"); found {
+		t.Errorf("expected: no profile report:\n%s", outputBuffer.String())
+	}
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	FLAG_VALIDATE_LIST_RULES     = "list-rules"
+	MSG_VALIDATE_FLAG_LIST_RULES = "list the effective custom validation rules (i.e., after layering all custom validation configuration files) instead of validating"
+)
+
+const (
+	MSG_OUTPUT_NO_RULES_FOUND = "[WARN] no rules found in custom validation configuration (i.e., \"custom.json\")"
+)
+
+const (
+	RULE_LIST_DATA_KEY_ID          = "id"
+	RULE_LIST_DATA_KEY_ENABLED     = "enabled"
+	RULE_LIST_DATA_KEY_SEVERITY    = "severity"
+	RULE_LIST_DATA_KEY_SELECTOR    = "selector"
+	RULE_LIST_DATA_KEY_FIELD       = "field"
+	RULE_LIST_DATA_KEY_CONDITION   = "condition"
+	RULE_LIST_DATA_KEY_DESCRIPTION = "description"
+	RULE_LIST_DATA_KEY_SOURCE      = "source"
+)
+
+// NOTE: columns will be output in order they are listed here:
+var RULE_LIST_ROW_DATA = []ColumnFormatData{
+	{RULE_LIST_DATA_KEY_ID, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_ENABLED, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_SEVERITY, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_SELECTOR, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_FIELD, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_CONDITION, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+	{RULE_LIST_DATA_KEY_DESCRIPTION, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, true},
+	{RULE_LIST_DATA_KEY_SOURCE, DEFAULT_COLUMN_TRUNCATE_LENGTH, REPORT_SUMMARY_DATA_TRUE, false},
+}
+
+// Command help formatting
+var RULE_LIST_SUPPORTED_FORMATS = MSG_SUPPORTED_OUTPUT_FORMATS_HELP +
+	strings.Join([]string{FORMAT_TEXT, FORMAT_JSON, FORMAT_CSV, FORMAT_MARKDOWN}, ", ")
+
+// A rule as listed in "json" format; includes the config file that (last) declared it
+type CustomValidationRuleJSON struct {
+	schema.CustomValidationRule
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
+}
+
+// List the effective rule set of the (layered) custom validation configuration files
+func ListCustomValidationRules(writer io.Writer, persistentFlags utils.PersistentCommandFlags, configFiles []string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = schema.LoadCustomValidationConfigFiles(configFiles, DEFAULT_CUSTOM_VALIDATION_CONFIG); err != nil {
+		return
+	}
+	rules := schema.CustomValidationChecks.GetCustomValidationRules()
+
+	format := persistentFlags.OutputFormat
+	switch format {
+	case FORMAT_DEFAULT, FORMAT_TEXT:
+		err = DisplayCustomValidationRulesTabbedText(writer, rules)
+	case FORMAT_JSON:
+		err = DisplayCustomValidationRulesJSON(writer, rules)
+	case FORMAT_CSV:
+		err = DisplayCustomValidationRulesCSV(writer, rules)
+	case FORMAT_MARKDOWN:
+		err = DisplayCustomValidationRulesMarkdown(writer, rules)
+	default:
+		// default to text format for anything else
+		getLogger().Warningf("unsupported format: `%s`; using default format.", format)
+		err = DisplayCustomValidationRulesTabbedText(writer, rules)
+	}
+	return
+}
+
+func customValidationRuleLineData(rule schema.CustomValidationRule) []string {
+	return []string{
+		rule.Id,
+		strconv.FormatBool(rule.IsEnabled()),
+		rule.Severity,
+		rule.Selector,
+		rule.Field,
+		rule.Condition.String(),
+		rule.Description,
+		rule.Source,
+	}
+}
+
+func DisplayCustomValidationRulesTabbedText(writer io.Writer, rules []schema.CustomValidationRule) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize tabwriter
+	w := new(tabwriter.Writer)
+
+	// min-width, tab-width, padding, pad-char, flags
+	w.Init(writer, 8, 2, 2, ' ', 0)
+	defer w.Flush()
+
+	// create title row and underline row from slices of optional and compulsory titles
+	titles, underlines := prepareReportTitleData(RULE_LIST_ROW_DATA, false)
+	fmt.Fprintf(w, "%s\n", strings.Join(titles, "\t"))
+	fmt.Fprintf(w, "%s\n", strings.Join(underlines, "\t"))
+
+	// Emit no rules found warning into output
+	if len(rules) == 0 {
+		fmt.Fprintf(w, "%s\n", MSG_OUTPUT_NO_RULES_FOUND)
+		return
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(w, "%s\n", strings.Join(customValidationRuleLineData(rule), "\t"))
+	}
+	return
+}
+
+func DisplayCustomValidationRulesJSON(writer io.Writer, rules []schema.CustomValidationRule) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	jsonRules := make([]CustomValidationRuleJSON, 0, len(rules))
+	for _, rule := range rules {
+		jsonRules = append(jsonRules, CustomValidationRuleJSON{
+			CustomValidationRule: rule,
+			Enabled:              rule.IsEnabled(),
+			Source:               rule.Source,
+		})
+	}
+
+	_, err = utils.WriteAnyAsEncodedJSONInt(writer, jsonRules,
+		utils.GlobalFlags.PersistentFlags.GetOutputIndentInt())
+	return
+}
+
+func DisplayCustomValidationRulesCSV(writer io.Writer, rules []schema.CustomValidationRule) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// initialize writer and prepare the list of entries (i.e., the "rows")
+	w := csv.NewWriter(writer)
+	defer w.Flush()
+
+	// create title row from slices of optional and compulsory titles
+	titles, _ := prepareReportTitleData(RULE_LIST_ROW_DATA, false)
+	if err = w.Write(titles); err != nil {
+		return getLogger().Errorf("error writing to output (%v): %s", titles, err)
+	}
+
+	// Emit no rules found warning into output
+	if len(rules) == 0 {
+		currentRow := []string{MSG_OUTPUT_NO_RULES_FOUND}
+		if err = w.Write(currentRow); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", currentRow, err)
+		}
+		return
+	}
+
+	for _, rule := range rules {
+		line := customValidationRuleLineData(rule)
+		if err = w.Write(line); err != nil {
+			return getLogger().Errorf("error writing to output (%v): %s", line, err)
+		}
+	}
+	return
+}
+
+func DisplayCustomValidationRulesMarkdown(writer io.Writer, rules []schema.CustomValidationRule) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// create title row and alignment row from slices of optional and compulsory titles
+	titles, _ := prepareReportTitleData(RULE_LIST_ROW_DATA, false)
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(titles))
+	fmt.Fprintf(writer, "%s\n", createMarkdownRow(createMarkdownColumnAlignment(titles)))
+
+	// Emit no rules found warning into output
+	if len(rules) == 0 {
+		fmt.Fprintf(writer, "%s\n", MSG_OUTPUT_NO_RULES_FOUND)
+		return
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(writer, "%s\n", createMarkdownRow(customValidationRuleLineData(rule)))
+	}
+	return
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Compiled JSON schemas that are reused when validating many documents (e.g., in batch or stream mode)
+var ValidationSchemaCache = sbom.DefaultSchemaCache
+
+// Returns the cache key of the schema a document is validated against
+func schemaCacheKey(document *schema.BOM, validateFlags utils.ValidateCommandFlags) string {
+	return sbom.SchemaCacheKey(document, validateFlags.ForcedJsonSchemaFile)
+}
+
+// Returns the (cached) compiled schema a document is validated against
+func loadValidationSchema(document *schema.BOM, validateFlags utils.ValidateCommandFlags) (compiled *gojsonschema.Schema, schemaName string, err error) {
+	return ValidationSchemaCache.LoadSchema(document, validateFlags.ForcedJsonSchemaFile)
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/CycloneDX/sbom-utility/utils"
+	"github.com/spf13/cobra"
+)
+
+// Stream modes (i.e., values of the "--stream" flag)
+const (
+	VALIDATE_STREAM_MODE_PATHS = "paths"
+	VALIDATE_STREAM_MODE_JSON  = "json"
+)
+
+var VALIDATE_STREAM_MODES = []string{VALIDATE_STREAM_MODE_PATHS, VALIDATE_STREAM_MODE_JSON}
+
+// Stream error messages
+const (
+	MSG_VALIDATE_STREAM_MODE_INVALID   = "invalid stream mode"
+	MSG_VALIDATE_STREAM_INPUT_MULTIPLE = "a stream can only be read from a single input (file or stdin)"
+	MSG_VALIDATE_STREAM_NO_DOCUMENTS   = "no documents found in stream"
+)
+
+// Name of the documents read from a stream of concatenated JSON documents
+// e.g., "stdin[1]" is the first document read from stdin
+const VALIDATE_STREAM_DOCUMENT_NAME_FORMAT = "%s[%v]"
+
+// Command PreRunE helper function to test the (single) input (file) the stream of documents is read from
+// Note: the stream is read from stdin if no input file is provided
+func preRunTestForStreamInput(cmd *cobra.Command, args []string) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	streamMode := utils.GlobalFlags.ValidateFlags.StreamMode
+	if !isValidStreamMode(streamMode) {
+		return getLogger().Errorf("%s: `%s` (supported modes: %s)", MSG_VALIDATE_STREAM_MODE_INVALID,
+			streamMode, strings.Join(VALIDATE_STREAM_MODES, ", "))
+	}
+
+	inputFiles := utils.GlobalFlags.BatchFlags.InputFiles
+	switch len(inputFiles) {
+	case 0:
+		utils.GlobalFlags.PersistentFlags.InputFile = INPUT_TYPE_STDIN
+	case 1:
+		utils.GlobalFlags.PersistentFlags.InputFile = inputFiles[0]
+	default:
+		return getLogger().Errorf("%s: %v", MSG_VALIDATE_STREAM_INPUT_MULTIPLE, inputFiles)
+	}
+	return
+}
+
+func isValidStreamMode(streamMode string) bool {
+	for _, mode := range VALIDATE_STREAM_MODES {
+		if streamMode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate the stream of documents read from the (persistent) input file (or stdin)
+func ValidateInputStream(writer io.Writer, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags, validateFlags utils.ValidateCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	inputFile := persistentFlags.InputFile
+	if inputFile == "" || inputFile == INPUT_TYPE_STDIN {
+		return ValidateStream(writer, os.Stdin, INPUT_TYPE_STDIN, persistentFlags, batchFlags, validateFlags)
+	}
+
+	// Conditionally append working directory if no abs. path detected
+	localFile := inputFile
+	if !filepath.IsAbs(localFile) {
+		localFile = filepath.Join(utils.GlobalFlags.WorkingDir, localFile)
+	}
+
+	var reader *os.File
+	if reader, err = os.Open(localFile); err != nil {
+		return
+	}
+	defer reader.Close()
+	return ValidateStream(writer, reader, inputFile, persistentFlags, batchFlags, validateFlags)
+}
+
+// Validate each document read from the stream (i.e., as it is read) using a pool of workers
+// and report their results together; compiled schemas are reused across all documents.
+// The stream (mode) consists of either newline-delimited file paths ("paths")
+// or concatenated JSON documents ("json").
+func ValidateStream(writer io.Writer, reader io.Reader, streamName string, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags, validateFlags utils.ValidateCommandFlags) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if streamName == INPUT_TYPE_STDIN {
+		streamName = "stdin"
+	}
+
+	// Note: the custom validation config is loaded once and shared (read-only) by all workers
+	var customConfig *schema.CustomValidationConfig
+	if customConfig, err = loadCustomValidationConfig(validateFlags); err != nil {
+		return
+	}
+
+	documents := make(chan BatchDocument)
+	switch validateFlags.StreamMode {
+	case VALIDATE_STREAM_MODE_PATHS:
+		go readStreamPaths(reader, streamName, documents)
+	case VALIDATE_STREAM_MODE_JSON:
+		go readStreamJSONDocuments(reader, streamName, documents)
+	default:
+		return NewUtilityError(ERR_TYPE_BATCH, MSG_VALIDATE_STREAM_MODE_INVALID, validateFlags.StreamMode, nil)
+	}
+
+	results := ProcessBatchStream(documents, batchFlags.MaxWorkers, func(document BatchDocument) ([][]string, error) {
+		return validateBatchDocument(document, persistentFlags, validateFlags, customConfig)
+	})
+	getLogger().Infof("Validated (%v) documents from stream `%s` using (%v) compiled schemas",
+		len(results), streamName, ValidationSchemaCache.Len())
+
+	if len(results) == 0 {
+		return NewUtilityError(ERR_TYPE_BATCH, MSG_VALIDATE_STREAM_NO_DOCUMENTS, streamName, nil)
+	}
+	return displayValidateBatchResults(writer, persistentFlags.OutputFormat, results)
+}
+
+// Send a document for each file path read from the stream (one per line)
+// Note: blank lines and lines beginning with "#" (i.e., comments) are ignored
+func readStreamPaths(reader io.Reader, streamName string, documents chan<- BatchDocument) {
+	defer close(documents)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" || strings.HasPrefix(path, BATCH_INPUT_FILE_LIST_COMMENT) {
+			continue
+		}
+		documents <- BatchDocument{Filename: path}
+	}
+	if err := scanner.Err(); err != nil {
+		documents <- BatchDocument{Filename: streamName, Err: err}
+	}
+}
+
+// Send each (concatenated) JSON document read from the stream; reading stops
+// at the first document that is not valid JSON (as the next document cannot be found)
+func readStreamJSONDocuments(reader io.Reader, streamName string, documents chan<- BatchDocument) {
+	defer close(documents)
+
+	decoder := json.NewDecoder(reader)
+	for index := 1; ; index++ {
+		name := fmt.Sprintf(VALIDATE_STREAM_DOCUMENT_NAME_FORMAT, streamName, index)
+		var data json.RawMessage
+		if err := decoder.Decode(&data); err != nil {
+			if err != io.EOF {
+				documents <- BatchDocument{Filename: name, Err: err}
+			}
+			return
+		}
+		documents <- BatchDocument{Filename: name, Data: data}
+	}
+}
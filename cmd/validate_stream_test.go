@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// -------------------------------------------
+// stream test helper functions
+// -------------------------------------------
+
+func readTestFile(t *testing.T, filename string) []byte {
+	data, err := os.ReadFile(filepath.Join(utils.GlobalFlags.WorkingDir, filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func innerTestValidateStream(t *testing.T, testInfo *CommonTestInfo, streamMode string, reader io.Reader) (outputBuffer bytes.Buffer, err error) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_CSV)
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	validateFlags.StreamMode = streamMode
+	return innerTestBatch(t, testInfo, func(writer *bufio.Writer) error {
+		return ValidateStream(writer, reader, INPUT_TYPE_STDIN, persistentFlags, batchFlags, validateFlags)
+	})
+}
+
+// -------------------------------------------
+// schema cache tests
+// -------------------------------------------
+
+func TestSchemaCacheReused(t *testing.T) {
+	document, err := LoadBOMFileAndDetectSchema(TEST_SPDX_2_2_MIN_REQUIRED)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ValidationSchemaCache.Reset()
+	compiled, _, err := loadValidationSchema(document, utils.GlobalFlags.ValidateFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, _, err := loadValidationSchema(document, utils.GlobalFlags.ValidateFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if compiled != cached {
+		t.Errorf("expected compiled schema to be reused from the cache")
+	}
+	if length := ValidationSchemaCache.Len(); length != 1 {
+		t.Errorf("invalid schema cache length: expected: 1, actual: %v", length)
+	}
+}
+
+func TestSchemaCacheKeyForced(t *testing.T) {
+	document, err := LoadBOMFileAndDetectSchema(TEST_SPDX_2_2_MIN_REQUIRED)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	key := schemaCacheKey(document, validateFlags)
+	validateFlags.ForcedJsonSchemaFile = "schema/spdx/2.2.1/spdx-schema.json"
+	if forcedKey := schemaCacheKey(document, validateFlags); forcedKey == key {
+		t.Errorf("expected forced schema cache key to differ from key: `%s`", key)
+	}
+}
+
+// Schema configs may map the same format, version and variant to different schema files
+func TestSchemaCacheKeySchemaFile(t *testing.T) {
+	document, err := LoadBOMFileAndDetectSchema(TEST_SPDX_2_2_MIN_REQUIRED)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	key := schemaCacheKey(document, validateFlags)
+	document.SchemaInfo.File = "schema/test/" + document.SchemaInfo.File
+	if fileKey := schemaCacheKey(document, validateFlags); fileKey == key {
+		t.Errorf("expected schema cache key to differ by schema file: `%s`", key)
+	}
+}
+
+// -------------------------------------------
+// stream tests
+// -------------------------------------------
+
+func TestValidateStreamJSONDocuments(t *testing.T) {
+	var stream bytes.Buffer
+	stream.Write(readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED))
+	stream.Write(readTestFile(t, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING))
+
+	ti := NewCommonTestInfoBasic(INPUT_TYPE_STDIN)
+	ti.ResultExpectedError = &InvalidSBOMError{}
+	ti.ResultLineContainsValues = []string{"stdin[1],true,none,none,none,none"}
+	ti.ResultLineContainsValuesAtLineNum = 1
+	_, _ = innerTestValidateStream(t, ti, VALIDATE_STREAM_MODE_JSON, bytes.NewReader(stream.Bytes()))
+
+	ti.ResultLineContainsValues = []string{"stdin[2],false,required,(root),creationInfo is required"}
+	ti.ResultLineContainsValuesAtLineNum = 2
+	_, _ = innerTestValidateStream(t, ti, VALIDATE_STREAM_MODE_JSON, bytes.NewReader(stream.Bytes()))
+}
+
+func TestValidateStreamJSONDocumentsFailSyntax(t *testing.T) {
+	// The (invalid) document is reported and the stream is no longer read
+	stream := string(readTestFile(t, TEST_SPDX_2_2_MIN_REQUIRED)) + "{invalid}"
+	ti := NewCommonTestInfoBasic(INPUT_TYPE_STDIN)
+	ti.ResultExpectedError = &UtilityError{}
+	ti.ResultLineContainsValues = []string{"stdin[2],none,none,none,none,invalid character"}
+	ti.ResultExpectedLineCount = 3
+	_, _ = innerTestValidateStream(t, ti, VALIDATE_STREAM_MODE_JSON, strings.NewReader(stream))
+}
+
+func TestValidateStreamPaths(t *testing.T) {
+	stream := strings.Join([]string{
+		"# SPDX documents",
+		TEST_SPDX_2_2_MIN_REQUIRED,
+		"",
+		TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES,
+	}, "\n")
+	ti := NewCommonTestInfoBasic(INPUT_TYPE_STDIN)
+	ti.ResultLineContainsValues = []string{TEST_RESOURCE_LIST_SPDX_2_3_PACKAGES + ",true,none,none,none,none"}
+	ti.ResultLineContainsValuesAtLineNum = 2
+	ti.ResultExpectedLineCount = 3
+	_, _ = innerTestValidateStream(t, ti, VALIDATE_STREAM_MODE_PATHS, strings.NewReader(stream))
+}
+
+func TestValidateStreamFailNoDocuments(t *testing.T) {
+	persistentFlags, batchFlags := newBatchTestFlags(FORMAT_CSV)
+	validateFlags := utils.GlobalFlags.ValidateFlags
+	validateFlags.StreamMode = VALIDATE_STREAM_MODE_PATHS
+	err := ValidateStream(io.Discard, strings.NewReader("# no paths\n"), INPUT_TYPE_STDIN, persistentFlags, batchFlags, validateFlags)
+	if !ErrorTypesMatch(err, &UtilityError{}) {
+		t.Errorf("expected error: %T, actual error: %T", &UtilityError{}, err)
+	}
+	EvaluateErrorAndKeyPhrases(t, err, []string{MSG_VALIDATE_STREAM_NO_DOCUMENTS})
+}
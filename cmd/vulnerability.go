@@ -19,14 +19,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
-	"sort"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 	"github.com/jwangsadinata/go-multimap"
@@ -128,10 +129,11 @@ func NewCommandVulnerability() *cobra.Command {
 		}
 
 		// Test for required flags (parameters)
-		err = preRunTestForInputFile(cmd, args)
+		err = preRunTestForBatchInputFiles(cmd, args)
 
 		return
 	}
+	initCommandBatchFlags(command)
 	return command
 }
 
@@ -161,6 +163,12 @@ func vulnerabilityCmdImpl(cmd *cobra.Command, args []string) (err error) {
 		return
 	}
 
+	if IsBatch(utils.GlobalFlags.BatchFlags) {
+		err = ListVulnerabilitiesBatch(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.BatchFlags,
+			utils.GlobalFlags.VulnerabilityFlags, whereFilters)
+		return
+	}
+
 	err = ListVulnerabilities(writer, utils.GlobalFlags.PersistentFlags, utils.GlobalFlags.VulnerabilityFlags, whereFilters)
 
 	return
@@ -175,16 +183,49 @@ func processVulnerabilityListResults(err error) {
 }
 
 func sortVulnerabilities(entries []multimap.Entry) {
-	// Sort by Id, Created date (descending)
-	sort.Slice(entries, func(i, j int) bool {
-		vuln1 := (entries[i].Value).(schema.VulnerabilityInfo)
-		vuln2 := (entries[j].Value).(schema.VulnerabilityInfo)
-		if vuln1.Id != vuln2.Id {
-			return vuln1.Id > vuln2.Id
-		}
+	sbom.SortVulnerabilities(entries)
+}
 
-		return vuln1.Created > vuln2.Created
+func ListVulnerabilitiesBatch(writer io.Writer, persistentFlags utils.PersistentCommandFlags, batchFlags utils.BatchCommandFlags,
+	flags utils.VulnerabilityCommandFlags, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	results := ProcessBatch(batchFlags.InputFiles, batchFlags.MaxWorkers, func(inputFile string) (rows [][]string, err error) {
+		var document *schema.BOM
+		if document, err = LoadBOMFileAndDetectSchema(inputFile); err != nil {
+			return
+		}
+		if err = loadDocumentVulnerabilities(document, whereFilters); err != nil {
+			return
+		}
+		return vulnerabilityListRows(document, flags)
 	})
+
+	titles, _ := prepareReportTitleData(VULNERABILITY_LIST_ROW_DATA, flags.Summary)
+	if err = DisplayBatchResults(writer, persistentFlags.OutputFormat, titles, results); err != nil {
+		return
+	}
+	return BatchResultsError(results)
+}
+
+// Returns the (sorted) vulnerability list report rows (i.e., in the order of VULNERABILITY_LIST_ROW_DATA)
+func vulnerabilityListRows(bom *schema.BOM, flags utils.VulnerabilityCommandFlags) (rows [][]string, err error) {
+	entries := bom.VulnerabilityMap.Entries()
+	sortVulnerabilities(entries)
+
+	var line []string
+	for _, entry := range entries {
+		if line, err = prepareReportLineData(
+			entry.Value.(schema.VulnerabilityInfo),
+			VULNERABILITY_LIST_ROW_DATA,
+			flags.Summary,
+		); err != nil {
+			return
+		}
+		rows = append(rows, line)
+	}
+	return
 }
 
 // NOTE: vulnerability type data has already been validated
@@ -240,30 +281,9 @@ func loadDocumentVulnerabilities(document *schema.BOM, whereFilters []common.Whe
 	getLogger().Enter()
 	defer getLogger().Exit(err)
 
-	// At this time, fail SPDX format SBOMs as "unsupported" (for "any" format)
-	if !document.FormatInfo.IsCycloneDx() {
-		err = schema.NewUnsupportedFormatForCommandError(
-			document.FormatInfo.CanonicalName,
-			document.GetFilename(),
-			CMD_LICENSE, FORMAT_ANY)
-		return
-	}
-
-	// Before looking for license data, fully unmarshal the SBOM
-	// into named structures
-	if err = document.UnmarshalCycloneDXBOM(); err != nil {
-		return
-	}
-
-	// Hash all components found in the (root).components[] (+ "nested" components)
-	pVulnerabilities := document.GetCdxVulnerabilities()
-	if pVulnerabilities != nil && len(*pVulnerabilities) > 0 {
-		if err = document.HashVulnerabilities(*pVulnerabilities, whereFilters); err != nil {
-			return
-		}
-	}
-
-	return
+	return sbom.HashVulnerabilities(context.Background(), document, sbom.VulnerabilityOptions{
+		WhereFilters: whereFilters,
+	})
 }
 
 // NOTE: This list is NOT de-duplicated
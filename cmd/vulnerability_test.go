@@ -25,7 +25,6 @@ import (
 	"testing"
 
 	"github.com/CycloneDX/sbom-utility/common"
-	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
 
@@ -35,6 +34,7 @@ const (
 	TEST_VULN_CDX_1_3_EXAMPLE_1_BOM_VEX = "test/vex/cdx-1-3-example1-bom-vex.json"
 	TEST_VULN_CDX_1_4_EXAMPLE_1_VEX     = "test/vex/cdx-1-4-example1-vex.json"
 	TEST_VULN_CDX_1_3_EXAMPLE_2_BOM_VEX = "test/vex/cdx-1-3-example2-bom-vex.json"
+	TEST_VULN_SPDX_2_3_PACKAGES         = "test/spdx/spdx-2-3-packages.json"
 )
 
 type VulnTestInfo struct {
@@ -122,25 +122,54 @@ func TestVulnListInvalidInputFileLoad(t *testing.T) {
 }
 
 // -------------------------------------------
-// Test format unsupported (SPDX)
+// SPDX variants
 // -------------------------------------------
-func TestVulnListFormatUnsupportedSPDXMinReq(t *testing.T) {
+func TestVulnListTextSpdx22MinReqNoVulnFound(t *testing.T) {
 	testInfo := NewVulnTestInfoBasic(
 		TEST_SPDX_2_2_MIN_REQUIRED,
-		FORMAT_DEFAULT,
-		&schema.UnsupportedFormatError{})
+		FORMAT_TEXT,
+		nil)
 
-	// verify correct error is returned
+	// verify there is a (warning) message present when no resources are found
+	testInfo.ResultLineContainsValues = []string{MSG_OUTPUT_NO_VULNERABILITIES_FOUND}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
 	innerTestVulnList(t, testInfo, VULN_TEST_DEFAULT_FLAGS)
 }
 
-func TestVulnListFormatUnsupportedSPDX22(t *testing.T) {
+func TestVulnListTextSpdx22Example1NoVulnFound(t *testing.T) {
 	testInfo := NewVulnTestInfoBasic(
 		TEST_SPDX_2_2_EXAMPLE_1,
-		FORMAT_DEFAULT,
-		&schema.UnsupportedFormatError{})
+		FORMAT_TEXT,
+		nil)
 
-	// verify correct error is returned
+	testInfo.ResultLineContainsValues = []string{MSG_OUTPUT_NO_VULNERABILITIES_FOUND}
+	testInfo.ResultLineContainsValuesAtLineNum = 2
+	innerTestVulnList(t, testInfo, VULN_TEST_DEFAULT_FLAGS)
+}
+
+// SPDX "SECURITY" "advisory" external references are listed as vulnerabilities
+func TestVulnListTextSpdx23PackageAdvisories(t *testing.T) {
+	testInfo := NewVulnTestInfoBasic(
+		TEST_VULN_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		nil)
+	testInfo.ResultExpectedLineCount = 4 // title, separator and 2 data rows
+	innerTestVulnList(t, testInfo, VULN_TEST_DEFAULT_FLAGS)
+}
+
+func TestVulnListTextSpdx23WhereClauseIdCVE(t *testing.T) {
+	TEST_INPUT_WHERE_CLAUSE := "id=CVE"
+	TEST_OUTPUT_CONTAINS := []string{"CVE-2021-43138", "https://nvd.nist.gov/vuln/detail/CVE-2021-43138", "Prototype pollution"}
+	TEST_OUTPUT_LINES := 3
+
+	testInfo := NewVulnTestInfo(
+		TEST_VULN_SPDX_2_3_PACKAGES,
+		FORMAT_TEXT,
+		TI_LIST_SUMMARY_FALSE,
+		TEST_INPUT_WHERE_CLAUSE,
+		TEST_OUTPUT_LINES)
+	testInfo.ResultLineContainsValues = TEST_OUTPUT_CONTAINS
+	testInfo.ResultLineContainsValuesAtLineNum = 2
 	innerTestVulnList(t, testInfo, VULN_TEST_DEFAULT_FLAGS)
 }
 
@@ -234,7 +263,8 @@ func TestVulnListCdx13JSON(t *testing.T) {
 		FORMAT_JSON,
 		nil)
 	// Note: this value will keep going down as we add more custom marshallers for vuln. structs
-	testInfo.ResultExpectedLineCount = 185
+	// Note: includes the (required) "ref" of the vulnerability's "affects" entry (i.e., 5 lines)
+	testInfo.ResultExpectedLineCount = 190
 	result, _, _ := innerTestVulnList(t, testInfo, VULN_TEST_DEFAULT_FLAGS)
 	getLogger().Debugf("result:\n%s", result.String())
 }
@@ -31,6 +31,11 @@ const (
 	MSG_QUERY_INVALID_SELECT_CLAUSE   = "invalid SELECT clause"
 	MSG_QUERY_INVALID_WHERE_CLAUSE    = "invalid WHERE clause"
 	MSG_QUERY_INVALID_ORDER_BY_CLAUSE = "invalid ORDERBY clause"
+	MSG_QUERY_INVALID_LIMIT_CLAUSE    = "invalid LIMIT clause"
+	MSG_QUERY_INVALID_OFFSET_CLAUSE   = "invalid OFFSET clause"
+	MSG_QUERY_INVALID_JSONPATH        = "invalid JSONPath expression"
+	MSG_QUERY_INVALID_JMESPATH        = "invalid JMESPath expression"
+	MSG_QUERY_CONFLICTING_CLAUSES     = "conflicting query clauses"
 )
 
 type QueryError struct {
@@ -65,6 +70,36 @@ func NewQueryWhereClauseError(qr *QueryRequest, detail string) *QueryError {
 	return err
 }
 
+func NewQueryOrderByClauseError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_INVALID_ORDER_BY_CLAUSE, detail)
+	return err
+}
+
+func NewQueryLimitClauseError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_INVALID_LIMIT_CLAUSE, detail)
+	return err
+}
+
+func NewQueryOffsetClauseError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_INVALID_OFFSET_CLAUSE, detail)
+	return err
+}
+
+func NewQueryJSONPathError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_INVALID_JSONPATH, detail)
+	return err
+}
+
+func NewQueryJMESPathError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_INVALID_JMESPATH, detail)
+	return err
+}
+
+func NewQueryConflictingClausesError(qr *QueryRequest, detail string) *QueryError {
+	var err = NewQueryError(qr, MSG_QUERY_CONFLICTING_CLAUSES, detail)
+	return err
+}
+
 // QueryError error interface
 func (err QueryError) Error() string {
 	// TODO: use a string buffer to build error message
@@ -82,3 +117,7 @@ func (err QueryError) Error() string {
 	}
 	return formattedMessage
 }
+
+func (err QueryError) ErrorType() string {
+	return err.Type
+}
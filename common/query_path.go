@@ -0,0 +1,386 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Array selector tokens used within FROM (and SELECT) paths, e.g.:
+// - "components[*]"               all entries of the "components" array
+// - "components[0]"               the first entry (negative indices count from the end)
+// - "components[name=foo]"        entries that match the (WHERE clause) filter
+// - "components[**]"              all entries including (recursively) nested "components"
+const (
+	QUERY_ARRAY_SELECTOR_BEGIN     = '['
+	QUERY_ARRAY_SELECTOR_END       = ']'
+	QUERY_ARRAY_SELECTOR_WILDCARD  = "*"
+	QUERY_ARRAY_SELECTOR_RECURSIVE = "**"
+)
+
+// Query path errors (i.e., found applying a path to a singleton object in "strict" mode)
+const (
+	MSG_QUERY_PATH_KEY_NOT_FOUND      = "key not found in path"
+	MSG_QUERY_PATH_INVALID_DATATYPE   = "invalid data type"
+	MSG_QUERY_PATH_KEY_NOT_ARRAY      = "array selector applied to a non-array value"
+	MSG_QUERY_PATH_INDEX_OUT_OF_RANGE = "array index out of range"
+)
+
+// Array selector types
+const (
+	QUERY_ARRAY_SELECTOR_TYPE_WILDCARD = iota
+	QUERY_ARRAY_SELECTOR_TYPE_RECURSIVE
+	QUERY_ARRAY_SELECTOR_TYPE_INDEX
+	QUERY_ARRAY_SELECTOR_TYPE_FILTER
+)
+
+// A single (dot-separated) path segment (i.e., a key name) and any array selectors
+// that are applied, in order, to the key's value
+type QueryPathSegment struct {
+	Key       string
+	Selectors []QueryArraySelector
+}
+
+type QueryArraySelector struct {
+	Type    int
+	Index   int
+	Filters []WhereFilter
+}
+
+// Return true if the path is a single key with no array selectors
+func IsSimpleQueryPath(segments []QueryPathSegment) bool {
+	return len(segments) == 1 && len(segments[0].Selectors) == 0
+}
+
+// Split the expression on the separator where it appears outside of array selectors
+// (i.e., square brackets) which may contain WHERE clauses that include separators.
+func splitQueryPath(expression string, separator byte) (parts []string) {
+	var depth, begin int
+	for i := 0; i < len(expression); i++ {
+		switch expression[i] {
+		case QUERY_WHERE_ESCAPE:
+			i++
+		case QUERY_ARRAY_SELECTOR_BEGIN:
+			depth++
+		case QUERY_ARRAY_SELECTOR_END:
+			if depth > 0 {
+				depth--
+			}
+		case separator:
+			if depth == 0 {
+				parts = append(parts, expression[begin:i])
+				begin = i + 1
+			}
+		}
+	}
+	parts = append(parts, expression[begin:])
+	return
+}
+
+// Parse a (dot-separated) path (e.g., "components[*].hashes[0].alg") into its segments
+func ParseQueryPath(rawPath string) (segments []QueryPathSegment, ok bool) {
+	for _, rawSegment := range splitQueryPath(rawPath, QUERY_FROM_CLAUSE_SEP[0]) {
+		var segment QueryPathSegment
+		if segment, ok = parseQueryPathSegment(rawSegment); !ok {
+			return nil, false
+		}
+		segments = append(segments, segment)
+	}
+	return segments, true
+}
+
+func ParseQueryPaths(rawPaths []string) (paths [][]QueryPathSegment, ok bool) {
+	for _, rawPath := range rawPaths {
+		var segments []QueryPathSegment
+		if segments, ok = ParseQueryPath(rawPath); !ok {
+			return nil, false
+		}
+		paths = append(paths, segments)
+	}
+	return paths, true
+}
+
+// Parse a path segment of the form: "key[selector]..."
+func parseQueryPathSegment(rawSegment string) (segment QueryPathSegment, ok bool) {
+	begin := strings.IndexByte(rawSegment, QUERY_ARRAY_SELECTOR_BEGIN)
+	if begin < 0 {
+		begin = len(rawSegment)
+	}
+
+	if segment.Key = rawSegment[:begin]; segment.Key == "" {
+		return
+	}
+
+	for begin < len(rawSegment) {
+		end := findQueryArraySelectorEnd(rawSegment, begin)
+		if rawSegment[begin] != QUERY_ARRAY_SELECTOR_BEGIN || end < 0 {
+			return segment, false
+		}
+
+		var selector QueryArraySelector
+		if selector, ok = parseQueryArraySelector(rawSegment[begin+1 : end]); !ok {
+			return
+		}
+		segment.Selectors = append(segment.Selectors, selector)
+		begin = end + 1
+	}
+	return segment, true
+}
+
+// Return the index of the bracket that ends the selector which begins at the index
+// (or -1 if the selector is not terminated)
+func findQueryArraySelectorEnd(expression string, begin int) int {
+	var depth int
+	for i := begin; i < len(expression); i++ {
+		switch expression[i] {
+		case QUERY_WHERE_ESCAPE:
+			i++
+		case QUERY_ARRAY_SELECTOR_BEGIN:
+			depth++
+		case QUERY_ARRAY_SELECTOR_END:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseQueryArraySelector(rawSelector string) (selector QueryArraySelector, ok bool) {
+	rawSelector = strings.TrimSpace(rawSelector)
+
+	switch rawSelector {
+	case "":
+		return
+	case QUERY_ARRAY_SELECTOR_WILDCARD:
+		selector.Type = QUERY_ARRAY_SELECTOR_TYPE_WILDCARD
+		return selector, true
+	case QUERY_ARRAY_SELECTOR_RECURSIVE:
+		selector.Type = QUERY_ARRAY_SELECTOR_TYPE_RECURSIVE
+		return selector, true
+	}
+
+	if index, errAtoi := strconv.Atoi(rawSelector); errAtoi == nil {
+		selector.Type = QUERY_ARRAY_SELECTOR_TYPE_INDEX
+		selector.Index = index
+		return selector, true
+	}
+
+	var err error
+	selector.Type = QUERY_ARRAY_SELECTOR_TYPE_FILTER
+	if selector.Filters, err = ParseWhereFilters(ParseWherePredicates(rawSelector)); err != nil {
+		return
+	}
+	return selector, true
+}
+
+// A value found at the path (i.e., keys and array indices) from the object
+// the (query) path was applied to
+type QueryPathNode struct {
+	Path  []string
+	Value interface{}
+	// the entries (with their paths) of a collection produced by an array selector
+	entries []QueryPathNode
+}
+
+type QueryPathOptions struct {
+	// Return errors for keys (or indices) not found in (or not applicable to) singleton objects
+	Strict bool
+	// Find keys that are present with a null value (otherwise, treated as not found)
+	IncludeNull bool
+}
+
+func NewQueryPathNode(value interface{}) QueryPathNode {
+	return QueryPathNode{Value: value}
+}
+
+// Find the node(s) at the path (segments) starting from the nodes.
+// If any segment dereferences a key of an array's entries (implicitly), or applies
+// a wildcard, filter or recursive array selector, the result is a "collection" of all
+// the nodes found (see: FlattenQueryPathNodes); otherwise, the result is the single node found.
+func FindQueryPathNodes(nodes []QueryPathNode, segments []QueryPathSegment, options QueryPathOptions) (found []QueryPathNode, collection bool, err error) {
+	for _, segment := range segments {
+		var values []QueryPathNode
+		for _, node := range nodes {
+			switch typedValue := node.Value.(type) {
+			case map[string]interface{}:
+				if value, present := typedValue[segment.Key]; present && (value != nil || options.IncludeNull) {
+					values = append(values, QueryPathNode{Path: appendQueryPath(node.Path, segment.Key), Value: value})
+				} else if options.Strict && !collection {
+					err = fmt.Errorf("%s: (%s)", MSG_QUERY_PATH_KEY_NOT_FOUND, segment.Key)
+					return
+				}
+			case []interface{}:
+				// dereference the key of each (map) entry of the array
+				collection = true
+				for _, entry := range node.Entries() {
+					if mapEntry, ok := entry.Value.(map[string]interface{}); ok {
+						if value, present := mapEntry[segment.Key]; present && (value != nil || options.IncludeNull) {
+							values = append(values, QueryPathNode{Path: appendQueryPath(entry.Path, segment.Key), Value: value})
+						}
+					}
+				}
+			default:
+				if options.Strict && !collection {
+					err = fmt.Errorf("%s: %T", MSG_QUERY_PATH_INVALID_DATATYPE, typedValue)
+					return
+				}
+			}
+		}
+
+		for _, selector := range segment.Selectors {
+			if values, err = selectQueryPathNodes(segment.Key, values, selector, options.Strict && !collection); err != nil {
+				return
+			}
+			// Note: selectors, other than indices, produce a collection of array entries
+			// which subsequent selectors (or keys) then apply to as a whole
+			if selector.Type != QUERY_ARRAY_SELECTOR_TYPE_INDEX {
+				collection = true
+				values = []QueryPathNode{newQueryPathCollection(values)}
+			}
+		}
+		nodes = values
+	}
+	return nodes, collection, nil
+}
+
+// Apply an array selector to each of the (array) nodes of the key
+func selectQueryPathNodes(key string, nodes []QueryPathNode, selector QueryArraySelector, strict bool) (selected []QueryPathNode, err error) {
+	for _, node := range nodes {
+		if _, ok := node.Value.([]interface{}); !ok {
+			if strict {
+				err = fmt.Errorf("%s: (%s)", MSG_QUERY_PATH_KEY_NOT_ARRAY, key)
+				return
+			}
+			continue
+		}
+		entries := node.Entries()
+
+		switch selector.Type {
+		case QUERY_ARRAY_SELECTOR_TYPE_WILDCARD:
+			selected = append(selected, entries...)
+		case QUERY_ARRAY_SELECTOR_TYPE_RECURSIVE:
+			selected = appendRecursiveQueryPathNodes(selected, key, entries)
+		case QUERY_ARRAY_SELECTOR_TYPE_INDEX:
+			// Note: negative indices are relative to the end of the array
+			index := selector.Index
+			if index < 0 {
+				index += len(entries)
+			}
+			if index >= 0 && index < len(entries) {
+				selected = append(selected, entries[index])
+			} else if strict {
+				err = fmt.Errorf("%s: (%s[%v])", MSG_QUERY_PATH_INDEX_OUT_OF_RANGE, key, selector.Index)
+				return
+			}
+		case QUERY_ARRAY_SELECTOR_TYPE_FILTER:
+			for _, entry := range entries {
+				if mapEntry, ok := entry.Value.(map[string]interface{}); ok {
+					if WhereFiltersMatch(mapEntry, selector.Filters) {
+						selected = append(selected, entry)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+// Append all array entries along with the entries of any arrays (recursively) found
+// under the same key within them (e.g., nested "components")
+func appendRecursiveQueryPathNodes(nodes []QueryPathNode, key string, entries []QueryPathNode) []QueryPathNode {
+	for _, entry := range entries {
+		nodes = append(nodes, entry)
+		if mapEntry, ok := entry.Value.(map[string]interface{}); ok {
+			if nested, ok := mapEntry[key].([]interface{}); ok {
+				nestedNode := QueryPathNode{Path: appendQueryPath(entry.Path, key), Value: nested}
+				nodes = appendRecursiveQueryPathNodes(nodes, key, nestedNode.Entries())
+			}
+		}
+	}
+	return nodes
+}
+
+// A collection (i.e., array) of the nodes which retains their paths
+func newQueryPathCollection(nodes []QueryPathNode) QueryPathNode {
+	values := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		values = append(values, node.Value)
+	}
+	if nodes == nil {
+		nodes = []QueryPathNode{}
+	}
+	return QueryPathNode{Value: values, entries: nodes}
+}
+
+// Return the entries (with their paths) of an array node; otherwise, nil
+func (node QueryPathNode) Entries() (entries []QueryPathNode) {
+	if node.entries != nil {
+		return node.entries
+	}
+	if slice, ok := node.Value.([]interface{}); ok {
+		entries = make([]QueryPathNode, 0, len(slice))
+		for i, entry := range slice {
+			entries = append(entries, QueryPathNode{Path: appendQueryPath(node.Path, strconv.Itoa(i)), Value: entry})
+		}
+	}
+	return
+}
+
+// Replace array nodes with their entries
+func FlattenQueryPathNodes(nodes []QueryPathNode) (flattened []QueryPathNode) {
+	for _, node := range nodes {
+		if _, ok := node.Value.([]interface{}); ok {
+			flattened = append(flattened, node.Entries()...)
+			continue
+		}
+		flattened = append(flattened, node)
+	}
+	return
+}
+
+// Replace collection nodes (i.e., produced by array selectors) with their entries
+func ExpandQueryPathCollections(nodes []QueryPathNode) (expanded []QueryPathNode) {
+	for _, node := range nodes {
+		if node.entries != nil {
+			expanded = append(expanded, node.entries...)
+			continue
+		}
+		expanded = append(expanded, node)
+	}
+	return
+}
+
+// Return the values of the nodes
+func QueryPathNodeValues(nodes []QueryPathNode) (values []interface{}) {
+	values = make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		values = append(values, node.Value)
+	}
+	return
+}
+
+// Note: always returns a new path (slice) as paths of sibling nodes share a common prefix
+func appendQueryPath(path []string, keys ...string) []string {
+	newPath := make([]string, 0, len(path)+len(keys))
+	newPath = append(newPath, path...)
+	return append(newPath, keys...)
+}
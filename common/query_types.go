@@ -32,7 +32,46 @@ const (
 	QUERY_FROM_CLAUSE_SEP      = "."
 	QUERY_SELECT_CLAUSE_SEP    = ","
 	QUERY_WHERE_EXPRESSION_SEP = ","
-	QUERY_WHERE_OPERAND_EQUALS = "="
+	QUERY_ORDER_BY_CLAUSE_SEP  = ","
+)
+
+// WHERE clause operands
+const (
+	QUERY_WHERE_OPERAND_EQUALS             = "="
+	QUERY_WHERE_OPERAND_NOT_EQUALS         = "!="
+	QUERY_WHERE_OPERAND_LESS_THAN          = "<"
+	QUERY_WHERE_OPERAND_LESS_THAN_EQUAL    = "<="
+	QUERY_WHERE_OPERAND_GREATER_THAN       = ">"
+	QUERY_WHERE_OPERAND_GREATER_THAN_EQUAL = ">="
+	QUERY_WHERE_OPERAND_CONTAINS           = "contains"
+	QUERY_WHERE_OPERAND_EXISTS             = "exists"
+	QUERY_WHERE_OPERAND_NOT_EXISTS         = "!exists"
+)
+
+// WHERE clause boolean keywords and grouping tokens
+// Note: the comma (i.e., QUERY_WHERE_EXPRESSION_SEP) is an alias for the AND keyword
+const (
+	QUERY_WHERE_KEYWORD_AND = "AND"
+	QUERY_WHERE_KEYWORD_OR  = "OR"
+	QUERY_WHERE_GROUP_BEGIN = '('
+	QUERY_WHERE_GROUP_END   = ')'
+	QUERY_WHERE_ESCAPE      = '\\'
+)
+
+// ORDERBY clause sort directions
+const (
+	QUERY_ORDER_BY_ASCENDING  = "asc"
+	QUERY_ORDER_BY_DESCENDING = "desc"
+)
+
+// WHERE clause predicate (and group) syntax:
+// - "key<operand>value" where operand is one of "=" (regex), "!=" (regex), "<", "<=", ">", ">="
+// - "key contains value", "key exists" or "key !exists"
+// - "(...)" a parenthesized group of predicates
+var (
+	regexWherePredicateCompare = regexp.MustCompile(`^([^\s=!<>(),]+)\s*(!=|<=|>=|=|<|>)\s*(.*)$`)
+	regexWherePredicateKeyword = regexp.MustCompile(`^([^\s=!<>(),]+)\s+(contains|exists|!exists)(?:\s+(.*))?$`)
+	regexWherePredicateStart   = regexp.MustCompile(`^\s*(\(|[^\s=!<>(),]+\s*(!=|<=|>=|=|<|>)|[^\s=!<>(),]+\s+(contains\s|!?exists(\s|,|\)|$)))`)
 )
 
 // ==================================================================
@@ -43,18 +82,26 @@ const (
 // SELECT: <key.1>, <key.2>, ... // "firstname, lastname, email" || * (default)
 // FROM: <key path>              // "product.customers"
 // WHERE: <key.X> == <value>     // "country='Germany'"
-// ORDERBY: <key.N>              // "lastname"
-// e.g.,SELECT * FROM product.customers WHERE country="Germany";
+// ORDERBY: <key.N> [asc|desc]   // "lastname"
+// LIMIT: <n> OFFSET: <m>        // "10", "20"
+// e.g.,SELECT * FROM product.customers WHERE country="Germany" ORDER BY lastname LIMIT 10;
 type QueryRequest struct {
 	selectKeysRaw      string
 	selectKeys         []string
+	selectPaths        [][]QueryPathSegment
 	fromPathsRaw       string
 	fromPathSelectors  []string
+	fromPathSegments   []QueryPathSegment
 	wherePredicatesRaw string
 	wherePredicates    []string
 	whereFilters       []WhereFilter
 	orderByKeysRaw     string
-	//orderByKeys       []string // TODO
+	orderByKeys        []OrderByKey
+	limit              int
+	offset             int
+	// Note: alternative query modes; if set, the above clauses are not used
+	jsonPathExpression string
+	jmesPathExpression string
 }
 
 // Implement the Stringer interface for QueryRequest
@@ -69,6 +116,10 @@ func (qr *QueryRequest) StringAsParameters() string {
 	sb.WriteString(fmt.Sprintf("--from: %s\n", qr.fromPathsRaw))
 	sb.WriteString(fmt.Sprintf("--where: %s\n", qr.wherePredicatesRaw))
 	sb.WriteString(fmt.Sprintf("--orderby: %s\n", qr.orderByKeysRaw))
+	sb.WriteString(fmt.Sprintf("--limit: %v\n", qr.limit))
+	sb.WriteString(fmt.Sprintf("--offset: %v\n", qr.offset))
+	sb.WriteString(fmt.Sprintf("--jsonpath: %s\n", qr.jsonPathExpression))
+	sb.WriteString(fmt.Sprintf("--jmespath: %s\n", qr.jmesPathExpression))
 	return sb.String()
 }
 
@@ -98,14 +149,27 @@ func NewQueryRequestSelectWildcardFromWhere(rawFrom string, rawWhere string) (qr
 	return NewQueryRequestSelectFromWhere(QUERY_TOKEN_WILDCARD, rawFrom, rawWhere)
 }
 
+func NewQueryRequestJSONPath(expression string) (qr *QueryRequest) {
+	qr = new(QueryRequest)
+	qr.jsonPathExpression = expression
+	return
+}
+
+func NewQueryRequestJMESPath(expression string) (qr *QueryRequest) {
+	qr = new(QueryRequest)
+	qr.jmesPathExpression = expression
+	return
+}
+
 // ------------
 // SELECT
 // ------------
 
 // parse out field (keys) from raw '--select' flag's value
+// Note: keys MAY be (dot-separated) paths which include array selectors (e.g., "hashes[0].alg")
 func ParseSelectKeys(rawSelectKeys string) (selectKeys []string) {
 	if rawSelectKeys != "" {
-		selectKeys = strings.Split(rawSelectKeys, QUERY_SELECT_CLAUSE_SEP)
+		selectKeys = splitQueryPath(rawSelectKeys, QUERY_SELECT_CLAUSE_SEP[0])
 	}
 	//getLogger().Tracef("SELECT keys: %v\n", selectKeys)
 	return
@@ -113,8 +177,9 @@ func ParseSelectKeys(rawSelectKeys string) (selectKeys []string) {
 
 func (qr *QueryRequest) SetRawSelectKeys(rawSelectKeys string) []string {
 	qr.selectKeysRaw = rawSelectKeys
-	// Note: it is an intentional side-effect to update the parsed, slice version
+	// Note: it is an intentional side-effect to update the parsed, slice versions
 	qr.selectKeys = ParseSelectKeys(rawSelectKeys)
+	qr.selectPaths, _ = ParseQueryPaths(qr.selectKeys)
 	return qr.selectKeys
 }
 
@@ -122,14 +187,20 @@ func (qr *QueryRequest) GetSelectKeys() []string {
 	return qr.selectKeys
 }
 
+// Return the parsed path for each SELECT key (in the same order as the keys)
+func (qr *QueryRequest) GetSelectPaths() [][]QueryPathSegment {
+	return qr.selectPaths
+}
+
 // ------------
 // FROM
 // ------------
 
-// parse out field (keys) from raw '--select' flag's value
+// parse out field (keys) from raw '--from' flag's value
+// Note: keys MAY include array selectors (e.g., "components[*]", "components[name=foo]")
 func ParseFromPaths(rawFromPaths string) (fromPaths []string) {
 	if rawFromPaths != "" {
-		fromPaths = strings.Split(rawFromPaths, QUERY_FROM_CLAUSE_SEP)
+		fromPaths = splitQueryPath(rawFromPaths, QUERY_FROM_CLAUSE_SEP[0])
 	}
 	//getLogger().Tracef("FROM paths: %v\n", fromPaths)
 	return
@@ -137,8 +208,9 @@ func ParseFromPaths(rawFromPaths string) (fromPaths []string) {
 
 func (qr *QueryRequest) SetRawFromPaths(rawFromPaths string) []string {
 	qr.fromPathsRaw = rawFromPaths
-	// Note: it is an intentional side-effect to update the parsed, slice version
+	// Note: it is an intentional side-effect to update the parsed, slice versions
 	qr.fromPathSelectors = ParseFromPaths(rawFromPaths)
+	qr.fromPathSegments, _ = ParseQueryPath(rawFromPaths)
 	return qr.fromPathSelectors
 }
 
@@ -146,14 +218,20 @@ func (qr *QueryRequest) GetFromKeys() []string {
 	return qr.fromPathSelectors
 }
 
+func (qr *QueryRequest) GetFromPathSegments() []QueryPathSegment {
+	return qr.fromPathSegments
+}
+
 // ------------
 // WHERE
 // ------------
 
-// parse out `key=<regex>` predicates from the raw `--where` flag's value
+// parse out predicates (e.g., `key=<regex>`) from the raw `--where` flag's value
+// Note: predicates are separated by commas (or the AND keyword) that appear outside
+// of any parenthesized group; all resulting predicates MUST match (i.e., logical AND).
 func ParseWherePredicates(rawWherePredicates string) (wherePredicates []string) {
 	if rawWherePredicates != "" {
-		wherePredicates = strings.Split(rawWherePredicates, QUERY_WHERE_EXPRESSION_SEP)
+		wherePredicates = splitWhereExpression(rawWherePredicates, QUERY_WHERE_KEYWORD_AND, true)
 	}
 	//getLogger().Tracef("WHERE predicates: %v\n", wherePredicates)
 	return
@@ -180,39 +258,173 @@ func ParseWhereFilters(wherePredicates []string) (whereFilters []WhereFilter, er
 	return
 }
 
+// Parse a single WHERE predicate which may be a simple comparison (e.g., `key=<regex>`,
+// `key<value`, `key exists`), a parenthesized group or a set of OR'ed predicates.
 // TODO: generate more specific error messages on why parsing failed
 func ParseWhereFilter(rawExpression string) (pWhereSelector *WhereFilter) {
+	expression := strings.TrimSpace(rawExpression)
 
-	if rawExpression == "" {
+	if expression == "" {
 		return // nil
 	}
 
-	tokens := strings.Split(rawExpression, QUERY_WHERE_OPERAND_EQUALS)
+	// OR has the lowest precedence; if present, create a group with each OR'ed term
+	if terms := splitWhereExpression(expression, QUERY_WHERE_KEYWORD_OR, false); len(terms) > 1 {
+		return parseWhereFilterGroup(expression, terms)
+	}
 
-	if len(tokens) != 2 {
+	// Remove the parentheses surrounding a group and parse its (AND'ed) predicates
+	if expression[0] == QUERY_WHERE_GROUP_BEGIN {
+		if end := findWhereGroupEnd(expression); end == len(expression)-1 {
+			return parseWhereFilterGroup(expression, []string{expression[1:end]})
+		}
 		return // nil
 	}
 
+	return parseWhereFilterPredicate(expression)
+}
+
+// Create a filter that matches if ALL predicates of ANY of the (OR'ed) terms match
+func parseWhereFilterGroup(expression string, terms []string) (pWhereSelector *WhereFilter) {
 	var whereFilter = WhereFilter{}
-	whereFilter.Operand = QUERY_WHERE_OPERAND_EQUALS
-	whereFilter.Key = tokens[0]
-	whereFilter.Value = tokens[1]
+	whereFilter.Operand = QUERY_WHERE_KEYWORD_OR
+	whereFilter.Value = expression
+
+	for _, term := range terms {
+		filters, err := ParseWhereFilters(ParseWherePredicates(term))
+		if err != nil || len(filters) == 0 {
+			return // nil
+		}
+		whereFilter.AnyOf = append(whereFilter.AnyOf, filters)
+	}
+
+	// Note: a group with a single predicate is equivalent to the predicate itself
+	if len(whereFilter.AnyOf) == 1 && len(whereFilter.AnyOf[0]) == 1 {
+		return &whereFilter.AnyOf[0][0]
+	}
+	return &whereFilter
+}
+
+func parseWhereFilterPredicate(expression string) (pWhereSelector *WhereFilter) {
+	var whereFilter = WhereFilter{}
+
+	if tokens := regexWherePredicateKeyword.FindStringSubmatch(expression); tokens != nil {
+		whereFilter.Key = tokens[1]
+		whereFilter.Operand = tokens[2]
+		whereFilter.Value = tokens[3]
+
+		// "exists" operands take no value; "contains" requires one
+		if (whereFilter.Operand == QUERY_WHERE_OPERAND_CONTAINS) == (whereFilter.Value == "") {
+			return // nil
+		}
+		return &whereFilter
+	}
 
-	if whereFilter.Value == "" {
+	tokens := regexWherePredicateCompare.FindStringSubmatch(expression)
+
+	if tokens == nil {
 		return // nil
 	}
 
-	var errCompile error
-	whereFilter.ValueRegEx, errCompile = utils.CompileRegex(whereFilter.Value)
-	//getLogger().Debugf(">> Regular expression: `%v`...", whereFilter.ValueRegEx)
+	whereFilter.Key = tokens[1]
+	whereFilter.Operand = tokens[2]
+	whereFilter.Value = tokens[3]
 
-	if errCompile != nil {
+	if whereFilter.Value == "" || strings.Contains(whereFilter.Value, QUERY_WHERE_OPERAND_EQUALS) {
 		return // nil
 	}
 
+	// Only (in)equality operands use regular expressions
+	if whereFilter.Operand == QUERY_WHERE_OPERAND_EQUALS ||
+		whereFilter.Operand == QUERY_WHERE_OPERAND_NOT_EQUALS {
+		var errCompile error
+		whereFilter.ValueRegEx, errCompile = utils.CompileRegex(whereFilter.Value)
+		//getLogger().Debugf(">> Regular expression: `%v`...", whereFilter.ValueRegEx)
+
+		if errCompile != nil {
+			return // nil
+		}
+	}
+
 	return &whereFilter
 }
 
+// Split a WHERE expression on the (whitespace delimited) keyword and, optionally,
+// commas that appear outside of parenthesized groups.
+// Note: a keyword is only treated as a separator if it is followed by another predicate
+// (or group) which allows values (regex) such as `expression=Apache-2.0 OR MIT`.
+// A keyword that begins or ends the expression (or is repeated) is also a separator
+// so that its missing operand (i.e., an empty part) is reported as a parse error.
+func splitWhereExpression(expression string, keyword string, splitOnComma bool) (parts []string) {
+	var depth, begin int
+
+	if trimmed := strings.TrimLeft(expression, " \t"); isWhereKeywordSeparator(trimmed, keyword) {
+		parts = append(parts, "")
+		begin = len(expression) - len(trimmed) + len(keyword)
+	}
+
+	for i := begin; i < len(expression); i++ {
+		switch c := expression[i]; {
+		case c == QUERY_WHERE_ESCAPE:
+			i++
+		case c == QUERY_WHERE_GROUP_BEGIN:
+			depth++
+		case c == QUERY_WHERE_GROUP_END:
+			if depth > 0 {
+				depth--
+			}
+		case depth > 0:
+			continue
+		case splitOnComma && c == QUERY_WHERE_EXPRESSION_SEP[0]:
+			parts = append(parts, expression[begin:i])
+			begin = i + 1
+		case c == ' ' || c == '\t':
+			if isWhereKeywordSeparator(expression[i+1:], keyword) {
+				next := i + 1 + len(keyword)
+				parts = append(parts, expression[begin:i])
+				begin = next
+				i = next - 1
+			}
+		}
+	}
+	parts = append(parts, expression[begin:])
+	return
+}
+
+// Return true if the expression begins with the (whitespace delimited) keyword followed by
+// another predicate (or group), the same keyword or nothing (i.e., a missing operand)
+func isWhereKeywordSeparator(expression string, keyword string) bool {
+	if !strings.HasPrefix(expression, keyword) {
+		return false
+	}
+	rest := expression[len(keyword):]
+	trimmed := strings.TrimLeft(rest, " \t")
+	if trimmed == "" {
+		return true
+	}
+	return len(trimmed) < len(rest) &&
+		(regexWherePredicateStart.MatchString(trimmed) || isWhereKeywordSeparator(trimmed, keyword))
+}
+
+// Return the index of the parenthesis that ends the group which begins the expression
+// (or -1 if the group is not terminated)
+func findWhereGroupEnd(expression string) int {
+	var depth int
+	for i := 0; i < len(expression); i++ {
+		switch expression[i] {
+		case QUERY_WHERE_ESCAPE:
+			i++
+		case QUERY_WHERE_GROUP_BEGIN:
+			depth++
+		case QUERY_WHERE_GROUP_END:
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 func (qr *QueryRequest) GetWhereFilters() ([]WhereFilter, error) {
 	if len(qr.wherePredicates) == 0 && qr.wherePredicatesRaw != "" {
 		// TODO: consider if we really need error handling
@@ -234,17 +446,126 @@ func (qr *QueryRequest) SetRawWherePredicates(rawWherePredicates string) []Where
 	return qr.whereFilters
 }
 
+// ------------
+// ORDERBY
+// ------------
+
+// parse out `key [asc|desc]` clauses from the raw `--orderby` flag's value
+// Note: the sort direction MAY also be separated from the key using a colon (e.g., `name:desc`)
+func ParseOrderByKeys(rawOrderByKeys string) (orderByKeys []OrderByKey, err error) {
+	if rawOrderByKeys == "" {
+		return
+	}
+
+	for _, clause := range strings.Split(rawOrderByKeys, QUERY_ORDER_BY_CLAUSE_SEP) {
+		tokens := strings.FieldsFunc(clause, func(r rune) bool {
+			return r == ':' || r == ' ' || r == '\t'
+		})
+
+		if len(tokens) == 0 || len(tokens) > 2 {
+			err = NewQueryOrderByClauseError(nil, clause)
+			return
+		}
+
+		orderByKey := OrderByKey{Key: tokens[0]}
+		if len(tokens) == 2 {
+			switch strings.ToLower(tokens[1]) {
+			case QUERY_ORDER_BY_ASCENDING:
+			case QUERY_ORDER_BY_DESCENDING:
+				orderByKey.Descending = true
+			default:
+				err = NewQueryOrderByClauseError(nil, clause)
+				return
+			}
+		}
+		orderByKeys = append(orderByKeys, orderByKey)
+	}
+	return
+}
+
+func (qr *QueryRequest) SetRawOrderByKeys(rawOrderByKeys string) (orderByKeys []OrderByKey, err error) {
+	qr.orderByKeysRaw = rawOrderByKeys
+	// Note: it is an intentional side-effect to update the parsed, slice version
+	qr.orderByKeys, err = ParseOrderByKeys(rawOrderByKeys)
+	return qr.orderByKeys, err
+}
+
+func (qr *QueryRequest) GetOrderByKeys() []OrderByKey {
+	return qr.orderByKeys
+}
+
+// ------------
+// LIMIT/OFFSET
+// ------------
+
+// Note: a limit of zero (default) indicates that all results are returned
+func (qr *QueryRequest) SetLimit(limit int) (err error) {
+	if limit < 0 {
+		return NewQueryLimitClauseError(qr, fmt.Sprintf("%v", limit))
+	}
+	qr.limit = limit
+	return
+}
+
+func (qr *QueryRequest) GetLimit() int {
+	return qr.limit
+}
+
+func (qr *QueryRequest) SetOffset(offset int) (err error) {
+	if offset < 0 {
+		return NewQueryOffsetClauseError(qr, fmt.Sprintf("%v", offset))
+	}
+	qr.offset = offset
+	return
+}
+
+func (qr *QueryRequest) GetOffset() int {
+	return qr.offset
+}
+
+// ------------------
+// JSONPath/JMESPath
+// ------------------
+
+func (qr *QueryRequest) SetJSONPathExpression(expression string) {
+	qr.jsonPathExpression = expression
+}
+
+func (qr *QueryRequest) GetJSONPathExpression() string {
+	return qr.jsonPathExpression
+}
+
+func (qr *QueryRequest) SetJMESPathExpression(expression string) {
+	qr.jmesPathExpression = expression
+}
+
+func (qr *QueryRequest) GetJMESPathExpression() string {
+	return qr.jmesPathExpression
+}
+
 // --------------
 // Other helpers
 // --------------
 
 // Parse command-line flag values including:
-// --select <clause> --from <clause> and --where <clause>
+// --select <clause> --from <clause> --where <clause> and --orderby <clause>
 func (qr *QueryRequest) parseQueryClauses() (err error) {
+	var ok bool
 	qr.selectKeys = ParseSelectKeys(qr.selectKeysRaw)
+	if qr.selectPaths, ok = ParseQueryPaths(qr.selectKeys); !ok {
+		return NewQuerySelectClauseError(nil, qr.selectKeysRaw)
+	}
 	qr.fromPathSelectors = ParseFromPaths(qr.fromPathsRaw)
+	if qr.fromPathsRaw != "" {
+		if qr.fromPathSegments, ok = ParseQueryPath(qr.fromPathsRaw); !ok {
+			return NewQueryFromClauseError(nil, qr.fromPathsRaw)
+		}
+	}
 	qr.wherePredicates = ParseWherePredicates(qr.wherePredicatesRaw)
-	qr.whereFilters, err = ParseWhereFilters(qr.wherePredicates)
+	if qr.whereFilters, err = ParseWhereFilters(qr.wherePredicates); err != nil {
+		return
+	}
+	qr.orderByKeys, err = ParseOrderByKeys(qr.orderByKeysRaw)
 	return
 }
 
@@ -297,6 +618,9 @@ type WhereFilter struct {
 	Operand    string
 	Value      string
 	ValueRegEx *regexp.Regexp
+	// Note: only used by (parenthesized or OR'ed) groups of filters (i.e., Operand "OR")
+	// where the group matches if ALL filters of ANY one of its terms match
+	AnyOf [][]WhereFilter `json:",omitempty"`
 }
 
 // Implement the Stringer interface for QueryRequest
@@ -315,3 +639,11 @@ func (filter *WhereFilter) GetNormalizedMapKey() (normalizedKey string) {
 	normalizedKey = strings.Replace(normalizedKey, "-", "", -1)
 	return
 }
+
+// ==================================================================
+// OrderByKey
+// ==================================================================
+type OrderByKey struct {
+	Key        string
+	Descending bool
+}
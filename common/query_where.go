@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// Key name suffix used to identify values that are compared as (semantic) versions
+const QUERY_KEY_SUFFIX_VERSION = "version"
+
+// Return true if the map object matches ALL the filters (i.e., logical AND)
+// Note: Golang supports the RE2 regular exp. engine which does not support many
+// features such as lookahead, lookbehind, etc.
+// See: https://en.wikipedia.org/wiki/Comparison_of_regular_expression_engines
+func WhereFiltersMatch(mapObject map[string]interface{}, whereFilters []WhereFilter) bool {
+	for i := range whereFilters {
+		if !whereFilters[i].Match(mapObject) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if the value of the filter's key, within the map object, satisfies the
+// filter's operand and value.
+// Note: keys not found in the map only match the "!=" and "!exists" operands.
+func (filter *WhereFilter) Match(mapObject map[string]interface{}) bool {
+	if filter.Operand == QUERY_WHERE_KEYWORD_OR {
+		for _, term := range filter.AnyOf {
+			if WhereFiltersMatch(mapObject, term) {
+				return true
+			}
+		}
+		return false
+	}
+
+	value, present := mapObject[filter.Key]
+	present = present && value != nil
+
+	switch filter.Operand {
+	case QUERY_WHERE_OPERAND_EXISTS:
+		return present
+	case QUERY_WHERE_OPERAND_NOT_EXISTS:
+		return !present
+	case QUERY_WHERE_OPERAND_NOT_EQUALS:
+		return !present || !filter.ValueRegEx.MatchString(QueryValueString(value))
+	}
+
+	if !present {
+		return false
+	}
+
+	switch filter.Operand {
+	case QUERY_WHERE_OPERAND_EQUALS:
+		return filter.ValueRegEx.MatchString(QueryValueString(value))
+	case QUERY_WHERE_OPERAND_CONTAINS:
+		// Note: arrays contain the value if any one of their entries does
+		if values, ok := value.([]interface{}); ok {
+			for _, entry := range values {
+				if strings.Contains(QueryValueString(entry), filter.Value) {
+					return true
+				}
+			}
+			return false
+		}
+		return strings.Contains(QueryValueString(value), filter.Value)
+	case QUERY_WHERE_OPERAND_LESS_THAN:
+		return CompareQueryValues(filter.Key, value, filter.Value) < 0
+	case QUERY_WHERE_OPERAND_LESS_THAN_EQUAL:
+		return CompareQueryValues(filter.Key, value, filter.Value) <= 0
+	case QUERY_WHERE_OPERAND_GREATER_THAN:
+		return CompareQueryValues(filter.Key, value, filter.Value) > 0
+	case QUERY_WHERE_OPERAND_GREATER_THAN_EQUAL:
+		return CompareQueryValues(filter.Key, value, filter.Value) >= 0
+	}
+	return false
+}
+
+// Compare two (JSON) values of the same key returning -1, 0 or +1.
+// Values of keys that end in "version" (e.g., "version", "specVersion") are compared
+// as semantic versions; otherwise, values are compared as numbers if both are numeric
+// and as strings if not.
+func CompareQueryValues(key string, value1 interface{}, value2 interface{}) int {
+	string1, string2 := QueryValueString(value1), QueryValueString(value2)
+
+	if strings.HasSuffix(strings.ToLower(key), QUERY_KEY_SUFFIX_VERSION) {
+		return utils.CompareVersions(string1, string2)
+	}
+
+	number1, errNumber1 := strconv.ParseFloat(string1, 64)
+	number2, errNumber2 := strconv.ParseFloat(string2, 64)
+	if errNumber1 == nil && errNumber2 == nil {
+		switch {
+		case number1 < number2:
+			return -1
+		case number1 > number2:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(string1, string2)
+}
+
+// Convert a (JSON) value to the string used for matching and comparison
+// Note: JSON objects and arrays are converted to their compact JSON encoding
+func QueryValueString(value interface{}) string {
+	switch data := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return data
+	case bool:
+		return strconv.FormatBool(data)
+	case int:
+		return strconv.Itoa(data)
+	case float64:
+		return strconv.FormatFloat(data, 'f', -1, 64)
+	}
+
+	if bytes, err := json.Marshal(value); err == nil {
+		return string(bytes)
+	}
+	return fmt.Sprintf("%v", value)
+}
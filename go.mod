@@ -3,9 +3,11 @@ module github.com/CycloneDX/sbom-utility
 go 1.20
 
 require (
+	github.com/PaesslerAG/jsonpath v0.1.1
 	github.com/fatih/color v1.15.0
 	github.com/hokaccha/go-prettyjson v0.0.0-20211117102719-0474bc63780f
 	github.com/iancoleman/orderedmap v0.3.0
+	github.com/jmespath/go-jmespath v0.4.0
 	github.com/jwangsadinata/go-multimap v0.0.0-20190620162914-c29f3d7f33b6
 	github.com/mrutkows/go-jsondiff v0.2.0
 	github.com/spf13/cobra v1.7.0
@@ -15,6 +17,7 @@ require (
 )
 
 require (
+	github.com/PaesslerAG/gval v1.0.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
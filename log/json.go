@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Log (line) formats
+const (
+	FORMAT_TEXT = "text" // default: human-readable (colored) text
+	FORMAT_JSON = "json" // one JSON object per log line
+)
+
+var VALID_FORMATS = []string{FORMAT_TEXT, FORMAT_JSON}
+
+// Log-related command line flags (also parsed before any command framework)
+const (
+	FLAG_LOG_FORMAT = "--log-format"
+	FLAG_LOG_FILE   = "--log-file"
+)
+
+// Reserved field names
+const (
+	FIELD_ERROR_TYPE = "errorType"
+	FIELD_TAG        = "tag"
+)
+
+// Level names without colors (i.e., for structured output)
+var LevelPlainNames = map[Level]string{
+	DEBUG:   "DEBUG",
+	TRACE:   "TRACE",
+	INFO:    "INFO",
+	WARNING: "WARN",
+	ERROR:   "ERROR",
+}
+
+// Structured (key-value) data added to log lines
+type Fields map[string]interface{}
+
+// Errors that declare their (application) type (e.g., "invalid SBOM") have it
+// added as a field when logged in JSON format
+type TypedError interface {
+	error
+	ErrorType() string
+}
+
+// A log line in JSON format
+type jsonRecord struct {
+	Level     string `json:"level"`
+	Timestamp string `json:"timestamp"`
+	Caller    string `json:"caller"`
+	Message   string `json:"message"`
+	Fields    Fields `json:"fields,omitempty"`
+}
+
+func IsValidFormat(format string) bool {
+	for _, value := range VALID_FORMATS {
+		if format == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (log *MiniLogger) SetFormat(format string) error {
+	if !IsValidFormat(format) {
+		return fmt.Errorf("invalid log format: `%s` (valid formats: %v)", format, VALID_FORMATS)
+	}
+	log.format = format
+	return nil
+}
+
+func (log *MiniLogger) GetFormat() string {
+	return log.format
+}
+
+// Write log lines to the writer (default: stdout)
+func (log *MiniLogger) SetOutput(writer io.Writer) {
+	log.outputFile = writer
+	log.outputWriter = bufio.NewWriter(writer)
+}
+
+// Append log lines to the named file (i.e., separate from command output on stdout/stderr)
+// NOTE: the returned file is also closed by Close() (e.g., on exit from the root command)
+func (log *MiniLogger) SetOutputFile(filename string) (file *os.File, err error) {
+	if file, err = os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		return
+	}
+	// Note: the last log file "wins"; do not leak any previously opened one
+	if err = log.Close(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	log.SetOutput(file)
+	log.outputCloser = file
+	return
+}
+
+// Flushes and closes the log file (if any) and reverts to writing log lines to stdout
+func (log *MiniLogger) Close() (err error) {
+	if log.outputCloser == nil {
+		return
+	}
+	err = log.Flush()
+	if errClose := log.outputCloser.Close(); err == nil {
+		err = errClose
+	}
+	log.outputCloser = nil
+	log.SetOutput(os.Stdout)
+	return
+}
+
+// Returns a logger that adds the fields to every log line
+func (log *MiniLogger) WithFields(fields Fields) *MiniLogger {
+	logger := *log
+	logger.fields = make(Fields, len(log.fields)+len(fields))
+	for key, value := range log.fields {
+		logger.fields[key] = value
+	}
+	for key, value := range fields {
+		logger.fields[key] = value
+	}
+	return &logger
+}
+
+// Returns the name (without package) of the function "skip" frames up the callstack
+// (i.e., where 0 is runtime.Callers itself and 1 is this function)
+func GetCallerFunctionName(skip int) (fxName string) {
+	pCallers := make([]uintptr, 4)
+	// Note: immediate caller is at index "2" on the stack
+	runtime.Callers(skip, pCallers)
+	if len(pCallers) > 0 {
+		fx := runtime.FuncForPC(pCallers[0])
+		if fx == nil {
+			return
+		}
+		fxName = fx.Name()
+		if index := strings.LastIndex(fxName, string(os.PathSeparator)); index > -1 {
+			fxName = fxName[index+1:]
+		}
+		if index := strings.LastIndex(fxName, "."); index > -1 {
+			fxName = fxName[index+1:]
+		}
+	}
+	return
+}
+
+// Returns the fields of a log line; in JSON format, the type of any (typed) errors is added
+func (log MiniLogger) lineFields(tag string, values ...interface{}) (fields Fields) {
+	fields = log.fields
+	if log.format != FORMAT_JSON {
+		return
+	}
+
+	fields = make(Fields, len(log.fields)+2)
+	for key, value := range log.fields {
+		fields[key] = value
+	}
+	if tag = strings.TrimSpace(tag); tag != "" {
+		fields[FIELD_TAG] = tag
+	}
+	for _, value := range values {
+		if err, ok := value.(error); ok {
+			var typedError TypedError
+			if errors.As(err, &typedError) {
+				fields[FIELD_ERROR_TYPE] = typedError.ErrorType()
+				break
+			}
+		}
+	}
+	return
+}
+
+// Format a log line as a JSON object (without a trailing newline)
+func formatJSONRecord(lvl Level, caller string, message string, fields Fields) string {
+	record := jsonRecord{
+		Level:     LevelPlainNames[lvl],
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Caller:    caller,
+		Message:   message,
+	}
+	if len(fields) > 0 {
+		record.Fields = fields
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		// Fields that cannot be marshalled (e.g., channels) are output as text
+		record.Fields = Fields{}
+		for key, value := range fields {
+			record.Fields[key] = fmt.Sprintf("%+v", value)
+		}
+		data, _ = json.Marshal(record)
+	}
+	return string(data)
+}
+
+// Format (sorted) fields as "key=value" pairs for text output
+func formatTextFields(fields Fields) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%+v", key, fields[key]))
+	}
+	return "{" + strings.Join(pairs, " ") + "}"
+}
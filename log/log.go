@@ -92,7 +92,10 @@ type MiniLogger struct {
 	quietMode       bool
 	outputFile      io.Writer
 	outputWriter    *bufio.Writer
+	outputCloser    io.Closer
 	maxStrLength    int
+	format          string
+	fields          Fields
 }
 
 func NewDefaultLogger() *MiniLogger {
@@ -106,6 +109,7 @@ func NewDefaultLogger() *MiniLogger {
 		tagColor:        color.New(color.FgMagenta),
 		outputFile:      os.Stdout,
 		maxStrLength:    64,
+		format:          FORMAT_TEXT,
 	}
 
 	// TODO: Use this instead of fmt.Print() variant functions
@@ -168,7 +172,8 @@ func (log *MiniLogger) InitLogLevelAndModeFromFlags() Level {
 	// with built-in flags.
 	// NOTE: flags MUST be defined within the "test" package or `go test` will error
 	// e.g., var TestLogLevelError = flag.Bool("error", false, "")
-	for _, arg := range os.Args[1:] {
+	args := os.Args[1:]
+	for index, arg := range args {
 		switch {
 		case arg == "-q" || arg == "-quiet" || arg == "--quiet" || arg == "quiet":
 			log.SetQuietMode(true)
@@ -178,12 +183,35 @@ func (log *MiniLogger) InitLogLevelAndModeFromFlags() Level {
 			log.SetLevel(DEBUG)
 		case arg == "--indent":
 			log.EnableIndent(true)
+		case arg == FLAG_LOG_FORMAT || strings.HasPrefix(arg, FLAG_LOG_FORMAT+"="):
+			// NOTE: invalid formats are ignored here (i.e., text is kept) and
+			// reported by the command layer which validates the flag value
+			if value, found := flagValue(args, index); found {
+				_ = log.SetFormat(value)
+			}
+		case arg == FLAG_LOG_FILE || strings.HasPrefix(arg, FLAG_LOG_FILE+"="):
+			if value, found := flagValue(args, index); found {
+				if _, err := log.SetOutputFile(value); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
 		}
 	}
 
 	return log.GetLevel()
 }
 
+// Returns the value of the flag at the index (i.e., either "--flag=value" or "--flag value")
+func flagValue(args []string, index int) (value string, found bool) {
+	if _, value, found = strings.Cut(args[index], "="); found {
+		return
+	}
+	if index+1 < len(args) {
+		return args[index+1], true
+	}
+	return
+}
+
 func (log *MiniLogger) Flush() (err error) {
 	if log.outputWriter != nil {
 		err = log.outputWriter.Flush()
@@ -232,9 +260,10 @@ func (log MiniLogger) Error(value interface{}) {
 	log.dumpInterface(ERROR, "", value, STACK_SKIP)
 }
 
+// Note: the type of any (typed) error values is logged as a field (in JSON format)
 func (log MiniLogger) Errorf(format string, value ...interface{}) error {
 	err := fmt.Errorf(format, value...)
-	log.dumpInterface(ERROR, "", err, STACK_SKIP)
+	log.dumpInterface(ERROR, "", err, STACK_SKIP, value...)
 	return err
 }
 
@@ -255,7 +284,7 @@ func (log *MiniLogger) Enter(values ...interface{}) {
 			}
 			sb.WriteByte(')')
 		}
-		log.dumpInterface(TRACE, log.tagColor.Sprintf(log.tagEnter), sb.String(), STACK_SKIP)
+		log.dumpInterface(TRACE, log.tagEnter, sb.String(), STACK_SKIP)
 
 		if log.indentEnabled {
 			// increase stack indent
@@ -288,14 +317,15 @@ func (log *MiniLogger) Exit(values ...interface{}) {
 			}
 		}
 
-		log.dumpInterface(TRACE, log.tagColor.Sprintf(log.tagExit), sb.String(), STACK_SKIP)
+		log.dumpInterface(TRACE, log.tagExit, sb.String(), STACK_SKIP)
 	}
 }
 
 // Note: currently, "dump" methods output directly to stdout (stderr)
 // Note: we comment out any "self-logging" or 'debug" for performance for release builds
 // compose log output using a "byte buffer" for performance
-func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, skip int) {
+// Note: any (optional) values are only checked for typed errors (i.e., to add their type as a field)
+func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, skip int, values ...interface{}) {
 
 	// Check for quiet mode enabled;
 	// if so, suppress any logging that is not an error
@@ -306,6 +336,21 @@ func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, sk
 		return
 	}
 
+	// Output one JSON object per log line (i.e., for log aggregators)
+	if log.format == FORMAT_JSON {
+		if lvl <= log.logLevel {
+			var message string
+			if value != nil && value != "" {
+				message = fmt.Sprintf("%+v", value)
+			}
+			// Note: skip the frames of this function and GetCallerFunctionName (itself)
+			caller := GetCallerFunctionName(skip + 2)
+			fields := log.lineFields(tag, append([]interface{}{value}, values...)...)
+			fmt.Fprintln(log.outputFile, formatJSONRecord(lvl, caller, message, fields))
+		}
+		return
+	}
+
 	sb := bytes.NewBufferString("")
 
 	// indent based upon current callstack (as incremented/decremented via Enter/Exit funcs.)
@@ -329,7 +374,7 @@ func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, sk
 			if log.logLevel == TRACE || log.logLevel == DEBUG {
 				// Append (optional) tag
 				if tag != "" {
-					sb.WriteString(fmt.Sprintf("[%s] ", tag))
+					sb.WriteString(fmt.Sprintf("[%s] ", log.tagColor.Sprintf(tag)))
 				}
 
 				// UTC time shows fractions of a second
@@ -360,8 +405,12 @@ func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, sk
 				sb.WriteString(fmt.Sprintf("%+v", value))
 			}
 
-			// TODO: use a general output writer (set to stdout, stderr, or file stream)
-			fmt.Println(sb.String())
+			// Append (optional) structured fields
+			if len(log.fields) > 0 {
+				sb.WriteString(" " + formatTextFields(log.fields))
+			}
+
+			fmt.Fprintln(log.outputFile, sb.String())
 		} else {
 			os.Stderr.WriteString("Error: Unable to retrieve call stack. Exiting...")
 			os.Exit(-2)
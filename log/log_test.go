@@ -19,6 +19,12 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -34,3 +40,151 @@ func TestNewLogger(t *testing.T) {
 	logger := NewLogger(TRACE)
 	assert.NotNil(t, logger)
 }
+
+// Test structured (JSON) logging
+type testTypedError struct {
+	message string
+}
+
+func (err testTypedError) Error() string {
+	return err.message
+}
+
+func (err testTypedError) ErrorType() string {
+	return "test error type"
+}
+
+func newTestJSONLogger(t *testing.T, buffer *bytes.Buffer) *MiniLogger {
+	logger := NewLogger(INFO)
+	if err := logger.SetFormat(FORMAT_JSON); err != nil {
+		t.Fatal(err)
+	}
+	logger.SetOutput(buffer)
+	return logger
+}
+
+func decodeLogLines(t *testing.T, buffer *bytes.Buffer, expectedLines int) (records []map[string]interface{}) {
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid JSON log line: `%s` (%s)", line, err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != expectedLines {
+		t.Fatalf("log lines: expected `%v`, actual: `%v`", expectedLines, len(records))
+	}
+	return
+}
+
+func getRecordField(record map[string]interface{}, key string) interface{} {
+	fields, _ := record["fields"].(map[string]interface{})
+	return fields[key]
+}
+
+func TestLogFormatInvalid(t *testing.T) {
+	logger := NewDefaultLogger()
+	if err := logger.SetFormat("xml"); err == nil {
+		t.Errorf("expected error for invalid log format")
+	}
+	if logger.GetFormat() != FORMAT_TEXT {
+		t.Errorf("log format: expected `%s`, actual: `%s`", FORMAT_TEXT, logger.GetFormat())
+	}
+}
+
+func TestLogFormatJSONRecord(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTestJSONLogger(t, &buffer)
+	logger.Infof("hello %s", "world")
+
+	record := decodeLogLines(t, &buffer, 1)[0]
+	expected := map[string]interface{}{
+		"level":   "INFO",
+		"message": "hello world",
+		"caller":  "TestLogFormatJSONRecord",
+	}
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("log record `%s`: expected `%v`, actual: `%v`", key, value, record[key])
+		}
+	}
+	if record["timestamp"] == nil || record["timestamp"] == "" {
+		t.Errorf("log record: missing timestamp")
+	}
+	if _, found := record["fields"]; found {
+		t.Errorf("log record: unexpected fields: `%v`", record["fields"])
+	}
+}
+
+func TestLogFormatJSONErrorType(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTestJSONLogger(t, &buffer)
+	err := logger.Errorf("failed: %w", testTypedError{message: "bad input"})
+	if !errors.As(err, &testTypedError{}) {
+		t.Errorf("expected wrapped error type: `%T`, actual: `%T`", testTypedError{}, errors.Unwrap(err))
+	}
+
+	record := decodeLogLines(t, &buffer, 1)[0]
+	if record["level"] != "ERROR" {
+		t.Errorf("log record level: expected `ERROR`, actual: `%v`", record["level"])
+	}
+	if errorType := getRecordField(record, FIELD_ERROR_TYPE); errorType != "test error type" {
+		t.Errorf("log record error type: expected `test error type`, actual: `%v`", errorType)
+	}
+}
+
+func TestLogFormatJSONWithFields(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := newTestJSONLogger(t, &buffer)
+	logger.WithFields(Fields{"format": "CycloneDX", "valid": true}).Info("validated")
+	// fields MUST NOT leak into the original logger
+	logger.Info("done")
+
+	records := decodeLogLines(t, &buffer, 2)
+	if value := getRecordField(records[0], "format"); value != "CycloneDX" {
+		t.Errorf("log record field `format`: expected `CycloneDX`, actual: `%v`", value)
+	}
+	if value := getRecordField(records[0], "valid"); value != true {
+		t.Errorf("log record field `valid`: expected `true`, actual: `%v`", value)
+	}
+	if _, found := records[1]["fields"]; found {
+		t.Errorf("log record: unexpected fields: `%v`", records[1]["fields"])
+	}
+}
+
+func TestLogFormatTextWithFields(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewLogger(INFO)
+	logger.SetOutput(&buffer)
+	logger.WithFields(Fields{"b": 2, "a": 1}).Info("validated")
+	if output := buffer.String(); !strings.Contains(output, "validated") || !strings.Contains(output, "{a=1 b=2}") {
+		t.Errorf("log line: expected message and sorted fields, actual: `%s`", output)
+	}
+}
+
+func TestLogSetOutputFileClose(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "log.txt")
+	logger := NewLogger(INFO)
+	file, err := logger.SetOutputFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("validated")
+	if err = logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The file is closed (i.e., further writes fail) and no longer the log output
+	if _, err = file.WriteString("unexpected"); err == nil {
+		t.Errorf("log file: expected closed file, actual: open")
+	}
+	if logger.outputFile != os.Stdout {
+		t.Errorf("log output: expected stdout, actual: `%v`", logger.outputFile)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "validated") {
+		t.Errorf("log file: expected log line, actual: `%s`", data)
+	}
+}
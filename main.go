@@ -25,6 +25,7 @@ import (
 
 	"github.com/CycloneDX/sbom-utility/cmd"
 	"github.com/CycloneDX/sbom-utility/log"
+	"github.com/CycloneDX/sbom-utility/pkg/sbom"
 	"github.com/CycloneDX/sbom-utility/schema"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
@@ -61,6 +62,7 @@ func init() {
 	// Provide access to project logger to other modules
 	cmd.ProjectLogger = Logger
 	schema.ProjectLogger = Logger
+	sbom.ProjectLogger = Logger
 
 	// Copy program package vars into command flags
 	utils.GlobalFlags.Project = Project
@@ -78,6 +80,11 @@ func init() {
 }
 
 func printWelcome() {
+	// Structured (JSON) logs only contain log lines
+	if Logger.GetFormat() == log.FORMAT_JSON {
+		Logger.Infof("Welcome to the %s! Version `%s` (%s) (%s/%s)", Project, Version, Binary, runtime.GOOS, runtime.GOARCH)
+		return
+	}
 	if !Logger.QuietModeOn() {
 		goos := fmt.Sprintf("(%s/%s)", runtime.GOOS, runtime.GOARCH)
 		echo := fmt.Sprintf("Welcome to the %s! Version `%s` (%s) %s\n", Project, Version, Binary, goos)
@@ -0,0 +1,498 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/schema"
+)
+
+// License list default values
+const (
+	LICENSE_LIST_NOT_APPLICABLE = "N/A"
+	LICENSE_NO_ASSERTION        = "NOASSERTION"
+)
+
+// License messages
+const (
+	MSG_LICENSE_INVALID_DATA = "invalid license data"
+	MSG_LICENSES_NOT_FOUND   = "licenses not found"
+)
+
+// Options used to find the licenses of BOM documents and apply license policies to them
+type LicenseOptions struct {
+	LicensePolicies *schema.LicensePolicyConfig // required
+	WhereFilters    []common.WhereFilter        // optional
+}
+
+// Returned if a license (choice) has no id, name or expression (i.e., if hashed without schema validation)
+type LicenseDataError struct {
+	BOMRef       string
+	ResourceName string
+}
+
+func (err *LicenseDataError) Error() string {
+	return fmt.Sprintf("%s: for entity: `%s` (%s)", MSG_LICENSE_INVALID_DATA, err.BOMRef, err.ResourceName)
+}
+
+// Returns the license information (sorted by license key) found in a (loaded) BOM document
+func ListLicenses(ctx context.Context, document *schema.BOM, options LicenseOptions) (licenses []schema.LicenseInfo, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = HashLicenses(ctx, document, options); err != nil {
+		return
+	}
+
+	licenseKeys := document.LicenseMap.KeySet()
+	SortLicenseKeys(licenseKeys)
+
+	licenses = []schema.LicenseInfo{}
+	for _, licenseKey := range licenseKeys {
+		values, _ := document.LicenseMap.Get(licenseKey)
+		for _, value := range values {
+			licenses = append(licenses, value.(schema.LicenseInfo))
+		}
+	}
+	return
+}
+
+func SortLicenseKeys(licenseKeys []interface{}) {
+	// Sort by license key (i.e., one of `id`, `name` or `expression`)
+	sort.Slice(licenseKeys, func(i, j int) bool {
+		return licenseKeys[i].(string) < licenseKeys[j].(string)
+	})
+}
+
+//------------------------------------
+// CDX License hashing functions
+//------------------------------------
+
+// Hash ALL licenses found in the SBOM document (i.e., into its LicenseMap)
+// Note: CDX spec. allows for licenses to be declared in the following places:
+// 1. (root).metadata.licenses[]
+// 2. (root).metadata.component.licenses[] + all "nested" components
+// 3. (root).components[](.license[]) (each component + all "nested" components)
+// 4. (root).services[](.license[]) (each service + all "nested" services)
+func HashLicenses(ctx context.Context, bom *schema.BOM, options LicenseOptions) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if err = checkDocument(ctx, bom); err != nil {
+		return
+	}
+	policyConfig := options.LicensePolicies
+	if policyConfig == nil {
+		return errors.New(MSG_CONFIG_LICENSE_POLICIES_MISSING)
+	}
+	whereFilters := options.WhereFilters
+
+	// NOTE: DEBUG: use this to debug license policy hashmaps have appropriate # of entries
+	//licensePolicyConfig.Debug()
+
+	// SPDX documents declare licenses in different locations (and forms)
+	if bom.FormatInfo.IsSpdx() {
+		return hashSpdxLicenses(bom, policyConfig, whereFilters)
+	}
+
+	// Fail any other (unknown) formats as "unsupported" (for "any" format)
+	if !bom.FormatInfo.IsCycloneDx() {
+		return newUnsupportedFormatError(bom, OPERATION_LICENSE)
+	}
+
+	// Before looking for license data, fully unmarshal the SBOM
+	// into named structures
+	if err = bom.UnmarshalCycloneDXBOM(); err != nil {
+		return
+	}
+
+	// 1. Hash all licenses in the SBOM metadata (i.e., (root).metadata.component)
+	// Note: this SHOULD represent a summary of all licenses that apply
+	// to the component being described in the SBOM
+	if err = hashMetadataLicenses(bom, policyConfig, schema.LC_LOC_METADATA, whereFilters); err != nil {
+		return
+	}
+
+	// 2. Hash all licenses in (root).metadata.component (+ "nested" components)
+	if err = hashMetadataComponentLicenses(bom, policyConfig, schema.LC_LOC_METADATA_COMPONENT, whereFilters); err != nil {
+		return
+	}
+
+	// 3. Hash all component licenses found in the (root).components[] (+ "nested" components)
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	pComponents := bom.GetCdxComponents()
+	if pComponents != nil && len(*pComponents) > 0 {
+		if err = hashComponentsLicenses(bom, policyConfig, *pComponents, schema.LC_LOC_COMPONENTS, whereFilters); err != nil {
+			return
+		}
+	}
+
+	// 4. Hash all service licenses found in the (root).services[] (array) (+ "nested" services)
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	pServices := bom.GetCdxServices()
+	if pServices != nil && len(*pServices) > 0 {
+		if err = hashServicesLicenses(bom, policyConfig, *pServices, schema.LC_LOC_SERVICES, whereFilters); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Hash all licenses found in an SPDX document's packages (i.e., both declared
+// and concluded licenses), files and snippets.
+// Note: SPDX license values are mapped to their CycloneDX license choice equivalents
+// (i.e., id, name or expression) so that license policies can be applied;
+// "NOASSERTION" and "NONE" values are hashed as LICENSE_NO_ASSERTION.
+func hashSpdxLicenses(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	// Before looking for license data, fully unmarshal the SBOM
+	// into named structures
+	if err = bom.UnmarshalSPDXDocument(); err != nil {
+		return
+	}
+
+	pDocument := bom.GetSpdxDocument()
+
+	// 1. Hash all (concluded and declared) licenses in (root).packages[]
+	for _, spdxPackage := range pDocument.GetPackages() {
+		cdxComponent := pDocument.ConvertPackageToCDXComponent(spdxPackage)
+		if err = hashSpdxLicense(bom, policyConfig, cdxComponent, spdxPackage.LicenseConcluded, schema.LC_LOC_SPDX_PACKAGES_CONCLUDED, whereFilters); err != nil {
+			return
+		}
+		if err = hashSpdxLicense(bom, policyConfig, cdxComponent, spdxPackage.LicenseDeclared, schema.LC_LOC_SPDX_PACKAGES_DECLARED, whereFilters); err != nil {
+			return
+		}
+	}
+
+	// 2. Hash all (concluded) licenses in (root).files[]
+	for _, spdxFile := range pDocument.GetFiles() {
+		cdxComponent := pDocument.ConvertFileToCDXComponent(spdxFile)
+		if err = hashSpdxLicense(bom, policyConfig, cdxComponent, spdxFile.LicenseConcluded, schema.LC_LOC_SPDX_FILES, whereFilters); err != nil {
+			return
+		}
+	}
+
+	// 3. Hash all (concluded) licenses in (root).snippets[]
+	for _, spdxSnippet := range pDocument.GetSnippets() {
+		bomRef := schema.CDXRefType(spdxSnippet.SPDXID)
+		cdxComponent := schema.CDXComponent{
+			Type:   schema.COMPONENT_TYPE_FILE,
+			Name:   spdxSnippet.Name,
+			BOMRef: &bomRef,
+		}
+		if cdxComponent.Name == "" {
+			cdxComponent.Name = spdxSnippet.SnippetFromFile
+		}
+		if err = hashSpdxLicense(bom, policyConfig, cdxComponent, spdxSnippet.LicenseConcluded, schema.LC_LOC_SPDX_SNIPPETS, whereFilters); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Hash a single SPDX license value for the (converted) SPDX element
+func hashSpdxLicense(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, cdxComponent schema.CDXComponent, licenseValue string, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+	var licenseInfo schema.LicenseInfo
+
+	licenseInfo.Component = cdxComponent
+	licenseInfo.BOMLocationValue = location
+	licenseInfo.ResourceName = cdxComponent.Name
+	if cdxComponent.BOMRef != nil {
+		licenseInfo.BOMRef = *cdxComponent.BOMRef
+	}
+
+	pLicenseChoice := bom.GetSpdxDocument().ConvertLicenseToCDXLicenseChoice(licenseValue)
+	if pLicenseChoice == nil {
+		_, err = bom.HashLicenseInfo(policyConfig, LICENSE_NO_ASSERTION, licenseInfo, whereFilters)
+		getLogger().Warningf("%s: %s (name:`%s`, version: `%s`, location: `%s`)",
+			"No license asserted for SPDX element. SPDXID",
+			licenseInfo.BOMRef,
+			licenseInfo.ResourceName,
+			cdxComponent.Version,
+			schema.GetLicenseChoiceLocationName(location))
+		return
+	}
+
+	licenseInfo.LicenseChoice = *pLicenseChoice
+	err = hashLicenseInfoByLicenseType(bom, policyConfig, licenseInfo, whereFilters)
+	return
+}
+
+// Hash the license found in the (root).metadata.licenses[] array
+func hashMetadataLicenses(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	pLicenses := bom.GetCdxMetadataLicenses()
+	if pLicenses == nil {
+		sbomError := fmt.Errorf("%s %s", MSG_LICENSES_NOT_FOUND,
+			formatLocation(bom, schema.GetLicenseChoiceLocationName(location)))
+		// Issue a warning as an SBOM without at least one, top-level license
+		// (in the metadata license summary) SHOULD be noted.
+		// Note: An actual error SHOULD ONLY be returned by
+		// the custom validation code.
+		getLogger().Warning(sbomError)
+		return
+	}
+
+	var licenseInfo schema.LicenseInfo
+	for _, pLicenseChoice := range *pLicenses {
+		getLogger().Tracef("hashing license: id: `%s`, name: `%s`",
+			pLicenseChoice.License.Id, pLicenseChoice.License.Name)
+
+		licenseInfo.LicenseChoice = pLicenseChoice
+		licenseInfo.BOMLocationValue = location
+		licenseInfo.ResourceName = LICENSE_LIST_NOT_APPLICABLE
+		licenseInfo.BOMRef = LICENSE_LIST_NOT_APPLICABLE
+		err = hashLicenseInfoByLicenseType(bom, policyConfig, licenseInfo, whereFilters)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Hash the license found in the (root).metadata.component object (and any "nested" components)
+func hashMetadataComponentLicenses(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	component := bom.GetCdxMetadataComponent()
+	if component == nil {
+		sbomError := fmt.Errorf("%s %s", MSG_LICENSES_NOT_FOUND,
+			formatLocation(bom, schema.GetLicenseChoiceLocationName(location)))
+		// Issue a warning as an SBOM without at least one
+		// top-level component license declared SHOULD be noted.
+		// Note: An actual error SHOULD ONLY be returned by
+		// the custom validation code.
+		getLogger().Warning(sbomError)
+		return
+	}
+
+	_, err = hashComponentLicense(bom, policyConfig, *component, location, whereFilters)
+
+	return
+}
+
+// Hash all licenses found in an array of CDX Components
+// TODO use array of pointer to []CDXComponent
+func hashComponentsLicenses(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, components []schema.CDXComponent, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	for _, cdxComponent := range components {
+		_, err = hashComponentLicense(bom, policyConfig, cdxComponent, location, whereFilters)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Hash all licenses found in an array of CDX Services
+// TODO use array of pointer to []CDXService
+func hashServicesLicenses(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, services []schema.CDXService, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	for _, cdxServices := range services {
+		err = hashServiceLicense(bom, policyConfig, cdxServices, location, whereFilters)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Hash a CDX Component's licenses and recursively those of any "nested" components
+func hashComponentLicense(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, cdxComponent schema.CDXComponent, location int, whereFilters []common.WhereFilter) (li *schema.LicenseInfo, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+	var licenseInfo schema.LicenseInfo
+
+	pLicenses := cdxComponent.Licenses
+	if pLicenses != nil && len(*pLicenses) > 0 {
+		for _, licenseChoice := range *pLicenses {
+			getLogger().Debugf("licenseChoice: %s", getLogger().FormatStruct(licenseChoice))
+			getLogger().Tracef("hashing license for component=`%s`", cdxComponent.Name)
+
+			licenseInfo.LicenseChoice = licenseChoice
+			licenseInfo.Component = cdxComponent
+			licenseInfo.BOMLocationValue = location
+			licenseInfo.ResourceName = cdxComponent.Name
+			if cdxComponent.BOMRef != nil {
+				licenseInfo.BOMRef = *cdxComponent.BOMRef
+			}
+			err = hashLicenseInfoByLicenseType(bom, policyConfig, licenseInfo, whereFilters)
+
+			if err != nil {
+				// Show intent to not check for error returns as there no intent to recover
+				_ = getLogger().Errorf("Unable to hash empty license: %v", licenseInfo)
+				return
+			}
+		}
+	} else {
+		// Account for component with no license with an "UNDEFINED" entry
+		// hash any component w/o a license using special key name
+		licenseInfo.Component = cdxComponent
+		licenseInfo.BOMLocationValue = location
+		licenseInfo.ResourceName = cdxComponent.Name
+		if cdxComponent.BOMRef != nil {
+			licenseInfo.BOMRef = *cdxComponent.BOMRef
+		}
+		_, err = bom.HashLicenseInfo(policyConfig, LICENSE_NO_ASSERTION, licenseInfo, whereFilters)
+
+		getLogger().Warningf("%s: %s (name:`%s`, version: `%s`, package-url: `%s`)",
+			"No license found for component. bomRef",
+			licenseInfo.BOMRef,
+			licenseInfo.ResourceName,
+			cdxComponent.Version,
+			cdxComponent.Purl)
+		// No actual licenses to process
+		return
+	}
+
+	// Recursively hash licenses for all child components (i.e., hierarchical composition)
+	pComponents := cdxComponent.Components
+	if pComponents != nil && len(*pComponents) > 0 {
+		err = hashComponentsLicenses(bom, policyConfig, *pComponents, location, whereFilters)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Hash all licenses found in a CDX Service
+// TODO use pointer to CDXService
+func hashServiceLicense(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, cdxService schema.CDXService, location int, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	var licenseInfo schema.LicenseInfo
+
+	pLicenses := cdxService.Licenses
+	if pLicenses != nil && len(*pLicenses) > 0 {
+		for _, licenseChoice := range *pLicenses {
+			getLogger().Debugf("licenseChoice: %s", getLogger().FormatStruct(licenseChoice))
+			getLogger().Tracef("Hashing license for service=`%s`", cdxService.Name)
+			licenseInfo.LicenseChoice = licenseChoice
+			licenseInfo.Service = cdxService
+			licenseInfo.ResourceName = cdxService.Name
+			if cdxService.BOMRef != nil {
+				licenseInfo.BOMRef = *cdxService.BOMRef
+			}
+			licenseInfo.BOMLocationValue = location
+			err = hashLicenseInfoByLicenseType(bom, policyConfig, licenseInfo, whereFilters)
+
+			if err != nil {
+				return
+			}
+		}
+	} else {
+		// Account for service with no license with an "UNDEFINED" entry
+		// hash any service w/o a license using special key name
+		licenseInfo.Service = cdxService
+		licenseInfo.BOMLocationValue = location
+		licenseInfo.ResourceName = cdxService.Name
+		if cdxService.BOMRef != nil {
+			licenseInfo.BOMRef = *cdxService.BOMRef
+		}
+		_, err = bom.HashLicenseInfo(policyConfig, LICENSE_NO_ASSERTION, licenseInfo, whereFilters)
+
+		getLogger().Warningf("%s: %s (name: `%s`, version: `%s`)",
+			"No license found for service. bomRef",
+			cdxService.BOMRef,
+			cdxService.Name,
+			cdxService.Version)
+
+		// No actual licenses to process
+		return
+	}
+
+	// Recursively hash licenses for all child components (i.e., hierarchical composition)
+	pServices := cdxService.Services
+	if pServices != nil && len(*pServices) > 0 {
+		err = hashServicesLicenses(bom, policyConfig, *pServices, location, whereFilters)
+		if err != nil {
+			// Show intent to not check for error returns as there is no recovery
+			_ = getLogger().Errorf("Unable to hash empty license: %v", licenseInfo)
+			return
+		}
+	}
+
+	return
+}
+
+// Wrap the license data itself in a "licenseInfo" object which tracks:
+// 1. What type of information do we have about the license (i.e., SPDX ID, Name or expression)
+// 2. Where the license was found within the SBOM
+// 3. The entity name (e.g., service or component name) that declared the license
+// 4. The entity local BOM reference (i.e., "bomRef")
+func hashLicenseInfoByLicenseType(bom *schema.BOM, policyConfig *schema.LicensePolicyConfig, licenseInfo schema.LicenseInfo, whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	licenseChoice := licenseInfo.LicenseChoice
+	pLicense := licenseChoice.License
+
+	if pLicense != nil && pLicense.Id != "" {
+		licenseInfo.LicenseChoiceTypeValue = schema.LC_TYPE_ID
+		_, err = bom.HashLicenseInfo(policyConfig, pLicense.Id, licenseInfo, whereFilters)
+	} else if pLicense != nil && pLicense.Name != "" {
+		licenseInfo.LicenseChoiceTypeValue = schema.LC_TYPE_NAME
+		_, err = bom.HashLicenseInfo(policyConfig, pLicense.Name, licenseInfo, whereFilters)
+	} else if licenseChoice.Expression != "" {
+		licenseInfo.LicenseChoiceTypeValue = schema.LC_TYPE_EXPRESSION
+		_, err = bom.HashLicenseInfo(policyConfig, licenseChoice.Expression, licenseInfo, whereFilters)
+	} else {
+		// Note: This code path only executes if hashing is performed
+		// without schema validation (which would find this as an error)
+		// Note: licenseInfo.LicenseChoiceType = 0 // default, invalid
+		err = &LicenseDataError{
+			BOMRef:       string(licenseInfo.BOMRef),
+			ResourceName: licenseInfo.ResourceName,
+		}
+		return
+	}
+
+	if err != nil {
+		err = &LicenseDataError{
+			BOMRef:       string(licenseInfo.BOMRef),
+			ResourceName: licenseInfo.ResourceName,
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+)
+
+// Options used to load BOM documents and detect their format and schema
+type LoadOptions struct {
+	Formats       *schema.BOMFormatAndSchemaConfig // required
+	SchemaVariant string                           // optional (e.g., "strict")
+}
+
+// A reader that stops reading once its context is done
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (reader contextReader) Read(p []byte) (n int, err error) {
+	if err = reader.ctx.Err(); err != nil {
+		return
+	}
+	return reader.reader.Read(p)
+}
+
+// Load a BOM document from the reader and detect its format and schema;
+// the name is only used to identify the document (e.g., in errors)
+func Load(ctx context.Context, name string, reader io.Reader, options LoadOptions) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var data []byte
+	if data, err = io.ReadAll(contextReader{ctx: ctx, reader: reader}); err != nil {
+		return
+	}
+	return LoadData(ctx, name, data, options)
+}
+
+// Load a BOM document from (already read) data and detect its format and schema;
+// the name is only used to identify the document (e.g., in errors)
+func LoadData(ctx context.Context, name string, data []byte, options LoadOptions) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	document = schema.NewBOM(name)
+	if err = document.UnmarshalBOMDataAsJSONMap(data); err != nil {
+		return
+	}
+
+	err = DetectSchema(ctx, document, options)
+	return
+}
+
+// Load a BOM document from the named file and detect its format and schema
+func LoadFile(ctx context.Context, filename string, options LoadOptions) (document *schema.BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	var file *os.File
+	if file, err = os.Open(filename); err != nil {
+		return
+	}
+	defer file.Close()
+
+	getLogger().Infof("Attempting to load and unmarshal data from: `%s`...", filename)
+	return Load(ctx, filename, file, options)
+}
+
+// Detect the format and schema (with the optional variant) of a (loaded) BOM document
+func DetectSchema(ctx context.Context, document *schema.BOM, options LoadOptions) (err error) {
+	if err = checkDocument(ctx, document); err != nil {
+		return
+	}
+	if options.Formats == nil {
+		return errors.New(MSG_CONFIG_FORMATS_MISSING)
+	}
+
+	// Search the document keys/values for known BOM formats and schema in the config. file
+	getLogger().Infof("Determining file's BOM format and version...")
+	if err = options.Formats.FindFormatAndSchemaVariant(document, options.SchemaVariant); err != nil {
+		return
+	}
+
+	// Display detected format, version with (optional) schema variant (i.e., if requested on command line)
+	getLogger().Infof("Determined BOM format, version (variant): `%s`, `%s` %s",
+		document.FormatInfo.CanonicalName,
+		document.SchemaInfo.Version,
+		schema.FormatSchemaVariant(document.SchemaInfo.Variant))
+	getLogger().Infof("Matching BOM schema (for validation): %s", document.SchemaInfo.File)
+	return
+}
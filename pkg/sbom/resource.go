@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/jwangsadinata/go-multimap"
+)
+
+// Options used to find the resources (i.e., components and services) of BOM documents
+type ResourceOptions struct {
+	ResourceType string               // optional: one of schema.VALID_RESOURCE_TYPES (default: all)
+	WhereFilters []common.WhereFilter // optional
+}
+
+// Returns the resources (sorted by type then name) found in a (loaded) BOM document
+func ListResources(ctx context.Context, document *schema.BOM, options ResourceOptions) (resources []schema.CDXResourceInfo, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = HashResources(ctx, document, options); err != nil {
+		return
+	}
+
+	entries := document.ResourceMap.Entries()
+	SortResources(entries)
+
+	resources = []schema.CDXResourceInfo{}
+	for _, entry := range entries {
+		resources = append(resources, entry.Value.(schema.CDXResourceInfo))
+	}
+	return
+}
+
+func SortResources(entries []multimap.Entry) {
+	// Sort by Type then Name
+	sort.Slice(entries, func(i, j int) bool {
+		resource1 := (entries[i].Value).(schema.CDXResourceInfo)
+		resource2 := (entries[j].Value).(schema.CDXResourceInfo)
+		if resource1.Type != resource2.Type {
+			return resource1.Type < resource2.Type
+		}
+
+		return resource1.Name < resource2.Name
+	})
+}
+
+// Hash the resources of the requested type found in the BOM document (i.e., into its ResourceMap)
+func HashResources(ctx context.Context, document *schema.BOM, options ResourceOptions) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if err = checkDocument(ctx, document); err != nil {
+		return
+	}
+
+	resourceType := options.ResourceType
+	if !schema.IsValidResourceType(resourceType) {
+		return fmt.Errorf("invalid resource type: `%s`", resourceType)
+	}
+	whereFilters := options.WhereFilters
+
+	// SPDX packages (and files) are hashed as (abstract) CycloneDX components
+	if document.FormatInfo.IsSpdx() {
+		if err = document.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+		// Note: SPDX has no concept of "services"
+		if resourceType == schema.RESOURCE_TYPE_DEFAULT || resourceType == schema.RESOURCE_TYPE_COMPONENT {
+			err = document.HashSPDXPackageResources(whereFilters)
+		}
+		return
+	}
+
+	// Fail any other (unknown) formats as "unsupported" (for "any" format)
+	if !document.FormatInfo.IsCycloneDx() {
+		return newUnsupportedFormatError(document, OPERATION_RESOURCE)
+	}
+
+	// Before looking for license data, fully unmarshal the SBOM into named structures
+	if err = document.UnmarshalCycloneDXBOM(); err != nil {
+		return
+	}
+
+	// Add top-level SBOM component
+	if resourceType == schema.RESOURCE_TYPE_DEFAULT || resourceType == schema.RESOURCE_TYPE_COMPONENT {
+		err = document.HashComponentResources(whereFilters)
+		if err != nil {
+			return
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	if resourceType == schema.RESOURCE_TYPE_DEFAULT || resourceType == schema.RESOURCE_TYPE_SERVICE {
+		err = document.HashServiceResources(whereFilters)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
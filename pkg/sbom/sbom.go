@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/CycloneDX/sbom-utility/log"
+	"github.com/CycloneDX/sbom-utility/schema"
+)
+
+// Default (embedded) configuration files
+const (
+	DEFAULT_SCHEMA_CONFIG         = "config.json"
+	DEFAULT_LICENSE_POLICY_CONFIG = "license.json"
+)
+
+// Names of the operations (i.e., as used in "unsupported format" errors)
+const (
+	OPERATION_LICENSE       = "license"
+	OPERATION_RESOURCE      = "resource"
+	OPERATION_VULNERABILITY = "vulnerability"
+	FORMAT_ANY              = "<any>"
+)
+
+// Error messages
+const (
+	MSG_CONFIG_FORMATS_MISSING          = "missing BOM format and schema configuration"
+	MSG_CONFIG_LICENSE_POLICIES_MISSING = "missing license policy configuration"
+	MSG_DOCUMENT_MISSING                = "missing BOM document"
+)
+
+var (
+	ProjectLogger *log.MiniLogger
+)
+
+func getLogger() *log.MiniLogger {
+	if ProjectLogger == nil {
+		// TODO: use LDFLAGS to turn on "TRACE" (and require creation of a Logger)
+		// ONLY if needed to debug init() methods in the "cmd" package
+		ProjectLogger = log.NewLogger(log.ERROR)
+
+		// Attempt to read in `--args` values such as `--trace`
+		// Note: if they exist, quiet mode will be overridden
+		// Default to ERROR level and, turn on "Quiet mode" for tests
+		// This simplifies the test output to simply RUN/PASS|FAIL messages.
+		ProjectLogger.InitLogLevelAndModeFromFlags()
+	}
+	return ProjectLogger
+}
+
+// Load the BOM format and schema configuration from the named file;
+// if no file is named, the default (embedded) configuration is loaded
+func LoadFormatConfig(filename string) (config *schema.BOMFormatAndSchemaConfig, err error) {
+	config = new(schema.BOMFormatAndSchemaConfig)
+	if err = config.LoadSchemaConfigFile(filename, DEFAULT_SCHEMA_CONFIG); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Load (and hash) the license policy configuration from the named file;
+// if no file is named, the default (embedded) configuration is loaded
+func LoadLicensePolicyConfig(filename string) (config *schema.LicensePolicyConfig, err error) {
+	config = new(schema.LicensePolicyConfig)
+	if err = config.LoadHashPolicyConfigurationFile(filename, DEFAULT_LICENSE_POLICY_CONFIG); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Returns an error if the document is missing or the context is done (i.e., canceled or timed out)
+func checkDocument(ctx context.Context, document *schema.BOM) error {
+	if document == nil {
+		return errors.New(MSG_DOCUMENT_MISSING)
+	}
+	return ctx.Err()
+}
+
+// Returns an "unsupported format" error for formats (e.g., SPDX) the operation does not support
+func newUnsupportedFormatError(document *schema.BOM, operation string) error {
+	return schema.NewUnsupportedFormatForCommandError(
+		document.GetFilename(),
+		document.FormatInfo.CanonicalName,
+		operation, FORMAT_ANY)
+}
+
+// Describe a (license or resource) location within the BOM for log messages
+func formatLocation(document *schema.BOM, location string) string {
+	return fmt.Sprintf("(%s) (%s)", location, document.GetFilename())
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/schema"
+)
+
+// Test files are relative to the repository root (i.e., two levels above this package)
+const TEST_DATA_ROOT = "../.."
+
+const (
+	TEST_SPDX_2_2_MIN_REQUIRED                  = "test/spdx/spdx-2-2-min-required.json"
+	TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING = "test/spdx/spdx-2-2-missing-creationinfo.json"
+	TEST_CDX_1_3_RESOURCE_LIST                  = "test/cyclonedx/cdx-1-3-resource-list.json"
+	TEST_CDX_1_4_VULNERABILITY_VEX              = "test/vex/cdx-1-4-example1-vex.json"
+)
+
+var (
+	TestFormatConfig        *schema.BOMFormatAndSchemaConfig
+	TestLicensePolicyConfig *schema.LicensePolicyConfig
+)
+
+func TestMain(m *testing.M) {
+	var err error
+	// Load the default (embedded) configurations
+	if TestFormatConfig, err = LoadFormatConfig(""); err != nil {
+		getLogger().Error(err)
+		os.Exit(1)
+	}
+	if TestLicensePolicyConfig, err = LoadLicensePolicyConfig(""); err != nil {
+		getLogger().Error(err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// -------------------------------------------
+// test helper functions
+// -------------------------------------------
+
+func loadTestDocument(t *testing.T, filename string) *schema.BOM {
+	document, err := LoadFile(context.Background(), filepath.Join(TEST_DATA_ROOT, filename),
+		LoadOptions{Formats: TestFormatConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+// -------------------------------------------
+// load tests
+// -------------------------------------------
+
+func TestLoadReader(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(TEST_DATA_ROOT, TEST_SPDX_2_2_MIN_REQUIRED))
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := Load(context.Background(), "reader", bytes.NewReader(data), LoadOptions{Formats: TestFormatConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !document.FormatInfo.IsSpdx() || document.GetFilename() != "reader" {
+		t.Errorf("invalid document: format: `%s`, name: `%s`", document.FormatInfo.CanonicalName, document.GetFilename())
+	}
+}
+
+func TestLoadContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Load(ctx, "reader", bytes.NewReader([]byte("{}")), LoadOptions{Formats: TestFormatConfig})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error: `%v`, actual: `%v`", context.Canceled, err)
+	}
+}
+
+func TestLoadFormatsMissing(t *testing.T) {
+	_, err := LoadData(context.Background(), "data", []byte("{}"), LoadOptions{})
+	if err == nil || err.Error() != MSG_CONFIG_FORMATS_MISSING {
+		t.Errorf("expected error: `%s`, actual: `%v`", MSG_CONFIG_FORMATS_MISSING, err)
+	}
+}
+
+func TestLoadUnknownFormat(t *testing.T) {
+	_, err := LoadData(context.Background(), "data", []byte(`{"foo":"bar"}`), LoadOptions{Formats: TestFormatConfig})
+	if _, ok := err.(*schema.UnsupportedFormatError); !ok {
+		t.Errorf("expected error type: `%T`, actual: `%T` (%v)", &schema.UnsupportedFormatError{}, err, err)
+	}
+}
+
+// -------------------------------------------
+// validate tests
+// -------------------------------------------
+
+func TestValidateSpdxValid(t *testing.T) {
+	document := loadTestDocument(t, TEST_SPDX_2_2_MIN_REQUIRED)
+	result, err := Validate(context.Background(), document, ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || len(result.SchemaErrors) != 0 {
+		t.Errorf("expected valid document: %+v", result)
+	}
+	if result.Format != schema.SCHEMA_FORMAT_SPDX || result.Version != "SPDX-2.2" {
+		t.Errorf("invalid format (version): `%s` (`%s`)", result.Format, result.Version)
+	}
+}
+
+func TestValidateSpdxInvalid(t *testing.T) {
+	document := loadTestDocument(t, TEST_SPDX_2_2_INVALID_CREATION_INFO_MISSING)
+	cache := NewSchemaCache()
+	result, err := Validate(context.Background(), document, ValidateOptions{SchemaCache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.SchemaErrors) != 1 {
+		t.Errorf("expected (1) schema error: %+v", result)
+	}
+	if length := cache.Len(); length != 1 {
+		t.Errorf("invalid schema cache length: expected: 1, actual: %v", length)
+	}
+}
+
+// -------------------------------------------
+// license, resource and vulnerability tests
+// -------------------------------------------
+
+func TestListLicensesCdx(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_3_RESOURCE_LIST)
+	licenses, err := ListLicenses(context.Background(), document, LicenseOptions{LicensePolicies: TestLicensePolicyConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(licenses) == 0 {
+		t.Errorf("expected licenses")
+	}
+}
+
+func TestListLicensesPoliciesMissing(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_3_RESOURCE_LIST)
+	_, err := ListLicenses(context.Background(), document, LicenseOptions{})
+	if err == nil || err.Error() != MSG_CONFIG_LICENSE_POLICIES_MISSING {
+		t.Errorf("expected error: `%s`, actual: `%v`", MSG_CONFIG_LICENSE_POLICIES_MISSING, err)
+	}
+}
+
+func TestListResourcesService(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_3_RESOURCE_LIST)
+	resources, err := ListResources(context.Background(), document, ResourceOptions{ResourceType: schema.RESOURCE_TYPE_SERVICE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resources) == 0 {
+		t.Errorf("expected service resources")
+	}
+	for _, resource := range resources {
+		if resource.Type != schema.RESOURCE_TYPE_SERVICE {
+			t.Errorf("invalid resource type: `%s`", resource.Type)
+		}
+	}
+}
+
+func TestListResourcesInvalidType(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_3_RESOURCE_LIST)
+	if _, err := ListResources(context.Background(), document, ResourceOptions{ResourceType: "foo"}); err == nil {
+		t.Errorf("expected invalid resource type error")
+	}
+}
+
+func TestListVulnerabilitiesCdx(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_4_VULNERABILITY_VEX)
+	vulnerabilities, err := ListVulnerabilities(context.Background(), document, VulnerabilityOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vulnerabilities) == 0 {
+		t.Errorf("expected vulnerabilities")
+	}
+}
+
+func TestListVulnerabilitiesContextCanceled(t *testing.T) {
+	document := loadTestDocument(t, TEST_CDX_1_4_VULNERABILITY_VEX)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ListVulnerabilities(ctx, document, VulnerabilityOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error: `%v`, actual: `%v`", context.Canceled, err)
+	}
+}
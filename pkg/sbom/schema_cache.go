@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/CycloneDX/sbom-utility/resources"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	SCHEMA_CACHE_KEY_SEP           = "/"
+	SCHEMA_CACHE_KEY_PREFIX_FORCED = "forced:"
+	SCHEMA_LOAD_RETRY              = 3
+)
+
+// Compiled JSON schemas (keyed by format, version, variant and schema file) that are reused
+// when validating many documents (e.g., in batch, stream or server mode)
+// Note: a compiled gojsonschema.Schema is safe for concurrent use by validators
+var DefaultSchemaCache = NewSchemaCache()
+
+type SchemaCache struct {
+	mutex   sync.Mutex
+	entries map[string]*schemaCacheEntry
+}
+
+// Each entry is locked separately so that different schemas can be compiled
+// concurrently while a schema shared by many documents is only compiled once
+type schemaCacheEntry struct {
+	mutex  sync.Mutex
+	schema *gojsonschema.Schema
+}
+
+func NewSchemaCache() *SchemaCache {
+	return &SchemaCache{
+		entries: make(map[string]*schemaCacheEntry),
+	}
+}
+
+// Remove all compiled schemas from the cache
+func (cache *SchemaCache) Reset() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.entries = make(map[string]*schemaCacheEntry)
+}
+
+// Returns the number of compiled schemas in the cache
+func (cache *SchemaCache) Len() (length int) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for _, entry := range cache.entries {
+		entry.mutex.Lock()
+		if entry.schema != nil {
+			length++
+		}
+		entry.mutex.Unlock()
+	}
+	return
+}
+
+// Returns the compiled schema for the key; if not yet cached, the schema is compiled from
+// the loader returned by the load function
+// Note: schemas that fail to compile are not cached (i.e., they are retried by the next caller)
+func (cache *SchemaCache) Get(key string, load func() (gojsonschema.JSONLoader, error)) (compiled *gojsonschema.Schema, err error) {
+	cache.mutex.Lock()
+	entry, found := cache.entries[key]
+	if !found {
+		entry = new(schemaCacheEntry)
+		cache.entries[key] = entry
+	}
+	cache.mutex.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	if entry.schema != nil {
+		getLogger().Debugf("Schema `%s` found in cache.", key)
+		return entry.schema, nil
+	}
+
+	var schemaLoader gojsonschema.JSONLoader
+	if schemaLoader, err = load(); err != nil {
+		return
+	}
+
+	// WARNING: if schemas reference "remote" schemas which are loaded
+	// over http... then there is a chance of 503 errors (as the pkg. loads
+	// externally referenced schemas over network)... attempt fixed retry...
+	for i := 0; i < SCHEMA_LOAD_RETRY; i++ {
+		compiled, err = gojsonschema.NewSchema(schemaLoader)
+		if err == nil {
+			break
+		}
+		getLogger().Warningf("unable to load referenced schema over HTTP: \"%v\"\n retrying...", err)
+	}
+	if err != nil {
+		return
+	}
+
+	entry.schema = compiled
+	return
+}
+
+// Returns the cache key of the schema a document is validated against
+// i.e., its format, version, variant and (embedded) schema file or the forced schema file (if any)
+// Note: the schema file is included as schema configs (i.e., "--config-schema") may map
+// the same format, version and variant to different schema files
+func SchemaCacheKey(document *schema.BOM, forcedSchemaFile string) string {
+	if forcedSchemaFile != "" {
+		return SCHEMA_CACHE_KEY_PREFIX_FORCED + forcedSchemaFile
+	}
+	return document.FormatInfo.CanonicalName +
+		SCHEMA_CACHE_KEY_SEP + document.SchemaInfo.Version +
+		SCHEMA_CACHE_KEY_SEP + document.SchemaInfo.Variant +
+		SCHEMA_CACHE_KEY_SEP + document.SchemaInfo.File
+}
+
+// Returns the (cached) compiled schema a document is validated against; either the
+// (embedded) schema matching its detected format, version and variant or the forced schema file
+func (cache *SchemaCache) LoadSchema(document *schema.BOM, forcedSchemaFile string) (compiled *gojsonschema.Schema, schemaName string, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// If caller "forced" a specific schema file (version), load it instead of
+	// any SchemaInfo found in config.json
+	// TODO: support remote schema load (via URL) with a flag (default should always be local file for security)
+	schemaName = document.SchemaInfo.File
+	if forcedSchemaFile != "" {
+		schemaName = "file://" + forcedSchemaFile
+	}
+
+	var errRead error
+	compiled, err = cache.Get(SchemaCacheKey(document, forcedSchemaFile), func() (gojsonschema.JSONLoader, error) {
+		getLogger().Infof("Loading schema `%s`...", schemaName)
+		if forcedSchemaFile != "" {
+			getLogger().Infof("Validating document using forced schema (i.e., `--force %s`)", forcedSchemaFile)
+			return gojsonschema.NewReferenceLoader(schemaName), nil
+		}
+
+		// Load the matching JSON schema (format, version and variant) from embedded resources
+		// i.e., using the matching schema found in config.json (as SchemaInfo)
+		var bSchema []byte
+		if bSchema, errRead = resources.BOMSchemaFiles.ReadFile(schemaName); errRead != nil {
+			return nil, errRead
+		}
+		return gojsonschema.NewBytesLoader(bSchema), nil
+	})
+
+	if errRead != nil {
+		return nil, schemaName, errRead
+	}
+	if err != nil {
+		getLogger().Debugf("%v", err)
+		return nil, schemaName, fmt.Errorf("unable to load schema: `%s`", schemaName)
+	}
+	getLogger().Infof("Schema `%s` loaded.", schemaName)
+	return
+}
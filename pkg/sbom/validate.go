@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/CycloneDX/sbom-utility/log"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Options used to validate BOM documents against their (detected or forced) JSON schema
+type ValidateOptions struct {
+	ForcedSchemaFile string       // optional: validate against this schema file (and not the detected schema)
+	SchemaCache      *SchemaCache // optional: defaults to the DefaultSchemaCache
+}
+
+// The result of validating a BOM document against its JSON schema
+type ValidationResult struct {
+	Valid        bool
+	Format       string
+	Version      string
+	Variant      string
+	SchemaName   string
+	SchemaErrors []gojsonschema.ResultError
+}
+
+// Validate a (loaded) BOM document against its (detected or forced) JSON schema
+// Note: a document that is not valid is not an error; schema errors are returned in the result
+func Validate(ctx context.Context, document *schema.BOM, options ValidateOptions) (result *ValidationResult, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = checkDocument(ctx, document); err != nil {
+		return
+	}
+
+	cache := options.SchemaCache
+	if cache == nil {
+		cache = DefaultSchemaCache
+	}
+
+	// Create a loader for the BOM (JSON) document
+	var documentLoader gojsonschema.JSONLoader
+	if bDocument := document.GetRawBytes(); len(bDocument) > 0 {
+		bufferTemp := new(bytes.Buffer)
+		// Strip off newlines which the json Decoder dislikes at EOF (as well as extra spaces, etc.)
+		if err = json.Compact(bufferTemp, bDocument); err != nil {
+			return nil, fmt.Errorf("unable to load document: `%s`: %w", document.GetFilename(), err)
+		}
+		documentLoader = gojsonschema.NewBytesLoader(bufferTemp.Bytes())
+	} else {
+		// Documents without raw (JSON) data (e.g., built in memory) are validated using their JSON map
+		documentLoader = gojsonschema.NewGoLoader(document.JsonMap)
+	}
+
+	// Load the (cached) compiled schema that matches the document's format, version and variant
+	jsonBOMSchema, schemaName, err := cache.LoadSchema(document, options.ForcedSchemaFile)
+	if err != nil {
+		return
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	// Validate against the schema and save result determination
+	getLogger().Infof("Validating `%s`...", document.GetFilenameInterpolated())
+	schemaResult, err := jsonBOMSchema.Validate(documentLoader)
+	if err != nil {
+		// errors from the validation package/library itself mean we could NOT
+		// actually confirm the input documents validity
+		return
+	}
+
+	result = &ValidationResult{
+		Valid:        schemaResult.Valid(),
+		Format:       document.FormatInfo.CanonicalName,
+		Version:      document.SchemaInfo.Version,
+		Variant:      document.SchemaInfo.Variant,
+		SchemaName:   schemaName,
+		SchemaErrors: schemaResult.Errors(),
+	}
+	getLogger().WithFields(log.Fields{
+		"format":  result.Format,
+		"version": result.Version,
+		"valid":   result.Valid,
+	}).Infof("BOM valid against JSON schema: `%t`", result.Valid)
+	return
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"context"
+	"sort"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/schema"
+	"github.com/jwangsadinata/go-multimap"
+)
+
+// Options used to find the vulnerabilities of BOM (or VEX) documents
+type VulnerabilityOptions struct {
+	WhereFilters []common.WhereFilter // optional
+}
+
+// Returns the vulnerabilities (sorted by id then created date, descending) found in a (loaded) BOM document
+func ListVulnerabilities(ctx context.Context, document *schema.BOM, options VulnerabilityOptions) (vulnerabilities []schema.VulnerabilityInfo, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if err = HashVulnerabilities(ctx, document, options); err != nil {
+		return
+	}
+
+	entries := document.VulnerabilityMap.Entries()
+	SortVulnerabilities(entries)
+
+	vulnerabilities = []schema.VulnerabilityInfo{}
+	for _, entry := range entries {
+		vulnerabilities = append(vulnerabilities, entry.Value.(schema.VulnerabilityInfo))
+	}
+	return
+}
+
+func SortVulnerabilities(entries []multimap.Entry) {
+	// Sort by Id, Created date (descending)
+	sort.Slice(entries, func(i, j int) bool {
+		vuln1 := (entries[i].Value).(schema.VulnerabilityInfo)
+		vuln2 := (entries[j].Value).(schema.VulnerabilityInfo)
+		if vuln1.Id != vuln2.Id {
+			return vuln1.Id > vuln2.Id
+		}
+
+		return vuln1.Created > vuln2.Created
+	})
+}
+
+// Hash the vulnerabilities found in the BOM document (i.e., into its VulnerabilityMap)
+func HashVulnerabilities(ctx context.Context, document *schema.BOM, options VulnerabilityOptions) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if err = checkDocument(ctx, document); err != nil {
+		return
+	}
+	whereFilters := options.WhereFilters
+
+	// SPDX packages may reference vulnerabilities using "SECURITY" "advisory" external references
+	if document.FormatInfo.IsSpdx() {
+		if err = document.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+		err = document.HashSPDXVulnerabilityResources(whereFilters)
+		return
+	}
+
+	// Fail any other (unknown) formats as "unsupported" (for "any" format)
+	if !document.FormatInfo.IsCycloneDx() {
+		return newUnsupportedFormatError(document, OPERATION_VULNERABILITY)
+	}
+
+	// Before looking for license data, fully unmarshal the SBOM
+	// into named structures
+	if err = document.UnmarshalCycloneDXBOM(); err != nil {
+		return
+	}
+
+	// Hash all components found in the (root).components[] (+ "nested" components)
+	pVulnerabilities := document.GetCdxVulnerabilities()
+	if pVulnerabilities != nil && len(*pVulnerabilities) > 0 {
+		if err = document.HashVulnerabilities(*pVulnerabilities, whereFilters); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -41,6 +41,7 @@ type BOM struct {
 	FormatInfo       FormatSchema
 	SchemaInfo       FormatSchemaInstance
 	CdxBom           *CDXBom
+	SpdxDocument     *SPDXDocument
 	Statistics       *StatisticsInfo
 	ResourceMap      *slicemultimap.MultiMap
 	ComponentMap     *slicemultimap.MultiMap
@@ -127,6 +128,10 @@ func (bom *BOM) GetCdxBom() (pCdxBom *CDXBom) {
 	return bom.CdxBom
 }
 
+func (bom *BOM) GetSpdxDocument() (pSpdxDocument *SPDXDocument) {
+	return bom.SpdxDocument
+}
+
 func (bom *BOM) GetCdxMetadata() (pMetadata *CDXMetadata) {
 	if bom := bom.GetCdxBom(); bom != nil {
 		pMetadata = bom.Metadata
@@ -291,7 +296,25 @@ func (bom *BOM) UnmarshalBOMAsJSONMap() (err error) {
 	}
 
 	getLogger().Tracef("read data from: `%s`", bom.filename)
-	getLogger().Tracef("\n  >> rawBytes[:100]=[%s]", bom.rawBytes[:100])
+	return bom.unmarshalRawBytesAsJSONMap()
+}
+
+// Unmarshal BOM data that was already read (e.g., from a stream of documents)
+// Note: the BOM's filename is only used to identify the data (e.g., in messages)
+func (bom *BOM) UnmarshalBOMDataAsJSONMap(data []byte) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	bom.rawBytes = data
+	return bom.unmarshalRawBytesAsJSONMap()
+}
+
+func (bom *BOM) unmarshalRawBytesAsJSONMap() (err error) {
+	traceBytes := bom.rawBytes
+	if len(traceBytes) > 100 {
+		traceBytes = traceBytes[:100]
+	}
+	getLogger().Tracef("\n  >> rawBytes[:100]=[%s]", traceBytes)
 
 	// Attempt to unmarshal the prospective JSON document to a map
 	bom.JsonMap = make(map[string]interface{})
@@ -331,6 +354,22 @@ func (bom *BOM) UnmarshalCycloneDXBOM() (err error) {
 	return
 }
 
+func (bom *BOM) UnmarshalSPDXDocument() (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// Unmarshal as a JSON Map if not done already
+	if bom.JsonMap == nil {
+		if err = bom.UnmarshalBOMAsJSONMap(); err != nil {
+			return
+		}
+	}
+
+	// Use the JSON Map to unmarshal to SPDX-specific types
+	bom.SpdxDocument, err = UnMarshalSPDXDocument(bom.JsonMap)
+	return
+}
+
 // NOTE: This method uses JSON Marshal() (i.e, from the json/encoding package)
 // which, by default, encodes characters using Unicode for HTML transmission
 // (assuming its primary use is for HTML servers).
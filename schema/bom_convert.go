@@ -0,0 +1,1207 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// Target format versions produced by conversion
+const (
+	CONVERT_SPDX_VERSION        = "SPDX-2.3"
+	CONVERT_CDX_SPEC_VERSION    = "1.5"
+	CONVERT_CDX_BOM_FORMAT      = "CycloneDX"
+	CONVERT_SPDX_DATA_LICENSE   = "CC0-1.0"
+	CONVERT_SPDX_DOCUMENT_ID    = "SPDXRef-DOCUMENT"
+	CONVERT_SPDX_ID_PREFIX      = "SPDXRef-"
+	CONVERT_SPDX_NAMESPACE_BASE = "https://spdx.org/spdxdocs/"
+	CONVERT_SPDX_TIME_FORMAT    = "2006-01-02T15:04:05Z"
+	CONVERT_DEFAULT_NAME        = "sbom"
+)
+
+// Reasons why a field could not be represented (losslessly) in the target format
+const (
+	CONVERT_REASON_UNSUPPORTED  = "not supported by target format"
+	CONVERT_REASON_UNRESOLVED   = "unresolved reference"
+	CONVERT_REASON_VALUE        = "unsupported value"
+	CONVERT_REASON_MULTIPLE     = "multiple values not supported"
+	CONVERT_REASON_RELATIONSHIP = "relationship type not supported"
+)
+
+// Location used to report fields of the (root) document object
+const CONVERT_LOCATION_ROOT = "(root)"
+
+// SPDX relationship type suffix used to qualify dependencies (e.g., "DEV_DEPENDENCY_OF")
+const SPDX_RELATIONSHIP_SUFFIX_DEPENDENCY_OF = "_DEPENDENCY_OF"
+
+// A single field (or entity) that could not be represented losslessly in the target format
+type ConversionLossyEntry struct {
+	Location string `json:"location"`
+	Ref      string `json:"ref,omitempty"`
+	Field    string `json:"field"`
+	Reason   string `json:"reason"`
+}
+
+type ConversionReport struct {
+	SourceFormat  string                 `json:"sourceFormat"`
+	SourceVersion string                 `json:"sourceVersion"`
+	TargetFormat  string                 `json:"targetFormat"`
+	TargetVersion string                 `json:"targetVersion"`
+	Lossy         []ConversionLossyEntry `json:"lossy"`
+}
+
+func (report *ConversionReport) addLossy(location string, ref string, field string, reason string) {
+	report.Lossy = append(report.Lossy, ConversionLossyEntry{
+		Location: location,
+		Ref:      ref,
+		Field:    field,
+		Reason:   reason,
+	})
+}
+
+func (report *ConversionReport) IsLossless() bool {
+	return len(report.Lossy) == 0
+}
+
+// Returns the (sorted) JSON keys of the value that are not in the set of mapped keys
+// Note: keys with SPDX values that assert no information (e.g., "NOASSERTION") are ignored
+func unmappedKeys(value interface{}, mapped map[string]bool) (keys []string) {
+	var jsonMap map[string]interface{}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err = json.Unmarshal(bytes, &jsonMap); err != nil {
+		return
+	}
+	for key, keyValue := range jsonMap {
+		if mapped[key] || isNoAssertionValue(keyValue) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+func isNoAssertionValue(value interface{}) bool {
+	switch typed := value.(type) {
+	case string:
+		return IsSPDXLicenseNoAssertion(typed)
+	case []interface{}:
+		for _, entry := range typed {
+			if !isNoAssertionValue(entry) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// -------------------
+// CycloneDX to SPDX
+// -------------------
+
+// CycloneDX component keys that are mapped to SPDX package properties
+var mapConvertCdxComponentKeys = map[string]bool{
+	"type": true, "bom-ref": true, "supplier": true, "author": true, "name": true,
+	"version": true, "description": true, "hashes": true, "licenses": true,
+	"copyright": true, "cpe": true, "purl": true, "externalReferences": true,
+	"components": true, "swid": true,
+}
+
+// CycloneDX vulnerability keys that are mapped to SPDX "advisory" external references
+var mapConvertCdxVulnerabilityKeys = map[string]bool{
+	"bom-ref": true, "id": true, "source": true, "affects": true, "description": true,
+}
+
+// CycloneDX metadata keys that are mapped to SPDX document (creation info) properties
+var mapConvertCdxMetadataKeys = map[string]bool{
+	"timestamp": true, "tools": true, "authors": true, "component": true,
+	"manufacture": true, "supplier": true, "licenses": true,
+}
+
+// Maps CycloneDX component types to SPDX (v2.3) "primaryPackagePurpose" values
+var mapCDXTypeToSPDXPackagePurpose = map[string]string{
+	COMPONENT_TYPE_APPLICATION:      "APPLICATION",
+	COMPONENT_TYPE_FRAMEWORK:        "FRAMEWORK",
+	COMPONENT_TYPE_LIBRARY:          "LIBRARY",
+	COMPONENT_TYPE_CONTAINER:        "CONTAINER",
+	COMPONENT_TYPE_OPERATING_SYSTEM: "OPERATING-SYSTEM",
+	COMPONENT_TYPE_DEVICE:           "DEVICE",
+	COMPONENT_TYPE_FIRMWARE:         "FIRMWARE",
+	COMPONENT_TYPE_FILE:             "FILE",
+}
+
+// Known advisory databases used to derive an advisory URL from a vulnerability ID
+var mapVulnerabilityIdPrefixToAdvisoryUrl = map[string]string{
+	"CVE-":  "https://nvd.nist.gov/vuln/detail/",
+	"GHSA-": "https://github.com/advisories/",
+}
+
+var regexpInvalidSPDXIdChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+type cdxToSpdxConverter struct {
+	cdxBom          *CDXBom
+	document        *SPDXDocument
+	report          *ConversionReport
+	packages        []SPDXPackage
+	relationships   []SPDXRelationship
+	extracted       []SPDXExtractedLicensingInfo
+	mapBomRefToId   map[string]string
+	mapIdToIndex    map[string]int
+	mapExtractedIds map[string]string
+	usedIds         map[string]bool
+}
+
+// Converts a (fully unmarshalled) CycloneDX BOM to an SPDX v2.3 document and reports
+// any fields (or entities) that could not be represented losslessly
+func (bom *BOM) ConvertCycloneDXToSPDX() (document *SPDXDocument, report *ConversionReport, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	cdxBom := bom.GetCdxBom()
+	if cdxBom == nil {
+		err = fmt.Errorf("invalid BOM: no CycloneDX data found (`%s`)", bom.GetFilenameInterpolated())
+		return
+	}
+
+	converter := cdxToSpdxConverter{
+		cdxBom:          cdxBom,
+		document:        new(SPDXDocument),
+		mapBomRefToId:   make(map[string]string),
+		mapIdToIndex:    make(map[string]int),
+		mapExtractedIds: make(map[string]string),
+		usedIds:         map[string]bool{CONVERT_SPDX_DOCUMENT_ID: true},
+		report: &ConversionReport{
+			SourceFormat:  bom.FormatInfo.CanonicalName,
+			SourceVersion: cdxBom.SpecVersion,
+			TargetFormat:  SCHEMA_FORMAT_SPDX,
+			TargetVersion: CONVERT_SPDX_VERSION,
+		},
+	}
+
+	if err = converter.convert(); err != nil {
+		return
+	}
+	document, report = converter.document, converter.report
+	return
+}
+
+func (converter *cdxToSpdxConverter) convert() (err error) {
+	cdxBom := converter.cdxBom
+	document := converter.document
+	report := converter.report
+
+	document.SPDXID = CONVERT_SPDX_DOCUMENT_ID
+	document.SpdxVersion = CONVERT_SPDX_VERSION
+	document.DataLicense = CONVERT_SPDX_DATA_LICENSE
+
+	// Packages: (root) metadata.component followed by all components (+ nested)
+	var describedIds []string
+	pMetadata := cdxBom.Metadata
+	if pMetadata != nil && pMetadata.Component != nil {
+		describedIds = append(describedIds, converter.convertComponent(*pMetadata.Component, "metadata.component", ""))
+	}
+	if cdxBom.Components != nil {
+		for i, component := range *cdxBom.Components {
+			id := converter.convertComponent(component, fmt.Sprintf("components[%v]", i), "")
+			// Without a root component, the document describes all top-level components
+			if pMetadata == nil || pMetadata.Component == nil {
+				describedIds = append(describedIds, id)
+			}
+		}
+	}
+	// Note: DESCRIBES relationships are listed first (in document order)
+	var describes []SPDXRelationship
+	for _, id := range describedIds {
+		describes = append(describes, SPDXRelationship{
+			SpdxElementId:      CONVERT_SPDX_DOCUMENT_ID,
+			RelationshipType:   SPDX_RELATIONSHIP_DESCRIBES,
+			RelatedSpdxElement: id,
+		})
+	}
+	converter.relationships = append(describes, converter.relationships...)
+
+	if err = converter.convertMetadata(); err != nil {
+		return
+	}
+	converter.convertDependencies()
+	converter.convertVulnerabilities()
+
+	// Report top-level entities that have no SPDX equivalent
+	if cdxBom.Services != nil {
+		for i, service := range *cdxBom.Services {
+			var ref string
+			if service.BOMRef != nil {
+				ref = service.BOMRef.String()
+			}
+			report.addLossy(fmt.Sprintf("services[%v]", i), ref, "services", CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+	if cdxBom.ExternalReferences != nil && len(*cdxBom.ExternalReferences) > 0 {
+		report.addLossy("externalReferences", "", "externalReferences", CONVERT_REASON_UNSUPPORTED)
+	}
+	if cdxBom.Compositions != nil && len(*cdxBom.Compositions) > 0 {
+		report.addLossy("compositions", "", "compositions", CONVERT_REASON_UNSUPPORTED)
+	}
+	if cdxBom.Annotations != nil && len(*cdxBom.Annotations) > 0 {
+		report.addLossy("annotations", "", "annotations", CONVERT_REASON_UNSUPPORTED)
+	}
+	if cdxBom.Formulation != nil && len(*cdxBom.Formulation) > 0 {
+		report.addLossy("formulation", "", "formulation", CONVERT_REASON_UNSUPPORTED)
+	}
+	if cdxBom.Properties != nil && len(*cdxBom.Properties) > 0 {
+		report.addLossy("properties", "", "properties", CONVERT_REASON_UNSUPPORTED)
+	}
+	if cdxBom.Signature != nil {
+		report.addLossy("signature", "", "signature", CONVERT_REASON_UNSUPPORTED)
+	}
+
+	if len(converter.packages) > 0 {
+		document.Packages = &converter.packages
+	}
+	if len(converter.relationships) > 0 {
+		document.Relationships = &converter.relationships
+	}
+	if len(converter.extracted) > 0 {
+		document.HasExtractedLicensingInfos = &converter.extracted
+	}
+	return
+}
+
+func (converter *cdxToSpdxConverter) convertMetadata() (err error) {
+	cdxBom := converter.cdxBom
+	document := converter.document
+	report := converter.report
+	creationInfo := new(SPDXCreationInfo)
+	document.CreationInfo = creationInfo
+
+	// Document name (required): derive from the root component (if any)
+	name := CONVERT_DEFAULT_NAME
+	pMetadata := cdxBom.Metadata
+	if pMetadata != nil && pMetadata.Component != nil && pMetadata.Component.Name != "" {
+		name = pMetadata.Component.Name
+		if pMetadata.Component.Version != "" {
+			name = fmt.Sprintf("%s-%s", name, pMetadata.Component.Version)
+		}
+	}
+	document.Name = name
+
+	// Document namespace (required): reuse the BOM's serial number UUID (if any)
+	uuid := strings.TrimPrefix(cdxBom.SerialNumber, utils.URN_UUID_PREFIX)
+	if uuid == "" {
+		var urn string
+		if urn, err = utils.GenerateURNUUID(); err != nil {
+			return
+		}
+		uuid = strings.TrimPrefix(urn, utils.URN_UUID_PREFIX)
+	}
+	document.DocumentNamespace = fmt.Sprintf("%s%s-%s", CONVERT_SPDX_NAMESPACE_BASE,
+		regexpInvalidSPDXIdChars.ReplaceAllString(name, "-"), uuid)
+
+	// Creation info: timestamp (required) normalized to SPDX (UTC) format
+	created := time.Now().UTC()
+	if pMetadata != nil && pMetadata.Timestamp != "" {
+		if parsed, errParse := time.Parse(time.RFC3339, pMetadata.Timestamp); errParse == nil {
+			created = parsed.UTC()
+		} else {
+			report.addLossy("metadata.timestamp", "", "timestamp", CONVERT_REASON_VALUE)
+		}
+	}
+	creationInfo.Created = created.Format(CONVERT_SPDX_TIME_FORMAT)
+
+	if pMetadata != nil {
+		for _, tool := range getCdxToolNameVersions(pMetadata.Tools) {
+			creationInfo.Creators = append(creationInfo.Creators, SPDX_CREATOR_PREFIX_TOOL+tool)
+		}
+		if pMetadata.Authors != nil {
+			for _, author := range *pMetadata.Authors {
+				creationInfo.Creators = append(creationInfo.Creators,
+					SPDX_CREATOR_PREFIX_PERSON+formatSPDXActorName(author.Name, author.Email))
+			}
+		}
+		for _, entity := range []*CDXOrganizationalEntity{pMetadata.Manufacturer, pMetadata.Supplier} {
+			if entity != nil && entity.Name != "" {
+				creationInfo.Creators = append(creationInfo.Creators,
+					SPDX_CREATOR_PREFIX_ORGANIZATION+formatSPDXActorName(entity.Name, ""))
+			}
+		}
+		// Note: the SPDX data license MUST be "CC0-1.0"
+		if pMetadata.Licenses != nil {
+			for _, licenseChoice := range *pMetadata.Licenses {
+				if licenseChoice.License == nil || licenseChoice.License.Id != CONVERT_SPDX_DATA_LICENSE {
+					report.addLossy("metadata.licenses", "", "licenses", CONVERT_REASON_VALUE)
+					break
+				}
+			}
+		}
+		for _, key := range unmappedKeys(pMetadata, mapConvertCdxMetadataKeys) {
+			report.addLossy("metadata", "", key, CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+
+	// Assure the conversion tool itself is credited as a creator (at least one is required)
+	creationInfo.Creators = append(creationInfo.Creators,
+		SPDX_CREATOR_PREFIX_TOOL+formatToolNameVersion(utils.GlobalFlags.Project, utils.GlobalFlags.Version))
+	return
+}
+
+// Returns the unique SPDX ID (for a package) derived from a CycloneDX bom-ref (or name)
+func (converter *cdxToSpdxConverter) newSPDXId(value string) (id string) {
+	value = strings.Trim(regexpInvalidSPDXIdChars.ReplaceAllString(value, "-"), "-")
+	if value == "" {
+		value = "Package"
+	}
+	id = CONVERT_SPDX_ID_PREFIX + value
+	for i := 1; converter.usedIds[id]; i++ {
+		id = fmt.Sprintf("%s%s-%v", CONVERT_SPDX_ID_PREFIX, value, i)
+	}
+	converter.usedIds[id] = true
+	return
+}
+
+// Converts a CycloneDX component (+ nested components) to SPDX packages; returns the package SPDX ID
+func (converter *cdxToSpdxConverter) convertComponent(component CDXComponent, location string, parentId string) (id string) {
+	report := converter.report
+	var bomRef string
+	if component.BOMRef != nil {
+		bomRef = component.BOMRef.String()
+	}
+
+	if bomRef != "" {
+		id = converter.newSPDXId(bomRef)
+		converter.mapBomRefToId[bomRef] = id
+	} else {
+		id = converter.newSPDXId(fmt.Sprintf("Package-%s-%s", component.Name, component.Version))
+	}
+
+	var spdxPackage SPDXPackage
+	spdxPackage.SPDXID = id
+	spdxPackage.Name = component.Name
+	spdxPackage.VersionInfo = component.Version
+	spdxPackage.Description = component.Description
+	spdxPackage.DownloadLocation = SPDX_NOASSERTION
+	filesAnalyzed := false
+	spdxPackage.FilesAnalyzed = &filesAnalyzed
+
+	if purpose, ok := mapCDXTypeToSPDXPackagePurpose[component.Type]; ok {
+		spdxPackage.PrimaryPackagePurpose = purpose
+	} else if component.Type != "" {
+		spdxPackage.PrimaryPackagePurpose = "OTHER"
+		report.addLossy(location, bomRef, "type", CONVERT_REASON_VALUE)
+	}
+
+	if component.Supplier != nil && component.Supplier.Name != "" {
+		var email string
+		if component.Supplier.Contact != nil && len(*component.Supplier.Contact) > 0 {
+			email = (*component.Supplier.Contact)[0].Email
+		}
+		spdxPackage.Supplier = SPDX_CREATOR_PREFIX_ORGANIZATION + formatSPDXActorName(component.Supplier.Name, email)
+	}
+	if component.Author != "" {
+		spdxPackage.Originator = SPDX_CREATOR_PREFIX_PERSON + formatSPDXActorName(component.Author, "")
+	}
+
+	spdxPackage.CopyrightText = SPDX_NOASSERTION
+	if component.Copyright != "" {
+		spdxPackage.CopyrightText = component.Copyright
+	}
+
+	// Hashes
+	if component.Hashes != nil && len(*component.Hashes) > 0 {
+		var checksums []SPDXChecksum
+		for _, hash := range *component.Hashes {
+			algorithm, ok := mapCDXHashAlgorithmToSPDX[hash.Alg]
+			if !ok {
+				report.addLossy(location, bomRef, "hashes", CONVERT_REASON_VALUE)
+				continue
+			}
+			checksums = append(checksums, SPDXChecksum{Algorithm: algorithm, ChecksumValue: hash.Content})
+		}
+		if len(checksums) > 0 {
+			spdxPackage.Checksums = &checksums
+		}
+	}
+
+	// Licenses (CycloneDX does not distinguish "declared" from "concluded")
+	spdxPackage.LicenseConcluded = SPDX_NOASSERTION
+	spdxPackage.LicenseDeclared = converter.convertLicenses(component.Licenses, location, bomRef)
+
+	// External references: identifiers (i.e., purl, cpe, swid) and external references
+	var externalRefs []SPDXExternalRef
+	if component.Purl != "" {
+		externalRefs = append(externalRefs, SPDXExternalRef{
+			ReferenceCategory: SPDX_REF_CATEGORY_PACKAGE_MANAGER,
+			ReferenceType:     SPDX_REF_TYPE_PURL,
+			ReferenceLocator:  component.Purl,
+		})
+	}
+	if component.Cpe != "" {
+		cpeType := SPDX_REF_TYPE_CPE23
+		if strings.HasPrefix(component.Cpe, "cpe:/") {
+			cpeType = SPDX_REF_TYPE_CPE22
+		}
+		externalRefs = append(externalRefs, SPDXExternalRef{
+			ReferenceCategory: SPDX_REF_CATEGORY_SECURITY,
+			ReferenceType:     cpeType,
+			ReferenceLocator:  component.Cpe,
+		})
+	}
+	if component.Swid != nil && component.Swid.TagId != "" {
+		externalRefs = append(externalRefs, SPDXExternalRef{
+			ReferenceCategory: SPDX_REF_CATEGORY_SECURITY,
+			ReferenceType:     SPDX_REF_TYPE_SWID,
+			ReferenceLocator:  "swid:" + component.Swid.TagId,
+		})
+	}
+	if component.ExternalReferences != nil {
+		for _, reference := range *component.ExternalReferences {
+			switch {
+			case reference.Type == CDX_EXTERNAL_REF_TYPE_WEBSITE && spdxPackage.Homepage == "":
+				spdxPackage.Homepage = reference.Url
+			case reference.Type == CDX_EXTERNAL_REF_TYPE_DISTRIBUTION && spdxPackage.DownloadLocation == SPDX_NOASSERTION:
+				spdxPackage.DownloadLocation = reference.Url
+			case reference.Type == CDX_EXTERNAL_REF_TYPE_ADVISORIES:
+				externalRefs = append(externalRefs, SPDXExternalRef{
+					ReferenceCategory: SPDX_REF_CATEGORY_SECURITY,
+					ReferenceType:     SPDX_REF_TYPE_ADVISORY,
+					ReferenceLocator:  reference.Url,
+					Comment:           reference.Comment,
+				})
+			default:
+				externalRefs = append(externalRefs, SPDXExternalRef{
+					ReferenceCategory: SPDX_REF_CATEGORY_OTHER,
+					ReferenceType:     reference.Type,
+					ReferenceLocator:  reference.Url,
+					Comment:           reference.Comment,
+				})
+			}
+			if reference.Hashes != nil && len(*reference.Hashes) > 0 {
+				report.addLossy(location, bomRef, "externalReferences.hashes", CONVERT_REASON_UNSUPPORTED)
+			}
+		}
+	}
+	if len(externalRefs) > 0 {
+		spdxPackage.ExternalRefs = &externalRefs
+	}
+
+	for _, key := range unmappedKeys(component, mapConvertCdxComponentKeys) {
+		report.addLossy(location, bomRef, key, CONVERT_REASON_UNSUPPORTED)
+	}
+
+	converter.mapIdToIndex[id] = len(converter.packages)
+	converter.packages = append(converter.packages, spdxPackage)
+
+	// Nested components are "contained" by their parent component
+	if parentId != "" {
+		converter.relationships = append(converter.relationships, SPDXRelationship{
+			SpdxElementId:      parentId,
+			RelationshipType:   SPDX_RELATIONSHIP_CONTAINS,
+			RelatedSpdxElement: id,
+		})
+	}
+	if component.Components != nil {
+		for i, child := range *component.Components {
+			converter.convertComponent(child, fmt.Sprintf("%s.components[%v]", location, i), id)
+		}
+	}
+	return
+}
+
+// Converts CycloneDX license choices to a single SPDX license expression
+// Note: multiple licenses are combined conjunctively (i.e., using "AND")
+func (converter *cdxToSpdxConverter) convertLicenses(pLicenses *[]CDXLicenseChoice, location string, bomRef string) (expression string) {
+	if pLicenses == nil || len(*pLicenses) == 0 {
+		return SPDX_NOASSERTION
+	}
+
+	var terms []string
+	for _, licenseChoice := range *pLicenses {
+		var term string
+		if licenseChoice.License != nil && licenseChoice.License.Id != "" {
+			term = licenseChoice.License.Id
+		} else if licenseChoice.License != nil && licenseChoice.License.Name != "" {
+			term = converter.getExtractedLicenseId(*licenseChoice.License)
+		} else if licenseChoice.Expression != "" {
+			term = licenseChoice.Expression
+		}
+		if term == "" {
+			report := converter.report
+			report.addLossy(location, bomRef, "licenses", CONVERT_REASON_VALUE)
+			continue
+		}
+		terms = append(terms, term)
+	}
+
+	switch len(terms) {
+	case 0:
+		expression = SPDX_NOASSERTION
+	case 1:
+		expression = terms[0]
+	default:
+		for i, term := range terms {
+			if strings.Contains(term, " ") && !strings.HasPrefix(term, "(") {
+				terms[i] = fmt.Sprintf("(%s)", term)
+			}
+		}
+		expression = strings.Join(terms, " AND ")
+	}
+	return
+}
+
+// Returns the "LicenseRef-" for a named license; the name and text are declared
+// as (document) extracted licensing information
+func (converter *cdxToSpdxConverter) getExtractedLicenseId(license CDXLicense) (licenseId string) {
+	if licenseId, ok := converter.mapExtractedIds[license.Name]; ok {
+		return licenseId
+	}
+
+	licenseId = SPDX_LICENSE_REF + strings.Trim(regexpInvalidSPDXIdChars.ReplaceAllString(license.Name, "-"), "-")
+	extracted := SPDXExtractedLicensingInfo{
+		LicenseId:     licenseId,
+		Name:          license.Name,
+		ExtractedText: license.Name,
+	}
+	if license.Text != nil && license.Text.Content != "" {
+		extracted.ExtractedText = license.Text.Content
+		if license.Text.Encoding == "base64" {
+			if decoded, err := base64.StdEncoding.DecodeString(license.Text.Content); err == nil {
+				extracted.ExtractedText = string(decoded)
+			}
+		}
+	}
+	if license.Url != "" {
+		extracted.SeeAlsos = []string{license.Url}
+	}
+	converter.mapExtractedIds[license.Name] = licenseId
+	converter.extracted = append(converter.extracted, extracted)
+	return
+}
+
+func (converter *cdxToSpdxConverter) convertDependencies() {
+	report := converter.report
+	pDependencies := converter.cdxBom.Dependencies
+	if pDependencies == nil {
+		return
+	}
+	for i, dependency := range *pDependencies {
+		if dependency.Ref == nil || dependency.DependsOn == nil {
+			continue
+		}
+		location := fmt.Sprintf("dependencies[%v]", i)
+		ref := dependency.Ref.String()
+		id, ok := converter.mapBomRefToId[ref]
+		if !ok {
+			report.addLossy(location, ref, "ref", CONVERT_REASON_UNRESOLVED)
+			continue
+		}
+		for _, dependsOn := range *dependency.DependsOn {
+			dependsOnId, ok := converter.mapBomRefToId[dependsOn.String()]
+			if !ok {
+				report.addLossy(location, dependsOn.String(), "dependsOn", CONVERT_REASON_UNRESOLVED)
+				continue
+			}
+			converter.relationships = append(converter.relationships, SPDXRelationship{
+				SpdxElementId:      id,
+				RelationshipType:   SPDX_RELATIONSHIP_DEPENDS_ON,
+				RelatedSpdxElement: dependsOnId,
+			})
+		}
+	}
+}
+
+// Vulnerabilities are represented as "SECURITY" "advisory" external references
+// on the packages they affect; all other vulnerability data is lost
+func (converter *cdxToSpdxConverter) convertVulnerabilities() {
+	report := converter.report
+	pVulnerabilities := converter.cdxBom.Vulnerabilities
+	if pVulnerabilities == nil {
+		return
+	}
+	for i, vulnerability := range *pVulnerabilities {
+		location := fmt.Sprintf("vulnerabilities[%v]", i)
+		var locator string
+		if vulnerability.Source != nil && vulnerability.Source.Url != "" {
+			locator = vulnerability.Source.Url
+		} else {
+			for prefix, baseUrl := range mapVulnerabilityIdPrefixToAdvisoryUrl {
+				if strings.HasPrefix(vulnerability.Id, prefix) {
+					locator = baseUrl + vulnerability.Id
+				}
+			}
+		}
+		if locator == "" || vulnerability.Affects == nil || len(*vulnerability.Affects) == 0 {
+			report.addLossy(location, vulnerability.Id, "vulnerabilities", CONVERT_REASON_UNSUPPORTED)
+			continue
+		}
+		for _, affect := range *vulnerability.Affects {
+			if affect.Ref == nil {
+				continue
+			}
+			id, ok := converter.mapBomRefToId[affect.Ref.String()]
+			if !ok {
+				report.addLossy(location, affect.Ref.String(), "affects.ref", CONVERT_REASON_UNRESOLVED)
+				continue
+			}
+			spdxPackage := &converter.packages[converter.mapIdToIndex[id]]
+			var externalRefs []SPDXExternalRef
+			if spdxPackage.ExternalRefs != nil {
+				externalRefs = *spdxPackage.ExternalRefs
+			}
+			externalRefs = append(externalRefs, SPDXExternalRef{
+				ReferenceCategory: SPDX_REF_CATEGORY_SECURITY,
+				ReferenceType:     SPDX_REF_TYPE_ADVISORY,
+				ReferenceLocator:  locator,
+				Comment:           vulnerability.Description,
+			})
+			spdxPackage.ExternalRefs = &externalRefs
+			if affect.Versions != nil && len(*affect.Versions) > 0 {
+				report.addLossy(location, vulnerability.Id, "affects.versions", CONVERT_REASON_UNSUPPORTED)
+			}
+		}
+		for _, key := range unmappedKeys(&vulnerability, mapConvertCdxVulnerabilityKeys) {
+			report.addLossy(location, vulnerability.Id, key, CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+}
+
+// -------------------
+// SPDX to CycloneDX
+// -------------------
+
+// SPDX package keys that are mapped to CycloneDX component properties
+var mapConvertSpdxPackageKeys = map[string]bool{
+	"SPDXID": true, "name": true, "versionInfo": true, "supplier": true, "originator": true,
+	"downloadLocation": true, "filesAnalyzed": true, "checksums": true, "homepage": true,
+	"licenseConcluded": true, "licenseDeclared": true, "copyrightText": true, "description": true,
+	"externalRefs": true, "primaryPackagePurpose": true, "hasFiles": true,
+}
+
+// SPDX file keys that are mapped to CycloneDX (file) component properties
+var mapConvertSpdxFileKeys = map[string]bool{
+	"SPDXID": true, "fileName": true, "checksums": true, "licenseConcluded": true, "copyrightText": true,
+}
+
+// SPDX document keys that are mapped to CycloneDX BOM properties
+var mapConvertSpdxDocumentKeys = map[string]bool{
+	"SPDXID": true, "spdxVersion": true, "creationInfo": true, "name": true, "dataLicense": true,
+	"hasExtractedLicensingInfos": true, "documentNamespace": true, "documentDescribes": true,
+	"packages": true, "files": true, "snippets": true, "relationships": true,
+}
+
+// Valid CycloneDX (v1.5) external reference types
+const (
+	CDX_EXTERNAL_REF_TYPE_WEBSITE      = "website"
+	CDX_EXTERNAL_REF_TYPE_DISTRIBUTION = "distribution"
+	CDX_EXTERNAL_REF_TYPE_ADVISORIES   = "advisories"
+	CDX_EXTERNAL_REF_TYPE_OTHER        = "other"
+)
+
+var mapCDXExternalReferenceTypes = map[string]bool{
+	"vcs": true, "issue-tracker": true, "website": true, "advisories": true, "bom": true,
+	"mailing-list": true, "social": true, "chat": true, "documentation": true, "support": true,
+	"distribution": true, "distribution-intake": true, "license": true, "build-meta": true,
+	"build-system": true, "release-notes": true, "security-contact": true, "model-card": true,
+	"log": true, "configuration": true, "evidence": true, "formulation": true, "attestation": true,
+	"threat-model": true, "adversary-model": true, "risk-assessment": true,
+	"vulnerability-assertion": true, "exploitability-statement": true, "pentest-report": true,
+	"static-analysis-report": true, "dynamic-analysis-report": true, "runtime-analysis-report": true,
+	"component-analysis-report": true, "maturity-report": true, "certification-report": true,
+	"quality-metrics": true, "codified-infrastructure": true, "poam": true, "other": true,
+}
+
+// Maps CycloneDX hash algorithms to SPDX checksum algorithms (i.e., the reverse of the SPDX map)
+var mapCDXHashAlgorithmToSPDX = func() map[string]string {
+	reverse := make(map[string]string, len(mapSPDXChecksumAlgorithms))
+	for spdxAlgorithm, cdxAlgorithm := range mapSPDXChecksumAlgorithms {
+		reverse[cdxAlgorithm] = spdxAlgorithm
+	}
+	return reverse
+}()
+
+type spdxToCdxConverter struct {
+	document       *SPDXDocument
+	cdxBom         *CDXBom
+	report         *ConversionReport
+	mapIdToElement map[string]*CDXComponent
+	elementIds     []string
+	mapChildren    map[string][]string
+	mapParent      map[string]string
+	placed         map[string]bool
+}
+
+// Converts a (fully unmarshalled) SPDX v2.x document to a CycloneDX v1.5 BOM and reports
+// any fields (or entities) that could not be represented losslessly
+func (bom *BOM) ConvertSPDXToCycloneDX() (cdxBom *CDXBom, report *ConversionReport, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	document := bom.GetSpdxDocument()
+	if document == nil {
+		err = fmt.Errorf("invalid BOM: no SPDX data found (`%s`)", bom.GetFilenameInterpolated())
+		return
+	}
+
+	converter := spdxToCdxConverter{
+		document:       document,
+		cdxBom:         new(CDXBom),
+		mapIdToElement: make(map[string]*CDXComponent),
+		mapChildren:    make(map[string][]string),
+		mapParent:      make(map[string]string),
+		placed:         make(map[string]bool),
+		report: &ConversionReport{
+			SourceFormat:  bom.FormatInfo.CanonicalName,
+			SourceVersion: document.SpdxVersion,
+			TargetFormat:  SCHEMA_FORMAT_CYCLONEDX,
+			TargetVersion: CONVERT_CDX_SPEC_VERSION,
+		},
+	}
+
+	if err = converter.convert(); err != nil {
+		return
+	}
+	cdxBom, report = converter.cdxBom, converter.report
+	return
+}
+
+func (converter *spdxToCdxConverter) convert() (err error) {
+	document := converter.document
+	cdxBom := converter.cdxBom
+	report := converter.report
+
+	cdxBom.BOMFormat = CONVERT_CDX_BOM_FORMAT
+	cdxBom.SpecVersion = CONVERT_CDX_SPEC_VERSION
+	cdxBom.Version = 1
+	if cdxBom.SerialNumber, err = utils.GenerateURNUUID(); err != nil {
+		return
+	}
+
+	// Convert all SPDX elements (i.e., packages and files) to components
+	for i, spdxPackage := range document.GetPackages() {
+		location := fmt.Sprintf("packages[%v]", i)
+		component := document.ConvertPackageToCDXComponent(spdxPackage)
+		converter.convertPackageFields(spdxPackage, &component, location)
+		converter.addElement(spdxPackage.SPDXID, component)
+		for _, key := range unmappedKeys(spdxPackage, mapConvertSpdxPackageKeys) {
+			report.addLossy(location, spdxPackage.SPDXID, key, CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+	for i, spdxFile := range document.GetFiles() {
+		location := fmt.Sprintf("files[%v]", i)
+		component := document.ConvertFileToCDXComponent(spdxFile)
+		if _, lossy := ConvertSPDXChecksumsToCDXHashes(spdxFile.Checksums); len(lossy) > 0 {
+			report.addLossy(location, spdxFile.SPDXID, "checksums", CONVERT_REASON_VALUE)
+		}
+		converter.addElement(spdxFile.SPDXID, component)
+		for _, key := range unmappedKeys(spdxFile, mapConvertSpdxFileKeys) {
+			report.addLossy(location, spdxFile.SPDXID, key, CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+	for i, snippet := range document.GetSnippets() {
+		report.addLossy(fmt.Sprintf("snippets[%v]", i), snippet.SPDXID, "snippets", CONVERT_REASON_UNSUPPORTED)
+	}
+
+	converter.convertMetadata()
+	converter.convertRelationships()
+	converter.convertVulnerabilities()
+
+	for _, key := range unmappedKeys(document, mapConvertSpdxDocumentKeys) {
+		report.addLossy(CONVERT_LOCATION_ROOT, document.SPDXID, key, CONVERT_REASON_UNSUPPORTED)
+	}
+	return
+}
+
+func (converter *spdxToCdxConverter) addElement(id string, component CDXComponent) {
+	if _, exists := converter.mapIdToElement[id]; exists {
+		converter.report.addLossy(CONVERT_LOCATION_ROOT, id, "SPDXID", CONVERT_REASON_MULTIPLE)
+		return
+	}
+	pComponent := new(CDXComponent)
+	*pComponent = component
+	converter.mapIdToElement[id] = pComponent
+	converter.elementIds = append(converter.elementIds, id)
+}
+
+// Maps SPDX package fields not already handled by the (shared) package-to-component conversion
+func (converter *spdxToCdxConverter) convertPackageFields(spdxPackage SPDXPackage, component *CDXComponent, location string) {
+	report := converter.report
+	id := spdxPackage.SPDXID
+
+	if originator := ConvertSPDXActorToCDXOrganizationalEntity(spdxPackage.Originator); originator != nil {
+		component.Author = originator.Name
+	}
+	if spdxPackage.Description != "" && spdxPackage.Summary != "" {
+		report.addLossy(location, id, "summary", CONVERT_REASON_MULTIPLE)
+	}
+	if _, lossy := ConvertSPDXChecksumsToCDXHashes(spdxPackage.Checksums); len(lossy) > 0 {
+		report.addLossy(location, id, "checksums", CONVERT_REASON_VALUE)
+	}
+	if _, ok := mapSPDXPackagePurposeToCDXType[spdxPackage.PrimaryPackagePurpose]; !ok && spdxPackage.PrimaryPackagePurpose != "" {
+		report.addLossy(location, id, "primaryPackagePurpose", CONVERT_REASON_VALUE)
+	}
+
+	// Note: the concluded license is preferred; a different declared license is lost
+	if !IsSPDXLicenseNoAssertion(spdxPackage.LicenseConcluded) &&
+		!IsSPDXLicenseNoAssertion(spdxPackage.LicenseDeclared) &&
+		spdxPackage.LicenseConcluded != spdxPackage.LicenseDeclared {
+		report.addLossy(location, id, "licenseDeclared", CONVERT_REASON_MULTIPLE)
+	}
+
+	var references []CDXExternalReference
+	if spdxPackage.Homepage != "" && !IsSPDXLicenseNoAssertion(spdxPackage.Homepage) {
+		references = append(references, CDXExternalReference{Type: CDX_EXTERNAL_REF_TYPE_WEBSITE, Url: spdxPackage.Homepage})
+	}
+	if spdxPackage.DownloadLocation != "" && !IsSPDXLicenseNoAssertion(spdxPackage.DownloadLocation) {
+		references = append(references, CDXExternalReference{Type: CDX_EXTERNAL_REF_TYPE_DISTRIBUTION, Url: spdxPackage.DownloadLocation})
+	}
+
+	if spdxPackage.ExternalRefs != nil {
+		var purls, cpes int
+		for _, ref := range *spdxPackage.ExternalRefs {
+			switch {
+			case ref.ReferenceType == SPDX_REF_TYPE_PURL:
+				if purls++; purls > 1 {
+					report.addLossy(location, id, "externalRefs.purl", CONVERT_REASON_MULTIPLE)
+				}
+			case ref.ReferenceType == SPDX_REF_TYPE_CPE23 || ref.ReferenceType == SPDX_REF_TYPE_CPE22:
+				if cpes++; cpes > 1 {
+					report.addLossy(location, id, "externalRefs."+ref.ReferenceType, CONVERT_REASON_MULTIPLE)
+				}
+			case ref.ReferenceType == SPDX_REF_TYPE_ADVISORY:
+				// Note: converted to vulnerabilities
+			case ref.ReferenceCategory == SPDX_REF_CATEGORY_OTHER && mapCDXExternalReferenceTypes[ref.ReferenceType]:
+				references = append(references, CDXExternalReference{Type: ref.ReferenceType, Url: ref.ReferenceLocator, Comment: ref.Comment})
+			default:
+				report.addLossy(location, id, "externalRefs."+ref.ReferenceType, CONVERT_REASON_UNSUPPORTED)
+			}
+		}
+	}
+	if len(references) > 0 {
+		component.ExternalReferences = &references
+	}
+}
+
+func (converter *spdxToCdxConverter) convertMetadata() {
+	document := converter.document
+	report := converter.report
+	metadata := new(CDXMetadata)
+	converter.cdxBom.Metadata = metadata
+
+	if document.DataLicense != "" {
+		metadata.Licenses = &[]CDXLicenseChoice{{License: &CDXLicense{Id: document.DataLicense}}}
+	}
+
+	if creationInfo := document.CreationInfo; creationInfo != nil {
+		metadata.Timestamp = creationInfo.Created
+		var tools []CDXComponent
+		var authors []CDXOrganizationalContact
+		for _, creator := range creationInfo.Creators {
+			entity := ConvertSPDXActorToCDXOrganizationalEntity(creator)
+			if entity == nil {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(creator, SPDX_CREATOR_PREFIX_TOOL):
+				name, version := parseToolNameVersion(entity.Name)
+				tools = append(tools, CDXComponent{Type: COMPONENT_TYPE_APPLICATION, Name: name, Version: version})
+			case strings.HasPrefix(creator, SPDX_CREATOR_PREFIX_PERSON):
+				contact := CDXOrganizationalContact{Name: entity.Name}
+				if entity.Contact != nil {
+					contact.Email = (*entity.Contact)[0].Email
+				}
+				authors = append(authors, contact)
+			case strings.HasPrefix(creator, SPDX_CREATOR_PREFIX_ORGANIZATION):
+				if metadata.Supplier == nil {
+					metadata.Supplier = entity
+				} else {
+					report.addLossy("creationInfo.creators", creator, "creators", CONVERT_REASON_MULTIPLE)
+				}
+			}
+		}
+		if len(tools) > 0 {
+			metadata.Tools = CDXCreationTools{Components: &tools}
+		}
+		if len(authors) > 0 {
+			metadata.Authors = &authors
+		}
+		if creationInfo.Comment != "" {
+			report.addLossy("creationInfo", "", "comment", CONVERT_REASON_UNSUPPORTED)
+		}
+		if creationInfo.LicenseListVersion != "" {
+			report.addLossy("creationInfo", "", "licenseListVersion", CONVERT_REASON_UNSUPPORTED)
+		}
+	}
+}
+
+// Converts SPDX relationships to CycloneDX (metadata) root component, dependencies and
+// (nested) component containment
+func (converter *spdxToCdxConverter) convertRelationships() {
+	document := converter.document
+	report := converter.report
+
+	type dependencyEntry struct {
+		ref       string
+		dependsOn []string
+	}
+	var dependencies []*dependencyEntry
+	mapDependencies := make(map[string]*dependencyEntry)
+	addDependency := func(ref string, dependsOn string) {
+		entry, ok := mapDependencies[ref]
+		if !ok {
+			entry = &dependencyEntry{ref: ref}
+			mapDependencies[ref] = entry
+			dependencies = append(dependencies, entry)
+		}
+		for _, existing := range entry.dependsOn {
+			if existing == dependsOn {
+				return
+			}
+		}
+		entry.dependsOn = append(entry.dependsOn, dependsOn)
+	}
+	addChild := func(parent string, child string) {
+		if _, ok := converter.mapParent[child]; ok {
+			report.addLossy("relationships", child, SPDX_RELATIONSHIP_CONTAINS, CONVERT_REASON_MULTIPLE)
+			return
+		}
+		converter.mapParent[child] = parent
+		converter.mapChildren[parent] = append(converter.mapChildren[parent], child)
+	}
+
+	for i, relationship := range document.GetRelationships() {
+		location := fmt.Sprintf("relationships[%v]", i)
+		from, to := relationship.SpdxElementId, relationship.RelatedSpdxElement
+		relationshipType := relationship.RelationshipType
+
+		// Relationships about the document itself are handled as "described" elements
+		if relationshipType == SPDX_RELATIONSHIP_DESCRIBES || relationshipType == SPDX_RELATIONSHIP_DESCRIBED_BY {
+			continue
+		}
+		_, fromFound := converter.mapIdToElement[from]
+		_, toFound := converter.mapIdToElement[to]
+		if !fromFound || !toFound {
+			unresolved := from
+			if fromFound {
+				unresolved = to
+			}
+			report.addLossy(location, unresolved, relationshipType, CONVERT_REASON_UNRESOLVED)
+			continue
+		}
+
+		switch {
+		case relationshipType == SPDX_RELATIONSHIP_DEPENDS_ON:
+			addDependency(from, to)
+		case relationshipType == SPDX_RELATIONSHIP_DEPENDENCY_OF:
+			addDependency(to, from)
+		case strings.HasSuffix(relationshipType, SPDX_RELATIONSHIP_SUFFIX_DEPENDENCY_OF):
+			// Note: the type of dependency (e.g., "DEV", "BUILD") is lost
+			addDependency(to, from)
+			report.addLossy(location, from, relationshipType, CONVERT_REASON_RELATIONSHIP)
+		case relationshipType == SPDX_RELATIONSHIP_CONTAINS:
+			addChild(from, to)
+		case relationshipType == SPDX_RELATIONSHIP_CONTAINED_BY:
+			addChild(to, from)
+		default:
+			report.addLossy(location, from, relationshipType, CONVERT_REASON_RELATIONSHIP)
+		}
+	}
+
+	// The (first) described element becomes the BOM's (metadata) root component
+	describedIds := document.GetDescribedElementIds()
+	var rootId string
+	for _, id := range describedIds {
+		if _, ok := converter.mapIdToElement[id]; !ok {
+			report.addLossy("relationships", id, SPDX_RELATIONSHIP_DESCRIBES, CONVERT_REASON_UNRESOLVED)
+			continue
+		}
+		if rootId == "" {
+			rootId = id
+		} else {
+			report.addLossy("relationships", id, SPDX_RELATIONSHIP_DESCRIBES, CONVERT_REASON_MULTIPLE)
+		}
+	}
+	if rootId != "" {
+		// Note: a root component cannot also be nested within another
+		if parent, ok := converter.mapParent[rootId]; ok {
+			report.addLossy("relationships", rootId, SPDX_RELATIONSHIP_CONTAINS, CONVERT_REASON_UNSUPPORTED)
+			delete(converter.mapParent, rootId)
+			converter.mapChildren[parent] = removeString(converter.mapChildren[parent], rootId)
+		}
+		root := converter.buildComponent(rootId)
+		converter.cdxBom.Metadata.Component = &root
+	}
+
+	// All elements not placed (nested) by containment become top-level components
+	var components []CDXComponent
+	for _, id := range converter.elementIds {
+		if _, contained := converter.mapParent[id]; contained || converter.placed[id] {
+			continue
+		}
+		components = append(components, converter.buildComponent(id))
+	}
+	// Note: containment cycles leave elements unplaced; add them as top-level components
+	for _, id := range converter.elementIds {
+		if !converter.placed[id] {
+			report.addLossy("relationships", id, SPDX_RELATIONSHIP_CONTAINS, CONVERT_REASON_VALUE)
+			components = append(components, converter.buildComponent(id))
+		}
+	}
+	if len(components) > 0 {
+		converter.cdxBom.Components = &components
+	}
+
+	if len(dependencies) > 0 {
+		var cdxDependencies []CDXDependency
+		for _, entry := range dependencies {
+			ref := CDXRefLinkType(entry.ref)
+			var dependsOn []CDXRefLinkType
+			for _, value := range entry.dependsOn {
+				dependsOn = append(dependsOn, CDXRefLinkType(value))
+			}
+			cdxDependencies = append(cdxDependencies, CDXDependency{Ref: &ref, DependsOn: &dependsOn})
+		}
+		converter.cdxBom.Dependencies = &cdxDependencies
+	}
+}
+
+// Returns a copy of the element's component with all contained elements nested within
+func (converter *spdxToCdxConverter) buildComponent(id string) (component CDXComponent) {
+	converter.placed[id] = true
+	component = *converter.mapIdToElement[id]
+	var children []CDXComponent
+	for _, childId := range converter.mapChildren[id] {
+		if converter.placed[childId] {
+			continue
+		}
+		children = append(children, converter.buildComponent(childId))
+	}
+	if len(children) > 0 {
+		component.Components = &children
+	}
+	return
+}
+
+// SPDX "advisory" references are converted to vulnerabilities (by ID) affecting the
+// referencing packages
+func (converter *spdxToCdxConverter) convertVulnerabilities() {
+	document := converter.document
+	var vulnerabilities []CDXVulnerability
+	mapIdToIndex := make(map[string]int)
+	for _, spdxPackage := range document.GetPackages() {
+		for _, vulnerability := range document.ConvertPackageAdvisoriesToCDXVulnerabilities(spdxPackage) {
+			if index, ok := mapIdToIndex[vulnerability.Id]; ok {
+				affects := append(*vulnerabilities[index].Affects, *vulnerability.Affects...)
+				vulnerabilities[index].Affects = &affects
+				continue
+			}
+			mapIdToIndex[vulnerability.Id] = len(vulnerabilities)
+			vulnerabilities = append(vulnerabilities, vulnerability)
+		}
+	}
+	if len(vulnerabilities) > 0 {
+		converter.cdxBom.Vulnerabilities = &vulnerabilities
+	}
+}
+
+// -------------------
+// Helpers
+// -------------------
+
+// Returns "name-version" values for all tools declared in either the (legacy)
+// tools array or the (v1.5) tools object form
+func getCdxToolNameVersions(tools interface{}) (values []string) {
+	if tools == nil {
+		return
+	}
+	bytes, err := json.Marshal(tools)
+	if err != nil {
+		return
+	}
+	if IsInterfaceASlice(tools) {
+		var legacyTools []CDXLegacyCreationTool
+		if err = json.Unmarshal(bytes, &legacyTools); err == nil {
+			for _, tool := range legacyTools {
+				name := tool.Name
+				if tool.Vendor != "" {
+					name = fmt.Sprintf("%s-%s", tool.Vendor, tool.Name)
+				}
+				values = append(values, formatToolNameVersion(name, tool.Version))
+			}
+		}
+		return
+	}
+	var creationTools CDXCreationTools
+	if err = json.Unmarshal(bytes, &creationTools); err == nil {
+		if creationTools.Components != nil {
+			for _, component := range *creationTools.Components {
+				values = append(values, formatToolNameVersion(component.Name, component.Version))
+			}
+		}
+		if creationTools.Services != nil {
+			for _, service := range *creationTools.Services {
+				values = append(values, formatToolNameVersion(service.Name, service.Version))
+			}
+		}
+	}
+	return
+}
+
+func formatToolNameVersion(name string, version string) string {
+	if version == "" {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, version)
+}
+
+// Splits an SPDX tool value (by convention "name-version") into its name and version
+func parseToolNameVersion(value string) (name string, version string) {
+	name = value
+	if index := strings.LastIndex(value, "-"); index > 0 && index < len(value)-1 {
+		if suffix := value[index+1:]; suffix[0] >= '0' && suffix[0] <= '9' || suffix[0] == 'v' && len(suffix) > 1 {
+			name, version = value[:index], suffix
+		}
+	}
+	return
+}
+
+func formatSPDXActorName(name string, email string) string {
+	if email == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, email)
+}
+
+func removeString(values []string, value string) (result []string) {
+	for _, entry := range values {
+		if entry != value {
+			result = append(result, entry)
+		}
+	}
+	return
+}
@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Dependency (graph) analysis values
+const (
+	DEPENDENCY_DEPTH_UNREACHABLE          = -1
+	DEPENDENCY_RELATIONSHIP_DIRECT        = "direct"
+	DEPENDENCY_RELATIONSHIP_TRANSITIVE    = "transitive"
+	DEPENDENCY_RESOURCE_TYPE_MISSING      = "missing"      // i.e., a dangling (undeclared) bom-ref
+	DEPENDENCY_REFERENCED_BY_DEPENDENCIES = "dependencies" // i.e., a dangling "ref" of a dependencies[] entry
+	DEPENDENCY_CYCLE_SEPARATOR            = " -> "
+)
+
+// Directed graph of bom-refs built from the CycloneDX "dependencies" array
+// i.e., an edge exists from each dependencies[].ref to each of its "dependsOn" values
+type DependencyGraph struct {
+	Root      string                     // bom-ref of "metadata.component" (if any)
+	Resources map[string]CDXResourceInfo // declared components and services (by bom-ref)
+	Edges     map[string][]string        // unique "dependsOn" bom-refs (in document order)
+}
+
+// A bom-ref (in either a "ref" or "dependsOn") that does not match any declared component or service
+type DependencyDanglingRef struct {
+	MissingRef   string `json:"missing-ref"`
+	ReferencedBy string `json:"referenced-by"`
+}
+
+type DependencyNodeInfo struct {
+	BOMRef     string   `json:"bom-ref"`
+	Type       string   `json:"type"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Depth      int      `json:"depth"` // i.e., distance from the root; -1 if unreachable
+	Direct     []string `json:"direct"`
+	Transitive []string `json:"transitive"`
+	Dangling   []string `json:"dangling"`
+	Orphan     bool     `json:"orphan"`
+	Cycle      bool     `json:"cycle"`
+}
+
+type DependencyStatistics struct {
+	Nodes        int     `json:"nodes"`
+	Edges        int     `json:"edges"`
+	Leaves       int     `json:"leaves"`
+	MaxDepth     int     `json:"max-depth"`
+	AverageDepth float64 `json:"average-depth"` // of nodes reachable from (excluding) the root
+	Orphans      int     `json:"orphans"`
+	DanglingRefs int     `json:"dangling-refs"`
+	Cycles       int     `json:"cycles"`
+}
+
+type DependencyAnalysis struct {
+	Root         string                  `json:"root"`
+	Statistics   DependencyStatistics    `json:"statistics"`
+	Nodes        []DependencyNodeInfo    `json:"nodes"`
+	DanglingRefs []DependencyDanglingRef `json:"dangling-refs"`
+	Orphans      []string                `json:"orphans"`
+	Cycles       [][]string              `json:"cycles"`
+}
+
+// A single (direct or transitive) dependency of a selected bom-ref
+type DependencyEntry struct {
+	BOMRef       string `json:"bom-ref"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Relationship string `json:"relationship"`
+	Distance     int    `json:"distance"`
+}
+
+// Builds the dependency graph of a CycloneDX BOM
+// NOTE: the BOM MUST be unmarshalled and its components and services hashed
+// (i.e., HashComponentResources() and HashServiceResources()) before calling.
+func (bom *BOM) NewDependencyGraph() (graph *DependencyGraph) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	graph = &DependencyGraph{
+		Resources: make(map[string]CDXResourceInfo),
+		Edges:     make(map[string][]string),
+	}
+
+	if pComponent := bom.GetCdxMetadataComponent(); pComponent != nil && pComponent.BOMRef != nil {
+		graph.Root = pComponent.BOMRef.String()
+	}
+
+	for _, key := range bom.ResourceMap.KeySet() {
+		ref, _ := key.(string)
+		if ref == "" {
+			continue
+		}
+		values, _ := bom.ResourceMap.Get(key)
+		if len(values) > 1 {
+			getLogger().Warningf("bom-ref `%s` declared by (%v) resources", ref, len(values))
+		}
+		if resourceInfo, ok := values[0].(CDXResourceInfo); ok {
+			graph.Resources[ref] = resourceInfo
+		}
+	}
+
+	if pDependencies := bom.GetCdxDependencies(); pDependencies != nil {
+		for _, dependency := range *pDependencies {
+			if dependency.Ref == nil {
+				getLogger().Warningf("dependency missing required value `ref`")
+				continue
+			}
+			ref := dependency.Ref.String()
+			// Note: entries (for the same ref) are merged
+			dependsOn := graph.Edges[ref]
+			if dependency.DependsOn != nil {
+				for _, target := range *dependency.DependsOn {
+					if !containsString(dependsOn, target.String()) {
+						dependsOn = append(dependsOn, target.String())
+					}
+				}
+			}
+			graph.Edges[ref] = dependsOn
+		}
+	}
+	return
+}
+
+func containsString(values []string, value string) bool {
+	for _, entry := range values {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (graph *DependencyGraph) IsDeclared(ref string) bool {
+	_, declared := graph.Resources[ref]
+	return declared
+}
+
+// Returns the declared bom-refs in sorted order
+func (graph *DependencyGraph) SortedRefs() (refs []string) {
+	for ref := range graph.Resources {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return
+}
+
+// Returns all bom-refs (i.e., declared and dangling) in sorted order
+func (graph *DependencyGraph) SortedAllRefs() (refs []string) {
+	refs = graph.SortedRefs()
+	dangling := make(map[string]bool)
+	for ref, dependsOn := range graph.Edges {
+		for _, value := range append([]string{ref}, dependsOn...) {
+			if !graph.IsDeclared(value) && !dangling[value] {
+				dangling[value] = true
+				refs = append(refs, value)
+			}
+		}
+	}
+	sort.Strings(refs)
+	return
+}
+
+// Returns the (shortest) distance to all bom-refs reachable from the start bom-ref
+// Note: the start bom-ref itself has a distance of 0
+func (graph *DependencyGraph) Distances(start string) (distances map[string]int) {
+	distances = map[string]int{start: 0}
+	queue := []string{start}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		for _, target := range graph.Edges[ref] {
+			if _, visited := distances[target]; !visited {
+				distances[target] = distances[ref] + 1
+				queue = append(queue, target)
+			}
+		}
+	}
+	return
+}
+
+// Returns the direct and transitive dependencies of a bom-ref
+func (graph *DependencyGraph) Dependencies(ref string) (entries []DependencyEntry) {
+	distances := graph.Distances(ref)
+	for target, distance := range distances {
+		if target == ref {
+			continue
+		}
+		entry := DependencyEntry{
+			BOMRef:       target,
+			Type:         DEPENDENCY_RESOURCE_TYPE_MISSING,
+			Relationship: DEPENDENCY_RELATIONSHIP_TRANSITIVE,
+			Distance:     distance,
+		}
+		if distance == 1 {
+			entry.Relationship = DEPENDENCY_RELATIONSHIP_DIRECT
+		}
+		if resourceInfo, declared := graph.Resources[target]; declared {
+			entry.Type = resourceInfo.Type
+			entry.Name = resourceInfo.Name
+			entry.Version = resourceInfo.Version
+		}
+		entries = append(entries, entry)
+	}
+
+	// Sort by distance, then bom-ref
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Distance != entries[j].Distance {
+			return entries[i].Distance < entries[j].Distance
+		}
+		return entries[i].BOMRef < entries[j].BOMRef
+	})
+	return
+}
+
+// Returns the elementary cycles found using a depth-first search (one per "back" edge)
+// Each cycle is rotated to start with its (lexically) lowest bom-ref
+func (graph *DependencyGraph) FindCycles() (cycles [][]string) {
+	const (
+		WHITE = iota // unvisited
+		GRAY         // on the current search path
+		BLACK        // fully explored
+	)
+	color := make(map[string]int)
+	var path []string
+	found := make(map[string]bool)
+
+	var visit func(ref string)
+	visit = func(ref string) {
+		color[ref] = GRAY
+		path = append(path, ref)
+		for _, target := range graph.Edges[ref] {
+			switch color[target] {
+			case WHITE:
+				visit(target)
+			case GRAY:
+				// back edge: the cycle is the search path from the target to this ref
+				var cycle []string
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i] == target {
+						cycle = append(cycle, path[i:]...)
+						break
+					}
+				}
+				cycle = rotateCycle(cycle)
+				key := strings.Join(cycle, DEPENDENCY_CYCLE_SEPARATOR)
+				if !found[key] {
+					found[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		color[ref] = BLACK
+	}
+
+	for _, ref := range graph.SortedAllRefs() {
+		if color[ref] == WHITE {
+			visit(ref)
+		}
+	}
+	return
+}
+
+func rotateCycle(cycle []string) (rotated []string) {
+	lowest := 0
+	for i, ref := range cycle {
+		if ref < cycle[lowest] {
+			lowest = i
+		}
+	}
+	rotated = append(rotated, cycle[lowest:]...)
+	return append(rotated, cycle[:lowest]...)
+}
+
+// Formats a cycle as a path that returns to its first bom-ref
+func FormatDependencyCycle(cycle []string) string {
+	if len(cycle) == 0 {
+		return ""
+	}
+	return strings.Join(append(append([]string{}, cycle...), cycle[0]), DEPENDENCY_CYCLE_SEPARATOR)
+}
+
+// Analyzes the graph for direct and transitive dependencies, dangling refs,
+// orphans (i.e., resources not reachable from the root), cycles and depth
+func (graph *DependencyGraph) Analyze() (analysis *DependencyAnalysis) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	analysis = &DependencyAnalysis{
+		Root:         graph.Root,
+		Nodes:        []DependencyNodeInfo{},
+		DanglingRefs: []DependencyDanglingRef{},
+		Orphans:      []string{},
+		Cycles:       graph.FindCycles(),
+	}
+	if analysis.Cycles == nil {
+		analysis.Cycles = [][]string{}
+	}
+
+	var rootDistances map[string]int
+	if graph.Root != "" {
+		rootDistances = graph.Distances(graph.Root)
+	} else {
+		getLogger().Warningf("BOM `metadata.component` has no `bom-ref`; unable to determine orphans or depth")
+	}
+
+	// Dangling refs (sorted by referencing bom-ref)
+	refs := graph.SortedAllRefs()
+	for _, ref := range refs {
+		dependsOn, hasEdges := graph.Edges[ref]
+		if hasEdges && !graph.IsDeclared(ref) {
+			analysis.DanglingRefs = append(analysis.DanglingRefs,
+				DependencyDanglingRef{MissingRef: ref, ReferencedBy: DEPENDENCY_REFERENCED_BY_DEPENDENCIES})
+		}
+		for _, target := range dependsOn {
+			if !graph.IsDeclared(target) {
+				analysis.DanglingRefs = append(analysis.DanglingRefs,
+					DependencyDanglingRef{MissingRef: target, ReferencedBy: ref})
+			}
+		}
+	}
+
+	cycleRefs := make(map[string]bool)
+	for _, cycle := range analysis.Cycles {
+		for _, ref := range cycle {
+			cycleRefs[ref] = true
+		}
+	}
+
+	stats := &analysis.Statistics
+	var depthTotal, depthCount int
+	for _, ref := range graph.SortedRefs() {
+		resourceInfo := graph.Resources[ref]
+		node := DependencyNodeInfo{
+			BOMRef:     ref,
+			Type:       resourceInfo.Type,
+			Name:       resourceInfo.Name,
+			Version:    resourceInfo.Version,
+			Depth:      DEPENDENCY_DEPTH_UNREACHABLE,
+			Direct:     []string{},
+			Transitive: []string{},
+			Dangling:   []string{},
+			Cycle:      cycleRefs[ref],
+		}
+
+		for _, entry := range graph.Dependencies(ref) {
+			if entry.Relationship == DEPENDENCY_RELATIONSHIP_DIRECT {
+				node.Direct = append(node.Direct, entry.BOMRef)
+			} else {
+				node.Transitive = append(node.Transitive, entry.BOMRef)
+			}
+		}
+		for _, target := range graph.Edges[ref] {
+			if !graph.IsDeclared(target) {
+				node.Dangling = append(node.Dangling, target)
+			}
+		}
+
+		if depth, reachable := rootDistances[ref]; reachable {
+			node.Depth = depth
+			if depth > stats.MaxDepth {
+				stats.MaxDepth = depth
+			}
+			if depth > 0 {
+				depthTotal += depth
+				depthCount++
+			}
+		} else if graph.Root != "" {
+			node.Orphan = true
+			analysis.Orphans = append(analysis.Orphans, ref)
+		}
+
+		if len(graph.Edges[ref]) == 0 {
+			stats.Leaves++
+		}
+		analysis.Nodes = append(analysis.Nodes, node)
+	}
+
+	stats.Nodes = len(analysis.Nodes)
+	for _, dependsOn := range graph.Edges {
+		stats.Edges += len(dependsOn)
+	}
+	if depthCount > 0 {
+		// round to 2 decimal places
+		stats.AverageDepth = math.Round(float64(depthTotal)/float64(depthCount)*100) / 100
+	}
+	stats.Orphans = len(analysis.Orphans)
+	stats.DanglingRefs = len(analysis.DanglingRefs)
+	stats.Cycles = len(analysis.Cycles)
+	return
+}
@@ -0,0 +1,481 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Semantic (identity-aware) diff change types
+const (
+	DIFF_CHANGE_ADDED   = "added"
+	DIFF_CHANGE_REMOVED = "removed"
+	DIFF_CHANGE_CHANGED = "changed"
+)
+
+// The identity used to match an entity in the base BOM to one in the revised BOM.
+// Identities are tried in order of (declaration) precedence; the "package" and
+// "group-name" identities are only tried (as a fallback) for entities left
+// unmatched by all other identities (e.g., a component whose version changed).
+const (
+	DIFF_MATCH_BOMREF     = "bom-ref"
+	DIFF_MATCH_PURL       = "purl"
+	DIFF_MATCH_NAME       = "name"       // i.e., name + group + version
+	DIFF_MATCH_ID         = "id"         // i.e., vulnerability id + source name
+	DIFF_MATCH_REF        = "ref"        // i.e., dependency "ref"
+	DIFF_MATCH_PACKAGE    = "package"    // i.e., purl without its version (qualifiers or subpath)
+	DIFF_MATCH_GROUP_NAME = "group-name" // i.e., name + group (of any version)
+)
+
+// Resource types reported by the semantic diff
+const (
+	DIFF_RESOURCE_TYPE_COMPONENT     = RESOURCE_TYPE_COMPONENT
+	DIFF_RESOURCE_TYPE_SERVICE       = RESOURCE_TYPE_SERVICE
+	DIFF_RESOURCE_TYPE_VULNERABILITY = "vulnerability"
+	DIFF_RESOURCE_TYPE_DEPENDENCY    = "dependency"
+)
+
+// Entity fields compared by the semantic diff
+const (
+	DIFF_FIELD_VERSION        = "version"
+	DIFF_FIELD_PURL           = "purl"
+	DIFF_FIELD_LICENSES       = "licenses"
+	DIFF_FIELD_HASHES         = "hashes"
+	DIFF_FIELD_DESCRIPTION    = "description"
+	DIFF_FIELD_ANALYSIS_STATE = "analysis-state"
+	DIFF_FIELD_RATINGS        = "ratings"
+	DIFF_FIELD_AFFECTS        = "affects"
+	DIFF_FIELD_DEPENDS_ON     = "dependsOn"
+)
+
+// Separator used when a multi-valued field (e.g., licenses) is flattened to a string
+const DIFF_VALUE_SEPARATOR = ", "
+
+type DiffFieldChange struct {
+	Field   string `json:"field"`
+	Base    string `json:"base"`
+	Revised string `json:"revised"`
+}
+
+type DiffEntityResult struct {
+	ResourceType string            `json:"resource-type"`
+	Change       string            `json:"change"`
+	MatchedBy    string            `json:"matched-by,omitempty"`
+	BOMRef       string            `json:"bom-ref,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Version      string            `json:"version,omitempty"`
+	Fields       []DiffFieldChange `json:"fields,omitempty"`
+}
+
+type BOMDiffResult struct {
+	Components      []DiffEntityResult `json:"components"`
+	Services        []DiffEntityResult `json:"services"`
+	Vulnerabilities []DiffEntityResult `json:"vulnerabilities"`
+	Dependencies    []DiffEntityResult `json:"dependencies"`
+}
+
+// Returns all entity results (in resource type order) as a single slice
+func (result *BOMDiffResult) Entities() (entities []DiffEntityResult) {
+	entities = append(entities, result.Components...)
+	entities = append(entities, result.Services...)
+	entities = append(entities, result.Vulnerabilities...)
+	entities = append(entities, result.Dependencies...)
+	return
+}
+
+func (result *BOMDiffResult) HasChanges() bool {
+	return len(result.Components) > 0 ||
+		len(result.Services) > 0 ||
+		len(result.Vulnerabilities) > 0 ||
+		len(result.Dependencies) > 0
+}
+
+// Normalized (identity and comparable field) data for any BOM entity
+type diffEntity struct {
+	bomRef       string
+	purl         string
+	nameKey      string
+	packageKey   string
+	groupNameKey string
+	name         string
+	version      string
+	fieldNames   []string
+	fields       map[string]string
+}
+
+func newDiffEntity() diffEntity {
+	return diffEntity{fields: make(map[string]string)}
+}
+
+func (entity *diffEntity) setField(name string, value string) {
+	entity.fieldNames = append(entity.fieldNames, name)
+	entity.fields[name] = value
+}
+
+// Compares the base BOM against a revised BOM using entity identities
+// (i.e., bom-ref, then purl, then name+group+version) rather than document
+// (array) positions; this allows reordered arrays to compare as equal.
+// Entities left unmatched are then matched by package (i.e., purl without
+// its version) and then by name+group so that version changes are reported
+// as changed entities (rather than as removed and added).
+// NOTE: both BOMs MUST have been unmarshalled as CycloneDX (i.e., UnmarshalCycloneDXBOM())
+func (bom *BOM) DiffSemantic(revised *BOM) (result *BOMDiffResult, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	result = new(BOMDiffResult)
+
+	// Components (including the metadata component and any "nested" components)
+	if err = bom.HashComponentResources(nil); err != nil {
+		return
+	}
+	if err = revised.HashComponentResources(nil); err != nil {
+		return
+	}
+	result.Components = diffEntities(DIFF_RESOURCE_TYPE_COMPONENT,
+		componentDiffEntities(bom), componentDiffEntities(revised))
+
+	// Services (including any "nested" services)
+	if err = bom.HashServiceResources(nil); err != nil {
+		return
+	}
+	if err = revised.HashServiceResources(nil); err != nil {
+		return
+	}
+	result.Services = diffEntities(DIFF_RESOURCE_TYPE_SERVICE,
+		serviceDiffEntities(bom), serviceDiffEntities(revised))
+
+	result.Vulnerabilities = diffEntities(DIFF_RESOURCE_TYPE_VULNERABILITY,
+		vulnerabilityDiffEntities(bom), vulnerabilityDiffEntities(revised))
+
+	result.Dependencies = diffEntities(DIFF_RESOURCE_TYPE_DEPENDENCY,
+		dependencyDiffEntities(bom), dependencyDiffEntities(revised))
+
+	return
+}
+
+// Match base entities to revised entities and produce the added, removed
+// and changed results for the resource type.
+func diffEntities(resourceType string, base []diffEntity, revised []diffEntity) (results []DiffEntityResult) {
+	byBOMRef := make(map[string][]int)
+	byPurl := make(map[string][]int)
+	byName := make(map[string][]int)
+	byPackage := make(map[string][]int)
+	byGroupName := make(map[string][]int)
+	for index, entity := range revised {
+		if entity.bomRef != "" {
+			byBOMRef[entity.bomRef] = append(byBOMRef[entity.bomRef], index)
+		}
+		if entity.purl != "" {
+			byPurl[entity.purl] = append(byPurl[entity.purl], index)
+		}
+		if entity.nameKey != "" {
+			byName[entity.nameKey] = append(byName[entity.nameKey], index)
+		}
+		if entity.packageKey != "" {
+			byPackage[entity.packageKey] = append(byPackage[entity.packageKey], index)
+		}
+		if entity.groupNameKey != "" {
+			byGroupName[entity.groupNameKey] = append(byGroupName[entity.groupNameKey], index)
+		}
+	}
+
+	matched := make([]bool, len(revised))
+	// Returns the first unmatched revised entity under the key (if any)
+	findUnmatched := func(index map[string][]int, key string) int {
+		if key == "" {
+			return -1
+		}
+		for _, candidate := range index[key] {
+			if !matched[candidate] {
+				return candidate
+			}
+		}
+		return -1
+	}
+
+	// Note: all (exact) identities are matched before any fallback identity so that
+	// a fallback match never takes a revised entity that exactly matches another
+	matches := make([]int, len(base))
+	matchesBy := make([]string, len(base))
+	for index, baseEntity := range base {
+		matchesBy[index] = DIFF_MATCH_BOMREF
+		match := findUnmatched(byBOMRef, baseEntity.bomRef)
+		if match < 0 {
+			matchesBy[index] = DIFF_MATCH_PURL
+			match = findUnmatched(byPurl, baseEntity.purl)
+		}
+		if match < 0 {
+			matchesBy[index] = DIFF_MATCH_NAME
+			match = findUnmatched(byName, baseEntity.nameKey)
+		}
+		if match >= 0 {
+			matched[match] = true
+		}
+		matches[index] = match
+	}
+	for index, baseEntity := range base {
+		if matches[index] >= 0 {
+			continue
+		}
+		matchesBy[index] = DIFF_MATCH_PACKAGE
+		match := findUnmatched(byPackage, baseEntity.packageKey)
+		if match < 0 {
+			matchesBy[index] = DIFF_MATCH_GROUP_NAME
+			match = findUnmatched(byGroupName, baseEntity.groupNameKey)
+		}
+		if match >= 0 {
+			matched[match] = true
+		}
+		matches[index] = match
+	}
+
+	for index, baseEntity := range base {
+		match, matchedBy := matches[index], matchesBy[index]
+		if match < 0 {
+			results = append(results, newDiffEntityResult(resourceType, DIFF_CHANGE_REMOVED, "", baseEntity))
+			continue
+		}
+
+		revisedEntity := revised[match]
+		var fieldChanges []DiffFieldChange
+		for _, field := range baseEntity.fieldNames {
+			if baseEntity.fields[field] != revisedEntity.fields[field] {
+				fieldChanges = append(fieldChanges, DiffFieldChange{
+					Field:   field,
+					Base:    baseEntity.fields[field],
+					Revised: revisedEntity.fields[field],
+				})
+			}
+		}
+		if len(fieldChanges) > 0 {
+			entityResult := newDiffEntityResult(resourceType, DIFF_CHANGE_CHANGED, matchedBy, revisedEntity)
+			entityResult.Fields = fieldChanges
+			results = append(results, entityResult)
+		}
+	}
+
+	for index, revisedEntity := range revised {
+		if !matched[index] {
+			results = append(results, newDiffEntityResult(resourceType, DIFF_CHANGE_ADDED, "", revisedEntity))
+		}
+	}
+	return
+}
+
+func newDiffEntityResult(resourceType string, change string, matchedBy string, entity diffEntity) DiffEntityResult {
+	return DiffEntityResult{
+		ResourceType: resourceType,
+		Change:       change,
+		MatchedBy:    matchedBy,
+		BOMRef:       entity.bomRef,
+		Name:         entity.name,
+		Version:      entity.version,
+	}
+}
+
+// NOTE: multimap "Values()" order is not stable; entities are sorted to assure
+// consistent matching (and output) order for entities that share identities.
+func componentDiffEntities(bom *BOM) (entities []diffEntity) {
+	for _, value := range bom.ComponentMap.Values() {
+		resourceInfo, ok := value.(CDXResourceInfo)
+		if !ok {
+			continue
+		}
+		component := resourceInfo.Component
+		entity := newDiffEntity()
+		entity.bomRef = resourceInfo.BOMRef
+		entity.purl = component.Purl
+		entity.name = component.Name
+		entity.version = component.Version
+		entity.nameKey = diffNameKey(component.Group, component.Name, component.Version)
+		entity.packageKey = diffPackageKey(component.Purl)
+		entity.groupNameKey = diffNameKey(component.Group, component.Name, "")
+		entity.setField(DIFF_FIELD_VERSION, component.Version)
+		entity.setField(DIFF_FIELD_PURL, component.Purl)
+		entity.setField(DIFF_FIELD_LICENSES, diffLicenseValue(component.Licenses))
+		entity.setField(DIFF_FIELD_HASHES, diffHashValue(component.Hashes))
+		entities = append(entities, entity)
+	}
+	sortDiffEntities(entities)
+	return
+}
+
+func serviceDiffEntities(bom *BOM) (entities []diffEntity) {
+	for _, value := range bom.ServiceMap.Values() {
+		resourceInfo, ok := value.(CDXResourceInfo)
+		if !ok {
+			continue
+		}
+		service := resourceInfo.Service
+		entity := newDiffEntity()
+		entity.bomRef = resourceInfo.BOMRef
+		entity.name = service.Name
+		entity.version = service.Version
+		entity.nameKey = diffNameKey(service.Group, service.Name, service.Version)
+		entity.groupNameKey = diffNameKey(service.Group, service.Name, "")
+		entity.setField(DIFF_FIELD_VERSION, service.Version)
+		entity.setField(DIFF_FIELD_LICENSES, diffLicenseValue(service.Licenses))
+		entities = append(entities, entity)
+	}
+	sortDiffEntities(entities)
+	return
+}
+
+func vulnerabilityDiffEntities(bom *BOM) (entities []diffEntity) {
+	pVulnerabilities := bom.GetCdxVulnerabilities()
+	if pVulnerabilities == nil {
+		return
+	}
+
+	for _, vulnerability := range *pVulnerabilities {
+		entity := newDiffEntity()
+		if vulnerability.BOMRef != nil {
+			entity.bomRef = vulnerability.BOMRef.String()
+		}
+		entity.name = vulnerability.Id
+		if vulnerability.Id != "" {
+			entity.nameKey = vulnerability.Id
+			if vulnerability.Source != nil && vulnerability.Source.Name != "" {
+				entity.nameKey = fmt.Sprintf("%s@%s", vulnerability.Id, vulnerability.Source.Name)
+			}
+		}
+
+		var state string
+		if vulnerability.Analysis != nil {
+			state = vulnerability.Analysis.State
+		}
+
+		var ratings []string
+		if vulnerability.Ratings != nil {
+			for _, rating := range *vulnerability.Ratings {
+				ratings = append(ratings, fmt.Sprintf("%s: %v (%s)", rating.Method, rating.Score, rating.Severity))
+			}
+		}
+
+		var affects []string
+		if vulnerability.Affects != nil {
+			for _, affect := range *vulnerability.Affects {
+				if affect.Ref != nil {
+					affects = append(affects, affect.Ref.String())
+				}
+			}
+		}
+
+		entity.setField(DIFF_FIELD_DESCRIPTION, vulnerability.Description)
+		entity.setField(DIFF_FIELD_ANALYSIS_STATE, state)
+		entity.setField(DIFF_FIELD_RATINGS, joinSortedDiffValues(ratings))
+		entity.setField(DIFF_FIELD_AFFECTS, joinSortedDiffValues(affects))
+		entities = append(entities, entity)
+	}
+	return
+}
+
+// Dependencies are only identified by their "ref" value
+func dependencyDiffEntities(bom *BOM) (entities []diffEntity) {
+	pDependencies := bom.GetCdxDependencies()
+	if pDependencies == nil {
+		return
+	}
+
+	for _, dependency := range *pDependencies {
+		if dependency.Ref == nil {
+			continue
+		}
+		entity := newDiffEntity()
+		entity.bomRef = dependency.Ref.String()
+		var dependsOn []string
+		if dependency.DependsOn != nil {
+			for _, ref := range *dependency.DependsOn {
+				dependsOn = append(dependsOn, ref.String())
+			}
+		}
+		entity.setField(DIFF_FIELD_DEPENDS_ON, joinSortedDiffValues(dependsOn))
+		entities = append(entities, entity)
+	}
+	return
+}
+
+func diffNameKey(group string, name string, version string) string {
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s@%s", group, name, version)
+}
+
+// Returns the package identity of a purl (i.e., "pkg:type/namespace/name")
+// without its version, qualifiers or subpath
+func diffPackageKey(purl string) string {
+	if end := strings.IndexAny(purl, "?#"); end >= 0 {
+		purl = purl[:end]
+	}
+	if at := strings.LastIndex(purl, "@"); at > strings.LastIndex(purl, "/") {
+		purl = purl[:at]
+	}
+	return purl
+}
+
+// Flatten licenses (by id, name or expression) into a single, order-independent value
+func diffLicenseValue(pLicenses *[]CDXLicenseChoice) string {
+	if pLicenses == nil {
+		return ""
+	}
+	var values []string
+	for _, choice := range *pLicenses {
+		if choice.License != nil && choice.License.Id != "" {
+			values = append(values, choice.License.Id)
+		} else if choice.License != nil && choice.License.Name != "" {
+			values = append(values, choice.License.Name)
+		} else if choice.Expression != "" {
+			values = append(values, choice.Expression)
+		}
+	}
+	return joinSortedDiffValues(values)
+}
+
+// Flatten hashes into a single, order-independent value of "alg:content" pairs
+func diffHashValue(pHashes *[]CDXHash) string {
+	if pHashes == nil {
+		return ""
+	}
+	var values []string
+	for _, hash := range *pHashes {
+		values = append(values, fmt.Sprintf("%s:%s", hash.Alg, hash.Content))
+	}
+	return joinSortedDiffValues(values)
+}
+
+func joinSortedDiffValues(values []string) string {
+	sort.Strings(values)
+	return strings.Join(values, DIFF_VALUE_SEPARATOR)
+}
+
+func sortDiffEntities(entities []diffEntity) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].bomRef != entities[j].bomRef {
+			return entities[i].bomRef < entities[j].bomRef
+		}
+		if entities[i].nameKey != entities[j].nameKey {
+			return entities[i].nameKey < entities[j].nameKey
+		}
+		return entities[i].purl < entities[j].purl
+	})
+}
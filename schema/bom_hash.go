@@ -453,3 +453,65 @@ func (bom *BOM) HashVulnerability(cdxVulnerability CDXVulnerability, whereFilter
 
 	return
 }
+
+// -------------------
+// SPDX
+// -------------------
+
+// Hash all SPDX packages (and files) as (abstract) CycloneDX components so that
+// they can be listed and filtered identically to CycloneDX components.
+// Note: packages the document "describes" are marked as root resources.
+func (bom *BOM) HashSPDXPackageResources(whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	pDocument := bom.GetSpdxDocument()
+	if pDocument == nil {
+		return
+	}
+
+	describedIds := pDocument.GetDescribedElementIds()
+	for _, spdxPackage := range pDocument.GetPackages() {
+		root := false
+		for _, id := range describedIds {
+			if id == spdxPackage.SPDXID {
+				root = true
+				break
+			}
+		}
+		cdxComponent := pDocument.ConvertPackageToCDXComponent(spdxPackage)
+		if _, err = bom.HashComponent(cdxComponent, whereFilters, root); err != nil {
+			return
+		}
+	}
+
+	for _, spdxFile := range pDocument.GetFiles() {
+		cdxComponent := pDocument.ConvertFileToCDXComponent(spdxFile)
+		if _, err = bom.HashComponent(cdxComponent, whereFilters, false); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Hash all vulnerabilities referenced by SPDX packages using "SECURITY" "advisory"
+// external references; SPDX v2.x has no other means to declare vulnerabilities.
+func (bom *BOM) HashSPDXVulnerabilityResources(whereFilters []common.WhereFilter) (err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	pDocument := bom.GetSpdxDocument()
+	if pDocument == nil {
+		return
+	}
+
+	for _, spdxPackage := range pDocument.GetPackages() {
+		vulnerabilities := pDocument.ConvertPackageAdvisoriesToCDXVulnerabilities(spdxPackage)
+		if len(vulnerabilities) > 0 {
+			if err = bom.HashVulnerabilities(vulnerabilities, whereFilters); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,584 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// Merge conflict strategies (i.e., what to do when two inputs declare the same entity)
+const (
+	MERGE_STRATEGY_FIRST_WINS = "first-wins"
+	MERGE_STRATEGY_LAST_WINS  = "last-wins"
+	MERGE_STRATEGY_FAIL       = "fail"
+)
+
+var VALID_MERGE_STRATEGIES = []string{
+	MERGE_STRATEGY_FIRST_WINS,
+	MERGE_STRATEGY_LAST_WINS,
+	MERGE_STRATEGY_FAIL,
+}
+
+// Prefixes used to namespace de-duplication keys by the identity they represent
+const (
+	MERGE_KEY_PREFIX_BOMREF = "bom-ref:"
+	MERGE_KEY_PREFIX_PURL   = "purl:"
+	MERGE_KEY_PREFIX_ID     = "id:"
+	MERGE_KEY_PREFIX_URL    = "url:"
+)
+
+const (
+	ERR_TYPE_MERGE_CONFLICT = "merge conflict"
+	MSG_MERGE_CONFLICT      = "duplicate entity found in input BOMs"
+)
+
+type MergeConflictError struct {
+	Type         string
+	Message      string
+	InputFile    string
+	ResourceType string
+	Key          string
+}
+
+func NewMergeConflictError(inputFile string, resourceType string, key string) *MergeConflictError {
+	var err = new(MergeConflictError)
+	err.Type = ERR_TYPE_MERGE_CONFLICT
+	err.Message = MSG_MERGE_CONFLICT
+	err.InputFile = inputFile
+	err.ResourceType = resourceType
+	err.Key = key
+	return err
+}
+
+func (err MergeConflictError) Error() string {
+	return fmt.Sprintf("%s: %s (`%s`): %s: `%s`",
+		err.Type, err.Message, err.InputFile, err.ResourceType, err.Key)
+}
+
+type MergeOptions struct {
+	Strategy     string
+	Hierarchical bool
+	// (Optional) component used as the merged BOM's "metadata.component";
+	// required for hierarchical merges.
+	Component *CDXComponent
+}
+
+// Records where a (de-duplicated) entity was placed in the merged BOM
+// so that it can be replaced in position (i.e., "last-wins")
+type mergeLocation[T any] struct {
+	items *[]T
+	index int
+}
+
+type mergeIndex[T any] map[string]mergeLocation[T]
+
+// Merged BOM state used while merging each input
+type bomMerger struct {
+	options            MergeOptions
+	root               *CDXComponent // the input component used as the merged root (if any)
+	mergedRoot         *CDXComponent // the merged BOM's root (i.e., "metadata.component")
+	rootKeys           map[string]bool
+	components         []CDXComponent
+	services           []CDXService
+	externalReferences []CDXExternalReference
+	dependencies       []CDXDependency
+	compositions       []CDXCompositions
+	vulnerabilities    []CDXVulnerability
+	componentIndex     mergeIndex[CDXComponent]
+	serviceIndex       mergeIndex[CDXService]
+	referenceIndex     mergeIndex[CDXExternalReference]
+	dependencyIndex    mergeIndex[CDXDependency]
+	compositionIndex   mergeIndex[CDXCompositions]
+	vulnerabilityIndex mergeIndex[CDXVulnerability]
+}
+
+// Merge (i.e., union) the components, services, dependencies, vulnerabilities,
+// compositions and externalReferences of all input BOMs into a new BOM.
+// Entities are de-duplicated by bom-ref (or purl) with conflicts resolved using
+// the requested strategy.  In a "flat" merge, all entities are placed at the
+// top-level; whereas, a "hierarchical" merge nests each input's components
+// under its own "metadata.component" beneath the new (root) "metadata.component".
+// NOTE: all input BOMs MUST have been unmarshalled as CycloneDX (i.e., UnmarshalCycloneDXBOM())
+func MergeCycloneDXBOMs(boms []*BOM, options MergeOptions) (merged *BOM, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	if len(boms) == 0 {
+		err = fmt.Errorf("invalid merge request: no input BOMs provided")
+		return
+	}
+
+	switch options.Strategy {
+	case "":
+		options.Strategy = MERGE_STRATEGY_FIRST_WINS
+	case MERGE_STRATEGY_FIRST_WINS, MERGE_STRATEGY_LAST_WINS, MERGE_STRATEGY_FAIL:
+	default:
+		err = fmt.Errorf("invalid merge strategy: `%s` (valid strategies: %s)",
+			options.Strategy, strings.Join(VALID_MERGE_STRATEGIES, ", "))
+		return
+	}
+
+	if options.Hierarchical && options.Component == nil {
+		err = fmt.Errorf("invalid merge request: hierarchical merge requires a (root) `metadata.component`")
+		return
+	}
+
+	merger := &bomMerger{
+		options:            options,
+		rootKeys:           make(map[string]bool),
+		componentIndex:     make(mergeIndex[CDXComponent]),
+		serviceIndex:       make(mergeIndex[CDXService]),
+		referenceIndex:     make(mergeIndex[CDXExternalReference]),
+		dependencyIndex:    make(mergeIndex[CDXDependency]),
+		compositionIndex:   make(mergeIndex[CDXCompositions]),
+		vulnerabilityIndex: make(mergeIndex[CDXVulnerability]),
+	}
+
+	cdxBom := new(CDXBom)
+	cdxBom.BOMFormat = boms[0].FormatInfo.CanonicalName
+	if cdxBom.BOMFormat == "" {
+		cdxBom.BOMFormat = "CycloneDX"
+	}
+	cdxBom.Version = 1
+	if cdxBom.SerialNumber, err = utils.GenerateURNUUID(); err != nil {
+		return
+	}
+	cdxBom.Metadata = new(CDXMetadata)
+	cdxBom.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	// The new BOM's root (i.e., "metadata.component") is either provided or,
+	// for flat merges only, defaults to that of the first input BOM
+	var root *CDXComponent
+	if options.Component != nil {
+		rootCopy := *options.Component
+		root = &rootCopy
+	} else if pComponent := boms[0].GetCdxMetadataComponent(); pComponent != nil {
+		merger.root = pComponent
+		rootCopy := *pComponent
+		root = &rootCopy
+	}
+	if root != nil {
+		for _, key := range componentMergeKeys(*root) {
+			merger.rootKeys[key] = true
+		}
+		cdxBom.Metadata.Component = root
+		merger.mergedRoot = root
+	}
+
+	var rootDependsOn []CDXRefLinkType
+	for _, bom := range boms {
+		if pCdxBom := bom.GetCdxBom(); pCdxBom == nil {
+			err = fmt.Errorf("invalid merge request: BOM not unmarshalled: `%s`", bom.GetFilenameInterpolated())
+			return
+		}
+
+		if getSpecVersionMinor(bom.GetCdxBom().SpecVersion) > getSpecVersionMinor(cdxBom.SpecVersion) {
+			cdxBom.SpecVersion = bom.GetCdxBom().SpecVersion
+		}
+
+		if options.Hierarchical {
+			var ref CDXRefLinkType
+			if ref, err = merger.mergeHierarchicalComponents(bom); err != nil {
+				return
+			}
+			if ref != "" {
+				rootDependsOn = append(rootDependsOn, ref)
+			}
+		} else if err = merger.mergeFlatComponents(bom); err != nil {
+			return
+		}
+
+		if err = merger.mergeOther(bom); err != nil {
+			return
+		}
+	}
+
+	// Hierarchical merges declare the new root depends on each input's (nested) root
+	if options.Hierarchical && root.BOMRef != nil && len(rootDependsOn) > 0 {
+		rootDependency := CDXDependency{
+			Ref:       (*CDXRefLinkType)(root.BOMRef),
+			DependsOn: &rootDependsOn,
+		}
+		if err = merger.mergeDependency("", rootDependency); err != nil {
+			return
+		}
+	}
+
+	// Only assign non-empty arrays so they are omitted from the output
+	if len(merger.components) > 0 {
+		cdxBom.Components = &merger.components
+	}
+	if len(merger.services) > 0 {
+		cdxBom.Services = &merger.services
+	}
+	if len(merger.externalReferences) > 0 {
+		cdxBom.ExternalReferences = &merger.externalReferences
+	}
+	if len(merger.dependencies) > 0 {
+		cdxBom.Dependencies = &merger.dependencies
+	}
+	if len(merger.compositions) > 0 {
+		cdxBom.Compositions = &merger.compositions
+	}
+	if len(merger.vulnerabilities) > 0 {
+		cdxBom.Vulnerabilities = &merger.vulnerabilities
+	}
+
+	merged = NewBOM("")
+	merged.FormatInfo = boms[0].FormatInfo
+	merged.CdxBom = cdxBom
+	return
+}
+
+// Flat merge: all components (including any input root that is not the merged
+// BOM's root) are placed in the top-level "components" array
+func (merger *bomMerger) mergeFlatComponents(bom *BOM) (err error) {
+	filename := bom.GetFilenameInterpolated()
+	// Note: the merged BOM's root MAY be the same object as this input's root
+	if pComponent := bom.GetCdxMetadataComponent(); pComponent != nil && !merger.isRoot(pComponent) {
+		if err = merger.mergeComponent(filename, &merger.components, *pComponent); err != nil {
+			return
+		}
+	}
+	if pComponents := bom.GetCdxComponents(); pComponents != nil {
+		for _, component := range *pComponents {
+			if err = merger.mergeComponent(filename, &merger.components, component); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// Hierarchical merge: each input's components are nested under (a copy of) its
+// "metadata.component" which is then placed in the top-level "components" array.
+// Returns the reference to the nested root (if any) so that it can be declared
+// as a dependency of the new (merged) root.
+func (merger *bomMerger) mergeHierarchicalComponents(bom *BOM) (ref CDXRefLinkType, err error) {
+	filename := bom.GetFilenameInterpolated()
+	pInputRoot := bom.GetCdxMetadataComponent()
+
+	// An input without a root has its components merged at the top-level
+	if pInputRoot == nil {
+		return "", merger.mergeFlatComponents(bom)
+	}
+
+	inputRoot := *pInputRoot
+	if inputRoot.BOMRef != nil {
+		ref = CDXRefLinkType(*inputRoot.BOMRef)
+	}
+
+	// The input root's own (nested) components are merged below (with all others)
+	// so that the merged BOM never shares the input BOM's arrays
+	inputRoot.Components = nil
+
+	// Retain any components already nested under an entity with the same
+	// identity even if that entity is replaced (i.e., "last-wins")
+	var pNested *[]CDXComponent
+	if pExisting := merger.findComponent(inputRoot); pExisting != nil {
+		pNested = pExisting.Components
+	}
+
+	if err = merger.mergeComponent(filename, &merger.components, inputRoot); err != nil {
+		return
+	}
+
+	// Locate where the input root was placed (it may have been de-duplicated)
+	pParent := merger.findComponent(inputRoot)
+	if pParent == nil {
+		// The input root is the same entity as the merged BOM's root (or has no identity);
+		// nest its components beneath the new root instead
+		if err = merger.mergeComponents(filename, &merger.components, pInputRoot.Components); err != nil {
+			return
+		}
+		return "", merger.mergeComponents(filename, &merger.components, bom.GetCdxComponents())
+	}
+
+	// Merge directly into the parent's (single) nested array so that the index
+	// locations recorded for nested components remain valid
+	if pParent.Components == nil {
+		if pNested == nil {
+			pNested = new([]CDXComponent)
+		}
+		pParent.Components = pNested
+	}
+	pNested = pParent.Components
+	if err = merger.mergeComponents(filename, pNested, pInputRoot.Components); err != nil {
+		return
+	}
+	if err = merger.mergeComponents(filename, pNested, bom.GetCdxComponents()); err != nil {
+		return
+	}
+
+	// Note: the parent may have moved if a (last-wins) merge removed a duplicate
+	if len(*pNested) == 0 {
+		if pParent = merger.findComponent(inputRoot); pParent != nil {
+			pParent.Components = nil
+		}
+	}
+	return
+}
+
+// Merge all remaining (non-component) entities
+func (merger *bomMerger) mergeOther(bom *BOM) (err error) {
+	filename := bom.GetFilenameInterpolated()
+
+	if pServices := bom.GetCdxServices(); pServices != nil {
+		for _, service := range *pServices {
+			var keys []string
+			if service.BOMRef != nil && *service.BOMRef != "" {
+				keys = append(keys, MERGE_KEY_PREFIX_BOMREF+service.BOMRef.String())
+			}
+			if err = mergeEntity(merger.serviceIndex, &merger.services, keys, service,
+				merger.options.Strategy, RESOURCE_TYPE_SERVICE, filename); err != nil {
+				return
+			}
+		}
+	}
+
+	if pReferences := bom.GetCdxExternalReferences(); pReferences != nil {
+		for _, reference := range *pReferences {
+			keys := []string{fmt.Sprintf("%s%s:%s", MERGE_KEY_PREFIX_URL, reference.Type, reference.Url)}
+			// Identical external references are not considered a conflict
+			if err = mergeEntity(merger.referenceIndex, &merger.externalReferences, keys, reference,
+				MERGE_STRATEGY_FIRST_WINS, "externalReference", filename); err != nil {
+				return
+			}
+		}
+	}
+
+	if pDependencies := bom.GetCdxDependencies(); pDependencies != nil {
+		for _, dependency := range *pDependencies {
+			if err = merger.mergeDependency(filename, dependency); err != nil {
+				return
+			}
+		}
+	}
+
+	if pCompositions := bom.GetCdxCompositions(); pCompositions != nil {
+		for _, composition := range *pCompositions {
+			var keys []string
+			if composition.BOMRef != nil && *composition.BOMRef != "" {
+				keys = append(keys, MERGE_KEY_PREFIX_BOMREF+composition.BOMRef.String())
+			}
+			if err = mergeEntity(merger.compositionIndex, &merger.compositions, keys, composition,
+				merger.options.Strategy, "composition", filename); err != nil {
+				return
+			}
+		}
+	}
+
+	if pVulnerabilities := bom.GetCdxVulnerabilities(); pVulnerabilities != nil {
+		for _, vulnerability := range *pVulnerabilities {
+			var keys []string
+			if vulnerability.BOMRef != nil && *vulnerability.BOMRef != "" {
+				keys = append(keys, MERGE_KEY_PREFIX_BOMREF+vulnerability.BOMRef.String())
+			}
+			if vulnerability.Id != "" {
+				keys = append(keys, MERGE_KEY_PREFIX_ID+vulnerability.Id)
+			}
+			if err = mergeEntity(merger.vulnerabilityIndex, &merger.vulnerabilities, keys, vulnerability,
+				merger.options.Strategy, DIFF_RESOURCE_TYPE_VULNERABILITY, filename); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (merger *bomMerger) isRoot(pComponent *CDXComponent) bool {
+	return merger.root != nil && merger.root == pComponent
+}
+
+// Returns the merged component with any of the same identity (keys) as the one provided
+func (merger *bomMerger) findComponent(component CDXComponent) *CDXComponent {
+	for _, key := range componentMergeKeys(component) {
+		if location, found := merger.componentIndex[key]; found {
+			return &(*location.items)[location.index]
+		}
+	}
+	return nil
+}
+
+func (merger *bomMerger) mergeComponents(filename string, items *[]CDXComponent, pComponents *[]CDXComponent) (err error) {
+	if pComponents == nil {
+		return
+	}
+	for _, component := range *pComponents {
+		if err = merger.mergeComponent(filename, items, component); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (merger *bomMerger) mergeComponent(filename string, items *[]CDXComponent, component CDXComponent) (err error) {
+	// The merged BOM owns its (nested) arrays; never append to those of an input BOM
+	if component.Components != nil {
+		nested := append([]CDXComponent(nil), *component.Components...)
+		component.Components = &nested
+	}
+
+	keys := componentMergeKeys(component)
+	// The merged BOM's root is never duplicated within its own components;
+	// instead, the conflict strategy decides which of the two is kept as the root
+	for _, key := range keys {
+		if !merger.rootKeys[key] {
+			continue
+		}
+		switch merger.options.Strategy {
+		case MERGE_STRATEGY_LAST_WINS:
+			getLogger().Tracef("merge: replacing root %s: `%s` (%s)", RESOURCE_TYPE_COMPONENT, key, filename)
+			*merger.mergedRoot = component
+			for _, otherKey := range keys {
+				merger.rootKeys[otherKey] = true
+			}
+		case MERGE_STRATEGY_FAIL:
+			err = NewMergeConflictError(filename, RESOURCE_TYPE_COMPONENT, key)
+		default:
+			getLogger().Tracef("merge: skipping duplicate root %s: `%s` (%s)", RESOURCE_TYPE_COMPONENT, key, filename)
+		}
+		return
+	}
+	return mergeEntity(merger.componentIndex, items, keys, component,
+		merger.options.Strategy, RESOURCE_TYPE_COMPONENT, filename)
+}
+
+// Dependencies for the same "ref" are never a conflict; their "dependsOn" values are combined
+func (merger *bomMerger) mergeDependency(filename string, dependency CDXDependency) (err error) {
+	if dependency.Ref == nil || *dependency.Ref == "" {
+		return
+	}
+	key := MERGE_KEY_PREFIX_BOMREF + dependency.Ref.String()
+	location, found := merger.dependencyIndex[key]
+	if !found {
+		var dependsOn []CDXRefLinkType
+		if dependency.DependsOn != nil {
+			dependsOn = append(dependsOn, *dependency.DependsOn...)
+		}
+		dependency.DependsOn = &dependsOn
+		return mergeEntity(merger.dependencyIndex, &merger.dependencies, []string{key}, dependency,
+			MERGE_STRATEGY_FIRST_WINS, DIFF_RESOURCE_TYPE_DEPENDENCY, filename)
+	}
+
+	existing := &(*location.items)[location.index]
+	if dependency.DependsOn != nil {
+		for _, ref := range *dependency.DependsOn {
+			if !containsRefLink(*existing.DependsOn, ref) {
+				*existing.DependsOn = append(*existing.DependsOn, ref)
+			}
+		}
+	}
+	return
+}
+
+// Add an entity to the merged items unless one of its keys was already merged;
+// in which case, the conflict strategy decides which entity is kept.
+// Note: under "last-wins", every (distinct) entity the item collides with
+// (by any of its keys) is removed and the first is replaced in position.
+func mergeEntity[T any](index mergeIndex[T], items *[]T, keys []string, item T, strategy string, resourceType string, filename string) (err error) {
+	for _, key := range keys {
+		location, found := index[key]
+		if !found {
+			continue
+		}
+		switch strategy {
+		case MERGE_STRATEGY_LAST_WINS:
+			for _, otherKey := range keys {
+				other, found := index[otherKey]
+				if !found || other == index[key] {
+					continue
+				}
+				getLogger().Tracef("merge: removing duplicate %s: `%s` (%s)", resourceType, otherKey, filename)
+				removeMergeLocation(index, other)
+			}
+			// Note: removals may have shifted the location of the entity being replaced
+			location = index[key]
+			getLogger().Tracef("merge: replacing %s: `%s` (%s)", resourceType, key, filename)
+			(*location.items)[location.index] = item
+			for _, otherKey := range keys {
+				index[otherKey] = location
+			}
+		case MERGE_STRATEGY_FAIL:
+			err = NewMergeConflictError(filename, resourceType, key)
+		default:
+			getLogger().Tracef("merge: skipping duplicate %s: `%s` (%s)", resourceType, key, filename)
+		}
+		return
+	}
+
+	*items = append(*items, item)
+	location := mergeLocation[T]{items: items, index: len(*items) - 1}
+	for _, key := range keys {
+		index[key] = location
+	}
+	return
+}
+
+// Remove a merged entity along with its index entries; entities that followed it
+// (in the same items) have their locations updated accordingly.
+func removeMergeLocation[T any](index mergeIndex[T], location mergeLocation[T]) {
+	items := *location.items
+	*location.items = append(items[:location.index], items[location.index+1:]...)
+	for key, other := range index {
+		if other.items != location.items {
+			continue
+		}
+		if other.index == location.index {
+			delete(index, key)
+		} else if other.index > location.index {
+			other.index--
+			index[key] = other
+		}
+	}
+}
+
+func componentMergeKeys(component CDXComponent) (keys []string) {
+	if component.BOMRef != nil && *component.BOMRef != "" {
+		keys = append(keys, MERGE_KEY_PREFIX_BOMREF+component.BOMRef.String())
+	}
+	if component.Purl != "" {
+		keys = append(keys, MERGE_KEY_PREFIX_PURL+component.Purl)
+	}
+	return
+}
+
+func containsRefLink(refs []CDXRefLinkType, ref CDXRefLinkType) bool {
+	for _, existing := range refs {
+		if existing == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the minor version of a CycloneDX "specVersion" (e.g., "1.5" => 5)
+func getSpecVersionMinor(specVersion string) int {
+	if _, minor, found := strings.Cut(specVersion, "."); found {
+		if value, err := strconv.Atoi(minor); err == nil {
+			return value
+		}
+	}
+	return -1
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NTIA "minimum elements" for a Software Bill of Materials (SBOM)
+// See: https://www.ntia.gov/report/2021/minimum-elements-software-bill-materials-sbom
+const (
+	NTIA_ELEMENT_SUPPLIER     = "supplier"
+	NTIA_ELEMENT_NAME         = "name"
+	NTIA_ELEMENT_VERSION      = "version"
+	NTIA_ELEMENT_IDENTIFIERS  = "identifiers"
+	NTIA_ELEMENT_DEPENDENCIES = "dependencies"
+	NTIA_ELEMENT_AUTHOR       = "author"
+	NTIA_ELEMENT_TIMESTAMP    = "timestamp"
+)
+
+// A minimum element and the SCVS identifiers of the (tagged) BOM fields that satisfy it
+// Note: "document" elements (i.e., author, timestamp) apply to all components
+type NTIAElement struct {
+	Name        string
+	Description string
+	Scvs        []string
+	Document    bool
+}
+
+var NTIA_MINIMUM_ELEMENTS = []NTIAElement{
+	{NTIA_ELEMENT_SUPPLIER, "Supplier Name", []string{SCVS_BOM_RESOURCE_SUPPLIER}, false},
+	{NTIA_ELEMENT_NAME, "Component Name", []string{SCVS_BOM_RESOURCE_NAME}, false},
+	{NTIA_ELEMENT_VERSION, "Version of the Component", []string{SCVS_BOM_RESOURCE_VERSION}, false},
+	{NTIA_ELEMENT_IDENTIFIERS, "Other Unique Identifiers", []string{
+		SCVS_BOM_RESOURCE_IDENTIFIERS_PURL,
+		SCVS_BOM_RESOURCE_IDENTIFIERS_CPE,
+		SCVS_BOM_RESOURCE_IDENTIFIERS_SWID}, false},
+	{NTIA_ELEMENT_DEPENDENCIES, "Dependency Relationship", []string{SCVS_BOM_CORE_DEPENDENCIES}, false},
+	{NTIA_ELEMENT_AUTHOR, "Author of SBOM Data", []string{SCVS_BOM_CORE_AUTHORS}, true},
+	{NTIA_ELEMENT_TIMESTAMP, "Timestamp", []string{SCVS_BOM_CORE_TIMESTAMP}, true},
+}
+
+// Maps SPDX package external reference types to the SCVS (unique) identifiers they declare
+var mapSPDXRefTypeToScvsIdentifier = map[string]string{
+	SPDX_REF_TYPE_PURL:  SCVS_BOM_RESOURCE_IDENTIFIERS_PURL,
+	SPDX_REF_TYPE_CPE22: SCVS_BOM_RESOURCE_IDENTIFIERS_CPE,
+	SPDX_REF_TYPE_CPE23: SCVS_BOM_RESOURCE_IDENTIFIERS_CPE,
+	SPDX_REF_TYPE_SWID:  SCVS_BOM_RESOURCE_IDENTIFIERS_SWID,
+}
+
+// The coverage of a single minimum element (i.e., the percentage of components that declare it)
+type NTIAElementCoverage struct {
+	Element     string   `json:"element"`
+	Description string   `json:"description"`
+	Scvs        []string `json:"scvs"`
+	Present     int      `json:"present"`
+	Total       int      `json:"total"`
+	Coverage    float64  `json:"coverage"`
+}
+
+// A component (or SPDX package) missing one or more minimum elements
+type NTIAComponent struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Missing []string `json:"missing"`
+}
+
+// Results of checking a BOM's conformance to the NTIA minimum elements
+// Note: "coverage" is the percentage of components that declare all minimum elements
+type NTIAConformance struct {
+	Components    int                   `json:"components"`
+	Conforming    int                   `json:"conforming"`
+	Coverage      float64               `json:"coverage"`
+	Elements      []NTIAElementCoverage `json:"elements"`
+	NonConforming []NTIAComponent       `json:"nonConforming"`
+}
+
+// Checks each component (CycloneDX) or package (SPDX) of the BOM for the NTIA minimum elements
+// using the SCVS (struct) tags of the BOM fields that declare them.
+// Note: CycloneDX components are found using HashComponentResources() which includes
+// the top-level (metadata) component and all nested components.
+func (bom *BOM) CheckNTIAConformance() (conformance *NTIAConformance, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	var components []NTIAComponent
+	var documentMissing []string
+
+	switch {
+	case bom.FormatInfo.IsCycloneDx():
+		components, documentMissing, err = bom.findNTIACdxComponents()
+	case bom.FormatInfo.IsSpdx():
+		components, documentMissing, err = bom.findNTIASpdxPackages()
+	default:
+		err = fmt.Errorf("unsupported BOM format: `%s`", bom.FormatInfo.CanonicalName)
+	}
+	if err != nil {
+		return
+	}
+
+	conformance = NewNTIAConformance(components, documentMissing)
+	return
+}
+
+// Calculate the per-element coverage (and the non-conforming components) from
+// the elements each component is missing and the (document) elements the BOM is missing
+func NewNTIAConformance(components []NTIAComponent, documentMissing []string) (conformance *NTIAConformance) {
+	conformance = &NTIAConformance{
+		Components:    len(components),
+		NonConforming: []NTIAComponent{},
+	}
+
+	missingCount := make(map[string]int)
+	for _, component := range components {
+		component.Missing = append(component.Missing, documentMissing...)
+		if len(component.Missing) == 0 {
+			conformance.Conforming++
+			continue
+		}
+		for _, element := range component.Missing {
+			missingCount[element]++
+		}
+		conformance.NonConforming = append(conformance.NonConforming, component)
+	}
+
+	for _, element := range NTIA_MINIMUM_ELEMENTS {
+		coverage := NTIAElementCoverage{
+			Element:     element.Name,
+			Description: element.Description,
+			Scvs:        element.Scvs,
+			Total:       len(components),
+		}
+		if element.Document {
+			coverage.Total = 1
+			if !containsString(documentMissing, element.Name) {
+				coverage.Present = 1
+			}
+		} else {
+			coverage.Present = coverage.Total - missingCount[element.Name]
+		}
+		coverage.Coverage = coveragePercentage(coverage.Present, coverage.Total)
+		conformance.Elements = append(conformance.Elements, coverage)
+	}
+	conformance.Coverage = coveragePercentage(conformance.Conforming, conformance.Components)
+
+	sort.Slice(conformance.NonConforming, func(i, j int) bool {
+		a, b := conformance.NonConforming[i], conformance.NonConforming[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Id < b.Id
+	})
+	return
+}
+
+func (bom *BOM) findNTIACdxComponents() (components []NTIAComponent, documentMissing []string, err error) {
+	if bom.GetCdxBom() == nil {
+		if err = bom.UnmarshalCycloneDXBOM(); err != nil {
+			return
+		}
+	}
+
+	if bom.ComponentMap.Empty() {
+		if err = bom.HashComponentResources(nil); err != nil {
+			return
+		}
+	}
+
+	// Components related by the "dependencies" array (i.e., as a "ref" or "dependsOn" value)
+	related := make(map[string]bool)
+	for ref, dependsOn := range bom.NewDependencyGraph().Edges {
+		related[ref] = true
+		for _, target := range dependsOn {
+			related[target] = true
+		}
+	}
+
+	for _, value := range bom.ComponentMap.Values() {
+		resourceInfo, ok := value.(CDXResourceInfo)
+		if !ok {
+			continue
+		}
+		component := NTIAComponent{
+			Id:      resourceInfo.BOMRef,
+			Name:    resourceInfo.Name,
+			Version: resourceInfo.Version,
+		}
+		for _, element := range NTIA_MINIMUM_ELEMENTS {
+			var present bool
+			switch element.Name {
+			case NTIA_ELEMENT_DEPENDENCIES:
+				present = resourceInfo.BOMRef != "" && related[resourceInfo.BOMRef]
+			default:
+				if element.Document {
+					continue
+				}
+				present = HasAnyScvsValue(resourceInfo.Component, element.Scvs)
+			}
+			if !present {
+				component.Missing = append(component.Missing, element.Name)
+			}
+		}
+		components = append(components, component)
+	}
+
+	documentMissing = findNTIADocumentMissing(bom.GetCdxMetadata())
+	return
+}
+
+func (bom *BOM) findNTIASpdxPackages() (components []NTIAComponent, documentMissing []string, err error) {
+	if bom.GetSpdxDocument() == nil {
+		if err = bom.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+	}
+	pDocument := bom.GetSpdxDocument()
+
+	// Packages related to any other SPDX element (including those the document describes)
+	related := make(map[string]bool)
+	for _, relationship := range pDocument.GetRelationships() {
+		related[relationship.SpdxElementId] = true
+		related[relationship.RelatedSpdxElement] = true
+	}
+	for _, id := range pDocument.GetDescribedElementIds() {
+		related[id] = true
+	}
+
+	for _, spdxPackage := range pDocument.GetPackages() {
+		component := NTIAComponent{
+			Id:      spdxPackage.SPDXID,
+			Name:    spdxPackage.Name,
+			Version: spdxPackage.VersionInfo,
+		}
+		for _, element := range NTIA_MINIMUM_ELEMENTS {
+			var present bool
+			switch element.Name {
+			case NTIA_ELEMENT_DEPENDENCIES:
+				present = spdxPackage.SPDXID != "" && related[spdxPackage.SPDXID]
+			case NTIA_ELEMENT_IDENTIFIERS:
+				present = hasSPDXPackageIdentifier(spdxPackage, element.Scvs)
+			default:
+				if element.Document {
+					continue
+				}
+				present = HasAnyScvsValue(spdxPackage, element.Scvs)
+			}
+			if !present {
+				component.Missing = append(component.Missing, element.Name)
+			}
+		}
+		components = append(components, component)
+	}
+
+	documentMissing = findNTIADocumentMissing(pDocument.CreationInfo)
+	return
+}
+
+// Returns the (document) minimum elements not declared by the (tagged) SCVS fields
+// of the given (e.g., CycloneDX "metadata" or SPDX "creationInfo") object
+func findNTIADocumentMissing(object interface{}) (missing []string) {
+	for _, element := range NTIA_MINIMUM_ELEMENTS {
+		if element.Document && !HasAnyScvsValue(object, element.Scvs) {
+			missing = append(missing, element.Name)
+		}
+	}
+	return
+}
+
+// SPDX declares unique identifiers as (typed) package external references
+func hasSPDXPackageIdentifier(spdxPackage SPDXPackage, ids []string) bool {
+	if spdxPackage.ExternalRefs == nil {
+		return false
+	}
+	for _, ref := range *spdxPackage.ExternalRefs {
+		if id, found := mapSPDXRefTypeToScvsIdentifier[ref.ReferenceType]; found &&
+			containsString(ids, id) && ref.ReferenceLocator != "" {
+			return true
+		}
+	}
+	return false
+}
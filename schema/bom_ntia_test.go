@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	TEST_PROFILE_CDX_1_5_NTIA_MINIMUM_ELEMENTS = "test/profile/cdx-1-5-ntia-minimum-elements.json"
+	TEST_PROFILE_SPDX_2_3_PACKAGES             = "test/spdx/spdx-2-3-packages.json"
+	TEST_PROFILE_SPDX_2_2_MIN_REQUIRED         = "test/spdx/spdx-2-2-min-required.json"
+)
+
+func checkNTIAConformance(t *testing.T, inputFile string) *NTIAConformance {
+	document, err := loadBOMFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conformance, err := document.CheckNTIAConformance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conformance
+}
+
+func testNTIAElementCoverage(t *testing.T, conformance *NTIAConformance, expected map[string]float64) {
+	if len(conformance.Elements) != len(NTIA_MINIMUM_ELEMENTS) {
+		t.Fatalf("expected: %v elements, actual: %v", len(NTIA_MINIMUM_ELEMENTS), len(conformance.Elements))
+	}
+	for _, element := range conformance.Elements {
+		if coverage, found := expected[element.Element]; found && element.Coverage != coverage {
+			t.Errorf("element `%s`: expected coverage: %v, actual: %v", element.Element, coverage, element.Coverage)
+		}
+	}
+}
+
+func testNTIANonConforming(t *testing.T, conformance *NTIAConformance, expected map[string]string) {
+	if len(conformance.NonConforming) != len(expected) {
+		t.Fatalf("expected: %v non-conforming components, actual: %v: %v",
+			len(expected), len(conformance.NonConforming), conformance.NonConforming)
+	}
+	for _, component := range conformance.NonConforming {
+		missing := strings.Join(component.Missing, ",")
+		if missing != expected[component.Id] {
+			t.Errorf("component `%s`: expected missing: `%s`, actual: `%s`", component.Id, expected[component.Id], missing)
+		}
+	}
+}
+
+func TestNTIAConformanceCdx15(t *testing.T) {
+	conformance := checkNTIAConformance(t, TEST_PROFILE_CDX_1_5_NTIA_MINIMUM_ELEMENTS)
+
+	// Note: includes the (metadata) component and the nested component
+	if conformance.Components != 5 || conformance.Conforming != 3 || conformance.Coverage != 60 {
+		t.Errorf("expected: (components: 5, conforming: 3, coverage: 60), actual: (%v, %v, %v)",
+			conformance.Components, conformance.Conforming, conformance.Coverage)
+	}
+	testNTIAElementCoverage(t, conformance, map[string]float64{
+		NTIA_ELEMENT_SUPPLIER:     80,
+		NTIA_ELEMENT_NAME:         100,
+		NTIA_ELEMENT_VERSION:      80,
+		NTIA_ELEMENT_IDENTIFIERS:  80,
+		NTIA_ELEMENT_DEPENDENCIES: 80,
+		NTIA_ELEMENT_AUTHOR:       100,
+		NTIA_ELEMENT_TIMESTAMP:    100,
+	})
+	testNTIANonConforming(t, conformance, map[string]string{
+		"chardet":   "version,identifiers,dependencies",
+		"log4j-api": "supplier",
+	})
+}
+
+func TestNTIAConformanceSpdx23Packages(t *testing.T) {
+	conformance := checkNTIAConformance(t, TEST_PROFILE_SPDX_2_3_PACKAGES)
+
+	// Note: a "NOASSERTION" supplier is not a (declared) supplier
+	if conformance.Components != 4 || conformance.Conforming != 2 || conformance.Coverage != 50 {
+		t.Errorf("expected: (components: 4, conforming: 2, coverage: 50), actual: (%v, %v, %v)",
+			conformance.Components, conformance.Conforming, conformance.Coverage)
+	}
+	testNTIAElementCoverage(t, conformance, map[string]float64{
+		NTIA_ELEMENT_SUPPLIER:     75,
+		NTIA_ELEMENT_IDENTIFIERS:  75,
+		NTIA_ELEMENT_DEPENDENCIES: 100,
+		NTIA_ELEMENT_AUTHOR:       100,
+		NTIA_ELEMENT_TIMESTAMP:    100,
+	})
+	testNTIANonConforming(t, conformance, map[string]string{
+		"SPDXRef-Package-acme-application": "identifiers",
+		"SPDXRef-Package-npm-async":        "supplier",
+	})
+}
+
+func TestNTIAConformanceSpdx22NoPackages(t *testing.T) {
+	conformance := checkNTIAConformance(t, TEST_PROFILE_SPDX_2_2_MIN_REQUIRED)
+	if conformance.Components != 0 || conformance.Coverage != 0 {
+		t.Errorf("expected: (components: 0, coverage: 0), actual: (%v, %v)",
+			conformance.Components, conformance.Coverage)
+	}
+	testNTIANonConforming(t, conformance, map[string]string{})
+}
+
+func TestNTIAConformanceDocumentElementsMissing(t *testing.T) {
+	components := []NTIAComponent{
+		{Id: "a", Name: "a", Version: "1.0"},
+		{Id: "b", Name: "b", Missing: []string{NTIA_ELEMENT_VERSION}},
+	}
+	conformance := NewNTIAConformance(components, []string{NTIA_ELEMENT_TIMESTAMP})
+
+	// Note: missing document elements apply to all components
+	if conformance.Conforming != 0 || conformance.Coverage != 0 {
+		t.Errorf("expected: (conforming: 0, coverage: 0), actual: (%v, %v)",
+			conformance.Conforming, conformance.Coverage)
+	}
+	testNTIAElementCoverage(t, conformance, map[string]float64{
+		NTIA_ELEMENT_VERSION:   50,
+		NTIA_ELEMENT_AUTHOR:    100,
+		NTIA_ELEMENT_TIMESTAMP: 0,
+	})
+	testNTIANonConforming(t, conformance, map[string]string{
+		"a": "timestamp",
+		"b": "version,timestamp",
+	})
+}
+
+func TestHasScvsValue(t *testing.T) {
+	component := CDXComponent{Name: "test", Supplier: &CDXOrganizationalEntity{}}
+	if !HasScvsValue(component, SCVS_BOM_RESOURCE_NAME) || !HasScvsValue(&component, SCVS_BOM_RESOURCE_NAME) {
+		t.Errorf("expected: `%s` value", SCVS_BOM_RESOURCE_NAME)
+	}
+	// Note: an empty (struct) value is not an asserted value
+	if HasScvsValue(component, SCVS_BOM_RESOURCE_SUPPLIER) || HasScvsValue(component, SCVS_BOM_RESOURCE_VERSION) {
+		t.Errorf("expected: no `%s` or `%s` value", SCVS_BOM_RESOURCE_SUPPLIER, SCVS_BOM_RESOURCE_VERSION)
+	}
+
+	spdxPackage := SPDXPackage{Supplier: SPDX_NOASSERTION, VersionInfo: "1.0"}
+	if HasScvsValue(spdxPackage, SCVS_BOM_RESOURCE_SUPPLIER) || !HasScvsValue(spdxPackage, SCVS_BOM_RESOURCE_VERSION) {
+		t.Errorf("expected: `%s` value only", SCVS_BOM_RESOURCE_VERSION)
+	}
+
+	var pMetadata *CDXMetadata
+	if HasScvsValue(pMetadata, SCVS_BOM_CORE_TIMESTAMP) {
+		t.Errorf("expected: no `%s` value", SCVS_BOM_CORE_TIMESTAMP)
+	}
+}
@@ -19,65 +19,12 @@
 package schema
 
 import (
-	"bytes"
-	"encoding/gob"
-	"strconv"
-
 	"github.com/CycloneDX/sbom-utility/common"
 )
 
-// Note: Golang supports the RE2 regular exp. engine which does not support many
-// features such as lookahead, lookbehind, etc.
-// See: https://en.wikipedia.org/wiki/Comparison_of_regular_expression_engines
+// Note: the matching logic (including regex., comparison and boolean operands)
+// is shared with the query command (see common.WhereFilter)
 func whereFilterMatch(mapObject map[string]interface{}, whereFilters []common.WhereFilter) (match bool, err error) {
-	var buf bytes.Buffer
-	var key string
-
-	// create a byte encoder
-	enc := gob.NewEncoder(&buf)
-
-	for _, filter := range whereFilters {
-
-		key = filter.Key
-		value, present := mapObject[key]
-		getLogger().Debugf("testing object map[%s]: `%v`", key, value)
-
-		if !present {
-			match = false
-			err = getLogger().Errorf("key `%s` not found ib object map", key)
-			break
-		}
-
-		// Reset the encoder'a byte buffer on each iteration and
-		// convert the value (an interface{}) to []byte we can use on regex. eval.
-		buf.Reset()
-
-		// Do not encode nil pointer values; replace with empty string
-		if value == nil {
-			value = ""
-		}
-
-		// Handle non-string data types in the map by converting them to string
-		switch data := value.(type) {
-		case bool:
-			value = strconv.FormatBool(data)
-		case int:
-			value = strconv.Itoa(data)
-		}
-
-		err = enc.Encode(value)
-
-		if err != nil {
-			err = getLogger().Errorf("Unable to convert value: `%v`, to []byte", value)
-			return
-		}
-
-		// Test that the field value matches the regex supplied in the current filter
-		// Note: the regex compilation is performed during command param. processing
-		if match = filter.ValueRegEx.Match(buf.Bytes()); !match {
-			break
-		}
-	}
-
+	match = common.WhereFiltersMatch(mapObject, whereFilters)
 	return
 }
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Referential integrity issue types
+const (
+	REFERENCE_ISSUE_NOT_UNIQUE   = "bom_ref_not_unique"
+	REFERENCE_ISSUE_NOT_RESOLVED = "ref_not_resolved"
+)
+
+// Referential integrity issue descriptions
+const (
+	MSG_REFERENCE_NOT_UNIQUE   = "bom-ref `%s` is not unique; first declared at `%s`"
+	MSG_REFERENCE_NOT_RESOLVED = "reference `%s` does not match any declared bom-ref"
+)
+
+// JSON keys that declare or reference a bom-ref
+const (
+	REFERENCE_KEY_BOM_REF             = "bom-ref"
+	REFERENCE_KEY_REF                 = "ref"
+	REFERENCE_KEY_DEPENDS_ON          = "dependsOn"
+	REFERENCE_KEY_ASSEMBLIES          = "assemblies"
+	REFERENCE_KEY_AFFECTS             = "affects"
+	REFERENCE_KEY_SUBJECTS            = "subjects"
+	REFERENCE_KEY_RESOURCE_REFERENCES = "resourceReferences"
+)
+
+// BOM-Links (v1.5) reference bom-refs within other BOMs and cannot be resolved locally
+const BOM_LINK_PREFIX = "urn:cdx:"
+
+// Keys (under "formulation") whose values are a "resourceReferenceChoice" object
+var FORMULATION_RESOURCE_REFERENCE_KEYS = []string{"source", "target", "resource"}
+
+// A bom-ref (declaration) or reference found to break referential integrity
+// Note: "Path" holds the keys (and array indices) that dereference into the JSON map
+type BOMReferenceIssue struct {
+	Type        string
+	Path        []string
+	Ref         string
+	Description string
+}
+
+// Checks that all bom-ref values are unique and that every reference (i.e., from
+// "dependencies", "compositions", "vulnerabilities.affects", "annotations.subjects"
+// and "formulation" resource references) resolves to a declared bom-ref
+// Note: the BOM (JSON map) is traversed as it retains the order of (array) elements
+// needed to report a precise path to each issue.
+func (bom *BOM) FindReferenceIssues() (issues []BOMReferenceIssue) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	jsonMap := bom.GetJSONMap()
+	if jsonMap == nil {
+		return
+	}
+
+	// Hash all bom-ref declarations (anywhere in the document) by value
+	declared := make(map[string][]string)
+	hashBOMRefDeclarations(jsonMap, nil, declared, &issues)
+
+	checkRef := func(value interface{}, path []string) {
+		ref, ok := value.(string)
+		if !ok || ref == "" || strings.HasPrefix(ref, BOM_LINK_PREFIX) {
+			return
+		}
+		if _, found := declared[ref]; !found {
+			issues = append(issues, BOMReferenceIssue{
+				Type:        REFERENCE_ISSUE_NOT_RESOLVED,
+				Path:        path,
+				Ref:         ref,
+				Description: fmt.Sprintf(MSG_REFERENCE_NOT_RESOLVED, ref),
+			})
+		}
+	}
+
+	checkRefArray := func(value interface{}, path []string) {
+		if refs, ok := value.([]interface{}); ok {
+			for i, ref := range refs {
+				checkRef(ref, appendPath(path, strconv.Itoa(i)))
+			}
+		}
+	}
+
+	// "dependencies": [{"ref": "", "dependsOn": [""]}]
+	forEachObject(jsonMap["dependencies"], []string{"dependencies"}, func(dependency map[string]interface{}, path []string) {
+		checkRef(dependency[REFERENCE_KEY_REF], appendPath(path, REFERENCE_KEY_REF))
+		checkRefArray(dependency[REFERENCE_KEY_DEPENDS_ON], appendPath(path, REFERENCE_KEY_DEPENDS_ON))
+	})
+
+	// "compositions": [{"assemblies": [""]}]
+	forEachObject(jsonMap["compositions"], []string{"compositions"}, func(composition map[string]interface{}, path []string) {
+		checkRefArray(composition[REFERENCE_KEY_ASSEMBLIES], appendPath(path, REFERENCE_KEY_ASSEMBLIES))
+	})
+
+	// "vulnerabilities": [{"affects": [{"ref": ""}]}]
+	forEachObject(jsonMap["vulnerabilities"], []string{"vulnerabilities"}, func(vulnerability map[string]interface{}, path []string) {
+		forEachObject(vulnerability[REFERENCE_KEY_AFFECTS], appendPath(path, REFERENCE_KEY_AFFECTS), func(affect map[string]interface{}, path []string) {
+			checkRef(affect[REFERENCE_KEY_REF], appendPath(path, REFERENCE_KEY_REF))
+		})
+	})
+
+	// "annotations": [{"subjects": [""]}]
+	forEachObject(jsonMap["annotations"], []string{"annotations"}, func(annotation map[string]interface{}, path []string) {
+		checkRefArray(annotation[REFERENCE_KEY_SUBJECTS], appendPath(path, REFERENCE_KEY_SUBJECTS))
+	})
+
+	// "formulation": resource references may appear at any depth (e.g., workflows, tasks, inputs, outputs)
+	checkResourceReferences(jsonMap["formulation"], []string{"formulation"}, checkRef)
+	return
+}
+
+// Note: this method is recursive
+func hashBOMRefDeclarations(entity interface{}, path []string, declared map[string][]string, issues *[]BOMReferenceIssue) {
+	switch typedEntity := entity.(type) {
+	case map[string]interface{}:
+		// Note: sort keys so that the "first" declaration of a bom-ref is deterministic
+		for _, key := range sortedKeys(typedEntity) {
+			value := typedEntity[key]
+			keyPath := appendPath(path, key)
+			if key == REFERENCE_KEY_BOM_REF {
+				if ref, ok := value.(string); ok && ref != "" {
+					if first, exists := declared[ref]; exists {
+						*issues = append(*issues, BOMReferenceIssue{
+							Type:        REFERENCE_ISSUE_NOT_UNIQUE,
+							Path:        keyPath,
+							Ref:         ref,
+							Description: fmt.Sprintf(MSG_REFERENCE_NOT_UNIQUE, ref, strings.Join(first, ".")),
+						})
+					} else {
+						declared[ref] = keyPath
+					}
+				}
+				continue
+			}
+			hashBOMRefDeclarations(value, keyPath, declared, issues)
+		}
+	case []interface{}:
+		for i, value := range typedEntity {
+			hashBOMRefDeclarations(value, appendPath(path, strconv.Itoa(i)), declared, issues)
+		}
+	}
+}
+
+// Note: this method is recursive
+func checkResourceReferences(entity interface{}, path []string, checkRef func(interface{}, []string)) {
+	switch typedEntity := entity.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(typedEntity) {
+			value := typedEntity[key]
+			keyPath := appendPath(path, key)
+			if key == REFERENCE_KEY_RESOURCE_REFERENCES {
+				forEachObject(value, keyPath, func(choice map[string]interface{}, path []string) {
+					checkRef(choice[REFERENCE_KEY_REF], appendPath(path, REFERENCE_KEY_REF))
+				})
+				continue
+			}
+			if choice, ok := value.(map[string]interface{}); ok && containsString(FORMULATION_RESOURCE_REFERENCE_KEYS, key) {
+				if ref, found := choice[REFERENCE_KEY_REF]; found {
+					checkRef(ref, appendPath(keyPath, REFERENCE_KEY_REF))
+					continue
+				}
+			}
+			checkResourceReferences(value, keyPath, checkRef)
+		}
+	case []interface{}:
+		for i, value := range typedEntity {
+			checkResourceReferences(value, appendPath(path, strconv.Itoa(i)), checkRef)
+		}
+	}
+}
+
+// Invokes the function for each object (i.e., JSON map) in the (JSON) array
+func forEachObject(entity interface{}, path []string, fx func(map[string]interface{}, []string)) {
+	if array, ok := entity.([]interface{}); ok {
+		for i, value := range array {
+			if object, ok := value.(map[string]interface{}); ok {
+				fx(object, appendPath(path, strconv.Itoa(i)))
+			}
+		}
+	}
+}
+
+func sortedKeys(jsonMap map[string]interface{}) (keys []string) {
+	keys = make([]string, 0, len(jsonMap))
+	for key := range jsonMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Returns a new path (slice); paths are retained by issues and MUST NOT share backing arrays
+func appendPath(path []string, key string) []string {
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, key)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES = "test/custom/cdx-1-5-test-custom-invalid-references.json"
+	TEST_CDX_1_5_MATURE_EXAMPLE_1          = "test/cyclonedx/cdx-1-5-mature-example-1.json"
+)
+
+func TestReferencesCdx15MatureExampleNoIssues(t *testing.T) {
+	document, err := loadBOMFile(TEST_CDX_1_5_MATURE_EXAMPLE_1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues := document.FindReferenceIssues(); len(issues) != 0 {
+		t.Errorf("expected: no reference issues, actual: %v", issues)
+	}
+}
+
+func TestReferencesCdx15InvalidReferences(t *testing.T) {
+	document, err := loadBOMFile(TEST_CUSTOM_CDX_1_5_INVALID_REFERENCES)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Note: BOM-Links (i.e., "urn:cdx:...") reference other BOMs and are not reported
+	expected := []BOMReferenceIssue{
+		{Type: REFERENCE_ISSUE_NOT_UNIQUE, Path: []string{"components", "1", "bom-ref"}, Ref: "pkg:npm/express@4.18.2"},
+		{Type: REFERENCE_ISSUE_NOT_UNIQUE, Path: []string{"vulnerabilities", "1", "bom-ref"}, Ref: "license-mit"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"dependencies", "0", "dependsOn", "1"}, Ref: "pkg:npm/debug@2.6.9"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"dependencies", "1", "ref"}, Ref: "pkg:npm/missing@1.0.0"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"compositions", "0", "assemblies", "1"}, Ref: "pkg:npm/assembly-missing@1.0.0"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"vulnerabilities", "0", "affects", "1", "ref"}, Ref: "pkg:npm/qs@6.11.0"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"annotations", "0", "subjects", "1"}, Ref: "service:missing"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"formulation", "0", "workflows", "0", "inputs", "0", "source", "ref"}, Ref: "input-source-missing"},
+		{Type: REFERENCE_ISSUE_NOT_RESOLVED, Path: []string{"formulation", "0", "workflows", "0", "resourceReferences", "1", "ref"}, Ref: "workflow-resource-missing"},
+	}
+
+	issues := document.FindReferenceIssues()
+	if len(issues) != len(expected) {
+		t.Fatalf("expected: %v issues, actual: %v: %v", len(expected), len(issues), issues)
+	}
+	for i, issue := range issues {
+		actualPath := strings.Join(issue.Path, ".")
+		expectedPath := strings.Join(expected[i].Path, ".")
+		if issue.Type != expected[i].Type || actualPath != expectedPath || issue.Ref != expected[i].Ref {
+			t.Errorf("issue %v: expected: (%s, %s, %s), actual: (%s, %s, %s)", i,
+				expected[i].Type, expectedPath, expected[i].Ref,
+				issue.Type, actualPath, issue.Ref)
+		}
+	}
+
+	// The first declaration of a duplicate bom-ref is referenced in its description
+	if !strings.Contains(issues[0].Description, "components.0.bom-ref") {
+		t.Errorf("expected description to reference first declaration: `%s`", issues[0].Description)
+	}
+}
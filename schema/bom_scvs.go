@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+)
+
+// SCVS BOM Maturity Model (requirement) scopes
+// i.e., "document" requirements are met by the BOM itself (e.g., its "metadata")
+// and "resource" requirements must be met by every component (or SPDX package)
+const (
+	SCVS_SCOPE_DOCUMENT = "document"
+	SCVS_SCOPE_RESOURCE = "resource"
+)
+
+// SCVS BOM Maturity Model levels
+const (
+	SCVS_LEVEL_NONE = 0
+	SCVS_LEVEL_1    = 1
+	SCVS_LEVEL_2    = 2
+	SCVS_LEVEL_3    = 3
+)
+
+// A maturity model requirement and the SCVS identifiers of the (tagged) BOM fields that satisfy it
+type ScvsRequirement struct {
+	Id          string
+	Level       int
+	Scope       string
+	Description string
+	Scvs        []string
+}
+
+// NOTE: requirements will be scored (and output) in the order they are listed here:
+var SCVS_BOM_MATURITY_REQUIREMENTS = []ScvsRequirement{
+	// Level 1
+	{SCVS_BOM_CORE_TIMESTAMP, SCVS_LEVEL_1, SCVS_SCOPE_DOCUMENT, "BOM creation timestamp", []string{SCVS_BOM_CORE_TIMESTAMP}},
+	{SCVS_BOM_RESOURCE_NAME, SCVS_LEVEL_1, SCVS_SCOPE_RESOURCE, "component name", []string{SCVS_BOM_RESOURCE_NAME}},
+	{SCVS_BOM_RESOURCE_VERSION, SCVS_LEVEL_1, SCVS_SCOPE_RESOURCE, "component version", []string{SCVS_BOM_RESOURCE_VERSION}},
+	{SCVS_BOM_RESOURCE_IDENTIFIERS, SCVS_LEVEL_1, SCVS_SCOPE_RESOURCE, "component identifier (purl, cpe or swid)", []string{
+		SCVS_BOM_RESOURCE_IDENTIFIERS_PURL,
+		SCVS_BOM_RESOURCE_IDENTIFIERS_CPE,
+		SCVS_BOM_RESOURCE_IDENTIFIERS_SWID}},
+	// Level 2
+	{SCVS_BOM_CORE_IDENTIFIER, SCVS_LEVEL_2, SCVS_SCOPE_DOCUMENT, "BOM unique identifier (i.e., serial number or namespace)", []string{SCVS_BOM_CORE_IDENTIFIER}},
+	{SCVS_BOM_CORE_AUTHORS, SCVS_LEVEL_2, SCVS_SCOPE_DOCUMENT, "BOM authors", []string{SCVS_BOM_CORE_AUTHORS}},
+	{SCVS_BOM_CORE_DEPENDENCIES, SCVS_LEVEL_2, SCVS_SCOPE_DOCUMENT, "BOM dependency relationships", []string{SCVS_BOM_CORE_DEPENDENCIES}},
+	{SCVS_BOM_RESOURCE_SUPPLIER, SCVS_LEVEL_2, SCVS_SCOPE_RESOURCE, "component supplier", []string{SCVS_BOM_RESOURCE_SUPPLIER}},
+	{SCVS_BOM_RESOURCE_LICENSES, SCVS_LEVEL_2, SCVS_SCOPE_RESOURCE, "component licenses", []string{SCVS_BOM_RESOURCE_LICENSES}},
+	// Level 3
+	{SCVS_BOM_RESOURCE_HASHES, SCVS_LEVEL_3, SCVS_SCOPE_RESOURCE, "component hashes", []string{SCVS_BOM_RESOURCE_HASHES}},
+	{SCVS_BOM_RESOURCE_IDENTIFIERS_PURL, SCVS_LEVEL_3, SCVS_SCOPE_RESOURCE, "component package URL (purl)", []string{SCVS_BOM_RESOURCE_IDENTIFIERS_PURL}},
+	{SCVS_BOM_RESOURCE_COPYRIGHT, SCVS_LEVEL_3, SCVS_SCOPE_RESOURCE, "component copyright", []string{SCVS_BOM_RESOURCE_COPYRIGHT}},
+	{SCVS_BOM_CORE_SIGNATURE, SCVS_LEVEL_3, SCVS_SCOPE_DOCUMENT, "BOM signature", []string{SCVS_BOM_CORE_SIGNATURE}},
+}
+
+// The (pass/fail) result of a single requirement
+// Note: "resource" requirements only pass if all components (i.e., 100% coverage) meet them
+// and are not applicable (i.e., neither pass nor fail) if the BOM has no components
+type ScvsRequirementResult struct {
+	Id          string  `json:"id"`
+	Level       int     `json:"level"`
+	Scope       string  `json:"scope"`
+	Description string  `json:"description"`
+	Present     int     `json:"present"`
+	Total       int     `json:"total"`
+	Coverage    float64 `json:"coverage"`
+	Applicable  bool    `json:"applicable"`
+	Passed      bool    `json:"passed"`
+}
+
+// The BOM's maturity score; that is, the percentage of (applicable) requirements passed
+// and the (highest) level whose requirements (and those of all lower levels) all passed
+type ScvsScore struct {
+	Components    int                     `json:"components"`
+	Requirements  int                     `json:"requirements"`
+	NotApplicable int                     `json:"notApplicable"`
+	Passed        int                     `json:"passed"`
+	Score         float64                 `json:"score"`
+	Level         int                     `json:"level"`
+	Results       []ScvsRequirementResult `json:"results"`
+}
+
+// Scores the BOM against the SCVS BOM Maturity Model requirements using the
+// SCVS (struct) tags of the BOM fields that declare them.
+// Note: CycloneDX components are found using HashComponentResources() which includes
+// the top-level (metadata) component and all nested components; SPDX packages are
+// scored as their (abstract) CycloneDX components.
+func (bom *BOM) ScoreScvsMaturity() (score *ScvsScore, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	var documentObjects []interface{}
+	var components []CDXComponent
+
+	switch {
+	case bom.FormatInfo.IsCycloneDx():
+		documentObjects, components, err = bom.findScvsCdxObjects()
+	case bom.FormatInfo.IsSpdx():
+		documentObjects, components, err = bom.findScvsSpdxObjects()
+	default:
+		err = fmt.Errorf("unsupported BOM format: `%s`", bom.FormatInfo.CanonicalName)
+	}
+	if err != nil {
+		return
+	}
+
+	score = NewScvsScore(SCVS_BOM_MATURITY_REQUIREMENTS, documentObjects, components)
+	return
+}
+
+// Evaluate each requirement against the document objects (e.g., the CycloneDX BOM and its "metadata")
+// or each of the components (i.e., by scope)
+func NewScvsScore(requirements []ScvsRequirement, documentObjects []interface{}, components []CDXComponent) (score *ScvsScore) {
+	score = &ScvsScore{
+		Components:   len(components),
+		Requirements: len(requirements),
+		Results:      []ScvsRequirementResult{},
+	}
+
+	// The level achieved is the highest level whose requirements (and those of all lower levels) passed
+	failedLevel := SCVS_LEVEL_NONE
+	maxLevel := SCVS_LEVEL_NONE
+	for _, requirement := range requirements {
+		result := ScvsRequirementResult{
+			Id:          requirement.Id,
+			Level:       requirement.Level,
+			Scope:       requirement.Scope,
+			Description: requirement.Description,
+		}
+		switch requirement.Scope {
+		case SCVS_SCOPE_DOCUMENT:
+			result.Total = 1
+			for _, object := range documentObjects {
+				if HasAnyScvsValue(object, requirement.Scvs) {
+					result.Present = 1
+					break
+				}
+			}
+		default:
+			result.Total = len(components)
+			for _, component := range components {
+				if HasAnyScvsValue(component, requirement.Scvs) {
+					result.Present++
+				}
+			}
+		}
+		result.Coverage = coveragePercentage(result.Present, result.Total)
+		result.Applicable = result.Total > 0
+		result.Passed = result.Applicable && result.Present == result.Total
+
+		if !result.Applicable {
+			score.NotApplicable++
+		} else if result.Passed {
+			score.Passed++
+		} else if failedLevel == SCVS_LEVEL_NONE || requirement.Level < failedLevel {
+			failedLevel = requirement.Level
+		}
+		if requirement.Level > maxLevel {
+			maxLevel = requirement.Level
+		}
+		score.Results = append(score.Results, result)
+	}
+
+	score.Score = coveragePercentage(score.Passed, score.Requirements-score.NotApplicable)
+	score.Level = maxLevel
+	if failedLevel != SCVS_LEVEL_NONE {
+		score.Level = failedLevel - 1
+	}
+	return
+}
+
+func (bom *BOM) findScvsCdxObjects() (documentObjects []interface{}, components []CDXComponent, err error) {
+	if bom.GetCdxBom() == nil {
+		if err = bom.UnmarshalCycloneDXBOM(); err != nil {
+			return
+		}
+	}
+
+	if bom.ComponentMap.Empty() {
+		if err = bom.HashComponentResources(nil); err != nil {
+			return
+		}
+	}
+
+	for _, value := range bom.ComponentMap.Values() {
+		if resourceInfo, ok := value.(CDXResourceInfo); ok {
+			components = append(components, resourceInfo.Component)
+		}
+	}
+
+	documentObjects = []interface{}{bom.GetCdxBom(), bom.GetCdxMetadata()}
+	return
+}
+
+func (bom *BOM) findScvsSpdxObjects() (documentObjects []interface{}, components []CDXComponent, err error) {
+	if bom.GetSpdxDocument() == nil {
+		if err = bom.UnmarshalSPDXDocument(); err != nil {
+			return
+		}
+	}
+	pDocument := bom.GetSpdxDocument()
+
+	for _, spdxPackage := range pDocument.GetPackages() {
+		components = append(components, pDocument.ConvertPackageToCDXComponent(spdxPackage))
+	}
+
+	documentObjects = []interface{}{pDocument, pDocument.CreationInfo}
+	return
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+)
+
+func scoreScvsMaturity(t *testing.T, inputFile string) *ScvsScore {
+	document, err := loadBOMFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, err := document.ScoreScvsMaturity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return score
+}
+
+func testScvsRequirementResults(t *testing.T, score *ScvsScore, expected map[string]bool) {
+	if len(score.Results) != len(SCVS_BOM_MATURITY_REQUIREMENTS) {
+		t.Fatalf("expected: %v results, actual: %v", len(SCVS_BOM_MATURITY_REQUIREMENTS), len(score.Results))
+	}
+	for _, result := range score.Results {
+		if passed, found := expected[result.Id]; found && result.Passed != passed {
+			t.Errorf("requirement `%s`: expected passed: %v, actual: %v (coverage: %v)",
+				result.Id, passed, result.Passed, result.Coverage)
+		}
+	}
+}
+
+func TestScvsScoreCdx15(t *testing.T) {
+	score := scoreScvsMaturity(t, TEST_PROFILE_CDX_1_5_NTIA_MINIMUM_ELEMENTS)
+
+	// Note: a component without a version fails a level 1 requirement
+	if score.Components != 5 || score.Passed != 5 || score.Level != SCVS_LEVEL_NONE {
+		t.Errorf("expected: (components: 5, passed: 5, level: 0), actual: (%v, %v, %v)",
+			score.Components, score.Passed, score.Level)
+	}
+	testScvsRequirementResults(t, score, map[string]bool{
+		SCVS_BOM_CORE_TIMESTAMP:       true,
+		SCVS_BOM_RESOURCE_NAME:        true,
+		SCVS_BOM_RESOURCE_VERSION:     false,
+		SCVS_BOM_CORE_IDENTIFIER:      true,
+		SCVS_BOM_CORE_DEPENDENCIES:    true,
+		SCVS_BOM_RESOURCE_LICENSES:    false,
+		SCVS_BOM_CORE_SIGNATURE:       false,
+		SCVS_BOM_RESOURCE_IDENTIFIERS: false,
+	})
+}
+
+func TestScvsScoreSpdx23Packages(t *testing.T) {
+	score := scoreScvsMaturity(t, TEST_PROFILE_SPDX_2_3_PACKAGES)
+
+	if score.Components != 4 || score.Passed != 7 || score.Score != 53.85 || score.Level != SCVS_LEVEL_NONE {
+		t.Errorf("expected: (components: 4, passed: 7, score: 53.85, level: 0), actual: (%v, %v, %v, %v)",
+			score.Components, score.Passed, score.Score, score.Level)
+	}
+	testScvsRequirementResults(t, score, map[string]bool{
+		SCVS_BOM_RESOURCE_VERSION:     true,
+		SCVS_BOM_RESOURCE_IDENTIFIERS: false,
+		SCVS_BOM_CORE_IDENTIFIER:      true,
+		SCVS_BOM_CORE_AUTHORS:         true,
+		SCVS_BOM_RESOURCE_SUPPLIER:    false,
+		SCVS_BOM_RESOURCE_LICENSES:    true,
+	})
+}
+
+func TestScvsScoreLevels(t *testing.T) {
+	documentObjects := []interface{}{CDXMetadata{Timestamp: "2024-01-01T00:00:00Z"}}
+	components := []CDXComponent{{Name: "a", Version: "1.0", Purl: "pkg:generic/a@1.0"}}
+
+	// Note: only (level 1) timestamp, name, version and identifiers requirements are met
+	score := NewScvsScore(SCVS_BOM_MATURITY_REQUIREMENTS, documentObjects, components)
+	if score.Level != SCVS_LEVEL_1 {
+		t.Errorf("expected level: %v, actual: %v", SCVS_LEVEL_1, score.Level)
+	}
+
+	// Note: resource requirements are not applicable (i.e., do not fail) without components
+	score = NewScvsScore(SCVS_BOM_MATURITY_REQUIREMENTS, documentObjects, nil)
+	if score.Level != SCVS_LEVEL_1 || score.Passed != 1 || score.NotApplicable != 8 || score.Score != 20 {
+		t.Errorf("expected: (level: 1, passed: 1, not applicable: 8, score: 20), actual: (%v, %v, %v, %v)",
+			score.Level, score.Passed, score.NotApplicable, score.Score)
+	}
+}
+
+// A document without components (i.e., SPDX packages) is scored by its document requirements only
+func TestScvsScoreSpdx22NoPackages(t *testing.T) {
+	score := scoreScvsMaturity(t, TEST_PROFILE_SPDX_2_2_MIN_REQUIRED)
+
+	if score.Components != 0 || score.Passed != 3 || score.NotApplicable != 8 || score.Score != 60 || score.Level != SCVS_LEVEL_1 {
+		t.Errorf("expected: (components: 0, passed: 3, not applicable: 8, score: 60, level: 1), actual: (%v, %v, %v, %v, %v)",
+			score.Components, score.Passed, score.NotApplicable, score.Score, score.Level)
+	}
+	for _, result := range score.Results {
+		if result.Applicable != (result.Scope == SCVS_SCOPE_DOCUMENT) {
+			t.Errorf("requirement `%s` (%s): unexpected applicable: %v", result.Id, result.Scope, result.Applicable)
+		}
+	}
+}
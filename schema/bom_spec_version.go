@@ -0,0 +1,453 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CycloneDX specification versions a BOM can be migrated to (or from)
+var VALID_CDX_SPEC_VERSIONS = []string{"1.2", "1.3", "1.4", "1.5"}
+
+// Struct tag (key) and values used to declare the spec. version a field was added (or deprecated)
+// e.g., `cdx:"+1.4"`, `cdx:"deprecated:1.4"` or `cdx:"+1.4,deprecated:1.5"`
+const (
+	CDX_TAG_KEY               = "cdx"
+	CDX_TAG_ADDED_PREFIX      = "+"
+	CDX_TAG_DEPRECATED        = "deprecated"
+	CDX_TAG_VALUE_SEPARATOR   = ","
+	CDX_TAG_VERSION_SEPARATOR = ":"
+)
+
+// Reasons for spec. version migration changes
+const (
+	MIGRATE_REASON_UNSUPPORTED = "not supported by target version"
+	MIGRATE_REASON_VALUE       = "value not supported by target version; replaced"
+	MIGRATE_REASON_DEPRECATED  = "deprecated in target version"
+	MIGRATE_REASON_MIGRATED    = "deprecated in target version; migrated"
+)
+
+// Pedigree notes used when migrating the deprecated component "modified" flag
+const MIGRATE_MODIFIED_PEDIGREE_NOTES = "This component was modified from its original (migrated from the deprecated \"modified\" field)."
+
+// Minor spec. versions (i.e., of CycloneDX v1.x) that introduced (or deprecated) constructs
+const (
+	CDX_MINOR_VERSION_MODIFIED_DEPRECATED = 4
+	CDX_MINOR_VERSION_COMPONENT_VERSION   = 4 // component "version" no longer required
+	CDX_MINOR_VERSION_TOOLS_OBJECT        = 5
+)
+
+// Enum. values (by field) and the minor spec. version that introduced them along with
+// the value they are replaced with in earlier spec. versions
+type cdxEnumValueMigration struct {
+	minor       int
+	replacement string
+}
+
+var mapCDXComponentTypeMigrations = map[string]cdxEnumValueMigration{
+	"platform":               {5, COMPONENT_TYPE_OPERATING_SYSTEM},
+	"device-driver":          {5, COMPONENT_TYPE_LIBRARY},
+	"machine-learning-model": {5, COMPONENT_TYPE_FILE},
+	"data":                   {5, COMPONENT_TYPE_FILE},
+}
+
+var mapCDXRatingMethodMigrations = map[string]cdxEnumValueMigration{
+	"CVSSv4": {5, "other"},
+	"SSVC":   {5, "other"},
+}
+
+var mapCDXAggregateMigrations = map[string]cdxEnumValueMigration{
+	"incomplete_first_party_proprietary_only": {5, "incomplete_first_party_only"},
+	"incomplete_first_party_opensource_only":  {5, "incomplete_first_party_only"},
+	"incomplete_third_party_proprietary_only": {5, "incomplete_third_party_only"},
+	"incomplete_third_party_opensource_only":  {5, "incomplete_third_party_only"},
+}
+
+var mapCDXExternalReferenceTypeMigrations = func() map[string]cdxEnumValueMigration {
+	migrations := map[string]cdxEnumValueMigration{
+		"release-notes": {4, CDX_EXTERNAL_REF_TYPE_OTHER},
+	}
+	for _, value := range []string{
+		"distribution-intake", "security-contact", "model-card", "log", "configuration",
+		"evidence", "formulation", "attestation", "threat-model", "adversary-model",
+		"risk-assessment", "vulnerability-assertion", "exploitability-statement",
+		"pentest-report", "static-analysis-report", "dynamic-analysis-report",
+		"runtime-analysis-report", "component-analysis-report", "maturity-report",
+		"certification-report", "quality-metrics", "codified-infrastructure", "poam",
+	} {
+		migrations[value] = cdxEnumValueMigration{5, CDX_EXTERNAL_REF_TYPE_OTHER}
+	}
+	return migrations
+}()
+
+// CycloneDX (tools) component keys that can be represented as a (legacy) tool
+var mapMigrateLegacyToolComponentKeys = map[string]bool{
+	"type": true, "group": true, "name": true, "version": true, "hashes": true, "externalReferences": true,
+}
+
+// CycloneDX (tools) service keys that can be represented as a (legacy) tool
+var mapMigrateLegacyToolServiceKeys = map[string]bool{
+	"provider": true, "name": true, "version": true, "externalReferences": true,
+}
+
+// Parsed `cdx` struct tag
+type cdxTagInfo struct {
+	addedMinor      int // -1 if not declared
+	deprecatedMinor int // -1 if not declared (or if declared without a version)
+	deprecated      bool
+}
+
+func parseCDXTag(tag string) (info cdxTagInfo) {
+	info.addedMinor, info.deprecatedMinor = -1, -1
+	for _, value := range strings.Split(tag, CDX_TAG_VALUE_SEPARATOR) {
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, CDX_TAG_ADDED_PREFIX) {
+			info.addedMinor = getSpecVersionMinor(strings.TrimPrefix(value, CDX_TAG_ADDED_PREFIX))
+		} else if strings.HasPrefix(value, CDX_TAG_DEPRECATED) {
+			info.deprecated = true
+			if _, version, found := strings.Cut(value, CDX_TAG_VERSION_SEPARATOR); found {
+				info.deprecatedMinor = getSpecVersionMinor(version)
+			}
+		}
+	}
+	return
+}
+
+func IsValidCDXSpecVersion(specVersion string) bool {
+	for _, value := range VALID_CDX_SPEC_VERSIONS {
+		if value == specVersion {
+			return true
+		}
+	}
+	return false
+}
+
+type specVersionMigrator struct {
+	cdxBom      *CDXBom
+	report      *ConversionReport
+	targetMinor int
+	missing     []string // locations of fields required by the target version that have no value
+}
+
+// Rewrites a (fully unmarshalled) CycloneDX BOM to the target spec. version; fields not supported
+// by the target version are removed (or transformed) and deprecated constructs are migrated.
+// All changes that are not lossless are recorded in the returned report.
+// Note: fails if a field required by the target version (e.g., component "version") has no value.
+func (bom *BOM) MigrateCycloneDXSpecVersion(targetVersion string) (report *ConversionReport, err error) {
+	getLogger().Enter()
+	defer getLogger().Exit(err)
+
+	cdxBom := bom.GetCdxBom()
+	if cdxBom == nil {
+		err = fmt.Errorf("invalid BOM: no CycloneDX data found (`%s`)", bom.GetFilenameInterpolated())
+		return
+	}
+	if !IsValidCDXSpecVersion(targetVersion) {
+		err = fmt.Errorf("invalid target spec. version: `%s` (valid versions: %s)",
+			targetVersion, strings.Join(VALID_CDX_SPEC_VERSIONS, ", "))
+		return
+	}
+
+	migrator := specVersionMigrator{
+		cdxBom:      cdxBom,
+		targetMinor: getSpecVersionMinor(targetVersion),
+		report: &ConversionReport{
+			SourceFormat:  bom.FormatInfo.CanonicalName,
+			SourceVersion: cdxBom.SpecVersion,
+			TargetFormat:  SCHEMA_FORMAT_CYCLONEDX,
+			TargetVersion: targetVersion,
+		},
+	}
+
+	if err = migrator.migrateTools(); err != nil {
+		return
+	}
+	migrator.migrateValue(reflect.ValueOf(cdxBom).Elem(), "", "")
+	// Required values cannot be invented; the BOM would not be valid for the target version
+	if len(migrator.missing) > 0 {
+		err = fmt.Errorf("unable to migrate BOM to spec. version `%s`: required field(s) missing: %s",
+			targetVersion, strings.Join(migrator.missing, ", "))
+		return
+	}
+	cdxBom.SpecVersion = targetVersion
+	report = migrator.report
+	return
+}
+
+// Normalizes all (generically unmarshalled) "tools" values to either the (legacy) tools
+// array or the (v1.5) tools object form supported by the target version
+func (migrator *specVersionMigrator) migrateTools() (err error) {
+	cdxBom := migrator.cdxBom
+	if cdxBom.Metadata != nil {
+		if cdxBom.Metadata.Tools, err = migrator.migrateToolsValue(cdxBom.Metadata.Tools, "metadata.tools"); err != nil {
+			return
+		}
+	}
+	if cdxBom.Vulnerabilities != nil {
+		for i := range *cdxBom.Vulnerabilities {
+			vulnerability := &(*cdxBom.Vulnerabilities)[i]
+			location := fmt.Sprintf("vulnerabilities[%v].tools", i)
+			if vulnerability.Tools, err = migrator.migrateToolsValue(vulnerability.Tools, location); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (migrator *specVersionMigrator) migrateToolsValue(tools interface{}, location string) (migrated interface{}, err error) {
+	if tools == nil {
+		return
+	}
+	var bytes []byte
+	if bytes, err = json.Marshal(tools); err != nil {
+		return
+	}
+
+	if IsInterfaceASlice(tools) {
+		var legacyTools []CDXLegacyCreationTool
+		if err = json.Unmarshal(bytes, &legacyTools); err != nil {
+			return
+		}
+		if len(legacyTools) == 0 {
+			return
+		}
+		// The (legacy) tools array is deprecated as of v1.5; migrate to the tools object form
+		if migrator.targetMinor >= CDX_MINOR_VERSION_TOOLS_OBJECT {
+			migrated = migrator.upgradeLegacyTools(legacyTools, location)
+			return
+		}
+		migrated = legacyTools
+		return
+	}
+
+	var creationTools CDXCreationTools
+	if err = json.Unmarshal(bytes, &creationTools); err != nil {
+		return
+	}
+	// The tools object form was added in v1.5; convert to the (legacy) tools array
+	if migrator.targetMinor < CDX_MINOR_VERSION_TOOLS_OBJECT {
+		migrated = migrator.downgradeCreationTools(creationTools, location)
+		return
+	}
+	migrated = creationTools
+	return
+}
+
+func (migrator *specVersionMigrator) upgradeLegacyTools(legacyTools []CDXLegacyCreationTool, location string) (creationTools CDXCreationTools) {
+	var components []CDXComponent
+	for _, tool := range legacyTools {
+		components = append(components, CDXComponent{
+			Type:               COMPONENT_TYPE_APPLICATION,
+			Group:              tool.Vendor,
+			Name:               tool.Name,
+			Version:            tool.Version,
+			Hashes:             tool.Hashes,
+			ExternalReferences: tool.ExternalReferences,
+		})
+	}
+	creationTools.Components = &components
+	migrator.report.addLossy(location, "", "tools", MIGRATE_REASON_MIGRATED)
+	return
+}
+
+func (migrator *specVersionMigrator) downgradeCreationTools(creationTools CDXCreationTools, location string) (legacyTools []CDXLegacyCreationTool) {
+	report := migrator.report
+	if creationTools.Components != nil {
+		for i, component := range *creationTools.Components {
+			toolLocation := fmt.Sprintf("%s.components[%v]", location, i)
+			vendor := component.Group
+			if vendor == "" && component.Supplier != nil {
+				vendor = component.Supplier.Name
+			}
+			legacyTools = append(legacyTools, CDXLegacyCreationTool{
+				Vendor:             vendor,
+				Name:               component.Name,
+				Version:            component.Version,
+				Hashes:             component.Hashes,
+				ExternalReferences: component.ExternalReferences,
+			})
+			for _, key := range unmappedKeys(component, mapMigrateLegacyToolComponentKeys) {
+				if key != "supplier" || component.Group != "" {
+					report.addLossy(toolLocation, getCDXRefString(component.BOMRef), key, MIGRATE_REASON_UNSUPPORTED)
+				}
+			}
+		}
+	}
+	if creationTools.Services != nil {
+		for i, service := range *creationTools.Services {
+			toolLocation := fmt.Sprintf("%s.services[%v]", location, i)
+			var vendor string
+			if service.Provider != nil {
+				vendor = service.Provider.Name
+			}
+			legacyTools = append(legacyTools, CDXLegacyCreationTool{
+				Vendor:             vendor,
+				Name:               service.Name,
+				Version:            service.Version,
+				ExternalReferences: service.ExternalReferences,
+			})
+			for _, key := range unmappedKeys(service, mapMigrateLegacyToolServiceKeys) {
+				report.addLossy(toolLocation, getCDXRefString(service.BOMRef), key, MIGRATE_REASON_UNSUPPORTED)
+			}
+		}
+	}
+	return
+}
+
+// Recursively walks all (exported) fields of the value using their `cdx` struct tags to
+// remove fields not supported by the target version; (known) types with enum. values or
+// deprecated constructs are migrated as they are found.
+func (migrator *specVersionMigrator) migrateValue(value reflect.Value, location string, ref string) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			migrator.migrateValue(value.Elem(), location, ref)
+		}
+	case reflect.Interface:
+		if value.IsNil() || !value.CanSet() {
+			return
+		}
+		// Note: values held by interfaces are not addressable; migrate (and set) a copy
+		elem := value.Elem()
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(elem)
+		migrator.migrateValue(copied, location, ref)
+		value.Set(copied)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			migrator.migrateValue(value.Index(i), fmt.Sprintf("%s[%v]", location, i), ref)
+		}
+	case reflect.Struct:
+		if value.CanAddr() {
+			ref = migrator.migrateStruct(value.Addr().Interface(), location, ref)
+		}
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			structField := valueType.Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+			field := value.Field(i)
+			// Embedded structs share the JSON (object) location of their parent
+			if structField.Anonymous {
+				migrator.migrateValue(field, location, ref)
+				continue
+			}
+			name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = structField.Name
+			}
+
+			if field.IsZero() {
+				continue
+			}
+			tagInfo := parseCDXTag(structField.Tag.Get(CDX_TAG_KEY))
+			if tagInfo.addedMinor > migrator.targetMinor && field.CanSet() {
+				migrator.report.addLossy(getMigrateLocation(location), ref, name, MIGRATE_REASON_UNSUPPORTED)
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+			if tagInfo.deprecated && tagInfo.deprecatedMinor >= 0 && tagInfo.deprecatedMinor <= migrator.targetMinor {
+				migrator.report.addLossy(getMigrateLocation(location), ref, name, MIGRATE_REASON_DEPRECATED)
+			}
+			migrator.migrateValue(field, joinMigrateLocation(location, name), ref)
+		}
+	}
+}
+
+// Migrates enum. values and deprecated constructs of known types; returns the
+// "bom-ref" (if any) used to identify changes to the value (and its children)
+func (migrator *specVersionMigrator) migrateStruct(value interface{}, location string, ref string) string {
+	report := migrator.report
+	switch typed := value.(type) {
+	case *CDXComponent:
+		if typed.BOMRef != nil && *typed.BOMRef != "" {
+			ref = typed.BOMRef.String()
+		}
+		typed.Type = migrator.migrateEnumValue(typed.Type, mapCDXComponentTypeMigrations, location, ref, "type")
+		// Note: migrating "modified" clears the field (before it is walked)
+		if typed.Modified && migrator.targetMinor >= CDX_MINOR_VERSION_MODIFIED_DEPRECATED {
+			if typed.Pedigree == nil {
+				typed.Pedigree = new(CDXPedigree)
+			}
+			if typed.Pedigree.Notes == "" {
+				typed.Pedigree.Notes = MIGRATE_MODIFIED_PEDIGREE_NOTES
+			} else {
+				typed.Pedigree.Notes = fmt.Sprintf("%s %s", typed.Pedigree.Notes, MIGRATE_MODIFIED_PEDIGREE_NOTES)
+			}
+			typed.Modified = false
+			report.addLossy(location, ref, "modified", MIGRATE_REASON_MIGRATED)
+		}
+		if typed.Version == "" && migrator.targetMinor < CDX_MINOR_VERSION_COMPONENT_VERSION {
+			migrator.missing = append(migrator.missing, fmt.Sprintf("`%s` (`%s`)", joinMigrateLocation(location, "version"), ref))
+		}
+	case *CDXService:
+		if typed.BOMRef != nil && *typed.BOMRef != "" {
+			ref = typed.BOMRef.String()
+		}
+	case *CDXVulnerability:
+		if typed.Id != "" {
+			ref = typed.Id
+		}
+	case *CDXExternalReference:
+		typed.Type = migrator.migrateEnumValue(typed.Type, mapCDXExternalReferenceTypeMigrations, location, ref, "type")
+	case *CDXRating:
+		typed.Method = migrator.migrateEnumValue(typed.Method, mapCDXRatingMethodMigrations, location, ref, "method")
+	case *CDXCompositions:
+		typed.Aggregate = migrator.migrateEnumValue(typed.Aggregate, mapCDXAggregateMigrations, location, ref, "aggregate")
+	}
+	return ref
+}
+
+func (migrator *specVersionMigrator) migrateEnumValue(value string, migrations map[string]cdxEnumValueMigration, location string, ref string, field string) string {
+	if migration, found := migrations[value]; found && migration.minor > migrator.targetMinor {
+		migrator.report.addLossy(location, ref, field, MIGRATE_REASON_VALUE)
+		return migration.replacement
+	}
+	return value
+}
+
+// Returns the location used to report changes to fields of the (root) BOM object
+func getMigrateLocation(location string) string {
+	if location == "" {
+		return CONVERT_LOCATION_ROOT
+	}
+	return location
+}
+
+func joinMigrateLocation(location string, name string) string {
+	if location == "" {
+		return name
+	}
+	return location + "." + name
+}
+
+func getCDXRefString(pRef *CDXRefType) string {
+	if pRef == nil {
+		return ""
+	}
+	return pRef.String()
+}
@@ -26,6 +26,18 @@ const (
 	KEY_SERVICES    = "services"
 )
 
+// Component "type" (enum) values
+const (
+	COMPONENT_TYPE_APPLICATION      = "application"
+	COMPONENT_TYPE_FRAMEWORK        = "framework"
+	COMPONENT_TYPE_LIBRARY          = "library"
+	COMPONENT_TYPE_CONTAINER        = "container"
+	COMPONENT_TYPE_OPERATING_SYSTEM = "operating-system"
+	COMPONENT_TYPE_DEVICE           = "device"
+	COMPONENT_TYPE_FIRMWARE         = "firmware"
+	COMPONENT_TYPE_FILE             = "file"
+)
+
 // Note: CycloneDX v1.2, 1.3, 1.4, 1.5 schema properties are currently supported
 // TODO: make ALL struct pointer references for (future) editing needs
 
@@ -40,19 +52,19 @@ var EMPTY_CDXLicense = CDXLicense{}
 type CDXBom struct {
 	BOMFormat          string                  `json:"bomFormat,omitempty"`
 	SpecVersion        string                  `json:"specVersion,omitempty"`
-	SerialNumber       string                  `json:"serialNumber,omitempty"`
+	SerialNumber       string                  `json:"serialNumber,omitempty" scvs:"bom:core:identifier"`
 	Version            int                     `json:"version,omitempty"`
 	Metadata           *CDXMetadata            `json:"metadata,omitempty"`
 	Components         *[]CDXComponent         `json:"components,omitempty"`
 	Services           *[]CDXService           `json:"services,omitempty"`
 	ExternalReferences *[]CDXExternalReference `json:"externalReferences,omitempty"`
-	Dependencies       *[]CDXDependency        `json:"dependencies,omitempty"`
-	Compositions       *[]CDXCompositions      `json:"compositions,omitempty" cdx:"+1.3"`    // v1.3 added
-	Vulnerabilities    *[]CDXVulnerability     `json:"vulnerabilities,omitempty" cdx:"+1.4"` // v1.4 added
-	Signature          *JSFSignature           `json:"signature,omitempty" cdx:"+1.4"`       // v1.4 added
-	Annotations        *[]CDXAnnotation        `json:"annotations,omitempty" cdx:"+1.5"`     // v1.5 added
-	Formulation        *[]CDXFormula           `json:"formulation,omitempty" cdx:"+1.5"`     // v1.5 added
-	Properties         *[]CDXProperty          `json:"properties,omitempty" cdx:"+1.5"`      // v1.5 added
+	Dependencies       *[]CDXDependency        `json:"dependencies,omitempty" scvs:"bom:core:dependencies"`
+	Compositions       *[]CDXCompositions      `json:"compositions,omitempty" cdx:"+1.3"`                        // v1.3 added
+	Vulnerabilities    *[]CDXVulnerability     `json:"vulnerabilities,omitempty" cdx:"+1.4"`                     // v1.4 added
+	Signature          *JSFSignature           `json:"signature,omitempty" cdx:"+1.4" scvs:"bom:core:signature"` // v1.4 added
+	Annotations        *[]CDXAnnotation        `json:"annotations,omitempty" cdx:"+1.5"`                         // v1.5 added
+	Formulation        *[]CDXFormula           `json:"formulation,omitempty" cdx:"+1.5"`                         // v1.5 added
+	Properties         *[]CDXProperty          `json:"properties,omitempty" cdx:"+1.5"`                          // v1.5 added
 }
 
 // v1.2: existed
@@ -61,13 +73,13 @@ type CDXBom struct {
 type CDXMetadata struct {
 	Timestamp    string                      `json:"timestamp,omitempty" scvs:"bom:core:timestamp"` // urn:owasp:scvs:bom:core:timestamp
 	Tools        interface{}                 `json:"tools,omitempty"`                               // v1.2: added.v1.5: "tools" is now an interface{}
-	Authors      *[]CDXOrganizationalContact `json:"authors,omitempty"`
+	Authors      *[]CDXOrganizationalContact `json:"authors,omitempty" scvs:"bom:core:authors"`
 	Component    *CDXComponent               `json:"component,omitempty"`
 	Manufacturer *CDXOrganizationalEntity    `json:"manufacture,omitempty"` // NOTE: Typo is in spec.
 	Supplier     *CDXOrganizationalEntity    `json:"supplier,omitempty"`
-	Licenses     *[]CDXLicenseChoice         `json:"licenses,omitempty"`   // v1.3 added
-	Properties   *[]CDXProperty              `json:"properties,omitempty"` // v1.3 added
-	Lifecycles   *[]CDXLifecycle             `json:"lifecycles,omitempty"` // v1.5 added
+	Licenses     *[]CDXLicenseChoice         `json:"licenses,omitempty" cdx:"+1.3"`   // v1.3 added
+	Properties   *[]CDXProperty              `json:"properties,omitempty" cdx:"+1.3"` // v1.3 added
+	Lifecycles   *[]CDXLifecycle             `json:"lifecycles,omitempty" cdx:"+1.5"` // v1.5 added
 }
 
 // v1.2: existed
@@ -85,30 +97,30 @@ type CDXComponent struct {
 	Type               string                   `json:"type,omitempty"` // Constraint: enum [see schema]
 	MimeType           string                   `json:"mime-type,omitempty"`
 	BOMRef             *CDXRefType              `json:"bom-ref,omitempty"`
-	Supplier           *CDXOrganizationalEntity `json:"supplier,omitempty"`
+	Supplier           *CDXOrganizationalEntity `json:"supplier,omitempty" scvs:"bom:resource:supplier"`
 	Author             string                   `json:"author,omitempty"`
 	Publisher          string                   `json:"publisher,omitempty"`
 	Group              string                   `json:"group,omitempty"`
-	Name               string                   `json:"name,omitempty"`
-	Version            string                   `json:"version,omitempty"`
+	Name               string                   `json:"name,omitempty" scvs:"bom:resource:name"`
+	Version            string                   `json:"version,omitempty" scvs:"bom:resource:version"`
 	Description        string                   `json:"description,omitempty"`
 	Scope              string                   `json:"scope,omitempty"` // Constraint: "enum": ["required","optional","excluded"]
-	Hashes             *[]CDXHash               `json:"hashes,omitempty"`
-	Licenses           *[]CDXLicenseChoice      `json:"licenses,omitempty"`
-	Copyright          string                   `json:"copyright,omitempty"`
-	Cpe                string                   `json:"cpe,omitempty"`                                       // See: https://nvd.nist.gov/products/cpe
+	Hashes             *[]CDXHash               `json:"hashes,omitempty" scvs:"bom:resource:hashes"`
+	Licenses           *[]CDXLicenseChoice      `json:"licenses,omitempty" scvs:"bom:resource:licenses"`
+	Copyright          string                   `json:"copyright,omitempty" scvs:"bom:resource:copyright"`
+	Cpe                string                   `json:"cpe,omitempty" scvs:"bom:resource:identifiers:cpe"`   // See: https://nvd.nist.gov/products/cpe
 	Purl               string                   `json:"purl,omitempty" scvs:"bom:resource:identifiers:purl"` // See: https://github.com/package-url/purl-spec
-	Swid               *CDXSwid                 `json:"swid,omitempty"`                                      // See: https://www.iso.org/standard/65666.html
+	Swid               *CDXSwid                 `json:"swid,omitempty" scvs:"bom:resource:identifiers:swid"` // See: https://www.iso.org/standard/65666.html
 	Pedigree           *CDXPedigree             `json:"pedigree,omitempty"`                                  // anon. type
 	ExternalReferences *[]CDXExternalReference  `json:"externalReferences,omitempty"`
 	Components         *[]CDXComponent          `json:"components,omitempty"`
-	Evidence           *CDXComponentEvidence    `json:"evidence,omitempty"`                  // v1.3: added
-	Properties         *[]CDXProperty           `json:"properties,omitempty"`                // v1.3: added
-	Modified           bool                     `json:"modified,omitempty" cdx:"deprecated"` // v1.4: deprecated
-	ReleaseNotes       *[]CDXReleaseNotes       `json:"releaseNotes,omitempty"`              // v1.4: added
-	Signature          *JSFSignature            `json:"signature,omitempty"`                 // v1.4: added
-	ModelCard          *CDXModelCard            `json:"modelCard,omitempty"`                 // v1.5: added
-	Data               *[]CDXComponentData      `json:"data,omitempty"`                      // v1.5: added
+	Evidence           *CDXComponentEvidence    `json:"evidence,omitempty" cdx:"+1.3"`           // v1.3: added
+	Properties         *[]CDXProperty           `json:"properties,omitempty" cdx:"+1.3"`         // v1.3: added
+	Modified           bool                     `json:"modified,omitempty" cdx:"deprecated:1.4"` // v1.4: deprecated
+	ReleaseNotes       *[]CDXReleaseNotes       `json:"releaseNotes,omitempty" cdx:"+1.4"`       // v1.4: added
+	Signature          *JSFSignature            `json:"signature,omitempty" cdx:"+1.4"`          // v1.4: added
+	ModelCard          *CDXModelCard            `json:"modelCard,omitempty" cdx:"+1.5"`          // v1.5: added
+	Data               *[]CDXComponentData      `json:"data,omitempty" cdx:"+1.5"`               // v1.5: added
 }
 
 // v1.5 added
@@ -170,14 +182,14 @@ type CDXService struct {
 	Endpoints          *[]string                `json:"endpoints,omitempty"`
 	Authenticated      bool                     `json:"authenticated,omitempty"`
 	XTrustBoundary     bool                     `json:"x-trust-boundary,omitempty"`
-	TrustZone          string                   `json:"trustZone,omitempty"`
+	TrustZone          string                   `json:"trustZone,omitempty" cdx:"+1.5"`
 	Data               *[]CDXServiceData        `json:"data,omitempty"`
 	Licenses           *[]CDXLicenseChoice      `json:"licenses,omitempty"`
 	ExternalReferences *[]CDXExternalReference  `json:"externalReferences,omitempty"`
 	Services           *[]CDXService            `json:"services,omitempty"`
-	Properties         *[]CDXProperty           `json:"properties,omitempty"`   // v1.3: added
-	ReleaseNotes       *[]CDXReleaseNotes       `json:"releaseNotes,omitempty"` // v1.4: added
-	Signature          *JSFSignature            `json:"signature,omitempty"`    // v1.4: added
+	Properties         *[]CDXProperty           `json:"properties,omitempty" cdx:"+1.3"`   // v1.3: added
+	ReleaseNotes       *[]CDXReleaseNotes       `json:"releaseNotes,omitempty" cdx:"+1.4"` // v1.4: added
+	Signature          *JSFSignature            `json:"signature,omitempty" cdx:"+1.4"`    // v1.4: added
 }
 
 // v1.5: added. aggregated related date from v1.2-v1.4 and added additional fields
@@ -187,11 +199,11 @@ type CDXService struct {
 type CDXServiceData struct {
 	Flow           string                 `json:"externalReferences,omitempty"`
 	Classification *CDXDataClassification `json:"classification,omitempty"`
-	Name           string                 `json:"name,omitempty"`        // v1.5: added
-	Description    string                 `json:"description,omitempty"` // v1.5: added
-	Governance     *CDXDataGovernance     `json:"governance,omitempty"`  // v1.5: added
-	Source         string                 `json:"source,omitempty"`      // v1.5: added
-	Destination    string                 `json:"destination,omitempty"` // v1.5: added
+	Name           string                 `json:"name,omitempty" cdx:"+1.5"`        // v1.5: added
+	Description    string                 `json:"description,omitempty" cdx:"+1.5"` // v1.5: added
+	Governance     *CDXDataGovernance     `json:"governance,omitempty" cdx:"+1.5"`  // v1.5: added
+	Source         string                 `json:"source,omitempty" cdx:"+1.5"`      // v1.5: added
+	Destination    string                 `json:"destination,omitempty" cdx:"+1.5"` // v1.5: added
 }
 
 // v1.2: existed as an anon. type in the "component" type defn.
@@ -246,9 +258,9 @@ type CDXLicense struct {
 	Name       string         `json:"name,omitempty"`
 	Text       *CDXAttachment `json:"text,omitempty"`
 	Url        string         `json:"url,omitempty"`
-	BOMRef     *CDXRefType    `json:"bom-ref,omitempty"`    // v1.5: added
-	Licensing  *CDXLicensing  `json:"licensing,omitempty"`  // v1.5: added
-	Properties *[]CDXProperty `json:"properties,omitempty"` // v1.5: added
+	BOMRef     *CDXRefType    `json:"bom-ref,omitempty" cdx:"+1.5"`    // v1.5: added
+	Licensing  *CDXLicensing  `json:"licensing,omitempty" cdx:"+1.5"`  // v1.5: added
+	Properties *[]CDXProperty `json:"properties,omitempty" cdx:"+1.5"` // v1.5: added
 }
 
 // v1.5: added
@@ -325,7 +337,7 @@ type CDXExternalReference struct {
 	Url     string     `json:"url,omitempty"`
 	Comment string     `json:"comment,omitempty"`
 	Type    string     `json:"type,omitempty"`
-	Hashes  *[]CDXHash `json:"hashes,omitempty"` // v1.3: added
+	Hashes  *[]CDXHash `json:"hashes,omitempty" cdx:"+1.3"` // v1.3: added
 }
 
 // v1.2: existed
@@ -371,9 +383,9 @@ type CDXCompositions struct {
 	Aggregate       string              `json:"aggregate,omitempty"`
 	Assemblies      *[]string           `json:"assemblies,omitempty"`
 	Dependencies    *[]string           `json:"dependencies,omitempty"`
-	Signature       *JSFSignature       `json:"signature,omitempty"`       // v1.4: added
-	Vulnerabilities *[]CDXVulnerability `json:"vulnerabilities,omitempty"` // v1.5: added
-	BOMRef          *CDXRefType         `json:"bom-ref,omitempty"`         // v1.5: added
+	Signature       *JSFSignature       `json:"signature,omitempty" cdx:"+1.4"`       // v1.4: added
+	Vulnerabilities *[]CDXVulnerability `json:"vulnerabilities,omitempty" cdx:"+1.5"` // v1.5: added
+	BOMRef          *CDXRefType         `json:"bom-ref,omitempty" cdx:"+1.5"`         // v1.5: added
 }
 
 // v1.4: created "releaseNotes" defn.
@@ -123,6 +123,10 @@ const (
 	LC_LOC_METADATA
 	LC_LOC_COMPONENTS
 	LC_LOC_SERVICES
+	LC_LOC_SPDX_PACKAGES_CONCLUDED
+	LC_LOC_SPDX_PACKAGES_DECLARED
+	LC_LOC_SPDX_FILES
+	LC_LOC_SPDX_SNIPPETS
 )
 
 var mapLicenseLocationNames = map[int]string{
@@ -131,6 +135,11 @@ var mapLicenseLocationNames = map[int]string{
 	LC_LOC_METADATA:           "metadata.licenses",
 	LC_LOC_COMPONENTS:         "components",
 	LC_LOC_SERVICES:           "services",
+	// SPDX locations
+	LC_LOC_SPDX_PACKAGES_CONCLUDED: "packages.licenseConcluded",
+	LC_LOC_SPDX_PACKAGES_DECLARED:  "packages.licenseDeclared",
+	LC_LOC_SPDX_FILES:              "files.licenseConcluded",
+	LC_LOC_SPDX_SNIPPETS:           "snippets.licenseConcluded",
 }
 
 // Note: the "License" property is used as hashmap key
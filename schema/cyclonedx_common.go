@@ -95,7 +95,7 @@ type CDXOrganizationalEntity struct {
 	Name    string                      `json:"name,omitempty"`
 	Url     []string                    `json:"url,omitempty"`
 	Contact *[]CDXOrganizationalContact `json:"contact,omitempty"`
-	BOMRef  *CDXRefType                 `json:"bom-ref,omitempty"` // v1.5 added
+	BOMRef  *CDXRefType                 `json:"bom-ref,omitempty" cdx:"+1.5"` // v1.5 added
 }
 
 // v1.2: existed
@@ -105,7 +105,7 @@ type CDXOrganizationalContact struct {
 	Name   string      `json:"name,omitempty"`
 	Email  string      `json:"email,omitempty"`
 	Phone  string      `json:"phone,omitempty"`
-	BOMRef *CDXRefType `json:"bom-ref,omitempty"` // v1.5 added
+	BOMRef *CDXRefType `json:"bom-ref,omitempty" cdx:"+1.5"` // v1.5 added
 }
 
 // v1.3: created "property" defn.
@@ -128,11 +128,11 @@ type CDXLicenseLegalParty struct {
 // - v1.5: In order to support the new object "Creation Tools", we need to combine these fields
 // into with the legacy structure fields
 type CDXLegacyCreationTool struct {
-	Vendor             string                  `json:"vendor,omitempty" cdx:"deprecated"`       // v1.5: deprecated
-	Name               string                  `json:"name,omitempty" cdx:"deprecated"`         // v1.5: deprecated
-	Version            string                  `json:"version,omitempty" cdx:"deprecated"`      // v1.5: deprecated
-	Hashes             *[]CDXHash              `json:"hashes,omitempty" cdx:"deprecated"`       // v1.5: deprecated
-	ExternalReferences *[]CDXExternalReference `json:"externalReferences,omitempty" cdx:"+1.4"` // v1.4: added, v1.5: deprecated
+	Vendor             string                  `json:"vendor,omitempty" cdx:"deprecated:1.5"`                  // v1.5: deprecated
+	Name               string                  `json:"name,omitempty" cdx:"deprecated:1.5"`                    // v1.5: deprecated
+	Version            string                  `json:"version,omitempty" cdx:"deprecated:1.5"`                 // v1.5: deprecated
+	Hashes             *[]CDXHash              `json:"hashes,omitempty" cdx:"deprecated:1.5"`                  // v1.5: deprecated
+	ExternalReferences *[]CDXExternalReference `json:"externalReferences,omitempty" cdx:"+1.4,deprecated:1.5"` // v1.4: added, v1.5: deprecated
 }
 
 // v1.5: created. Intended to be used instead of (legacy) Creation Tools which was deprecated
@@ -171,7 +171,7 @@ type CDXOutputType struct {
 // v1.5: Note: "ref" is a constrained "string" which can be "anyOf": ["#/definitions/refLinkType", "#/definitions/bomLinkElementType"]
 // TODO: actually, "Ref" should be its own anonymous type with "anyOf": ["#/definitions/refLinkType", "#/definitions/bomLinkElementType"]
 type CDXResourceReferenceChoice struct {
-	Ref               CDXRefLinkType       `json:"description,omitempty"`       // v1.5
+	Ref               CDXRefLinkType       `json:"ref,omitempty"`               // v1.5
 	ExternalReference CDXExternalReference `json:"externalReference,omitempty"` // v1.5
 }
 
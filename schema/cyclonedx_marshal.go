@@ -278,6 +278,9 @@ func (value *CDXCredit) MarshalJSON() ([]byte, error) {
 
 func (value *CDXAffect) MarshalJSON() ([]byte, error) {
 	temp := map[string]interface{}{}
+	if value.Ref != nil && *value.Ref != "" {
+		temp["ref"] = value.Ref
+	}
 	if value.Versions != nil && len(*value.Versions) > 0 {
 		temp["versions"] = value.Versions
 	}
@@ -24,27 +24,27 @@ package schema
 // Note: "cwes" is a array of "cwe" which is a constrained `int`
 // NOTE: CDXRefType is a named `string` type as of v1.5
 type CDXVulnerability struct {
-	BOMRef         *CDXRefType                  `json:"bom-ref,omitempty"`        // v1.4
-	Id             string                       `json:"id,omitempty"`             // v1.4
-	Source         *CDXVulnerabilitySource      `json:"source,omitempty"`         // v1.4
-	References     *[]CDXVulnerabilityReference `json:"references"`               // v1.4: anon. type
-	Ratings        *[]CDXRating                 `json:"ratings,omitempty"`        // v1.4
-	Cwes           *[]int                       `json:"cwes,omitempty"`           // v1.4
-	Description    string                       `json:"description,omitempty"`    // v1.4
-	Detail         string                       `json:"detail,omitempty"`         // v1.4
-	Recommendation string                       `json:"recommendation,omitempty"` // v1.4
-	Advisories     *[]CDXAdvisory               `json:"advisories,omitempty"`     // v1.4
-	Created        string                       `json:"created,omitempty"`        // v1.4
-	Published      string                       `json:"published,omitempty"`      // v1.4
-	Updated        string                       `json:"updated,omitempty"`        // v1.4
-	Credits        *CDXCredit                   `json:"credits,omitempty"`        // v1.4: anon. type
-	Tools          interface{}                  `json:"tools,omitempty"`          // v1.4: added; v1.5: changed to interface{}
-	Analysis       *CDXAnalysis                 `json:"analysis,omitempty"`       // v1.4: anon. type
-	Affects        *[]CDXAffect                 `json:"affects,omitempty"`        // v1.4: anon. type
-	Properties     *[]CDXProperty               `json:"properties,omitempty"`     // v1.4: added
-	Workaround     string                       `json:"workaround,omitempty"`     // v1.5: added
-	ProofOfConcept *CDXProofOfConcept           `json:"proofOfConcept,omitempty"` // v1.5: added
-	Rejected       string                       `json:"rejected,omitempty"`       // v1.5: added
+	BOMRef         *CDXRefType                  `json:"bom-ref,omitempty"`                   // v1.4
+	Id             string                       `json:"id,omitempty"`                        // v1.4
+	Source         *CDXVulnerabilitySource      `json:"source,omitempty"`                    // v1.4
+	References     *[]CDXVulnerabilityReference `json:"references"`                          // v1.4: anon. type
+	Ratings        *[]CDXRating                 `json:"ratings,omitempty"`                   // v1.4
+	Cwes           *[]int                       `json:"cwes,omitempty"`                      // v1.4
+	Description    string                       `json:"description,omitempty"`               // v1.4
+	Detail         string                       `json:"detail,omitempty"`                    // v1.4
+	Recommendation string                       `json:"recommendation,omitempty"`            // v1.4
+	Advisories     *[]CDXAdvisory               `json:"advisories,omitempty"`                // v1.4
+	Created        string                       `json:"created,omitempty"`                   // v1.4
+	Published      string                       `json:"published,omitempty"`                 // v1.4
+	Updated        string                       `json:"updated,omitempty"`                   // v1.4
+	Credits        *CDXCredit                   `json:"credits,omitempty"`                   // v1.4: anon. type
+	Tools          interface{}                  `json:"tools,omitempty"`                     // v1.4: added; v1.5: changed to interface{}
+	Analysis       *CDXAnalysis                 `json:"analysis,omitempty"`                  // v1.4: anon. type
+	Affects        *[]CDXAffect                 `json:"affects,omitempty"`                   // v1.4: anon. type
+	Properties     *[]CDXProperty               `json:"properties,omitempty"`                // v1.4: added
+	Workaround     string                       `json:"workaround,omitempty" cdx:"+1.5"`     // v1.5: added
+	ProofOfConcept *CDXProofOfConcept           `json:"proofOfConcept,omitempty" cdx:"+1.5"` // v1.5: added
+	Rejected       string                       `json:"rejected,omitempty" cdx:"+1.5"`       // v1.5: added
 }
 
 // v1.4 This is an anonymous type used in CDXVulnerability
@@ -94,12 +94,12 @@ type CDXCredit struct {
 // Note: "justification" is an "impactAnalysisJustification" type which is a constrained enum. of type `string`
 // TODO: "response" is also "in-lined" as a constrained enum. of `string`, but SHOULD be declared at top-level
 type CDXAnalysis struct {
-	State         string    `json:"state,omitempty"`         // v1.4
-	Justification string    `json:"justification,omitempty"` // v1.4
-	Response      *[]string `json:"response,omitempty"`      // v1.4: anon. type
-	Detail        string    `json:"detail,omitempty"`        // v1.4
-	FirstIssued   string    `json:"firstIssued,omitempty"`   // v1.5: added
-	LastUpdated   string    `json:"lastUpdated,omitempty"`   // v1.5: added
+	State         string    `json:"state,omitempty"`                  // v1.4
+	Justification string    `json:"justification,omitempty"`          // v1.4
+	Response      *[]string `json:"response,omitempty"`               // v1.4: anon. type
+	Detail        string    `json:"detail,omitempty"`                 // v1.4
+	FirstIssued   string    `json:"firstIssued,omitempty" cdx:"+1.5"` // v1.5: added
+	LastUpdated   string    `json:"lastUpdated,omitempty" cdx:"+1.5"` // v1.5: added
 }
 
 // v1.4: created "analysis" def. to represent an in-line, anon. type
@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// PEM (private key) block types
+const (
+	PEM_TYPE_PRIVATE_KEY     = "PRIVATE KEY"     // PKCS #8
+	PEM_TYPE_RSA_PRIVATE_KEY = "RSA PRIVATE KEY" // PKCS #1
+	PEM_TYPE_EC_PRIVATE_KEY  = "EC PRIVATE KEY"  // SEC 1
+)
+
+// Signing messages
+const (
+	MSG_JSF_CERTIFICATE_KEY_MISMATCH = "signature certificate (%s) does not match the private key"
+	MSG_JSF_EXCLUDES_CONFLICT        = "excludes `%v` do not match the excludes of the existing signers: `%v`"
+	MSG_JSF_PRIVATE_KEY_MISMATCH     = "private key type `%T` cannot be used with algorithm `%s`"
+	MSG_JSF_PRIVATE_KEY_UNSUPPORTED  = "private key type not supported: `%T`"
+	MSG_JSF_SIGNATURE_TYPE_CONFLICT  = "object already has a signature of a different type; expected: `%s`"
+	MSG_JSF_SIGNATURE_TYPE_INVALID   = "invalid signature type: `%s`"
+	MSG_JSF_SIGNERS_MISSING          = "no signing keys provided"
+	MSG_JSF_SIGNERS_MULTIPLE         = "a simple signature supports only one signer; use `%s` or `%s` for multiple signers"
+)
+
+// A private key (and the signer properties it is used with) used to create a JSF signature
+// Note: if a "CertificatePath" is provided, it is used instead of the (embedded) public key
+type JSFSigningKey struct {
+	Signer          crypto.Signer
+	Algorithm       string
+	KeyId           string
+	EmbedPublicKey  bool
+	CertificatePath []string
+}
+
+// Returns the default JWA algorithm for the private key (i.e., based upon its type and curve)
+func DefaultJSFAlgorithm(signer crypto.Signer) (algorithm string, err error) {
+	switch key := signer.Public().(type) {
+	case *rsa.PublicKey:
+		return JSF_ALGORITHM_RS256, nil
+	case *ecdsa.PublicKey:
+		for alg, curve := range jsfAlgorithmCurves {
+			if key.Curve == curve {
+				return alg, nil
+			}
+		}
+	case ed25519.PublicKey:
+		return JSF_ALGORITHM_ED25519, nil
+	}
+	return "", fmt.Errorf(MSG_JSF_PRIVATE_KEY_UNSUPPORTED, signer)
+}
+
+// Creates the signature(s) for each signing key and writes them into the object's "signature".
+// Signers are appended to any existing "signers" or "chain" (of the same type); a simple
+// signature replaces any existing simple signature. The (optional) excludes are set on the
+// simple signature or, for "signers" and "chain", on the (outer) signature object as a global
+// option; these must match the excludes of any existing signers.
+func SignJSFObject(object map[string]interface{}, signatureType string, signingKeys []JSFSigningKey, excludes []string) (err error) {
+	if len(signingKeys) == 0 {
+		return fmt.Errorf(MSG_JSF_SIGNERS_MISSING)
+	}
+
+	// Note: excludes are held as they are read (i.e., as a JSON array)
+	var jsonExcludes []interface{}
+	for _, exclude := range excludes {
+		jsonExcludes = append(jsonExcludes, exclude)
+	}
+
+	signature := make(map[string]interface{})
+	var signers []interface{}
+	switch signatureType {
+	case JSF_SIGNATURE_TYPE_SIGNATURE:
+		if len(signingKeys) > 1 {
+			return fmt.Errorf(MSG_JSF_SIGNERS_MULTIPLE, JSF_SIGNATURE_TYPE_SIGNERS, JSF_SIGNATURE_TYPE_CHAIN)
+		}
+	case JSF_SIGNATURE_TYPE_SIGNERS, JSF_SIGNATURE_TYPE_CHAIN:
+		if existing, found := object[JSF_KEY_SIGNATURE].(map[string]interface{}); found {
+			existingType, existingSigners := jsfSigners(existing)
+			if existingType != signatureType {
+				return fmt.Errorf(MSG_JSF_SIGNATURE_TYPE_CONFLICT, signatureType)
+			}
+			// Note: global options (e.g., "excludes") of the existing signature are kept
+			for key, value := range existing {
+				if key != JSF_KEY_SIGNERS && key != JSF_KEY_CHAIN {
+					signature[key] = value
+				}
+			}
+			signers = append(signers, existingSigners...)
+		}
+		if len(jsonExcludes) > 0 {
+			if len(signers) > 0 && fmt.Sprint(signature[JSF_KEY_EXCLUDES]) != fmt.Sprint(jsonExcludes) {
+				return fmt.Errorf(MSG_JSF_EXCLUDES_CONFLICT, excludes, signature[JSF_KEY_EXCLUDES])
+			}
+			signature[JSF_KEY_EXCLUDES] = jsonExcludes
+		}
+	default:
+		return fmt.Errorf(MSG_JSF_SIGNATURE_TYPE_INVALID, signatureType)
+	}
+
+	for _, signingKey := range signingKeys {
+		var signer map[string]interface{}
+		if signer, err = newJSFSignerObject(signingKey); err != nil {
+			return
+		}
+		signers = append(signers, signer)
+
+		switch signatureType {
+		case JSF_SIGNATURE_TYPE_SIGNATURE:
+			if len(jsonExcludes) > 0 {
+				signer[JSF_KEY_EXCLUDES] = jsonExcludes
+			}
+			signature = signer
+		case JSF_SIGNATURE_TYPE_SIGNERS:
+			signature[JSF_KEY_SIGNERS] = signers
+		case JSF_SIGNATURE_TYPE_CHAIN:
+			signature[JSF_KEY_CHAIN] = signers
+		}
+
+		var data []byte
+		if data, err = JSFCanonicalData(object, signature, len(signers)-1); err != nil {
+			return
+		}
+		if signer[JSF_KEY_VALUE], err = SignJSFValue(signer[JSF_KEY_ALGORITHM].(string), signingKey.Signer, data); err != nil {
+			return
+		}
+	}
+	object[JSF_KEY_SIGNATURE] = signature
+	return
+}
+
+// Returns the (unsigned) signer object as a JSON map (i.e., without its "value")
+func newJSFSignerObject(signingKey JSFSigningKey) (object map[string]interface{}, err error) {
+	signer := JSFSigner{
+		Algorithm:       signingKey.Algorithm,
+		KeyId:           signingKey.KeyId,
+		CertificatePath: signingKey.CertificatePath,
+	}
+	if signer.Algorithm == "" {
+		if signer.Algorithm, err = DefaultJSFAlgorithm(signingKey.Signer); err != nil {
+			return
+		}
+	}
+	if len(signingKey.CertificatePath) > 0 {
+		if err = checkJSFCertificateKey(signingKey.CertificatePath[0], signingKey.Signer.Public()); err != nil {
+			return
+		}
+	} else if signingKey.EmbedPublicKey {
+		if signer.PublicKey, err = NewJSFPublicKey(signingKey.Signer.Public()); err != nil {
+			return
+		}
+	}
+
+	// Note: the signer is converted to a JSON map so that it is canonicalized as it will be read
+	var data []byte
+	if data, err = json.Marshal(signer); err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &object)
+	return
+}
+
+// Assures the (signature) certificate holds the public key of the signer
+func checkJSFCertificateKey(encodedCertificate string, publicKey crypto.PublicKey) (err error) {
+	var der []byte
+	if der, err = base64.StdEncoding.DecodeString(encodedCertificate); err != nil {
+		return
+	}
+	var certificate *x509.Certificate
+	if certificate, err = x509.ParseCertificate(der); err != nil {
+		return
+	}
+	if key, ok := publicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !key.Equal(certificate.PublicKey) {
+		err = fmt.Errorf(MSG_JSF_CERTIFICATE_KEY_MISMATCH, certificate.Subject)
+	}
+	return
+}
+
+// Signs the data using the JWA algorithm and returns the (base64url-encoded) signature value
+func SignJSFValue(algorithm string, signer crypto.Signer, data []byte) (value string, err error) {
+	var signature []byte
+
+	switch algorithm {
+	case JSF_ALGORITHM_RS256, JSF_ALGORITHM_RS384, JSF_ALGORITHM_RS512:
+		if _, ok := signer.(*rsa.PrivateKey); !ok {
+			return "", fmt.Errorf(MSG_JSF_PRIVATE_KEY_MISMATCH, signer, algorithm)
+		}
+		signature, err = signer.Sign(rand.Reader, jsfDigest(algorithm, data), jsfAlgorithmHashes[algorithm])
+	case JSF_ALGORITHM_PS256, JSF_ALGORITHM_PS384, JSF_ALGORITHM_PS512:
+		if _, ok := signer.(*rsa.PrivateKey); !ok {
+			return "", fmt.Errorf(MSG_JSF_PRIVATE_KEY_MISMATCH, signer, algorithm)
+		}
+		signature, err = signer.Sign(rand.Reader, jsfDigest(algorithm, data),
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: jsfAlgorithmHashes[algorithm]})
+	case JSF_ALGORITHM_ES256, JSF_ALGORITHM_ES384, JSF_ALGORITHM_ES512:
+		ecKey, ok := signer.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != jsfAlgorithmCurves[algorithm] {
+			return "", fmt.Errorf(MSG_JSF_PRIVATE_KEY_MISMATCH, signer, algorithm)
+		}
+		r, s, errSign := ecdsa.Sign(rand.Reader, ecKey, jsfDigest(algorithm, data))
+		if errSign != nil {
+			return "", errSign
+		}
+		// JWA: the signature is the concatenation of the (fixed length) R and S values
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+	case JSF_ALGORITHM_ED25519:
+		if _, ok := signer.(ed25519.PrivateKey); !ok {
+			return "", fmt.Errorf(MSG_JSF_PRIVATE_KEY_MISMATCH, signer, algorithm)
+		}
+		signature, err = signer.Sign(rand.Reader, data, crypto.Hash(0))
+	default:
+		err = fmt.Errorf(MSG_JSF_ALGORITHM_UNSUPPORTED, algorithm)
+	}
+	if err != nil {
+		return
+	}
+	return base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Returns the JSF (JWK) public key object that represents the (crypto) public key
+func NewJSFPublicKey(publicKey crypto.PublicKey) (*JSFPublicKey, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return &JSFPublicKey{
+			Kty: JSF_KEY_TYPE_RSA,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(jsfIntBytes(key.E)),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		return &JSFPublicKey{
+			Kty: JSF_KEY_TYPE_EC,
+			Crv: key.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(x),
+			Y:   base64.RawURLEncoding.EncodeToString(y),
+		}, nil
+	case ed25519.PublicKey:
+		return &JSFPublicKey{
+			Kty: JSF_KEY_TYPE_OKP,
+			Crv: JSF_CURVE_ED25519,
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type: `%T`", publicKey)
+}
+
+// Returns the minimal (big-endian) byte encoding of a (positive) integer (e.g., an RSA exponent)
+func jsfIntBytes(value int) (bytes []byte) {
+	for ; value > 0; value >>= 8 {
+		bytes = append([]byte{byte(value)}, bytes...)
+	}
+	return
+}
+
+// Parses the first PEM-encoded private key found in the data (i.e., PKCS #8, PKCS #1 or SEC 1)
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case PEM_TYPE_PRIVATE_KEY:
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			signer, ok := key.(crypto.Signer)
+			if !ok {
+				return nil, fmt.Errorf(MSG_JSF_PRIVATE_KEY_UNSUPPORTED, key)
+			}
+			return signer, nil
+		case PEM_TYPE_RSA_PRIVATE_KEY:
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case PEM_TYPE_EC_PRIVATE_KEY:
+			return x509.ParseECPrivateKey(block.Bytes)
+		}
+	}
+	return nil, fmt.Errorf(MSG_JSF_PEM_NOT_FOUND,
+		strings.Join([]string{PEM_TYPE_PRIVATE_KEY, PEM_TYPE_RSA_PRIVATE_KEY, PEM_TYPE_EC_PRIVATE_KEY}, ", "))
+}
+
+// Parses all PEM-encoded certificates in the data and returns them as a JSF "certificatePath"
+// (i.e., base64-encoded DER) where the first certificate must be the signature certificate
+func ParseCertificatePathPEM(data []byte) (certificatePath []string, err error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != PEM_TYPE_CERTIFICATE {
+			continue
+		}
+		if _, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, err
+		}
+		certificatePath = append(certificatePath, base64.StdEncoding.EncodeToString(block.Bytes))
+	}
+	if len(certificatePath) == 0 {
+		err = fmt.Errorf(MSG_JSF_PEM_NOT_FOUND, PEM_TYPE_CERTIFICATE)
+	}
+	return
+}
@@ -21,13 +21,14 @@ package schema
 // See: https://github.com/CycloneDX/specification/blob/master/schema/jsf-0.82.schema.json
 
 // Note: struct will contain "oneOf": []"Signers", "Chain", "Signature"]
+// where a (simple) "Signature" is the signer object itself (i.e., its fields are inlined)
 type JSFSignature struct {
 	// "Unique top level property for Multiple Signatures."
 	Signers []JSFSigner `json:"signers,omitempty"`
 	// "Unique top level property for Signature Chains."
 	Chain []JSFSigner `json:"chain,omitempty"`
 	// "Unique top level property for simple signatures."
-	Signature JSFSigner `json:"signature,omitempty"`
+	JSFSigner
 }
 
 // Algorithm: "Signature algorithm. The currently recognized JWA [RFC7518] and RFC8037
@@ -38,15 +39,16 @@ type JSFSignature struct {
 // KeyId: "Optional. Application specific string identifying the signature key."
 // PublicKey: "Optional. Public key object."
 // CertificatePath: "Optional. Sorted array of X.509 [RFC5280] certificates, where the first element must contain the signature certificate. The certificate path must be contiguous but is not required to be complete."
-// Excludes: "Optional. Array holding the names of one or more application level properties that must be excluded from the signature process. Note that the \"excludes\" property itself, must also be excluded from the signature process. Since both the \"excludes\" property and the associated data it points to are unsigned, a conforming JSF implementation must provide options for specifying which properties to accept."
+// Excludes: "Optional. Array holding the names of one or more application level properties that must be excluded from the signature process."
+// Note: the "excludes" property itself is signed; for "signers" and "chain" it is a global option of the (outer) signature object.
 // Value: "The signature data. Note that the binary representation must follow the JWA [RFC7518] specifications."
 type JSFSigner struct {
-	Algorithm       string       `json:"algorithm,omitempty"`
-	KeyId           string       `json:"keyId,omitempty"`
-	PublicKey       JSFPublicKey `json:"publicKey,omitempty"`
-	CertificatePath []string     `json:"certificatePath,omitempty"`
-	Excludes        []string     `json:"excludes,omitempty"`
-	Value           string       `json:"value,omitempty"`
+	Algorithm       string        `json:"algorithm,omitempty"`
+	KeyId           string        `json:"keyId,omitempty"`
+	PublicKey       *JSFPublicKey `json:"publicKey,omitempty"`
+	CertificatePath []string      `json:"certificatePath,omitempty"`
+	Excludes        []string      `json:"excludes,omitempty"`
+	Value           string        `json:"value,omitempty"`
 }
 
 // constraint: "enum": ["EC","OKP","RSA"]
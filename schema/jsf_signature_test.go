@@ -0,0 +1,610 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+const (
+	TEST_SIGNATURE_CDX_1_5_RS256          = "test/signature/cdx-1-5-signature-rs256.json"
+	TEST_SIGNATURE_CDX_1_5_ES256_EXCLUDES = "test/signature/cdx-1-5-signature-es256-excludes.json"
+	TEST_SIGNATURE_CDX_1_5_SIGNERS        = "test/signature/cdx-1-5-signature-signers.json"
+	TEST_SIGNATURE_CDX_1_5_CHAIN          = "test/signature/cdx-1-5-signature-chain.json"
+	TEST_SIGNATURE_CDX_1_5_COMPONENTS     = "test/signature/cdx-1-5-signature-components.json"
+	TEST_SIGNATURE_CDX_1_5_KEY_ID         = "test/signature/cdx-1-5-signature-keyid.json"
+	TEST_SIGNATURE_CDX_1_5_INVALID        = "test/signature/cdx-1-5-signature-invalid.json"
+	TEST_SIGNATURE_KEY_RSA_PUBLIC         = "test/signature/keys/rsa-public.pem"
+	TEST_SIGNATURE_KEY_SIGNER_CERTIFICATE = "test/signature/keys/signer-certificate.pem"
+)
+
+// Signed using an independent JSF implementation (i.e., test/signature/jsf-reference.js)
+const (
+	TEST_SIGNATURE_CDX_1_5_SIGNERS_EXCLUDES = "test/signature/cdx-1-5-signature-signers-excludes.json"
+	TEST_SIGNATURE_CDX_1_5_CHAIN_EXCLUDES   = "test/signature/cdx-1-5-signature-chain-excludes.json"
+)
+
+// -------------------------------------------
+// JSON Canonicalization Scheme (JCS)
+// -------------------------------------------
+
+// See: RFC 8785, section 3.2.2 ("Sorting of Object Properties")
+func TestJCSCanonicalizeValues(t *testing.T) {
+	input := `{"numbers":[333333333.33333329,1E30,4.50,2e-3,0.000000000000000000000000001],` +
+		`"string":"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/","literals":[null,true,false]}`
+	expected := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+
+	canonical, err := utils.CanonicalizeJSONBytes([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(canonical) != expected {
+		t.Errorf("expected: `%s`, actual: `%s`", expected, canonical)
+	}
+}
+
+// See: RFC 8785, section 3.2.3 (properties are sorted by their UTF-16 code units)
+func TestJCSCanonicalizePropertySorting(t *testing.T) {
+	input := `{"€":"Euro Sign","\r":"Carriage Return","דּ":"Hebrew Letter Dalet With Dagesh",` +
+		`"1":"One","😀":"Emoji: Grinning Face","\u0080":"Control","ö":"Latin Small Letter O With Diaeresis"}`
+	expected := []string{"Carriage Return", "One", "Control", "Latin Small Letter O With Diaeresis",
+		"Euro Sign", "Emoji: Grinning Face", "Hebrew Letter Dalet With Dagesh"}
+
+	canonical, err := utils.CanonicalizeJSONBytes([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := -1
+	for _, value := range expected {
+		index := strings.Index(string(canonical), value)
+		if index <= last {
+			t.Errorf("property value `%s` out of order: `%s`", value, canonical)
+		}
+		last = index
+	}
+}
+
+func TestJCSCanonicalizeNumbers(t *testing.T) {
+	tests := map[string]string{
+		"0":                      "0",
+		"-0":                     "0",
+		"1":                      "1",
+		"-1.5":                   "-1.5",
+		"1e21":                   "1e+21",
+		"1e20":                   "100000000000000000000",
+		"0.000001":               "0.000001",
+		"0.0000001":              "1e-7",
+		"9007199254740992":       "9007199254740992",
+		"295147905179352830000":  "295147905179352830000",
+		"1.7976931348623157e308": "1.7976931348623157e+308",
+	}
+	for input, expected := range tests {
+		canonical, err := utils.CanonicalizeJSONBytes([]byte(input))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(canonical) != expected {
+			t.Errorf("input: `%s`, expected: `%s`, actual: `%s`", input, expected, canonical)
+		}
+	}
+}
+
+// -------------------------------------------
+// JSF signature verification
+// -------------------------------------------
+
+func innerTestVerifySignatures(t *testing.T, inputFile string, keyFile string) (results []JSFVerificationResult) {
+	document, err := loadBOMFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var publicKey interface{}
+	if keyFile != "" {
+		data, errRead := os.ReadFile(keyFile)
+		if errRead != nil {
+			t.Fatal(errRead)
+		}
+		if publicKey, err = ParsePublicKeyPEM(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return document.VerifySignatures(publicKey)
+}
+
+func assertVerificationResults(t *testing.T, results []JSFVerificationResult, expected []JSFVerificationResult) {
+	if len(results) != len(expected) {
+		t.Fatalf("expected: %v results, actual: %v: %v", len(expected), len(results), results)
+	}
+	for i, result := range results {
+		if result.Path != expected[i].Path || result.Type != expected[i].Type ||
+			result.Index != expected[i].Index || result.Algorithm != expected[i].Algorithm ||
+			result.KeySource != expected[i].KeySource || result.Valid != expected[i].Valid {
+			t.Errorf("result %v: expected: %+v, actual: %+v", i, expected[i], result)
+		}
+	}
+}
+
+func TestVerifySignatureCdx15Unsigned(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_CDX_1_5_MATURE_EXAMPLE_1, "")
+	if len(results) != 0 {
+		t.Errorf("expected: no signatures, actual: %v", results)
+	}
+}
+
+func TestVerifySignatureCdx15RS256(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_RS256, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_RS256, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15ES256Excludes(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_ES256_EXCLUDES, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_ES256, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15Signers(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_SIGNERS, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNERS, Index: 0, Algorithm: JSF_ALGORITHM_ED25519, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+		{Type: JSF_SIGNATURE_TYPE_SIGNERS, Index: 1, Algorithm: JSF_ALGORITHM_ES384, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15Chain(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_CHAIN, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_CHAIN, Index: 0, Algorithm: JSF_ALGORITHM_PS256, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+		{Type: JSF_SIGNATURE_TYPE_CHAIN, Index: 1, Algorithm: JSF_ALGORITHM_ES512, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15ComponentsAndServices(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_COMPONENTS, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Path: "components.0", Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_ES256, KeySource: JSF_KEY_SOURCE_CERTIFICATE, Valid: true},
+		{Path: "services.0", Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_ED25519, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15UserCertificate(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_COMPONENTS, TEST_SIGNATURE_KEY_SIGNER_CERTIFICATE)
+	// Note: the user's key (certificate) is used for all signatures (i.e., the service's Ed25519 signature fails)
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Path: "components.0", Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_ES256, KeySource: JSF_KEY_SOURCE_USER, Valid: true},
+		{Path: "services.0", Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_ED25519, KeySource: JSF_KEY_SOURCE_USER, Valid: false},
+	})
+}
+
+func TestVerifySignatureCdx15KeyIdWithoutKey(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_KEY_ID, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_RS512, Valid: false},
+	})
+	if results[0].Message != MSG_JSF_KEY_NOT_FOUND {
+		t.Errorf("expected message: `%s`, actual: `%s`", MSG_JSF_KEY_NOT_FOUND, results[0].Message)
+	}
+}
+
+func TestVerifySignatureCdx15KeyIdWithUserKey(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_KEY_ID, TEST_SIGNATURE_KEY_RSA_PUBLIC)
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_RS512, KeySource: JSF_KEY_SOURCE_USER, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15Invalid(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_INVALID, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNATURE, Algorithm: JSF_ALGORITHM_RS256, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: false},
+	})
+}
+
+func TestVerifySignatureUnsupportedAlgorithm(t *testing.T) {
+	object := map[string]interface{}{
+		"name": "example",
+		"signature": map[string]interface{}{
+			"algorithm": "HS256",
+			"publicKey": map[string]interface{}{"kty": "OKP", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+			"value":     "AAAA",
+		},
+	}
+	results := VerifyJSFSignatures(object, nil)
+	if len(results) != 1 || results[0].Valid ||
+		!strings.Contains(results[0].Message, "HS256") {
+		t.Errorf("expected unsupported algorithm result, actual: %v", results)
+	}
+}
+
+func TestVerifySignatureCdx15SignersExcludes(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_SIGNERS_EXCLUDES, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_SIGNERS, Index: 0, Algorithm: JSF_ALGORITHM_ED25519, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+		{Type: JSF_SIGNATURE_TYPE_SIGNERS, Index: 1, Algorithm: JSF_ALGORITHM_PS384, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+func TestVerifySignatureCdx15ChainExcludes(t *testing.T) {
+	results := innerTestVerifySignatures(t, TEST_SIGNATURE_CDX_1_5_CHAIN_EXCLUDES, "")
+	assertVerificationResults(t, results, []JSFVerificationResult{
+		{Type: JSF_SIGNATURE_TYPE_CHAIN, Index: 0, Algorithm: JSF_ALGORITHM_ES384, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+		{Type: JSF_SIGNATURE_TYPE_CHAIN, Index: 1, Algorithm: JSF_ALGORITHM_RS512, KeySource: JSF_KEY_SOURCE_PUBLIC_KEY, Valid: true},
+	})
+}
+
+// The "excludes" property is itself signed; for "signers" (and "chain") it is a
+// global option of the (outer) signature object
+func TestVerifySignatureExcludesAltered(t *testing.T) {
+	for _, inputFile := range []string{TEST_SIGNATURE_CDX_1_5_ES256_EXCLUDES, TEST_SIGNATURE_CDX_1_5_SIGNERS_EXCLUDES} {
+		document, err := loadBOMFile(inputFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		object := document.GetJSONMap()
+
+		// Excluded properties may change without invalidating the signature(s)
+		object["serialNumber"] = "urn:uuid:00000000-0000-0000-0000-000000000000"
+		for _, result := range VerifyJSFSignatures(object, nil) {
+			if !result.Valid {
+				t.Errorf("%s: expected valid signature after changing excluded property, actual: %v", inputFile, result)
+			}
+		}
+
+		// Changing "excludes" invalidates the signature(s)
+		object[JSF_KEY_SIGNATURE].(map[string]interface{})[JSF_KEY_EXCLUDES] = []interface{}{"serialNumber", "version", "components"}
+		for _, result := range VerifyJSFSignatures(object, nil) {
+			if result.Valid {
+				t.Errorf("%s: expected invalid signature after changing excludes, actual: %v", inputFile, result)
+			}
+		}
+	}
+}
+
+// See: RFC 8037, appendix A.4 ("Ed25519 Signing")
+func TestVerifyJSFValueEd25519RFC8037(t *testing.T) {
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc")
+	value := "hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+
+	if err = VerifyJSFValue(JSF_ALGORITHM_ED25519, ed25519.PublicKey(x), data, value); err != nil {
+		t.Errorf("expected valid signature, actual: %s", err)
+	}
+	if err = VerifyJSFValue(JSF_ALGORITHM_ED25519, ed25519.PublicKey(x), append(data, '.'), value); err == nil {
+		t.Errorf("expected invalid signature for altered data")
+	}
+}
+
+// -------------------------------------------
+// JSF signing
+// -------------------------------------------
+
+const (
+	TEST_SIGNATURE_KEY_RSA_PRIVATE     = "test/signature/keys/rsa-private.pem"
+	TEST_SIGNATURE_KEY_EC_P256_PRIVATE = "test/signature/keys/ec-p256-private.pem"
+	TEST_SIGNATURE_KEY_EC_P384_PRIVATE = "test/signature/keys/ec-p384-private.pem"
+	TEST_SIGNATURE_KEY_EC_P521_PRIVATE = "test/signature/keys/ec-p521-private.pem"
+	TEST_SIGNATURE_KEY_ED25519_PRIVATE = "test/signature/keys/ed25519-private.pem"
+)
+
+func loadTestSigningKey(t *testing.T, keyFile string, algorithm string) JSFSigningKey {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return JSFSigningKey{Signer: signer, Algorithm: algorithm, EmbedPublicKey: true}
+}
+
+func newTestSignedObject() map[string]interface{} {
+	return map[string]interface{}{
+		"name":    "example",
+		"version": "1.0.0",
+		"hashes":  []interface{}{map[string]interface{}{"alg": "SHA-256", "content": "abc123"}},
+		"count":   float64(42),
+	}
+}
+
+func TestSignJSFAlgorithms(t *testing.T) {
+	tests := []struct {
+		keyFile   string
+		algorithm string
+	}{
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_RS256},
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_RS384},
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_RS512},
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_PS256},
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_PS384},
+		{TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_PS512},
+		{TEST_SIGNATURE_KEY_EC_P256_PRIVATE, JSF_ALGORITHM_ES256},
+		{TEST_SIGNATURE_KEY_EC_P384_PRIVATE, JSF_ALGORITHM_ES384},
+		{TEST_SIGNATURE_KEY_EC_P521_PRIVATE, JSF_ALGORITHM_ES512},
+		{TEST_SIGNATURE_KEY_ED25519_PRIVATE, JSF_ALGORITHM_ED25519},
+	}
+	for _, test := range tests {
+		object := newTestSignedObject()
+		signingKey := loadTestSigningKey(t, test.keyFile, test.algorithm)
+		if err := SignJSFObject(object, JSF_SIGNATURE_TYPE_SIGNATURE, []JSFSigningKey{signingKey}, nil); err != nil {
+			t.Errorf("%s: %s", test.algorithm, err)
+			continue
+		}
+		results := VerifyJSFSignatures(object, nil)
+		if len(results) != 1 || !results[0].Valid || results[0].Algorithm != test.algorithm {
+			t.Errorf("%s: expected valid signature, actual: %v", test.algorithm, results)
+		}
+	}
+}
+
+func TestSignJSFDefaultAlgorithms(t *testing.T) {
+	tests := map[string]string{
+		TEST_SIGNATURE_KEY_RSA_PRIVATE:     JSF_ALGORITHM_RS256,
+		TEST_SIGNATURE_KEY_EC_P256_PRIVATE: JSF_ALGORITHM_ES256,
+		TEST_SIGNATURE_KEY_EC_P384_PRIVATE: JSF_ALGORITHM_ES384,
+		TEST_SIGNATURE_KEY_EC_P521_PRIVATE: JSF_ALGORITHM_ES512,
+		TEST_SIGNATURE_KEY_ED25519_PRIVATE: JSF_ALGORITHM_ED25519,
+	}
+	for keyFile, expected := range tests {
+		algorithm, err := DefaultJSFAlgorithm(loadTestSigningKey(t, keyFile, "").Signer)
+		if err != nil || algorithm != expected {
+			t.Errorf("%s: expected: `%s`, actual: `%s` (%v)", keyFile, expected, algorithm, err)
+		}
+	}
+}
+
+func TestSignJSFAlgorithmKeyMismatch(t *testing.T) {
+	object := newTestSignedObject()
+	signingKey := loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P384_PRIVATE, JSF_ALGORITHM_ES256)
+	if err := SignJSFObject(object, JSF_SIGNATURE_TYPE_SIGNATURE, []JSFSigningKey{signingKey}, nil); err == nil {
+		t.Errorf("expected error signing with algorithm `%s` using a P-384 key", JSF_ALGORITHM_ES256)
+	}
+}
+
+func TestSignJSFExcludes(t *testing.T) {
+	object := newTestSignedObject()
+	signingKey := loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P256_PRIVATE, "")
+	if err := SignJSFObject(object, JSF_SIGNATURE_TYPE_SIGNATURE, []JSFSigningKey{signingKey}, []string{"version"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Excluded properties may change without invalidating the signature
+	object["version"] = "2.0.0"
+	if results := VerifyJSFSignatures(object, nil); len(results) != 1 || !results[0].Valid {
+		t.Errorf("expected valid signature after changing excluded property, actual: %v", results)
+	}
+	object["name"] = "changed"
+	if results := VerifyJSFSignatures(object, nil); len(results) != 1 || results[0].Valid {
+		t.Errorf("expected invalid signature after changing signed property, actual: %v", results)
+	}
+}
+
+func TestSignJSFSignersAndChainAppend(t *testing.T) {
+	for _, signatureType := range []string{JSF_SIGNATURE_TYPE_SIGNERS, JSF_SIGNATURE_TYPE_CHAIN} {
+		object := newTestSignedObject()
+		signingKeys := []JSFSigningKey{
+			loadTestSigningKey(t, TEST_SIGNATURE_KEY_ED25519_PRIVATE, ""),
+			loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P384_PRIVATE, ""),
+		}
+		if err := SignJSFObject(object, signatureType, signingKeys, nil); err != nil {
+			t.Fatal(err)
+		}
+		// Add a signer to the existing signers (or chain)
+		signingKeys = []JSFSigningKey{loadTestSigningKey(t, TEST_SIGNATURE_KEY_RSA_PRIVATE, JSF_ALGORITHM_PS256)}
+		if err := SignJSFObject(object, signatureType, signingKeys, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		results := VerifyJSFSignatures(object, nil)
+		if len(results) != 3 {
+			t.Fatalf("%s: expected: 3 results, actual: %v", signatureType, results)
+		}
+		for i, result := range results {
+			if !result.Valid || result.Type != signatureType || result.Index != i {
+				t.Errorf("%s: expected valid signer (%v), actual: %v", signatureType, i, result)
+			}
+		}
+
+		// A different signature type cannot be added to the existing signature
+		otherType := JSF_SIGNATURE_TYPE_CHAIN
+		if signatureType == JSF_SIGNATURE_TYPE_CHAIN {
+			otherType = JSF_SIGNATURE_TYPE_SIGNERS
+		}
+		if err := SignJSFObject(object, otherType, signingKeys, nil); err == nil {
+			t.Errorf("expected error adding `%s` signer to existing `%s`", otherType, signatureType)
+		}
+	}
+}
+
+func TestSignJSFSignersAndChainExcludes(t *testing.T) {
+	for _, signatureType := range []string{JSF_SIGNATURE_TYPE_SIGNERS, JSF_SIGNATURE_TYPE_CHAIN} {
+		object := newTestSignedObject()
+		signingKeys := []JSFSigningKey{
+			loadTestSigningKey(t, TEST_SIGNATURE_KEY_ED25519_PRIVATE, ""),
+			loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P256_PRIVATE, ""),
+		}
+		if err := SignJSFObject(object, signatureType, signingKeys, []string{"version"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Excludes are a global option of the (outer) signature object, not of its signers
+		signature := object[JSF_KEY_SIGNATURE].(map[string]interface{})
+		if excludes, ok := signature[JSF_KEY_EXCLUDES].([]interface{}); !ok || len(excludes) != 1 || excludes[0] != "version" {
+			t.Errorf("%s: expected global excludes: `[version]`, actual: `%v`", signatureType, signature[JSF_KEY_EXCLUDES])
+		}
+		_, signers := jsfSigners(signature)
+		for i, signer := range signers {
+			if _, found := signer.(map[string]interface{})[JSF_KEY_EXCLUDES]; found {
+				t.Errorf("%s: expected no excludes on signer (%v)", signatureType, i)
+			}
+		}
+
+		// Signers added to the existing signature keep (and must match) its excludes
+		signingKeys = []JSFSigningKey{loadTestSigningKey(t, TEST_SIGNATURE_KEY_RSA_PRIVATE, "")}
+		if err := SignJSFObject(object, signatureType, signingKeys, []string{"name"}); err == nil {
+			t.Errorf("%s: expected error adding signer with different excludes", signatureType)
+		}
+		if err := SignJSFObject(object, signatureType, signingKeys, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		object["version"] = "2.0.0"
+		results := VerifyJSFSignatures(object, nil)
+		if len(results) != 3 {
+			t.Fatalf("%s: expected: 3 results, actual: %v", signatureType, results)
+		}
+		for i, result := range results {
+			if !result.Valid {
+				t.Errorf("%s: expected valid signer (%v) after changing excluded property, actual: %v", signatureType, i, result)
+			}
+		}
+	}
+}
+
+// Signatures created by this implementation are verified by an independent JSF
+// implementation (i.e., test/signature/jsf-reference.js) using Node.js
+func TestSignJSFCrossImplementation(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node not found; skipping cross-implementation verification")
+	}
+	tests := []struct {
+		signatureType string
+		keyFiles      []string
+		algorithms    []string
+	}{
+		{JSF_SIGNATURE_TYPE_SIGNATURE, []string{TEST_SIGNATURE_KEY_EC_P256_PRIVATE}, []string{""}},
+		{JSF_SIGNATURE_TYPE_SIGNERS, []string{TEST_SIGNATURE_KEY_ED25519_PRIVATE, TEST_SIGNATURE_KEY_RSA_PRIVATE}, []string{"", JSF_ALGORITHM_PS256}},
+		{JSF_SIGNATURE_TYPE_CHAIN, []string{TEST_SIGNATURE_KEY_EC_P521_PRIVATE, TEST_SIGNATURE_KEY_RSA_PRIVATE}, []string{"", JSF_ALGORITHM_RS384}},
+	}
+	for _, test := range tests {
+		document, err := loadBOMFile(TEST_CDX_1_5_MATURE_EXAMPLE_1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var signingKeys []JSFSigningKey
+		for i, keyFile := range test.keyFiles {
+			signingKeys = append(signingKeys, loadTestSigningKey(t, keyFile, test.algorithms[i]))
+		}
+		object := document.GetJSONMap()
+		if err = SignJSFObject(object, test.signatureType, signingKeys, []string{"serialNumber", "version"}); err != nil {
+			t.Fatal(err)
+		}
+
+		buffer, err := utils.EncodeAnyToIndentedJSONStr(object, "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		signedFile := filepath.Join(t.TempDir(), "signed.json")
+		if err = os.WriteFile(signedFile, buffer.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		output, err := exec.Command(node, "test/signature/jsf-reference.js", "verify", signedFile).CombinedOutput()
+		if err != nil {
+			t.Errorf("%s: expected valid signature(s): %s: %s", test.signatureType, err, output)
+		}
+	}
+}
+
+func TestSignJSFChainAltered(t *testing.T) {
+	object := newTestSignedObject()
+	signingKeys := []JSFSigningKey{
+		loadTestSigningKey(t, TEST_SIGNATURE_KEY_ED25519_PRIVATE, ""),
+		loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P256_PRIVATE, ""),
+	}
+	if err := SignJSFObject(object, JSF_SIGNATURE_TYPE_CHAIN, signingKeys, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Altering the first signer (of a chain) invalidates all subsequent signers
+	chain := object[JSF_KEY_SIGNATURE].(map[string]interface{})[JSF_KEY_CHAIN].([]interface{})
+	chain[0].(map[string]interface{})[JSF_KEY_KEY_ID] = "altered"
+	results := VerifyJSFSignatures(object, nil)
+	if len(results) != 2 || results[0].Valid || results[1].Valid {
+		t.Errorf("expected all chain signers to be invalid, actual: %v", results)
+	}
+}
+
+func TestSignJSFSimpleSignatureMultipleKeys(t *testing.T) {
+	signingKeys := []JSFSigningKey{
+		loadTestSigningKey(t, TEST_SIGNATURE_KEY_ED25519_PRIVATE, ""),
+		loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P256_PRIVATE, ""),
+	}
+	if err := SignJSFObject(newTestSignedObject(), JSF_SIGNATURE_TYPE_SIGNATURE, signingKeys, nil); err == nil {
+		t.Errorf("expected error creating a simple signature with multiple signers")
+	}
+}
+
+func TestSignJSFCertificatePath(t *testing.T) {
+	data, err := os.ReadFile(TEST_SIGNATURE_KEY_SIGNER_CERTIFICATE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificatePath, err := ParseCertificatePathPEM(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature certificate must match the private key
+	signingKey := loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P384_PRIVATE, "")
+	signingKey.CertificatePath = certificatePath
+	if err = SignJSFObject(newTestSignedObject(), JSF_SIGNATURE_TYPE_SIGNATURE, []JSFSigningKey{signingKey}, nil); err == nil {
+		t.Errorf("expected error signing with a certificate that does not match the private key")
+	}
+
+	object := newTestSignedObject()
+	signingKey = loadTestSigningKey(t, TEST_SIGNATURE_KEY_EC_P256_PRIVATE, "")
+	signingKey.CertificatePath = certificatePath
+	if err = SignJSFObject(object, JSF_SIGNATURE_TYPE_SIGNATURE, []JSFSigningKey{signingKey}, nil); err != nil {
+		t.Fatal(err)
+	}
+	signature := object[JSF_KEY_SIGNATURE].(map[string]interface{})
+	if _, found := signature["publicKey"]; found {
+		t.Errorf("expected certificate path to be used instead of public key: %v", signature)
+	}
+	results := VerifyJSFSignatures(object, nil)
+	if len(results) != 1 || !results[0].Valid || results[0].KeySource != JSF_KEY_SOURCE_CERTIFICATE {
+		t.Errorf("expected valid signature using certificate path, actual: %v", results)
+	}
+}
+
+func TestParsePrivateKeyPEMPublicKey(t *testing.T) {
+	data, err := os.ReadFile(TEST_SIGNATURE_KEY_RSA_PUBLIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParsePrivateKeyPEM(data); err == nil {
+		t.Errorf("expected error parsing a public key as a private key")
+	}
+}
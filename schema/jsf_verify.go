@@ -0,0 +1,494 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// See: https://cyberphone.github.io/doc/security/jsf.html
+
+// JSF (JSON) property names
+const (
+	JSF_KEY_ALGORITHM = "algorithm"
+	JSF_KEY_KEY_ID    = "keyId"
+	JSF_KEY_SIGNATURE = "signature"
+	JSF_KEY_SIGNERS   = "signers"
+	JSF_KEY_CHAIN     = "chain"
+	JSF_KEY_VALUE     = "value"
+	JSF_KEY_EXCLUDES  = "excludes"
+)
+
+// JSF signature types (i.e., "oneOf" the JSF signature object)
+const (
+	JSF_SIGNATURE_TYPE_SIGNATURE = "signature"
+	JSF_SIGNATURE_TYPE_SIGNERS   = "signers"
+	JSF_SIGNATURE_TYPE_CHAIN     = "chain"
+)
+
+// Sources of the public key used to verify a signature
+const (
+	JSF_KEY_SOURCE_PUBLIC_KEY  = "publicKey"
+	JSF_KEY_SOURCE_CERTIFICATE = "certificatePath"
+	JSF_KEY_SOURCE_USER        = "user"
+)
+
+// JSF (JWA) algorithms
+const (
+	JSF_ALGORITHM_RS256   = "RS256"
+	JSF_ALGORITHM_RS384   = "RS384"
+	JSF_ALGORITHM_RS512   = "RS512"
+	JSF_ALGORITHM_PS256   = "PS256"
+	JSF_ALGORITHM_PS384   = "PS384"
+	JSF_ALGORITHM_PS512   = "PS512"
+	JSF_ALGORITHM_ES256   = "ES256"
+	JSF_ALGORITHM_ES384   = "ES384"
+	JSF_ALGORITHM_ES512   = "ES512"
+	JSF_ALGORITHM_ED25519 = "Ed25519"
+)
+
+// JSF (JWK) public key types and curves
+const (
+	JSF_KEY_TYPE_EC   JSFKeyType = "EC"
+	JSF_KEY_TYPE_OKP  JSFKeyType = "OKP"
+	JSF_KEY_TYPE_RSA  JSFKeyType = "RSA"
+	JSF_CURVE_P256               = "P-256"
+	JSF_CURVE_P384               = "P-384"
+	JSF_CURVE_P521               = "P-521"
+	JSF_CURVE_ED25519            = "Ed25519"
+)
+
+// PEM block types
+const (
+	PEM_TYPE_CERTIFICATE    = "CERTIFICATE"
+	PEM_TYPE_PUBLIC_KEY     = "PUBLIC KEY"
+	PEM_TYPE_RSA_PUBLIC_KEY = "RSA PUBLIC KEY"
+)
+
+// Signature verification messages
+const (
+	MSG_JSF_ALGORITHM_UNSUPPORTED = "algorithm not supported: `%s`"
+	MSG_JSF_KEY_NOT_FOUND         = "no public key or certificate path found"
+	MSG_JSF_KEY_MISMATCH          = "public key type `%T` cannot be used with algorithm `%s`"
+	MSG_JSF_SIGNATURE_INVALID     = "signature value does not match"
+	MSG_JSF_SIGNATURE_MALFORMED   = "malformed signature object"
+	MSG_JSF_CERTIFICATE_PATH      = "certificate path is not contiguous: certificate %v is not signed by certificate %v"
+	MSG_JSF_PEM_NOT_FOUND         = "no supported PEM block (%s) found"
+)
+
+// A JSON object (e.g., the BOM, a component or a service) that carries a JSF signature
+// Note: "Path" holds the keys (and array indices) that dereference into the JSON map
+type JSFSignedObject struct {
+	Path   []string
+	Object map[string]interface{}
+}
+
+// The result of verifying a single signer (of a simple signature, "signers" or "chain")
+type JSFVerificationResult struct {
+	Path      string `json:"path"`
+	Type      string `json:"type"`
+	Index     int    `json:"index"`
+	Algorithm string `json:"algorithm"`
+	KeyId     string `json:"keyId,omitempty"`
+	KeySource string `json:"keySource,omitempty"`
+	Valid     bool   `json:"valid"`
+	Message   string `json:"message,omitempty"`
+}
+
+var jsfAlgorithmHashes = map[string]crypto.Hash{
+	JSF_ALGORITHM_RS256: crypto.SHA256,
+	JSF_ALGORITHM_RS384: crypto.SHA384,
+	JSF_ALGORITHM_RS512: crypto.SHA512,
+	JSF_ALGORITHM_PS256: crypto.SHA256,
+	JSF_ALGORITHM_PS384: crypto.SHA384,
+	JSF_ALGORITHM_PS512: crypto.SHA512,
+	JSF_ALGORITHM_ES256: crypto.SHA256,
+	JSF_ALGORITHM_ES384: crypto.SHA384,
+	JSF_ALGORITHM_ES512: crypto.SHA512,
+}
+
+var jsfAlgorithmCurves = map[string]elliptic.Curve{
+	JSF_ALGORITHM_ES256: elliptic.P256(),
+	JSF_ALGORITHM_ES384: elliptic.P384(),
+	JSF_ALGORITHM_ES512: elliptic.P521(),
+}
+
+// Returns all objects in the BOM (including the BOM itself) that carry a signature
+// (e.g., "components", "services", "compositions" and "annotations")
+func (bom *BOM) FindSignedObjects() (signedObjects []JSFSignedObject) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	if jsonMap := bom.GetJSONMap(); jsonMap != nil {
+		findSignedObjects(jsonMap, nil, &signedObjects)
+	}
+	return
+}
+
+// Note: this method is recursive
+func findSignedObjects(entity interface{}, path []string, signedObjects *[]JSFSignedObject) {
+	switch typedEntity := entity.(type) {
+	case map[string]interface{}:
+		if _, signed := typedEntity[JSF_KEY_SIGNATURE].(map[string]interface{}); signed {
+			*signedObjects = append(*signedObjects, JSFSignedObject{Path: path, Object: typedEntity})
+		}
+		for _, key := range sortedKeys(typedEntity) {
+			// Note: signatures do not nest within signature objects
+			if key != JSF_KEY_SIGNATURE {
+				findSignedObjects(typedEntity[key], appendPath(path, key), signedObjects)
+			}
+		}
+	case []interface{}:
+		for i, value := range typedEntity {
+			findSignedObjects(value, appendPath(path, fmt.Sprint(i)), signedObjects)
+		}
+	}
+}
+
+// Verifies all signatures in the BOM using either the public key provided (if not nil)
+// or the public key (or certificate path) embedded within each signature
+func (bom *BOM) VerifySignatures(publicKey crypto.PublicKey) (results []JSFVerificationResult) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	for _, signedObject := range bom.FindSignedObjects() {
+		path := strings.Join(signedObject.Path, ".")
+		for _, result := range VerifyJSFSignatures(signedObject.Object, publicKey) {
+			result.Path = path
+			results = append(results, result)
+		}
+	}
+	return
+}
+
+// Verifies each signer of the signature found in the (signed) JSON object
+func VerifyJSFSignatures(object map[string]interface{}, publicKey crypto.PublicKey) (results []JSFVerificationResult) {
+	signature, ok := object[JSF_KEY_SIGNATURE].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	signatureType, signers := jsfSigners(signature)
+	for index := range signers {
+		results = append(results, verifyJSFSigner(object, signature, signatureType, signers, index, publicKey))
+	}
+	return
+}
+
+// Returns the signature type and its signer objects
+func jsfSigners(signature map[string]interface{}) (signatureType string, signers []interface{}) {
+	if value, found := signature[JSF_KEY_SIGNERS]; found {
+		signers, _ = value.([]interface{})
+		return JSF_SIGNATURE_TYPE_SIGNERS, signers
+	}
+	if value, found := signature[JSF_KEY_CHAIN]; found {
+		signers, _ = value.([]interface{})
+		return JSF_SIGNATURE_TYPE_CHAIN, signers
+	}
+	return JSF_SIGNATURE_TYPE_SIGNATURE, []interface{}{signature}
+}
+
+func verifyJSFSigner(object map[string]interface{}, signature map[string]interface{}, signatureType string, signers []interface{}, index int, publicKey crypto.PublicKey) (result JSFVerificationResult) {
+	result.Type = signatureType
+	result.Index = index
+
+	signer, err := decodeJSFSigner(signers[index])
+	if err != nil {
+		result.Message = err.Error()
+		return
+	}
+	result.Algorithm = signer.Algorithm
+	result.KeyId = signer.KeyId
+
+	data, err := JSFCanonicalData(object, signature, index)
+	if err != nil {
+		result.Message = err.Error()
+		return
+	}
+
+	var key crypto.PublicKey
+	if key, result.KeySource, err = jsfVerificationKey(signer, publicKey); err != nil {
+		result.Message = err.Error()
+		return
+	}
+
+	if err = VerifyJSFValue(signer.Algorithm, key, data, signer.Value); err != nil {
+		result.Message = err.Error()
+		return
+	}
+	result.Valid = true
+	return
+}
+
+func decodeJSFSigner(value interface{}) (signer JSFSigner, err error) {
+	var data []byte
+	if data, err = json.Marshal(value); err != nil {
+		return
+	}
+	if err = json.Unmarshal(data, &signer); err != nil || signer.Algorithm == "" || signer.Value == "" {
+		err = fmt.Errorf(MSG_JSF_SIGNATURE_MALFORMED)
+	}
+	return
+}
+
+// Returns the canonical (JCS) data signed by the indexed signer; that is, the signed
+// object with only the signer's "value" removed. The "excludes" property is itself
+// signed; the properties it names are removed from the object. For "signers" and
+// "chain", "excludes" is a global option read from the outer signature object, which
+// holds only the indexed signer ("signers") or all prior signers, with their values,
+// followed by the indexed signer ("chain").
+func JSFCanonicalData(object map[string]interface{}, signature map[string]interface{}, index int) ([]byte, error) {
+	signatureType, signers := jsfSigners(signature)
+	if index < 0 || index >= len(signers) {
+		return nil, fmt.Errorf(MSG_JSF_SIGNATURE_MALFORMED)
+	}
+	signer, ok := signers[index].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf(MSG_JSF_SIGNATURE_MALFORMED)
+	}
+
+	unsigned := make(map[string]interface{}, len(signer))
+	for key, value := range signer {
+		if key != JSF_KEY_VALUE {
+			unsigned[key] = value
+		}
+	}
+
+	var signed map[string]interface{}
+	switch signatureType {
+	case JSF_SIGNATURE_TYPE_SIGNERS, JSF_SIGNATURE_TYPE_CHAIN:
+		// Note: global signature options (e.g., "excludes") are part of the signed data
+		signed = make(map[string]interface{}, len(signature))
+		for key, value := range signature {
+			if key != JSF_KEY_SIGNERS && key != JSF_KEY_CHAIN {
+				signed[key] = value
+			}
+		}
+		if signatureType == JSF_SIGNATURE_TYPE_SIGNERS {
+			signed[JSF_KEY_SIGNERS] = []interface{}{unsigned}
+		} else {
+			chain := make([]interface{}, 0, index+1)
+			chain = append(chain, signers[:index]...)
+			signed[JSF_KEY_CHAIN] = append(chain, unsigned)
+		}
+	default:
+		signed = unsigned
+	}
+
+	// Note: the signed object is copied (shallow) so that the BOM is not altered
+	signedObject := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		signedObject[key] = value
+	}
+	if excludes, ok := signed[JSF_KEY_EXCLUDES].([]interface{}); ok {
+		for _, exclude := range excludes {
+			if name, ok := exclude.(string); ok && name != JSF_KEY_SIGNATURE {
+				delete(signedObject, name)
+			}
+		}
+	}
+	signedObject[JSF_KEY_SIGNATURE] = signed
+	return utils.CanonicalizeJSON(signedObject)
+}
+
+// Returns the key used to verify the signer: a user-supplied key takes precedence
+// over a "publicKey" which, in turn, takes precedence over a "certificatePath"
+func jsfVerificationKey(signer JSFSigner, publicKey crypto.PublicKey) (key crypto.PublicKey, source string, err error) {
+	if publicKey != nil {
+		return publicKey, JSF_KEY_SOURCE_USER, nil
+	}
+	if signer.PublicKey != nil {
+		key, err = signer.PublicKey.CryptoPublicKey()
+		return key, JSF_KEY_SOURCE_PUBLIC_KEY, err
+	}
+	if len(signer.CertificatePath) > 0 {
+		key, err = jsfCertificatePathKey(signer.CertificatePath)
+		return key, JSF_KEY_SOURCE_CERTIFICATE, err
+	}
+	return nil, "", fmt.Errorf(MSG_JSF_KEY_NOT_FOUND)
+}
+
+// Returns the public key of the first (signature) certificate after assuring the path is contiguous
+func jsfCertificatePathKey(certificatePath []string) (crypto.PublicKey, error) {
+	certificates := make([]*x509.Certificate, 0, len(certificatePath))
+	for _, encoded := range certificatePath {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	for i := 0; i < len(certificates)-1; i++ {
+		if err := certificates[i].CheckSignatureFrom(certificates[i+1]); err != nil {
+			return nil, fmt.Errorf(MSG_JSF_CERTIFICATE_PATH, i, i+1)
+		}
+	}
+	return certificates[0].PublicKey, nil
+}
+
+// Verifies the (base64url-encoded) signature value of the data using the JWA algorithm
+func VerifyJSFValue(algorithm string, publicKey crypto.PublicKey, data []byte, value string) (err error) {
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return
+	}
+
+	switch algorithm {
+	case JSF_ALGORITHM_RS256, JSF_ALGORITHM_RS384, JSF_ALGORITHM_RS512,
+		JSF_ALGORITHM_PS256, JSF_ALGORITHM_PS384, JSF_ALGORITHM_PS512:
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf(MSG_JSF_KEY_MISMATCH, publicKey, algorithm)
+		}
+		digest := jsfDigest(algorithm, data)
+		if strings.HasPrefix(algorithm, "PS") {
+			err = rsa.VerifyPSS(rsaKey, jsfAlgorithmHashes[algorithm], digest, signature,
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			err = rsa.VerifyPKCS1v15(rsaKey, jsfAlgorithmHashes[algorithm], digest, signature)
+		}
+		if err != nil {
+			err = fmt.Errorf(MSG_JSF_SIGNATURE_INVALID)
+		}
+	case JSF_ALGORITHM_ES256, JSF_ALGORITHM_ES384, JSF_ALGORITHM_ES512:
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != jsfAlgorithmCurves[algorithm] {
+			return fmt.Errorf(MSG_JSF_KEY_MISMATCH, publicKey, algorithm)
+		}
+		// JWA: the signature is the concatenation of the (fixed length) R and S values
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf(MSG_JSF_SIGNATURE_INVALID)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, jsfDigest(algorithm, data), r, s) {
+			err = fmt.Errorf(MSG_JSF_SIGNATURE_INVALID)
+		}
+	case JSF_ALGORITHM_ED25519:
+		edKey, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf(MSG_JSF_KEY_MISMATCH, publicKey, algorithm)
+		}
+		if !ed25519.Verify(edKey, data, signature) {
+			err = fmt.Errorf(MSG_JSF_SIGNATURE_INVALID)
+		}
+	default:
+		err = fmt.Errorf(MSG_JSF_ALGORITHM_UNSUPPORTED, algorithm)
+	}
+	return
+}
+
+func jsfDigest(algorithm string, data []byte) []byte {
+	hash := jsfAlgorithmHashes[algorithm].New()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// Returns the (crypto) public key represented by the JSF (JWK) public key object
+func (publicKey *JSFPublicKey) CryptoPublicKey() (crypto.PublicKey, error) {
+	switch publicKey.Kty {
+	case JSF_KEY_TYPE_RSA:
+		n, errN := base64.RawURLEncoding.DecodeString(publicKey.N)
+		e, errE := base64.RawURLEncoding.DecodeString(publicKey.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid %s public key", publicKey.Kty)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case JSF_KEY_TYPE_EC:
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch publicKey.Crv {
+		case JSF_CURVE_P256:
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case JSF_CURVE_P384:
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case JSF_CURVE_P521:
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported %s curve: `%s`", publicKey.Kty, publicKey.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(publicKey.X)
+		y, errY := base64.RawURLEncoding.DecodeString(publicKey.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid %s public key", publicKey.Kty)
+		}
+		// Assure the point is on the curve (i.e., using its uncompressed encoding)
+		if _, err := ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("invalid %s public key: %s", publicKey.Kty, err)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case JSF_KEY_TYPE_OKP:
+		if publicKey.Crv != JSF_CURVE_ED25519 {
+			return nil, fmt.Errorf("unsupported %s curve: `%s`", publicKey.Kty, publicKey.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(publicKey.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid %s public key", publicKey.Kty)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported public key type: `%s`", publicKey.Kty)
+}
+
+// Parses the first PEM-encoded public key or (X.509) certificate found in the data
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case PEM_TYPE_CERTIFICATE:
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			return certificate.PublicKey, nil
+		case PEM_TYPE_PUBLIC_KEY:
+			return x509.ParsePKIXPublicKey(block.Bytes)
+		case PEM_TYPE_RSA_PUBLIC_KEY:
+			return x509.ParsePKCS1PublicKey(block.Bytes)
+		}
+	}
+	return nil, fmt.Errorf(MSG_JSF_PEM_NOT_FOUND,
+		strings.Join([]string{PEM_TYPE_CERTIFICATE, PEM_TYPE_PUBLIC_KEY, PEM_TYPE_RSA_PUBLIC_KEY}, ", "))
+}
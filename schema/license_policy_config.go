@@ -73,7 +73,6 @@ type LicensePolicyConfig struct {
 	hashOnce                sync.Once
 	licenseFamilyNameMap    *slicemultimap.MultiMap
 	licenseIdMap            *slicemultimap.MultiMap
-	filteredFamilyNameMap   *slicemultimap.MultiMap
 }
 
 func NewLicensePolicyConfig(configFile string) *LicensePolicyConfig {
@@ -94,9 +93,6 @@ func (config *LicensePolicyConfig) Reset() {
 	if config.licenseIdMap != nil {
 		config.licenseIdMap.Clear()
 	}
-	if config.filteredFamilyNameMap != nil {
-		config.filteredFamilyNameMap.Clear()
-	}
 }
 
 func (config *LicensePolicyConfig) GetFamilyNameMap() (hashmap *slicemultimap.MultiMap, err error) {
@@ -113,10 +109,12 @@ func (config *LicensePolicyConfig) GetLicenseIdMap() (hashmap *slicemultimap.Mul
 	return config.licenseIdMap, err
 }
 
+// Note: the filtered hashmap is not stored in the config. so that (concurrent) callers
+// can each request policies using different where filters
 func (config *LicensePolicyConfig) GetFilteredFamilyNameMap(whereFilters []common.WhereFilter) (hashmap *slicemultimap.MultiMap, err error) {
 	// NOTE: This call is necessary as this will cause all `licensePolicyConfig.PolicyList`
 	// entries to have alternative field names to be mapped (e.g., `usagePolicy` -> `usage-policy`)
-	config.filteredFamilyNameMap, err = config.GetFamilyNameMap()
+	hashmap, err = config.GetFamilyNameMap()
 
 	if err != nil {
 		return
@@ -124,10 +122,10 @@ func (config *LicensePolicyConfig) GetFilteredFamilyNameMap(whereFilters []commo
 
 	if len(whereFilters) > 0 {
 		// Always use a new filtered hashmap for each filtered list request
-		config.filteredFamilyNameMap = slicemultimap.New()
-		err = config.filteredHashLicensePolicies(whereFilters)
+		hashmap = slicemultimap.New()
+		err = config.filteredHashLicensePolicies(hashmap, whereFilters)
 	}
-	return config.filteredFamilyNameMap, err
+	return
 }
 
 func (config *LicensePolicyConfig) LoadHashPolicyConfigurationFile(policyFile string, defaultPolicyFile string) (err error) {
@@ -318,14 +316,14 @@ func (config *LicensePolicyConfig) hashChildPolicies(policy LicensePolicy) (err
 	return
 }
 
-func (config *LicensePolicyConfig) filteredHashLicensePolicies(whereFilters []common.WhereFilter) (err error) {
+func (config *LicensePolicyConfig) filteredHashLicensePolicies(hashmap *slicemultimap.MultiMap, whereFilters []common.WhereFilter) (err error) {
 	getLogger().Enter()
 	defer getLogger().Exit(err)
 
 	// NOTE: original []PolicyList includes values for both deprecated and current fields
 	// So that filtered "queries" will work regardless (for backwards compatibility)
 	for _, policy := range config.PolicyList {
-		err = config.filteredHashLicensePolicy(policy, whereFilters)
+		err = config.filteredHashLicensePolicy(hashmap, policy, whereFilters)
 		if err != nil {
 			return
 		}
@@ -335,7 +333,7 @@ func (config *LicensePolicyConfig) filteredHashLicensePolicies(whereFilters []co
 
 // Hash a CDX Component and recursively those of any "nested" components
 // TODO we should WARN if version is not a valid semver (e.g., examples/cyclonedx/BOM/laravel-7.12.0/bom.1.3.json)
-func (config *LicensePolicyConfig) filteredHashLicensePolicy(policy LicensePolicy, whereFilters []common.WhereFilter) (err error) {
+func (config *LicensePolicyConfig) filteredHashLicensePolicy(hashmap *slicemultimap.MultiMap, policy LicensePolicy, whereFilters []common.WhereFilter) (err error) {
 	var match bool = true
 	var mapPolicy map[string]interface{}
 
@@ -355,7 +353,7 @@ func (config *LicensePolicyConfig) filteredHashLicensePolicy(policy LicensePolic
 	// Hash policy if it matched where filters
 	if match {
 		getLogger().Debugf("Matched: Hashing Policy: id: %s, family: %s", policy.Id, policy.Family)
-		config.filteredFamilyNameMap.Put(policy.Family, policy)
+		hashmap.Put(policy.Family, policy)
 	}
 
 	return
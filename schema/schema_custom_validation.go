@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/CycloneDX/sbom-utility/resources"
 	"github.com/CycloneDX/sbom-utility/utils"
 )
 
@@ -34,51 +35,133 @@ var CustomValidationChecks CustomValidationConfig
 // ---------------------------------------------------------------
 
 func LoadCustomValidationConfig(filename string) (err error) {
+	return LoadCustomValidationConfigFiles([]string{filename}, "")
+}
+
+// Load the custom validation config files (see: NewCustomValidationConfig) into the global config
+func LoadCustomValidationConfigFiles(filenames []string, defaultFilename string) (err error) {
+	var config *CustomValidationConfig
+	if config, err = NewCustomValidationConfig(filenames, defaultFilename); err != nil {
+		return
+	}
+	CustomValidationChecks = *config
+	return
+}
+
+// Load the custom validation config files and layer them, in the order provided, into
+// a single (effective) config (e.g., an organization's baseline followed by product overrides).
+// If no files are provided, the default config file is loaded from embedded resources.
+// Note: the returned config is only read once loaded; it may be shared by concurrent validations
+func NewCustomValidationConfig(filenames []string, defaultFilename string) (config *CustomValidationConfig, err error) {
 	getLogger().Enter()
 	defer getLogger().Exit()
 
-	cfgFilename, err := utils.FindVerifyConfigFileAbsPath(getLogger(), filename)
-
-	if err != nil {
-		return fmt.Errorf("unable to find custom validation config file: `%s`", filename)
+	config = new(CustomValidationConfig)
+	if len(filenames) == 0 {
+		// Attempt to load the default config file from embedded file resources
+		getLogger().Infof("Loading (embedded) default custom validation config file: `%s`...", defaultFilename)
+		buffer, errLoad := resources.LoadConfigFile(defaultFilename)
+		if errLoad != nil {
+			return nil, fmt.Errorf("unable to read custom validation config file: `%s` from embedded resources: `%s`",
+				defaultFilename, resources.RESOURCES_CONFIG_DIR)
+		}
+		if err = config.layerConfigBuffer(buffer, defaultFilename); err != nil {
+			return
+		}
 	}
 
-	// Note we actively supply informative error messages to help user
-	// understand exactly how the load failed
-	getLogger().Infof("Loading custom validation config file: `%s`...", cfgFilename)
-	// #nosec G304 (suppress warning)
-	buffer, err := os.ReadFile(cfgFilename)
-	if err != nil {
-		return fmt.Errorf("unable to `ReadFile`: `%s`", cfgFilename)
+	for _, filename := range filenames {
+		cfgFilename, errFind := utils.FindVerifyConfigFileAbsPath(getLogger(), filename)
+		if errFind != nil {
+			return nil, fmt.Errorf("unable to find custom validation config file: `%s`", filename)
+		}
+
+		// Note we actively supply informative error messages to help user
+		// understand exactly how the load failed
+		getLogger().Infof("Loading custom validation config file: `%s`...", cfgFilename)
+		// #nosec G304 (suppress warning)
+		buffer, errRead := os.ReadFile(cfgFilename)
+		if errRead != nil {
+			return nil, fmt.Errorf("unable to `ReadFile`: `%s`", cfgFilename)
+		}
+		if err = config.layerConfigBuffer(buffer, cfgFilename); err != nil {
+			return
+		}
 	}
 
-	err = json.Unmarshal(buffer, &CustomValidationChecks)
-	if err != nil {
-		return fmt.Errorf("cannot `Unmarshal`: `%s`", cfgFilename)
+	// Assure all (declarative) rules are valid before any are evaluated
+	for i := range config.Validation.Rules {
+		if err = config.Validation.Rules[i].Compile(); err != nil {
+			return nil, fmt.Errorf("invalid custom validation rule (%s): %s", config.Validation.Rules[i].Source, err.Error())
+		}
 	}
 
 	return
 }
 
+func (config *CustomValidationConfig) layerConfigBuffer(buffer []byte, source string) (err error) {
+	var layer CustomValidationConfig
+	if err = json.Unmarshal(buffer, &layer); err != nil {
+		return fmt.Errorf("cannot `Unmarshal`: `%s`", source)
+	}
+	config.Layer(layer, source)
+	return
+}
+
+// Layer the config over the current config; rules (by "id") declared by the layer
+// override those already declared, others are appended.
+// Note: only the fields declared by an overriding rule are replaced (e.g., "enabled": false
+// disables a rule; "severity": "warning" lowers its severity)
+func (config *CustomValidationConfig) Layer(layer CustomValidationConfig, source string) {
+	rules := config.Validation.Rules
+	for _, layerRule := range layer.Validation.Rules {
+		layerRule.Source = source
+		if i := findCustomValidationRule(rules, layerRule.Id); i >= 0 {
+			getLogger().Debugf("custom validation rule `%s` (%s) overridden by: `%s`", layerRule.Id, rules[i].Source, source)
+			rules[i].override(layerRule)
+			continue
+		}
+		rules = append(rules, layerRule)
+	}
+	config.Validation.Rules = rules
+}
+
+func findCustomValidationRule(rules []CustomValidationRule, id string) int {
+	for i := range rules {
+		if rules[i].Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
 // TODO: return copies
 func (config *CustomValidationConfig) GetCustomValidationConfig() *CustomValidation {
 	return &config.Validation
 }
 
-func (config *CustomValidationConfig) GetCustomValidationMetadata() *CustomValidationMetadata {
+func (config *CustomValidationConfig) GetCustomValidationRules() []CustomValidationRule {
 
 	if cfg := config.GetCustomValidationConfig(); cfg != nil {
-		return &cfg.Metadata
+		return cfg.Rules
 	}
 	return nil
 }
 
-func (config *CustomValidationConfig) GetCustomValidationMetadataProperties() []CustomValidationProperty {
+// Evaluate all (compiled) enabled rules, in the order declared, against the BOM's JSON map
+func (config *CustomValidationConfig) EvaluateCustomValidationRules(jsonMap map[string]interface{}) (violations []CustomValidationRuleViolation) {
+	getLogger().Enter()
+	defer getLogger().Exit()
 
-	if metadata := config.GetCustomValidationMetadata(); metadata != nil {
-		return metadata.Properties
+	rules := config.GetCustomValidationRules()
+	for i := range rules {
+		if !rules[i].IsEnabled() {
+			getLogger().Debugf("custom validation rule `%s` disabled", rules[i].Id)
+			continue
+		}
+		violations = append(violations, rules[i].Evaluate(jsonMap)...)
 	}
-	return nil
+	return
 }
 
 type CustomValidationConfig struct {
@@ -86,25 +169,5 @@ type CustomValidationConfig struct {
 }
 
 type CustomValidation struct {
-	Metadata CustomValidationMetadata `json:"metadata"`
-}
-
-type CustomValidationMetadata struct {
-	Properties []CustomValidationProperty `json:"properties"`
-	//Tools      []CustomValidationTool     `json:"tools"`
+	Rules []CustomValidationRule `json:"rules"`
 }
-
-// NOTE: Assumes property "key" is the value in the "name" field
-type CustomValidationProperty struct {
-	CDXProperty
-	Description string `json:"_validate_description"`
-	Key         string `json:"_validate_key"`
-	CheckUnique string `json:"_validate_unique"`
-	CheckRegex  string `json:"_validate_regex"`
-}
-
-// TODO: if we keep using "custom" structs, then this needs to be updated to handle the new Creation Tools object
-// type CustomValidationTool struct {
-// 	CDXLegacyCreationTool
-// 	Description string `json:"_validate_description"`
-// }
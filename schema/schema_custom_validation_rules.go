@@ -0,0 +1,372 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/CycloneDX/sbom-utility/common"
+	"github.com/CycloneDX/sbom-utility/utils"
+)
+
+// Custom validation rule severities
+// Note: only rule violations with an "error" severity invalidate a BOM
+const (
+	RULE_SEVERITY_ERROR   = "error"
+	RULE_SEVERITY_WARNING = "warning"
+	RULE_SEVERITY_INFO    = "info"
+)
+
+var VALID_RULE_SEVERITIES = []string{RULE_SEVERITY_ERROR, RULE_SEVERITY_WARNING, RULE_SEVERITY_INFO}
+
+// Custom validation rule conditions (i.e., the condition a rule violation failed)
+const (
+	RULE_CONDITION_REQUIRED  = "required"
+	RULE_CONDITION_REGEX     = "regex"
+	RULE_CONDITION_ENUM      = "enum"
+	RULE_CONDITION_UNIQUE    = "unique"
+	RULE_CONDITION_MIN_COUNT = "minCount"
+	RULE_CONDITION_MAX_COUNT = "maxCount"
+	RULE_CONDITION_TYPE      = "type"
+)
+
+// (JSON) value types supported by the "type" condition
+const (
+	RULE_TYPE_STRING  = "string"
+	RULE_TYPE_NUMBER  = "number"
+	RULE_TYPE_INTEGER = "integer"
+	RULE_TYPE_BOOLEAN = "boolean"
+	RULE_TYPE_OBJECT  = "object"
+	RULE_TYPE_ARRAY   = "array"
+	RULE_TYPE_NULL    = "null"
+)
+
+var VALID_RULE_TYPES = []string{RULE_TYPE_STRING, RULE_TYPE_NUMBER, RULE_TYPE_INTEGER,
+	RULE_TYPE_BOOLEAN, RULE_TYPE_OBJECT, RULE_TYPE_ARRAY, RULE_TYPE_NULL}
+
+// A declarative validation rule applied to the BOM's JSON map. The "selector" is a
+// (query FROM clause style) path to the object(s) the rule applies to, for example:
+// - "components[**]"                  all components (including nested components)
+// - "metadata.tools.components[*]"    all (metadata) tool components
+// - "services[*]", "metadata.authors[*]", "components[**].licenses[*].license"
+// If a (relative) "field" path is declared, the condition is checked against the field's
+// value(s) of each selected object; otherwise, it is checked against the selected values
+// as a whole (e.g., to require "metadata.properties[name=foo]" exists with "minCount": 1).
+type CustomValidationRule struct {
+	Id          string                    `json:"id"`
+	Description string                    `json:"description,omitempty"`
+	Selector    string                    `json:"selector"`
+	Field       string                    `json:"field,omitempty"`
+	Condition   CustomValidationCondition `json:"condition"`
+	Severity    string                    `json:"severity,omitempty"`
+	Enabled     *bool                     `json:"enabled,omitempty"`
+	// the config file that (last) declared the rule
+	Source string `json:"-"`
+	// parsed selector and field paths and compiled condition values
+	selectorPath []common.QueryPathSegment
+	fieldPath    []common.QueryPathSegment
+	regex        *regexp.Regexp
+}
+
+// NOTE: all declared conditions are checked; array values are checked by entry
+// (e.g., "minCount" counts the entries of an array) except by the "type" condition
+type CustomValidationCondition struct {
+	Required bool     `json:"required,omitempty"`
+	Regex    string   `json:"regex,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
+	Unique   bool     `json:"unique,omitempty"`
+	MinCount *int     `json:"minCount,omitempty"`
+	MaxCount *int     `json:"maxCount,omitempty"`
+	Type     string   `json:"type,omitempty"`
+}
+
+// A custom validation rule violation; the path (i.e., keys and array indices) is
+// relative to the document root
+type CustomValidationRuleViolation struct {
+	RuleId      string
+	Severity    string
+	Condition   string
+	Path        []string
+	Value       interface{}
+	Description string
+}
+
+// Parse the rule's selector and field paths and compile its condition values
+// Note: assures the rule can be evaluated (i.e., before any BOM is validated)
+func (rule *CustomValidationRule) Compile() (err error) {
+	if rule.Id == "" {
+		return fmt.Errorf("rule `id` is required")
+	}
+
+	if rule.Severity == "" {
+		rule.Severity = RULE_SEVERITY_ERROR
+	} else if !containsString(VALID_RULE_SEVERITIES, rule.Severity) {
+		return fmt.Errorf("rule `%s`: invalid severity: `%s` (valid values: %v)", rule.Id, rule.Severity, VALID_RULE_SEVERITIES)
+	}
+
+	if rule.Selector == "" {
+		return fmt.Errorf("rule `%s`: `selector` is required", rule.Id)
+	}
+
+	var ok bool
+	if rule.selectorPath, ok = common.ParseQueryPath(rule.Selector); !ok {
+		return fmt.Errorf("rule `%s`: invalid selector: `%s`", rule.Id, rule.Selector)
+	}
+
+	if rule.Field != "" {
+		if rule.fieldPath, ok = common.ParseQueryPath(rule.Field); !ok {
+			return fmt.Errorf("rule `%s`: invalid field: `%s`", rule.Id, rule.Field)
+		}
+	}
+
+	condition := rule.Condition
+	if !condition.IsDeclared() {
+		return fmt.Errorf("rule `%s`: no condition declared", rule.Id)
+	}
+
+	if rule.regex, err = utils.CompileRegex(condition.Regex); err != nil {
+		return fmt.Errorf("rule `%s`: %s", rule.Id, err.Error())
+	}
+
+	if condition.Type != "" && !containsString(VALID_RULE_TYPES, condition.Type) {
+		return fmt.Errorf("rule `%s`: invalid type: `%s` (valid values: %v)", rule.Id, condition.Type, VALID_RULE_TYPES)
+	}
+
+	if (condition.MinCount != nil && *condition.MinCount < 0) || (condition.MaxCount != nil && *condition.MaxCount < 0) ||
+		(condition.MinCount != nil && condition.MaxCount != nil && *condition.MinCount > *condition.MaxCount) {
+		return fmt.Errorf("rule `%s`: invalid `minCount` or `maxCount`", rule.Id)
+	}
+	return
+}
+
+// Note: rules are enabled unless explicitly disabled (e.g., by a layered config file)
+func (rule *CustomValidationRule) IsEnabled() bool {
+	return rule.Enabled == nil || *rule.Enabled
+}
+
+// Replace the fields declared by the (layered) rule with the same id
+func (rule *CustomValidationRule) override(layerRule CustomValidationRule) {
+	if layerRule.Description != "" {
+		rule.Description = layerRule.Description
+	}
+	if layerRule.Selector != "" {
+		rule.Selector = layerRule.Selector
+	}
+	if layerRule.Field != "" {
+		rule.Field = layerRule.Field
+	}
+	if layerRule.Condition.IsDeclared() {
+		rule.Condition = layerRule.Condition
+	}
+	if layerRule.Severity != "" {
+		rule.Severity = layerRule.Severity
+	}
+	if layerRule.Enabled != nil {
+		rule.Enabled = layerRule.Enabled
+	}
+	rule.Source = layerRule.Source
+}
+
+func (condition CustomValidationCondition) IsDeclared() bool {
+	return condition.Required || condition.Regex != "" || len(condition.Enum) > 0 || condition.Unique ||
+		condition.MinCount != nil || condition.MaxCount != nil || condition.Type != ""
+}
+
+// Format the declared conditions (e.g., for listings)
+func (condition CustomValidationCondition) String() string {
+	var conditions []string
+	if condition.Required {
+		conditions = append(conditions, RULE_CONDITION_REQUIRED)
+	}
+	if condition.Regex != "" {
+		conditions = append(conditions, fmt.Sprintf("%s: %s", RULE_CONDITION_REGEX, condition.Regex))
+	}
+	if len(condition.Enum) > 0 {
+		conditions = append(conditions, fmt.Sprintf("%s: %s", RULE_CONDITION_ENUM, strings.Join(condition.Enum, "|")))
+	}
+	if condition.Unique {
+		conditions = append(conditions, RULE_CONDITION_UNIQUE)
+	}
+	if condition.MinCount != nil {
+		conditions = append(conditions, fmt.Sprintf("%s: %v", RULE_CONDITION_MIN_COUNT, *condition.MinCount))
+	}
+	if condition.MaxCount != nil {
+		conditions = append(conditions, fmt.Sprintf("%s: %v", RULE_CONDITION_MAX_COUNT, *condition.MaxCount))
+	}
+	if condition.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("%s: %s", RULE_CONDITION_TYPE, condition.Type))
+	}
+	return strings.Join(conditions, ", ")
+}
+
+// Evaluate the (compiled) rule against the BOM's JSON map and return any violations
+// Note: rules with a field are not applied if the selector finds no objects
+func (rule *CustomValidationRule) Evaluate(jsonMap map[string]interface{}) (violations []CustomValidationRuleViolation) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	targets := findRuleNodes(common.NewQueryPathNode(jsonMap), rule.selectorPath)
+	getLogger().Tracef("rule `%s`: selector: `%s`: %v value(s) found", rule.Id, rule.Selector, len(targets))
+
+	var values []common.QueryPathNode
+	if rule.fieldPath == nil {
+		violations = rule.evaluateValues(rule.selectorKeys(), targets)
+		values = common.FlattenQueryPathNodes(targets)
+	} else {
+		for _, target := range targets {
+			fieldValues := findRuleNodes(target, rule.fieldPath)
+			violations = append(violations, rule.evaluateValues(target.Path, fieldValues)...)
+			values = append(values, common.FlattenQueryPathNodes(fieldValues)...)
+		}
+	}
+
+	// Check: uniqueness of values across all the (selected) objects
+	if rule.Condition.Unique {
+		firstPaths := make(map[string][]string)
+		for _, node := range values {
+			key := common.QueryValueString(node.Value)
+			if firstPath, found := firstPaths[key]; found {
+				violations = append(violations, rule.newViolation(RULE_CONDITION_UNIQUE, node.Path, node.Value,
+					fmt.Sprintf("value not unique (first found at: `%s`)", strings.Join(firstPath, "."))))
+				continue
+			}
+			firstPaths[key] = node.Path
+		}
+	}
+	return
+}
+
+// Check the conditions against the values found for a selected object (or the selector);
+// the path is used to report conditions that are not met by any one value (e.g., counts)
+func (rule *CustomValidationRule) evaluateValues(path []string, nodes []common.QueryPathNode) (violations []CustomValidationRuleViolation) {
+	condition := rule.Condition
+
+	if condition.Type != "" {
+		for _, node := range nodes {
+			if !isRuleValueType(node.Value, condition.Type) {
+				violations = append(violations, rule.newViolation(RULE_CONDITION_TYPE, node.Path, node.Value,
+					fmt.Sprintf("value not of type: `%s`", condition.Type)))
+			}
+		}
+	}
+
+	values := common.FlattenQueryPathNodes(nodes)
+
+	if condition.Required && !hasRuleValue(values) {
+		violations = append(violations, rule.newViolation(RULE_CONDITION_REQUIRED, path, nil,
+			fmt.Sprintf("required value not found: `%s`", rule.valuePath())))
+	}
+
+	if condition.MinCount != nil && len(values) < *condition.MinCount {
+		violations = append(violations, rule.newViolation(RULE_CONDITION_MIN_COUNT, path, len(values),
+			fmt.Sprintf("found %v value(s) for `%s`; expected at least %v", len(values), rule.valuePath(), *condition.MinCount)))
+	}
+
+	if condition.MaxCount != nil && len(values) > *condition.MaxCount {
+		violations = append(violations, rule.newViolation(RULE_CONDITION_MAX_COUNT, path, len(values),
+			fmt.Sprintf("found %v value(s) for `%s`; expected at most %v", len(values), rule.valuePath(), *condition.MaxCount)))
+	}
+
+	for _, node := range values {
+		if node.Value == nil {
+			continue
+		}
+		stringValue := common.QueryValueString(node.Value)
+		if rule.regex != nil && !rule.regex.MatchString(stringValue) {
+			violations = append(violations, rule.newViolation(RULE_CONDITION_REGEX, node.Path, node.Value,
+				fmt.Sprintf("value does not match regex: `%s`", condition.Regex)))
+		}
+		if len(condition.Enum) > 0 && !containsString(condition.Enum, stringValue) {
+			violations = append(violations, rule.newViolation(RULE_CONDITION_ENUM, node.Path, node.Value,
+				fmt.Sprintf("value not one of: %v", condition.Enum)))
+		}
+	}
+	return
+}
+
+func (rule *CustomValidationRule) newViolation(condition string, path []string, value interface{}, message string) CustomValidationRuleViolation {
+	if rule.Description != "" {
+		message = fmt.Sprintf("%s: %s", rule.Description, message)
+	}
+	return CustomValidationRuleViolation{
+		RuleId:      rule.Id,
+		Severity:    rule.Severity,
+		Condition:   condition,
+		Path:        path,
+		Value:       value,
+		Description: message,
+	}
+}
+
+// Return the (key) path of the selector (i.e., without array selectors)
+func (rule *CustomValidationRule) selectorKeys() (keys []string) {
+	for _, segment := range rule.selectorPath {
+		keys = append(keys, segment.Key)
+	}
+	return
+}
+
+// Return the path of the checked value(s) as declared (i.e., for messages)
+func (rule *CustomValidationRule) valuePath() string {
+	if rule.Field != "" {
+		return rule.Field
+	}
+	return rule.Selector
+}
+
+// Find the value(s) at the path (segments) from the node; paths are tracked so violations
+// can be located. Note: follows the same path semantics as the query command (FROM clause)
+// except that keys present with null values are found (e.g., to check their type)
+func findRuleNodes(node common.QueryPathNode, segments []common.QueryPathSegment) []common.QueryPathNode {
+	nodes, _, _ := common.FindQueryPathNodes([]common.QueryPathNode{node}, segments,
+		common.QueryPathOptions{IncludeNull: true})
+	return common.ExpandQueryPathCollections(nodes)
+}
+
+// Return true if any value is present (i.e., not null or an empty string)
+func hasRuleValue(nodes []common.QueryPathNode) bool {
+	for _, node := range nodes {
+		if node.Value != nil && node.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isRuleValueType(value interface{}, valueType string) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return valueType == RULE_TYPE_NULL
+	case string:
+		return valueType == RULE_TYPE_STRING
+	case bool:
+		return valueType == RULE_TYPE_BOOLEAN
+	case float64:
+		return valueType == RULE_TYPE_NUMBER ||
+			(valueType == RULE_TYPE_INTEGER && typedValue == math.Trunc(typedValue))
+	case map[string]interface{}:
+		return valueType == RULE_TYPE_OBJECT
+	case []interface{}:
+		return valueType == RULE_TYPE_ARRAY
+	}
+	return false
+}
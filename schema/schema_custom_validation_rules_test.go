@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	TEST_CUSTOM_CONFIG_DEFAULT        = "custom.json"
+	TEST_CUSTOM_CONFIG_RULES          = "test/custom/custom-rules.json"
+	TEST_CUSTOM_CONFIG_RULES_OVERRIDE = "test/custom/custom-rules-override.json"
+	TEST_CUSTOM_CDX_1_5_RULES         = "test/custom/cdx-1-5-test-custom-rules.json"
+	TEST_CUSTOM_RULE_SELECTOR         = "components[**]"
+	TEST_CUSTOM_RULE_SELECTOR_BAD     = "components[name=foo"
+)
+
+type ExpectedRuleViolation struct {
+	RuleId    string
+	Severity  string
+	Condition string
+	Path      string
+}
+
+func innerTestCustomValidationRule(t *testing.T, rule CustomValidationRule, expected []ExpectedRuleViolation) {
+	document, err := loadBOMFile(TEST_CUSTOM_CDX_1_5_RULES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = rule.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	compareRuleViolations(t, rule.Evaluate(document.GetJSONMap()), expected)
+}
+
+func compareRuleViolations(t *testing.T, violations []CustomValidationRuleViolation, expected []ExpectedRuleViolation) {
+	if len(violations) != len(expected) {
+		t.Fatalf("expected: %v violations, actual: %v: %v", len(expected), len(violations), violations)
+	}
+	for i, violation := range violations {
+		actual := ExpectedRuleViolation{violation.RuleId, violation.Severity, violation.Condition, strings.Join(violation.Path, ".")}
+		if actual != expected[i] {
+			t.Errorf("violation %v: expected: %v, actual: %v", i, expected[i], actual)
+		}
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestCustomValidationRulesConfigCdx15(t *testing.T) {
+	defer func() { CustomValidationChecks = CustomValidationConfig{} }()
+
+	if err := LoadCustomValidationConfig(TEST_CUSTOM_CONFIG_RULES); err != nil {
+		t.Fatal(err)
+	}
+	document, err := loadBOMFile(TEST_CUSTOM_CDX_1_5_RULES)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ExpectedRuleViolation{
+		{"component-purl", RULE_SEVERITY_ERROR, RULE_CONDITION_REQUIRED, "components.1"},
+		{"component-purl", RULE_SEVERITY_ERROR, RULE_CONDITION_REGEX, "components.1.components.0.purl"},
+		{"component-hash-sha256", RULE_SEVERITY_ERROR, RULE_CONDITION_MIN_COUNT, "components.1"},
+		{"component-bom-ref-unique", RULE_SEVERITY_WARNING, RULE_CONDITION_UNIQUE, "components.2.bom-ref"},
+	}
+	compareRuleViolations(t, CustomValidationChecks.EvaluateCustomValidationRules(document.GetJSONMap()), expected)
+}
+
+func TestCustomValidationRuleSelectorOnly(t *testing.T) {
+	// Note: without a field, conditions apply to the selected values as a whole
+	rule := CustomValidationRule{
+		Id:        "metadata-property-disclaimer",
+		Selector:  "metadata.properties[name=urn:example.com:disclaimer]",
+		Condition: CustomValidationCondition{MinCount: intPtr(1)},
+	}
+	expected := []ExpectedRuleViolation{
+		{"metadata-property-disclaimer", RULE_SEVERITY_ERROR, RULE_CONDITION_MIN_COUNT, "metadata.properties"},
+	}
+	innerTestCustomValidationRule(t, rule, expected)
+
+	rule = CustomValidationRule{
+		Id:        "component-count",
+		Selector:  TEST_CUSTOM_RULE_SELECTOR,
+		Condition: CustomValidationCondition{MaxCount: intPtr(4)},
+	}
+	innerTestCustomValidationRule(t, rule, nil)
+	rule.Condition.MaxCount = intPtr(3)
+	expected = []ExpectedRuleViolation{
+		{"component-count", RULE_SEVERITY_ERROR, RULE_CONDITION_MAX_COUNT, "components"},
+	}
+	innerTestCustomValidationRule(t, rule, expected)
+}
+
+func TestCustomValidationRuleEnumAndType(t *testing.T) {
+	rule := CustomValidationRule{
+		Id:        "metadata-component-type",
+		Selector:  "metadata.component",
+		Field:     "type",
+		Condition: CustomValidationCondition{Enum: []string{"library", "framework"}},
+		Severity:  RULE_SEVERITY_WARNING,
+	}
+	expected := []ExpectedRuleViolation{
+		{"metadata-component-type", RULE_SEVERITY_WARNING, RULE_CONDITION_ENUM, "metadata.component.type"},
+	}
+	innerTestCustomValidationRule(t, rule, expected)
+
+	rule = CustomValidationRule{
+		Id:        "version-integer",
+		Selector:  "version",
+		Condition: CustomValidationCondition{Type: RULE_TYPE_INTEGER},
+	}
+	innerTestCustomValidationRule(t, rule, nil)
+	rule.Condition.Type = RULE_TYPE_STRING
+	expected = []ExpectedRuleViolation{
+		{"version-integer", RULE_SEVERITY_ERROR, RULE_CONDITION_TYPE, "version"},
+	}
+	innerTestCustomValidationRule(t, rule, expected)
+}
+
+func TestCustomValidationRuleCompileInvalid(t *testing.T) {
+	rules := []CustomValidationRule{
+		{Selector: TEST_CUSTOM_RULE_SELECTOR, Condition: CustomValidationCondition{Required: true}},
+		{Id: "no-condition", Selector: TEST_CUSTOM_RULE_SELECTOR},
+		{Id: "invalid-selector", Selector: TEST_CUSTOM_RULE_SELECTOR_BAD, Condition: CustomValidationCondition{Required: true}},
+		{Id: "invalid-severity", Selector: TEST_CUSTOM_RULE_SELECTOR, Condition: CustomValidationCondition{Required: true}, Severity: "fatal"},
+		{Id: "invalid-regex", Selector: TEST_CUSTOM_RULE_SELECTOR, Condition: CustomValidationCondition{Regex: "pkg:("}},
+		{Id: "invalid-type", Selector: TEST_CUSTOM_RULE_SELECTOR, Condition: CustomValidationCondition{Type: "date"}},
+		{Id: "invalid-count", Selector: TEST_CUSTOM_RULE_SELECTOR, Condition: CustomValidationCondition{MinCount: intPtr(2), MaxCount: intPtr(1)}},
+	}
+	for _, rule := range rules {
+		if err := rule.Compile(); err == nil {
+			t.Errorf("expected compile error for rule: %v", rule)
+		}
+	}
+}
+
+func TestCustomValidationRulesConfigLayered(t *testing.T) {
+	defer func() { CustomValidationChecks = CustomValidationConfig{} }()
+
+	if err := LoadCustomValidationConfigFiles([]string{TEST_CUSTOM_CONFIG_RULES, TEST_CUSTOM_CONFIG_RULES_OVERRIDE}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Note: overridden rules keep their (original) order; new rules are appended
+	rules := CustomValidationChecks.GetCustomValidationRules()
+	expectedIds := []string{"component-purl", "component-hash-sha256", "component-bom-ref-unique", "metadata-tools", "component-version"}
+	if len(rules) != len(expectedIds) {
+		t.Fatalf("expected: %v rules, actual: %v: %v", len(expectedIds), len(rules), rules)
+	}
+	for i, rule := range rules {
+		if rule.Id != expectedIds[i] {
+			t.Errorf("rule %v: expected id: `%s`, actual: `%s`", i, expectedIds[i], rule.Id)
+		}
+	}
+
+	// overridden fields are replaced, all others are inherited
+	if rule := rules[1]; rule.Severity != RULE_SEVERITY_WARNING || rule.Field != "hashes[alg=SHA-256]" || rule.Source != TEST_CUSTOM_CONFIG_RULES_OVERRIDE {
+		t.Errorf("expected overridden rule: severity: `%s`, field: `%s`, source: `%s`, actual: %v",
+			RULE_SEVERITY_WARNING, "hashes[alg=SHA-256]", TEST_CUSTOM_CONFIG_RULES_OVERRIDE, rule)
+	}
+	if rules[2].IsEnabled() || !rules[0].IsEnabled() {
+		t.Errorf("expected rule `%s` disabled and rule `%s` enabled", rules[2].Id, rules[0].Id)
+	}
+
+	document, err := loadBOMFile(TEST_CUSTOM_CDX_1_5_RULES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ExpectedRuleViolation{
+		{"component-purl", RULE_SEVERITY_ERROR, RULE_CONDITION_REQUIRED, "components.1"},
+		{"component-purl", RULE_SEVERITY_ERROR, RULE_CONDITION_REGEX, "components.1.components.0.purl"},
+		{"component-hash-sha256", RULE_SEVERITY_WARNING, RULE_CONDITION_MIN_COUNT, "components.1"},
+	}
+	compareRuleViolations(t, CustomValidationChecks.EvaluateCustomValidationRules(document.GetJSONMap()), expected)
+}
+
+func TestCustomValidationConfigDefaultEmbedded(t *testing.T) {
+	defer func() { CustomValidationChecks = CustomValidationConfig{} }()
+
+	if err := LoadCustomValidationConfigFiles(nil, TEST_CUSTOM_CONFIG_DEFAULT); err != nil {
+		t.Fatal(err)
+	}
+	if rules := CustomValidationChecks.GetCustomValidationRules(); len(rules) != 4 {
+		t.Errorf("expected: 4 rules, actual: %v", len(rules))
+	}
+}
+
+func TestCustomValidationConfigLayeredUndeclaredRule(t *testing.T) {
+	defer func() { CustomValidationChecks = CustomValidationConfig{} }()
+
+	// Note: overriding rules (i.e., without a selector) must override a previously declared rule
+	if err := LoadCustomValidationConfigFiles([]string{TEST_CUSTOM_CONFIG_RULES_OVERRIDE}, ""); err == nil {
+		t.Errorf("expected error loading: `%s`", TEST_CUSTOM_CONFIG_RULES_OVERRIDE)
+	}
+}
@@ -88,6 +88,10 @@ func (err UnsupportedFormatError) Error() string {
 	return baseMessage
 }
 
+func (err UnsupportedFormatError) ErrorType() string {
+	return err.Type
+}
+
 func (err UnsupportedSchemaError) Error() string {
 	return fmt.Sprintf("%s: %s: Schema Format: `%s`, Version: `%s`, Variant: `%s` ",
 		err.Type,
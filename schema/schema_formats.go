@@ -177,6 +177,13 @@ func (format *FormatSchema) IsCycloneDx() bool {
 }
 
 func (schemaConfig *BOMFormatAndSchemaConfig) FindFormatAndSchema(bom *BOM) (err error) {
+	return schemaConfig.FindFormatAndSchemaVariant(bom, utils.GlobalFlags.ValidateFlags.SchemaVariant)
+}
+
+// Find the format and schema (with the named variant) of the BOM
+// Note: the variant is passed (i.e., not read from flags) so that callers can
+// use different variants concurrently (e.g., in server mode)
+func (schemaConfig *BOMFormatAndSchemaConfig) FindFormatAndSchemaVariant(bom *BOM, variant string) (err error) {
 	getLogger().Enter()
 	defer getLogger().Exit()
 
@@ -191,7 +198,7 @@ func (schemaConfig *BOMFormatAndSchemaConfig) FindFormatAndSchema(bom *BOM) (err
 
 			// Copy format info into Sbom context
 			bom.FormatInfo = format
-			err = bom.findSchemaVersionWithVariant(format, version, utils.GlobalFlags.ValidateFlags.SchemaVariant)
+			err = bom.findSchemaVersionWithVariant(format, version, variant)
 			return // success
 		}
 	}
@@ -219,9 +226,9 @@ func (bom *BOM) findSchemaVersionWithVariant(format FormatSchema, version string
 
 			// If a variant is also requested, see if we can find one for that criteria
 			// Note: the default value for "variant" is an empty string
-			if utils.GlobalFlags.ValidateFlags.SchemaVariant == schema.Variant {
+			if variant == schema.Variant {
 				getLogger().Tracef("Match found for requested schema variant: `%s`",
-					FormatSchemaVariant(utils.GlobalFlags.ValidateFlags.SchemaVariant))
+					FormatSchemaVariant(variant))
 				bom.SchemaInfo = schema
 				return
 			}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"math"
+	"reflect"
+	"strings"
+)
+
+// OWASP Software Component Verification Standard (SCVS) BOM Maturity Model identifiers
+// Note: BOM (struct) fields are tagged using these values (e.g., `scvs:"bom:core:timestamp"`)
+// See: https://scvs.owasp.org/bom-maturity-model/
+const (
+	SCVS_TAG                           = "scvs"
+	SCVS_BOM_CORE_IDENTIFIER           = "bom:core:identifier"
+	SCVS_BOM_CORE_TIMESTAMP            = "bom:core:timestamp"
+	SCVS_BOM_CORE_AUTHORS              = "bom:core:authors"
+	SCVS_BOM_CORE_DEPENDENCIES         = "bom:core:dependencies"
+	SCVS_BOM_CORE_SIGNATURE            = "bom:core:signature"
+	SCVS_BOM_RESOURCE_SUPPLIER         = "bom:resource:supplier"
+	SCVS_BOM_RESOURCE_NAME             = "bom:resource:name"
+	SCVS_BOM_RESOURCE_VERSION          = "bom:resource:version"
+	SCVS_BOM_RESOURCE_IDENTIFIERS_PURL = "bom:resource:identifiers:purl"
+	SCVS_BOM_RESOURCE_IDENTIFIERS_CPE  = "bom:resource:identifiers:cpe"
+	SCVS_BOM_RESOURCE_IDENTIFIERS_SWID = "bom:resource:identifiers:swid"
+	SCVS_BOM_RESOURCE_IDENTIFIERS      = "bom:resource:identifiers"
+	SCVS_BOM_RESOURCE_HASHES           = "bom:resource:hashes"
+	SCVS_BOM_RESOURCE_LICENSES         = "bom:resource:licenses"
+	SCVS_BOM_RESOURCE_COPYRIGHT        = "bom:resource:copyright"
+)
+
+// Returns true if the struct (or pointer to a struct) has a field tagged with the
+// SCVS identifier which holds an asserted value; that is, a value that is not empty
+// and not an SPDX "NOASSERTION" (or "NONE") placeholder value.
+func HasScvsValue(object interface{}, id string) bool {
+	value := reflect.ValueOf(object)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return false
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		if valueType.Field(i).Tag.Get(SCVS_TAG) == id {
+			return isScvsValueAsserted(value.Field(i))
+		}
+	}
+	return false
+}
+
+// Returns true if any of the SCVS identifiers has an asserted value (see HasScvsValue())
+func HasAnyScvsValue(object interface{}, ids []string) bool {
+	for _, id := range ids {
+		if HasScvsValue(object, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the percentage (rounded to 2 decimal places); an empty set has no coverage
+func coveragePercentage(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(count)*10000/float64(total)) / 100
+}
+
+func isScvsValueAsserted(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return false
+		}
+		return isScvsValueAsserted(value.Elem())
+	case reflect.String:
+		text := strings.TrimSpace(value.String())
+		return text != "" && text != SPDX_NOASSERTION && text != SPDX_NONE
+	case reflect.Slice, reflect.Map:
+		return value.Len() > 0
+	}
+	return !value.IsZero()
+}
@@ -0,0 +1,539 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Note: SPDX v2.2, 2.3 (JSON) schema properties are currently supported
+// See: https://spdx.github.io/spdx-spec/v2.3/
+
+// SPDX reserved (license and property) values
+const (
+	SPDX_NOASSERTION = "NOASSERTION"
+	SPDX_NONE        = "NONE"
+	SPDX_LICENSE_REF = "LicenseRef-"
+)
+
+// SPDX external reference categories
+// NOTE: SPDX v2.3.1 (schema) also permits underscores (e.g., "PACKAGE_MANAGER")
+const (
+	SPDX_REF_CATEGORY_SECURITY             = "SECURITY"
+	SPDX_REF_CATEGORY_PACKAGE_MANAGER      = "PACKAGE-MANAGER"
+	SPDX_REF_CATEGORY_PACKAGE_MANAGER_V2_3 = "PACKAGE_MANAGER"
+	SPDX_REF_CATEGORY_PERSISTENT_ID        = "PERSISTENT-ID"
+	SPDX_REF_CATEGORY_OTHER                = "OTHER"
+)
+
+// SPDX external reference types (for categories above)
+const (
+	SPDX_REF_TYPE_PURL     = "purl"
+	SPDX_REF_TYPE_CPE22    = "cpe22Type"
+	SPDX_REF_TYPE_CPE23    = "cpe23Type"
+	SPDX_REF_TYPE_ADVISORY = "advisory"
+	SPDX_REF_TYPE_SWID     = "swid"
+)
+
+// SPDX relationship types (subset used to derive dependencies and containment)
+const (
+	SPDX_RELATIONSHIP_DESCRIBES      = "DESCRIBES"
+	SPDX_RELATIONSHIP_DESCRIBED_BY   = "DESCRIBED_BY"
+	SPDX_RELATIONSHIP_CONTAINS       = "CONTAINS"
+	SPDX_RELATIONSHIP_CONTAINED_BY   = "CONTAINED_BY"
+	SPDX_RELATIONSHIP_DEPENDS_ON     = "DEPENDS_ON"
+	SPDX_RELATIONSHIP_DEPENDENCY_OF  = "DEPENDENCY_OF"
+	SPDX_RELATIONSHIP_GENERATED_FROM = "GENERATED_FROM"
+)
+
+// SPDX creator (and supplier/originator) prefixes
+const (
+	SPDX_CREATOR_PREFIX_TOOL         = "Tool: "
+	SPDX_CREATOR_PREFIX_ORGANIZATION = "Organization: "
+	SPDX_CREATOR_PREFIX_PERSON       = "Person: "
+)
+
+// v2.2: existed
+// v2.3: added "snippets" (JSON), "annotations" (document-level)
+type SPDXDocument struct {
+	SPDXID                     string                        `json:"SPDXID,omitempty"`
+	SpdxVersion                string                        `json:"spdxVersion,omitempty"`
+	CreationInfo               *SPDXCreationInfo             `json:"creationInfo,omitempty"`
+	Name                       string                        `json:"name,omitempty"`
+	DataLicense                string                        `json:"dataLicense,omitempty"`
+	ExternalDocumentRefs       *[]SPDXExternalDocumentRef    `json:"externalDocumentRefs,omitempty"`
+	HasExtractedLicensingInfos *[]SPDXExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
+	DocumentNamespace          string                        `json:"documentNamespace,omitempty" scvs:"bom:core:identifier"`
+	DocumentDescribes          *[]string                     `json:"documentDescribes,omitempty"`
+	Comment                    string                        `json:"comment,omitempty"`
+	Packages                   *[]SPDXPackage                `json:"packages,omitempty"`
+	Files                      *[]SPDXFile                   `json:"files,omitempty"`
+	Snippets                   *[]SPDXSnippet                `json:"snippets,omitempty"`
+	Relationships              *[]SPDXRelationship           `json:"relationships,omitempty" scvs:"bom:core:dependencies"`
+	Annotations                *[]SPDXAnnotation             `json:"annotations,omitempty"`
+}
+
+type SPDXCreationInfo struct {
+	Created            string   `json:"created,omitempty" scvs:"bom:core:timestamp"`
+	Creators           []string `json:"creators,omitempty" scvs:"bom:core:authors"`
+	LicenseListVersion string   `json:"licenseListVersion,omitempty"`
+	Comment            string   `json:"comment,omitempty"`
+}
+
+type SPDXExternalDocumentRef struct {
+	ExternalDocumentId string        `json:"externalDocumentId,omitempty"`
+	SpdxDocument       string        `json:"spdxDocument,omitempty"`
+	Checksum           *SPDXChecksum `json:"checksum,omitempty"`
+}
+
+type SPDXExtractedLicensingInfo struct {
+	LicenseId     string   `json:"licenseId,omitempty"`
+	ExtractedText string   `json:"extractedText,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+	Comment       string   `json:"comment,omitempty"`
+}
+
+// v2.3: added "primaryPackagePurpose", "releaseDate", "builtDate", "validUntilDate"
+type SPDXPackage struct {
+	SPDXID                  string                       `json:"SPDXID,omitempty"`
+	Name                    string                       `json:"name,omitempty" scvs:"bom:resource:name"`
+	VersionInfo             string                       `json:"versionInfo,omitempty" scvs:"bom:resource:version"`
+	PackageFileName         string                       `json:"packageFileName,omitempty"`
+	Supplier                string                       `json:"supplier,omitempty" scvs:"bom:resource:supplier"`
+	Originator              string                       `json:"originator,omitempty"`
+	DownloadLocation        string                       `json:"downloadLocation,omitempty"`
+	FilesAnalyzed           *bool                        `json:"filesAnalyzed,omitempty"`
+	PackageVerificationCode *SPDXPackageVerificationCode `json:"packageVerificationCode,omitempty"`
+	Checksums               *[]SPDXChecksum              `json:"checksums,omitempty"`
+	Homepage                string                       `json:"homepage,omitempty"`
+	SourceInfo              string                       `json:"sourceInfo,omitempty"`
+	LicenseConcluded        string                       `json:"licenseConcluded,omitempty"`
+	LicenseInfoFromFiles    []string                     `json:"licenseInfoFromFiles,omitempty"`
+	LicenseDeclared         string                       `json:"licenseDeclared,omitempty"`
+	LicenseComments         string                       `json:"licenseComments,omitempty"`
+	CopyrightText           string                       `json:"copyrightText,omitempty"`
+	Summary                 string                       `json:"summary,omitempty"`
+	Description             string                       `json:"description,omitempty"`
+	Comment                 string                       `json:"comment,omitempty"`
+	ExternalRefs            *[]SPDXExternalRef           `json:"externalRefs,omitempty"`
+	AttributionTexts        []string                     `json:"attributionTexts,omitempty"`
+	PrimaryPackagePurpose   string                       `json:"primaryPackagePurpose,omitempty"` // v2.3: added
+	ReleaseDate             string                       `json:"releaseDate,omitempty"`           // v2.3: added
+	BuiltDate               string                       `json:"builtDate,omitempty"`             // v2.3: added
+	ValidUntilDate          string                       `json:"validUntilDate,omitempty"`        // v2.3: added
+	HasFiles                []string                     `json:"hasFiles,omitempty"`
+	Annotations             *[]SPDXAnnotation            `json:"annotations,omitempty"`
+}
+
+type SPDXPackageVerificationCode struct {
+	PackageVerificationCodeValue         string   `json:"packageVerificationCodeValue,omitempty"`
+	PackageVerificationCodeExcludedFiles []string `json:"packageVerificationCodeExcludedFiles,omitempty"`
+}
+
+type SPDXFile struct {
+	SPDXID             string            `json:"SPDXID,omitempty"`
+	FileName           string            `json:"fileName,omitempty"`
+	FileTypes          []string          `json:"fileTypes,omitempty"`
+	Checksums          *[]SPDXChecksum   `json:"checksums,omitempty"`
+	LicenseConcluded   string            `json:"licenseConcluded,omitempty"`
+	LicenseInfoInFiles []string          `json:"licenseInfoInFiles,omitempty"`
+	LicenseComments    string            `json:"licenseComments,omitempty"`
+	CopyrightText      string            `json:"copyrightText,omitempty"`
+	Comment            string            `json:"comment,omitempty"`
+	NoticeText         string            `json:"noticeText,omitempty"`
+	FileContributors   []string          `json:"fileContributors,omitempty"`
+	AttributionTexts   []string          `json:"attributionTexts,omitempty"`
+	Annotations        *[]SPDXAnnotation `json:"annotations,omitempty"`
+}
+
+type SPDXSnippet struct {
+	SPDXID                string             `json:"SPDXID,omitempty"`
+	Name                  string             `json:"name,omitempty"`
+	SnippetFromFile       string             `json:"snippetFromFile,omitempty"`
+	Ranges                []SPDXSnippetRange `json:"ranges,omitempty"`
+	LicenseConcluded      string             `json:"licenseConcluded,omitempty"`
+	LicenseInfoInSnippets []string           `json:"licenseInfoInSnippets,omitempty"`
+	LicenseComments       string             `json:"licenseComments,omitempty"`
+	CopyrightText         string             `json:"copyrightText,omitempty"`
+	Comment               string             `json:"comment,omitempty"`
+	AttributionTexts      []string           `json:"attributionTexts,omitempty"`
+	Annotations           *[]SPDXAnnotation  `json:"annotations,omitempty"`
+}
+
+type SPDXSnippetRange struct {
+	StartPointer SPDXRangePointer `json:"startPointer"`
+	EndPointer   SPDXRangePointer `json:"endPointer"`
+}
+
+type SPDXRangePointer struct {
+	Reference  string `json:"reference,omitempty"`
+	Offset     *int   `json:"offset,omitempty"`
+	LineNumber *int   `json:"lineNumber,omitempty"`
+}
+
+type SPDXRelationship struct {
+	SpdxElementId      string `json:"spdxElementId,omitempty"`
+	RelationshipType   string `json:"relationshipType,omitempty"`
+	RelatedSpdxElement string `json:"relatedSpdxElement,omitempty"`
+	Comment            string `json:"comment,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory,omitempty"`
+	ReferenceType     string `json:"referenceType,omitempty"`
+	ReferenceLocator  string `json:"referenceLocator,omitempty"`
+	Comment           string `json:"comment,omitempty"`
+}
+
+type SPDXChecksum struct {
+	Algorithm     string `json:"algorithm,omitempty"`
+	ChecksumValue string `json:"checksumValue,omitempty"`
+}
+
+type SPDXAnnotation struct {
+	Annotator      string `json:"annotator,omitempty"`
+	AnnotationDate string `json:"annotationDate,omitempty"`
+	AnnotationType string `json:"annotationType,omitempty"`
+	Comment        string `json:"comment,omitempty"`
+}
+
+func UnMarshalSPDXDocument(data interface{}) (*SPDXDocument, error) {
+	getLogger().Enter()
+	defer getLogger().Exit()
+
+	// we need to marshal the data to normalize it to a []byte
+	jsonString, errMarshal := json.Marshal(data)
+	if errMarshal != nil {
+		return nil, errMarshal
+	}
+
+	var document SPDXDocument
+	errUnmarshal := json.Unmarshal(jsonString, &document)
+	if errUnmarshal != nil {
+		getLogger().Warningf("unmarshal failed: %v", errUnmarshal)
+	}
+	return &document, errUnmarshal
+}
+
+// -------------------
+// Accessors
+// -------------------
+
+func (doc *SPDXDocument) GetPackages() (packages []SPDXPackage) {
+	if doc != nil && doc.Packages != nil {
+		packages = *doc.Packages
+	}
+	return
+}
+
+func (doc *SPDXDocument) GetFiles() (files []SPDXFile) {
+	if doc != nil && doc.Files != nil {
+		files = *doc.Files
+	}
+	return
+}
+
+func (doc *SPDXDocument) GetSnippets() (snippets []SPDXSnippet) {
+	if doc != nil && doc.Snippets != nil {
+		snippets = *doc.Snippets
+	}
+	return
+}
+
+func (doc *SPDXDocument) GetRelationships() (relationships []SPDXRelationship) {
+	if doc != nil && doc.Relationships != nil {
+		relationships = *doc.Relationships
+	}
+	return
+}
+
+// Returns the set of SPDX element IDs the document describes (i.e., its "root" elements).
+// SPDX allows these to be declared either using the (deprecated) "documentDescribes"
+// property or using "DESCRIBES" (or "DESCRIBED_BY") relationships on the document itself.
+func (doc *SPDXDocument) GetDescribedElementIds() (ids []string) {
+	if doc == nil {
+		return
+	}
+	found := make(map[string]bool)
+	if doc.DocumentDescribes != nil {
+		for _, id := range *doc.DocumentDescribes {
+			if !found[id] {
+				found[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	for _, relationship := range doc.GetRelationships() {
+		var id string
+		if relationship.RelationshipType == SPDX_RELATIONSHIP_DESCRIBES &&
+			relationship.SpdxElementId == doc.SPDXID {
+			id = relationship.RelatedSpdxElement
+		} else if relationship.RelationshipType == SPDX_RELATIONSHIP_DESCRIBED_BY &&
+			relationship.RelatedSpdxElement == doc.SPDXID {
+			id = relationship.SpdxElementId
+		}
+		if id != "" && !found[id] {
+			found[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+func (doc *SPDXDocument) FindExtractedLicensingInfo(licenseId string) (info *SPDXExtractedLicensingInfo) {
+	if doc == nil || doc.HasExtractedLicensingInfos == nil {
+		return
+	}
+	for i, extracted := range *doc.HasExtractedLicensingInfos {
+		if extracted.LicenseId == licenseId {
+			return &(*doc.HasExtractedLicensingInfos)[i]
+		}
+	}
+	return
+}
+
+// -------------------
+// Licenses
+// -------------------
+
+// Returns true if the SPDX license value asserts no (known) license information
+func IsSPDXLicenseNoAssertion(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "" || value == SPDX_NOASSERTION || value == SPDX_NONE
+}
+
+// Maps an SPDX license value (i.e., a license ID, "LicenseRef-" or expression)
+// to the equivalent CycloneDX license choice. Returns nil if the value
+// asserts no license (i.e., "NOASSERTION", "NONE" or empty).
+// Note: "LicenseRef-" values are mapped to license names using the document's
+// "hasExtractedLicensingInfos" (if a name was provided), otherwise the reference
+// value itself is used as the name.
+func (doc *SPDXDocument) ConvertLicenseToCDXLicenseChoice(value string) (licenseChoice *CDXLicenseChoice) {
+	value = strings.TrimSpace(value)
+	if IsSPDXLicenseNoAssertion(value) {
+		return
+	}
+
+	licenseChoice = new(CDXLicenseChoice)
+	// A single license (i.e., not a compound expression) has no whitespace or parens
+	if strings.ContainsAny(value, " ()") {
+		licenseChoice.Expression = value
+		return
+	}
+
+	if strings.HasPrefix(value, SPDX_LICENSE_REF) || strings.Contains(value, ":"+SPDX_LICENSE_REF) {
+		name := value
+		if extracted := doc.FindExtractedLicensingInfo(value); extracted != nil && extracted.Name != "" {
+			name = extracted.Name
+		}
+		licenseChoice.License = &CDXLicense{Name: name}
+		return
+	}
+
+	licenseChoice.License = &CDXLicense{Id: value}
+	return
+}
+
+// -------------------
+// Conversion
+// -------------------
+
+// Maps an SPDX "supplier" or "originator" value (e.g., "Organization: ACME")
+// to a CycloneDX organizational entity; returns nil for "NOASSERTION"
+func ConvertSPDXActorToCDXOrganizationalEntity(actor string) (entity *CDXOrganizationalEntity) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" || actor == SPDX_NOASSERTION {
+		return
+	}
+
+	var name string = actor
+	var email string
+	for _, prefix := range []string{SPDX_CREATOR_PREFIX_ORGANIZATION, SPDX_CREATOR_PREFIX_PERSON, SPDX_CREATOR_PREFIX_TOOL} {
+		if strings.HasPrefix(actor, prefix) {
+			name = strings.TrimSpace(strings.TrimPrefix(actor, prefix))
+			break
+		}
+	}
+
+	// Strip (optional) email; e.g., "Organization: ACME (contact@acme.com)"
+	if start := strings.LastIndex(name, "("); start > 0 && strings.HasSuffix(name, ")") {
+		email = strings.TrimSpace(name[start+1 : len(name)-1])
+		name = strings.TrimSpace(name[:start])
+	}
+
+	entity = &CDXOrganizationalEntity{Name: name}
+	if email != "" {
+		entity.Contact = &[]CDXOrganizationalContact{{Name: name, Email: email}}
+	}
+	return
+}
+
+// Maps SPDX checksums to CycloneDX hashes; SPDX algorithm names (e.g., "SHA256")
+// are normalized to their CycloneDX equivalents (e.g., "SHA-256").
+// Algorithms without a CycloneDX equivalent are returned as "lossy".
+func ConvertSPDXChecksumsToCDXHashes(checksums *[]SPDXChecksum) (pHashes *[]CDXHash, lossy []string) {
+	if checksums == nil || len(*checksums) == 0 {
+		return
+	}
+	var hashes []CDXHash
+	for _, checksum := range *checksums {
+		alg, ok := mapSPDXChecksumAlgorithms[checksum.Algorithm]
+		if !ok {
+			lossy = append(lossy, checksum.Algorithm)
+			continue
+		}
+		hashes = append(hashes, CDXHash{Alg: alg, Content: checksum.ChecksumValue})
+	}
+	if len(hashes) > 0 {
+		pHashes = &hashes
+	}
+	return
+}
+
+var mapSPDXChecksumAlgorithms = map[string]string{
+	"MD5":         "MD5",
+	"SHA1":        "SHA-1",
+	"SHA256":      "SHA-256",
+	"SHA384":      "SHA-384",
+	"SHA512":      "SHA-512",
+	"SHA3-256":    "SHA3-256",
+	"SHA3-384":    "SHA3-384",
+	"SHA3-512":    "SHA3-512",
+	"BLAKE2b-256": "BLAKE2b-256",
+	"BLAKE2b-384": "BLAKE2b-384",
+	"BLAKE2b-512": "BLAKE2b-512",
+	"BLAKE3":      "BLAKE3",
+}
+
+// Maps SPDX "primaryPackagePurpose" (v2.3) values to CycloneDX component types
+var mapSPDXPackagePurposeToCDXType = map[string]string{
+	"APPLICATION":      COMPONENT_TYPE_APPLICATION,
+	"FRAMEWORK":        COMPONENT_TYPE_FRAMEWORK,
+	"LIBRARY":          COMPONENT_TYPE_LIBRARY,
+	"CONTAINER":        COMPONENT_TYPE_CONTAINER,
+	"OPERATING-SYSTEM": COMPONENT_TYPE_OPERATING_SYSTEM,
+	"DEVICE":           COMPONENT_TYPE_DEVICE,
+	"FIRMWARE":         COMPONENT_TYPE_FIRMWARE,
+	"FILE":             COMPONENT_TYPE_FILE,
+	"SOURCE":           COMPONENT_TYPE_FILE,
+	"ARCHIVE":          COMPONENT_TYPE_FILE,
+	"INSTALL":          COMPONENT_TYPE_APPLICATION,
+	"OTHER":            COMPONENT_TYPE_LIBRARY,
+}
+
+// Converts an SPDX package to an (abstract) CycloneDX component so that
+// existing component-based hashing and reporting can be reused.
+// The package's SPDXID is used as the component's "bom-ref".
+// Note: the package's concluded license is preferred over its declared license
+// (as it represents the outcome of license analysis).
+func (doc *SPDXDocument) ConvertPackageToCDXComponent(spdxPackage SPDXPackage) (cdxComponent CDXComponent) {
+	bomRef := CDXRefType(spdxPackage.SPDXID)
+	cdxComponent.BOMRef = &bomRef
+	cdxComponent.Name = spdxPackage.Name
+	cdxComponent.Version = spdxPackage.VersionInfo
+	cdxComponent.Description = spdxPackage.Description
+	if cdxComponent.Description == "" {
+		cdxComponent.Description = spdxPackage.Summary
+	}
+	cdxComponent.Type = COMPONENT_TYPE_LIBRARY
+	if cdxType, ok := mapSPDXPackagePurposeToCDXType[spdxPackage.PrimaryPackagePurpose]; ok {
+		cdxComponent.Type = cdxType
+	}
+	cdxComponent.Supplier = ConvertSPDXActorToCDXOrganizationalEntity(spdxPackage.Supplier)
+	if spdxPackage.CopyrightText != SPDX_NOASSERTION && spdxPackage.CopyrightText != SPDX_NONE {
+		cdxComponent.Copyright = spdxPackage.CopyrightText
+	}
+	cdxComponent.Hashes, _ = ConvertSPDXChecksumsToCDXHashes(spdxPackage.Checksums)
+
+	if spdxPackage.ExternalRefs != nil {
+		for _, ref := range *spdxPackage.ExternalRefs {
+			switch ref.ReferenceCategory {
+			case SPDX_REF_CATEGORY_PACKAGE_MANAGER, SPDX_REF_CATEGORY_PACKAGE_MANAGER_V2_3:
+				if ref.ReferenceType == SPDX_REF_TYPE_PURL && cdxComponent.Purl == "" {
+					cdxComponent.Purl = ref.ReferenceLocator
+				}
+			case SPDX_REF_CATEGORY_SECURITY:
+				if (ref.ReferenceType == SPDX_REF_TYPE_CPE23 || ref.ReferenceType == SPDX_REF_TYPE_CPE22) &&
+					cdxComponent.Cpe == "" {
+					cdxComponent.Cpe = ref.ReferenceLocator
+				}
+			}
+		}
+	}
+
+	licenseValue := spdxPackage.LicenseConcluded
+	if IsSPDXLicenseNoAssertion(licenseValue) {
+		licenseValue = spdxPackage.LicenseDeclared
+	}
+	if licenseChoice := doc.ConvertLicenseToCDXLicenseChoice(licenseValue); licenseChoice != nil {
+		cdxComponent.Licenses = &[]CDXLicenseChoice{*licenseChoice}
+	}
+	return
+}
+
+// Converts an SPDX file to an (abstract) CycloneDX component of type "file"
+func (doc *SPDXDocument) ConvertFileToCDXComponent(spdxFile SPDXFile) (cdxComponent CDXComponent) {
+	bomRef := CDXRefType(spdxFile.SPDXID)
+	cdxComponent.BOMRef = &bomRef
+	cdxComponent.Type = COMPONENT_TYPE_FILE
+	cdxComponent.Name = spdxFile.FileName
+	if spdxFile.CopyrightText != SPDX_NOASSERTION && spdxFile.CopyrightText != SPDX_NONE {
+		cdxComponent.Copyright = spdxFile.CopyrightText
+	}
+	cdxComponent.Hashes, _ = ConvertSPDXChecksumsToCDXHashes(spdxFile.Checksums)
+	if licenseChoice := doc.ConvertLicenseToCDXLicenseChoice(spdxFile.LicenseConcluded); licenseChoice != nil {
+		cdxComponent.Licenses = &[]CDXLicenseChoice{*licenseChoice}
+	}
+	return
+}
+
+// Converts an SPDX package's "SECURITY" "advisory" external references
+// to (abstract) CycloneDX vulnerabilities that affect the package
+func (doc *SPDXDocument) ConvertPackageAdvisoriesToCDXVulnerabilities(spdxPackage SPDXPackage) (vulnerabilities []CDXVulnerability) {
+	if spdxPackage.ExternalRefs == nil {
+		return
+	}
+	for _, ref := range *spdxPackage.ExternalRefs {
+		if ref.ReferenceCategory != SPDX_REF_CATEGORY_SECURITY ||
+			ref.ReferenceType != SPDX_REF_TYPE_ADVISORY {
+			continue
+		}
+		var vulnerability CDXVulnerability
+		vulnerability.Id = GetSPDXAdvisoryId(ref.ReferenceLocator)
+		vulnerability.Source = &CDXVulnerabilitySource{Url: ref.ReferenceLocator}
+		vulnerability.Description = ref.Comment
+		affectedRef := CDXRefLinkType(spdxPackage.SPDXID)
+		vulnerability.Affects = &[]CDXAffect{{Ref: &affectedRef}}
+		vulnerabilities = append(vulnerabilities, vulnerability)
+	}
+	return
+}
+
+// Derives a vulnerability ID from an advisory URL; by convention, advisory
+// databases use the ID as the last segment of the URL path
+// (e.g., "https://nvd.nist.gov/vuln/detail/CVE-2020-28498")
+func GetSPDXAdvisoryId(locator string) (id string) {
+	id = strings.TrimRight(locator, "/")
+	if index := strings.LastIndex(id, "/"); index >= 0 && index < len(id)-1 {
+		id = id[index+1:]
+	}
+	return
+}
@@ -0,0 +1,129 @@
+#!/usr/bin/env node
+// An independent JSF (JSON Signature Format) reference implementation used to
+// cross-check the Go implementation. It relies only on Node.js built-in "crypto"
+// and JavaScript's own JSON serialization, which JCS (RFC 8785) is defined upon.
+//
+// Usage:
+//   node jsf-reference.js sign <file> <signature|signers|chain> <excludes|-> <key.pem>[:<alg>] ...
+//   node jsf-reference.js verify <file>
+//
+// "sign" writes the signed (top-level) object to stdout; "verify" prints one
+// line per signer and exits non-zero if any signer does not verify.
+'use strict';
+
+const crypto = require('crypto');
+const fs = require('fs');
+
+const HASHES = { '256': 'sha256', '384': 'sha384', '512': 'sha512' };
+
+// JCS (RFC 8785): object properties sorted by UTF-16 code units; primitives
+// serialized as ECMAScript JSON.stringify does
+function canonicalize(value) {
+  if (Array.isArray(value)) {
+    return '[' + value.map(canonicalize).join(',') + ']';
+  }
+  if (value !== null && typeof value === 'object') {
+    return '{' + Object.keys(value).sort().map(
+      (key) => JSON.stringify(key) + ':' + canonicalize(value[key])).join(',') + '}';
+  }
+  return JSON.stringify(value);
+}
+
+function defaultAlgorithm(key) {
+  switch (key.asymmetricKeyType) {
+    case 'rsa': return 'RS256';
+    case 'ed25519': return 'Ed25519';
+    case 'ec': return { 'P-256': 'ES256', 'P-384': 'ES384', 'P-521': 'ES512' }[key.export({ format: 'jwk' }).crv];
+  }
+  throw new Error('unsupported key type: ' + key.asymmetricKeyType);
+}
+
+function signOrVerify(algorithm, key, data, signature) {
+  const hash = HASHES[algorithm.slice(2)];
+  let args;
+  switch (algorithm.slice(0, 2)) {
+    case 'RS': args = [hash, { key: key, padding: crypto.constants.RSA_PKCS1_PADDING }]; break;
+    case 'PS': args = [hash, { key: key, padding: crypto.constants.RSA_PKCS1_PSS_PADDING, saltLength: crypto.constants.RSA_PSS_SALTLEN_DIGEST }]; break;
+    case 'ES': args = [hash, { key: key, dsaEncoding: 'ieee-p1363' }]; break;
+    case 'Ed': args = [null, key]; break;
+    default: throw new Error('unsupported algorithm: ' + algorithm);
+  }
+  if (signature === undefined) {
+    return crypto.sign(args[0], Buffer.from(data, 'utf8'), args[1]).toString('base64url');
+  }
+  return crypto.verify(args[0], Buffer.from(data, 'utf8'), args[1], Buffer.from(signature, 'base64url'));
+}
+
+// The data signed by signer "index": the object (less any excluded properties)
+// holding a signature object with only that signer's "value" removed. For
+// "signers" and "chain", global options (i.e., "excludes") are kept from the
+// outer signature object; "signers" holds only the signer, "chain" holds all
+// prior signers (with their values) followed by the signer.
+function signedData(object, signature, type, index) {
+  const signers = type === 'signature' ? [signature] : signature[type];
+  const signer = Object.assign({}, signers[index]);
+  delete signer.value;
+
+  let signed = signer;
+  if (type !== 'signature') {
+    signed = Object.assign({}, signature);
+    signed[type] = type === 'signers' ? [signer] : signers.slice(0, index).concat([signer]);
+  }
+  const copy = Object.assign({}, object);
+  for (const name of signed.excludes || []) {
+    delete copy[name];
+  }
+  copy.signature = signed;
+  return canonicalize(copy);
+}
+
+function sign(object, type, excludes, keySpecs) {
+  const signature = {};
+  if (type !== 'signature') {
+    if (excludes.length > 0) signature.excludes = excludes;
+    signature[type] = [];
+  }
+  for (const keySpec of keySpecs) {
+    const [file, alg] = keySpec.split(':');
+    const privateKey = crypto.createPrivateKey(fs.readFileSync(file));
+    const signer = { algorithm: alg || defaultAlgorithm(privateKey) };
+    const jwk = crypto.createPublicKey(privateKey).export({ format: 'jwk' });
+    signer.publicKey = jwk;
+    if (type === 'signature') {
+      if (excludes.length > 0) signer.excludes = excludes;
+      object.signature = signer;
+      signer.value = signOrVerify(signer.algorithm, privateKey, signedData(object, signer, type, 0));
+    } else {
+      signature[type].push(signer);
+      object.signature = signature;
+      signer.value = signOrVerify(signer.algorithm, privateKey,
+        signedData(object, signature, type, signature[type].length - 1));
+    }
+  }
+  return object;
+}
+
+function verify(object) {
+  const signature = object.signature;
+  const type = signature.signers ? 'signers' : signature.chain ? 'chain' : 'signature';
+  const signers = type === 'signature' ? [signature] : signature[type];
+  return signers.map((signer, index) => {
+    const key = crypto.createPublicKey({ key: signer.publicKey, format: 'jwk' });
+    return signOrVerify(signer.algorithm, key, signedData(object, signature, type, index), signer.value);
+  });
+}
+
+const [command, file, ...args] = process.argv.slice(2);
+const object = JSON.parse(fs.readFileSync(file, 'utf8'));
+if (command === 'sign') {
+  const [type, excludes, ...keySpecs] = args;
+  const signed = sign(object, type, excludes === '-' ? [] : excludes.split(','), keySpecs);
+  process.stdout.write(JSON.stringify(signed, null, 2) + '\n');
+} else if (command === 'verify') {
+  const results = verify(object);
+  results.forEach((valid, index) => console.log(index + ': ' + (valid ? 'valid' : 'invalid')));
+  process.exit(results.every((valid) => valid) ? 0 : 1);
+} else {
+  console.error('usage: jsf-reference.js sign|verify <file> ...');
+  process.exit(2);
+}
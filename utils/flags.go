@@ -36,26 +36,36 @@ type CommandFlags struct {
 	ExecDir    string
 
 	// Configurations
-	ConfigSchemaFile           string
-	ConfigCustomValidationFile string
-	ConfigLicensePolicyFile    string
+	ConfigSchemaFile            string
+	ConfigCustomValidationFiles []string
+	ConfigLicensePolicyFile     string
 
 	// persistent flags (common to all commands)
 	PersistentFlags PersistentCommandFlags
 
 	// Command-specific flags
+	BatchFlags              BatchCommandFlags
+	ConvertFlags            ConvertCommandFlags
 	CustomValidationOptions CustomValidationFlags
+	DependencyFlags         DependencyCommandFlags
 	DiffFlags               DiffCommandFlags
 	LicenseFlags            LicenseCommandFlags
+	MergeFlags              MergeCommandFlags
+	MigrateFlags            MigrateCommandFlags
+	QueryFlags              QueryCommandFlags
 	ResourceFlags           ResourceCommandFlags
 	SchemaFlags             SchemaCommandFlags
+	ServeFlags              ServeCommandFlags
+	SignatureFlags          SignatureCommandFlags
 	ValidateFlags           ValidateCommandFlags
 	VulnerabilityFlags      VulnerabilityCommandFlags
 	StatsFlags              StatsCommandFlags
 	TrimFlags               TrimCommandFlags
 
 	// Misc flags
-	LogOutputIndentCallstack bool // Log indent
+	LogOutputIndentCallstack bool   // Log indent
+	LogFormat                string // Log (line) format (i.e., "text" or "json")
+	LogFile                  string // Log output file (i.e., instead of stdout)
 }
 
 // NOTE: These flags are shared by both the list and policy subcommands
@@ -83,11 +93,29 @@ type ValidateCommandFlags struct {
 	ForcedJsonSchemaFile string
 	// Uses custom validation flags if "true"; defaults to config. "custom.json"
 	CustomValidation bool
+	// List the (effective) custom validation rules (i.e., instead of validating)
+	ListRules bool
+	// Validate conformance to a built-in profile (e.g., "ntia") and the (minimum)
+	// percentage of conforming components required to pass
+	Profile          string
+	ProfileThreshold float64
 	// error result processing
 	MaxNumErrors              int
 	MaxErrorDescriptionLength int
 	ColorizeErrorOutput       bool
 	ShowErrorValue            bool
+	// validate a stream of documents (i.e., "paths" or "json") read from the input
+	StreamMode string
+}
+
+// NOTE: the query command's output format is not shared with other commands
+// (i.e., as it defaults to "json" where other commands default to "txt")
+type QueryCommandFlags struct {
+	OutputFormat string
+}
+
+type DependencyCommandFlags struct {
+	Ref string
 }
 
 type VulnerabilityCommandFlags struct {
@@ -97,6 +125,7 @@ type VulnerabilityCommandFlags struct {
 type DiffCommandFlags struct {
 	Colorize    bool
 	RevisedFile string
+	Semantic    bool // compare BOM entities by identity (not document position)
 }
 
 type ResourceCommandFlags struct {
@@ -112,9 +141,31 @@ func NewResourceCommandFlags(resourceType string) ResourceCommandFlags {
 type SchemaCommandFlags struct {
 }
 
+type SignatureCommandFlags struct {
+	// verify: PEM-encoded public key or (X.509) certificate (at most one)
+	// sign: PEM-encoded private key (one per signer)
+	KeyFiles []string
+	// sign: (optional) values for each signer (i.e., in the same order as KeyFiles)
+	Algorithms       []string
+	KeyIds           []string
+	CertificateFiles []string
+	// sign: signer and signature options
+	EmbedPublicKey bool
+	SignatureType  string // i.e., "signature", "signers" or "chain"
+	Excludes       []string
+	// sign: select the objects to sign (defaults to the BOM)
+	From  string
+	Where string
+}
+
 type StatsCommandFlags struct {
 }
 
+type ServeCommandFlags struct {
+	Address        string // e.g., "localhost:8080"
+	MaxRequestSize int64  // maximum request body size (bytes)
+}
+
 // TODO: write a "parse" method for the struct (i.e., from "raw" to slice)
 type TrimCommandFlags struct {
 	RawKeys   string
@@ -123,6 +174,36 @@ type TrimCommandFlags struct {
 	FromPaths []string
 }
 
+type ConvertCommandFlags struct {
+	TargetFormat string // i.e., "cyclonedx" or "spdx"
+	ReportFile   string
+	ReportFormat string
+}
+
+type MigrateCommandFlags struct {
+	SpecVersion  string // i.e., CycloneDX "specVersion" (e.g., "1.4")
+	ReportFile   string
+	ReportFormat string
+}
+
+// NOTE: These flags are shared by all commands that support multiple input files
+type BatchCommandFlags struct {
+	// input filenames, glob patterns, directories or "@" (prefixed) file lists
+	// (resolved to filenames before the command is run)
+	InputFiles []string
+	MaxWorkers int // max. number of input files processed concurrently
+}
+
+type MergeCommandFlags struct {
+	InputFiles   []string
+	Strategy     string // i.e., "first-wins", "last-wins" or "fail"
+	Hierarchical bool
+	// (optional) new metadata.component (required for hierarchical merge)
+	Name    string
+	Version string
+	Group   string
+}
+
 type CustomValidationFlags struct {
 	Composition bool
 	License     bool
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// JSON Canonicalization Scheme (JCS)
+// See: https://www.rfc-editor.org/rfc/rfc8785
+
+// Returns the canonical (RFC 8785) serialization of a JSON value as unmarshalled
+// by the encoding/json package (i.e., maps, slices, strings, float64, bools and nil)
+func CanonicalizeJSON(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writeCanonicalJSON(&buffer, value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Canonicalizes raw JSON data (i.e., data that has not been unmarshalled)
+func CanonicalizeJSONBytes(data []byte) ([]byte, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return CanonicalizeJSON(value)
+}
+
+// Note: this method is recursive
+func writeCanonicalJSON(buffer *bytes.Buffer, value interface{}) (err error) {
+	switch typedValue := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case bool:
+		buffer.WriteString(strconv.FormatBool(typedValue))
+	case string:
+		writeCanonicalString(buffer, typedValue)
+	case float64:
+		var number string
+		if number, err = canonicalNumber(typedValue); err != nil {
+			return
+		}
+		buffer.WriteString(number)
+	case json.Number:
+		var f float64
+		if f, err = typedValue.Float64(); err != nil {
+			return
+		}
+		return writeCanonicalJSON(buffer, f)
+	case int:
+		return writeCanonicalJSON(buffer, float64(typedValue))
+	case []interface{}:
+		buffer.WriteByte('[')
+		for i, item := range typedValue {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err = writeCanonicalJSON(buffer, item); err != nil {
+				return
+			}
+		}
+		buffer.WriteByte(']')
+	case map[string]interface{}:
+		// Properties are sorted by their (UTF-16 code unit) names
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return compareUTF16(keys[i], keys[j]) < 0
+		})
+		buffer.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeCanonicalString(buffer, key)
+			buffer.WriteByte(':')
+			if err = writeCanonicalJSON(buffer, typedValue[key]); err != nil {
+				return
+			}
+		}
+		buffer.WriteByte('}')
+	default:
+		return fmt.Errorf("unsupported JSON type: %T", value)
+	}
+	return
+}
+
+func compareUTF16(a string, b string) int {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return int(ua[i]) - int(ub[i])
+		}
+	}
+	return len(ua) - len(ub)
+}
+
+// Strings are serialized with only the minimal set of escapes
+func writeCanonicalString(buffer *bytes.Buffer, value string) {
+	buffer.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"':
+			buffer.WriteString("\\\"")
+		case '\\':
+			buffer.WriteString("\\\\")
+		case '\b':
+			buffer.WriteString("\\b")
+		case '\f':
+			buffer.WriteString("\\f")
+		case '\n':
+			buffer.WriteString("\\n")
+		case '\r':
+			buffer.WriteString("\\r")
+		case '\t':
+			buffer.WriteString("\\t")
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buffer, "\\u%04x", r)
+			} else {
+				buffer.WriteRune(r)
+			}
+		}
+	}
+	buffer.WriteByte('"')
+}
+
+// Numbers are serialized using the ECMAScript (ES6) Number.prototype.toString() algorithm
+func canonicalNumber(value float64) (string, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "", fmt.Errorf("invalid JSON number: %v", value)
+	}
+	if value == 0 {
+		return "0", nil
+	}
+	var sign string
+	if value < 0 {
+		value = -value
+		sign = "-"
+	}
+	var format byte = 'e'
+	if value < 1e+21 && value >= 1e-6 {
+		format = 'f'
+	}
+	formatted := strconv.FormatFloat(value, format, -1, 64)
+	// Go's exponent has (at least) 2 digits (e.g., "1e+09") which must be "1e+9"
+	if exponent := strings.IndexByte(formatted, 'e'); exponent > 0 && formatted[exponent+2] == '0' {
+		formatted = formatted[:exponent+2] + formatted[exponent+3:]
+	}
+	return sign + formatted, nil
+}
@@ -19,24 +19,11 @@
 package utils
 
 import (
-	"os"
-	"runtime"
-	"strings"
+	"github.com/CycloneDX/sbom-utility/log"
 )
 
+// Returns the name (without package) of the function "skip" frames up the callstack
 func GetCallerFunctionName(skip int) (fxName string) {
-	pCallers := make([]uintptr, 4)
-	// Note: immediate caller is at index "2" on the stack
-	runtime.Callers(skip, pCallers)
-	if len(pCallers) > 0 {
-		fx := runtime.FuncForPC(pCallers[0])
-		fxName = fx.Name()
-		if index := strings.LastIndex(fxName, string(os.PathSeparator)); index > -1 {
-			fxName = fxName[index+1:]
-		}
-		if index := strings.LastIndex(fxName, "."); index > -1 {
-			fxName = fxName[index+1:]
-		}
-	}
-	return
+	// Note: skip this (delegating) function's frame
+	return log.GetCallerFunctionName(skip + 1)
 }
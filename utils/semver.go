@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare two (semantic) version strings returning -1, 0 or +1 if v1 is less than,
+// equal to or greater than v2 respectively.
+// Note: comparison is lenient as BOM component versions are often not strict semver;
+// a leading "v" is ignored, missing version parts are treated as "0", non-numeric parts
+// are compared lexically and build metadata (i.e., "+<build>") is ignored.
+// Pre-release versions (i.e., "-<pre-release>") have lower precedence than the
+// associated release version as per the semver specification.
+func CompareVersions(v1 string, v2 string) int {
+	release1, preRelease1 := splitVersion(v1)
+	release2, preRelease2 := splitVersion(v2)
+
+	if result := compareVersionIdentifiers(
+		strings.Split(release1, "."), strings.Split(release2, "."), true); result != 0 {
+		return result
+	}
+
+	// A version without a pre-release has higher precedence (e.g., 1.0.0 > 1.0.0-rc.1)
+	switch {
+	case preRelease1 == preRelease2:
+		return 0
+	case preRelease1 == "":
+		return 1
+	case preRelease2 == "":
+		return -1
+	}
+	return compareVersionIdentifiers(
+		strings.Split(preRelease1, "."), strings.Split(preRelease2, "."), false)
+}
+
+// Split a version string into its release (e.g., "1.2.3") and pre-release parts
+func splitVersion(version string) (release string, preRelease string) {
+	release = strings.TrimSpace(version)
+	release = strings.TrimPrefix(strings.TrimPrefix(release, "v"), "V")
+	if index := strings.Index(release, "+"); index >= 0 {
+		release = release[:index]
+	}
+	if index := strings.Index(release, "-"); index >= 0 {
+		preRelease = release[index+1:]
+		release = release[:index]
+	}
+	return
+}
+
+// Compare dot-separated version identifiers; numeric identifiers are compared by value
+// and have lower precedence than alphanumeric ones.  If padZero is true, the shorter
+// list of identifiers is padded with "0" (release parts); otherwise, the shorter list
+// has lower precedence (pre-release parts).
+func compareVersionIdentifiers(ids1 []string, ids2 []string, padZero bool) int {
+	length := len(ids1)
+	if len(ids2) > length {
+		length = len(ids2)
+	}
+
+	for i := 0; i < length; i++ {
+		var id1, id2 string
+		if i < len(ids1) {
+			id1 = ids1[i]
+		} else if padZero {
+			id1 = "0"
+		} else {
+			return -1
+		}
+		if i < len(ids2) {
+			id2 = ids2[i]
+		} else if padZero {
+			id2 = "0"
+		} else {
+			return 1
+		}
+
+		num1, errNum1 := strconv.ParseUint(id1, 10, 64)
+		num2, errNum2 := strconv.ParseUint(id2, 10, 64)
+		switch {
+		case errNum1 == nil && errNum2 == nil:
+			if num1 != num2 {
+				if num1 < num2 {
+					return -1
+				}
+				return 1
+			}
+		case errNum1 == nil:
+			return -1
+		case errNum2 == nil:
+			return 1
+		default:
+			if result := strings.Compare(id1, id2); result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
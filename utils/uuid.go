@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+const URN_UUID_PREFIX = "urn:uuid:"
+
+// Generate a random (version 4) UUID as a URN (RFC 4122) which is the form
+// CycloneDX requires for the BOM "serialNumber"
+func GenerateURNUUID() (urn string, err error) {
+	uuid := make([]byte, 16)
+	if _, err = rand.Read(uuid); err != nil {
+		return
+	}
+	// set version (4) and variant (RFC 4122) bits
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	urn = fmt.Sprintf("%s%x-%x-%x-%x-%x", URN_UUID_PREFIX,
+		uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+	return
+}